@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"crypto"
 	"crypto/rand"
-	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
@@ -69,13 +68,13 @@ func (ca *CA) GenerateCACert(input *CACSRInput) (*Certificate, error) {
 	}
 
 	// generate key
-	caPrivKey, err := rsa.GenerateKey(rand.Reader, input.KeySize)
+	caPrivKey, err := generateKey(input.KeyAlgorithm, input.KeySize)
 	if err != nil {
 		return nil, err
 	}
 
 	// create the certificate
-	caBytes, err := x509.CreateCertificate(rand.Reader, certTemplate, certTemplate, &caPrivKey.PublicKey, caPrivKey)
+	caBytes, err := x509.CreateCertificate(rand.Reader, certTemplate, certTemplate, caPrivKey.Public(), caPrivKey)
 	if err != nil {
 		return nil, err
 	}
@@ -88,16 +87,15 @@ func (ca *CA) GenerateCACert(input *CACSRInput) (*Certificate, error) {
 	})
 
 	// convert Private Key into PEM format
-	caPrivKeyPEM := new(bytes.Buffer)
-	pem.Encode(caPrivKeyPEM, &pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(caPrivKey),
-	})
+	caPrivKeyPEM, err := marshalKeyPEM(caPrivKey)
+	if err != nil {
+		return nil, err
+	}
 
 	// create the clab certificate struct
 	clabCert := &Certificate{
 		Cert: caPEM.Bytes(),
-		Key:  caPrivKeyPEM.Bytes(),
+		Key:  caPrivKeyPEM,
 	}
 
 	return clabCert, nil
@@ -108,9 +106,13 @@ func (ca *CA) GenerateAndSignNodeCert(input *NodeCSRInput) (*Certificate, error)
 	// parse hosts from input to retrieve dns and ip SANs
 	dns, ip := parseHostsInput(input.Hosts)
 
-	keysize := 2048
-	if input.KeySize > 0 {
-		keysize = input.KeySize
+	// node keys use the algorithm of the CA key unless set explicitly
+	keyAlgo := input.KeyAlgorithm
+	if keyAlgo == "" {
+		var err error
+		if keyAlgo, err = keyAlgorithm(ca.key); err != nil {
+			return nil, err
+		}
 	}
 
 	expiry := time.Until(time.Now().AddDate(1, 0, 0)) // 1 year as default
@@ -118,9 +120,15 @@ func (ca *CA) GenerateAndSignNodeCert(input *NodeCSRInput) (*Certificate, error)
 		expiry = input.Expiry
 	}
 
+	// renewed certificates must not reuse the serial number of the certificates they replace
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
 	certTemplate := &x509.Certificate{
 		RawSubject:   []byte{},
-		SerialNumber: big.NewInt(1658),
+		SerialNumber: serial,
 		Subject: pkix.Name{
 			Organization:       []string{input.Organization},
 			OrganizationalUnit: []string{input.OrganizationUnit},
@@ -134,16 +142,17 @@ func (ca *CA) GenerateAndSignNodeCert(input *NodeCSRInput) (*Certificate, error)
 		NotAfter:     time.Now().Add(expiry),
 		SubjectKeyId: []byte{1, 2, 3, 4, 6},
 		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
-		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
 	}
 
-	newPrivKey, err := rsa.GenerateKey(rand.Reader, keysize)
+	newPrivKey, err := generateKey(keyAlgo, input.KeySize)
 	if err != nil {
 		return nil, err
 	}
 
+	certTemplate.KeyUsage = keyUsage(newPrivKey)
+
 	// create the certificate
-	certBytes, err := x509.CreateCertificate(rand.Reader, certTemplate, ca.cert, &newPrivKey.PublicKey, ca.key)
+	certBytes, err := x509.CreateCertificate(rand.Reader, certTemplate, ca.cert, newPrivKey.Public(), ca.key)
 	if err != nil {
 		return nil, err
 	}
@@ -154,16 +163,15 @@ func (ca *CA) GenerateAndSignNodeCert(input *NodeCSRInput) (*Certificate, error)
 		Bytes: certBytes,
 	})
 
-	certPrivKeyPEM := new(bytes.Buffer)
-	pem.Encode(certPrivKeyPEM, &pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(newPrivKey),
-	})
+	certPrivKeyPEM, err := marshalKeyPEM(newPrivKey)
+	if err != nil {
+		return nil, err
+	}
 
 	// create the clab certificate struct
 	clabCert := &Certificate{
 		Cert: certPEM.Bytes(),
-		Key:  certPrivKeyPEM.Bytes(),
+		Key:  certPrivKeyPEM,
 	}
 
 	return clabCert, nil
@@ -0,0 +1,132 @@
+package cert
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+)
+
+func TestGenerateCerts(t *testing.T) {
+	tests := map[string]struct {
+		caAlgo   string
+		nodeAlgo string
+		// expected PEM types of the CA and node keys
+		caKeyType   string
+		nodeKeyType string
+		nodePubAlgo x509.PublicKeyAlgorithm
+	}{
+		"rsa": {
+			caAlgo:      KeyAlgorithmRSA,
+			caKeyType:   "PRIVATE KEY",
+			nodeKeyType: "PRIVATE KEY",
+			nodePubAlgo: x509.RSA,
+		},
+		"node key follows ecdsa ca key": {
+			caAlgo:      KeyAlgorithmECDSAP384,
+			caKeyType:   "PRIVATE KEY",
+			nodeKeyType: "PRIVATE KEY",
+			nodePubAlgo: x509.ECDSA,
+		},
+		"ed25519 node key signed by ecdsa ca": {
+			caAlgo:      KeyAlgorithmECDSAP256,
+			nodeAlgo:    KeyAlgorithmEd25519,
+			caKeyType:   "PRIVATE KEY",
+			nodeKeyType: "PRIVATE KEY",
+			nodePubAlgo: x509.Ed25519,
+		},
+		"ecdsa node key signed by ed25519 ca": {
+			caAlgo:      KeyAlgorithmEd25519,
+			nodeAlgo:    KeyAlgorithmECDSAP256,
+			caKeyType:   "PRIVATE KEY",
+			nodeKeyType: "PRIVATE KEY",
+			nodePubAlgo: x509.ECDSA,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			caCert, err := NewCA().GenerateCACert(&CACSRInput{
+				CommonName:   "test CA",
+				Expiry:       time.Hour,
+				KeyAlgorithm: tt.caAlgo,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if b, _ := pem.Decode(caCert.Key); b == nil || b.Type != tt.caKeyType {
+				t.Fatalf("unexpected CA key PEM block %v", b)
+			}
+
+			// load the CA from PEM as it is done for the lab CA
+			ca := NewCA()
+			if err := ca.SetCACert(caCert); err != nil {
+				t.Fatal(err)
+			}
+
+			nodeCert, err := ca.GenerateAndSignNodeCert(&NodeCSRInput{
+				CommonName:   "node1",
+				Hosts:        []string{"node1", "172.20.20.2"},
+				KeyAlgorithm: tt.nodeAlgo,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if b, _ := pem.Decode(nodeCert.Key); b == nil || b.Type != tt.nodeKeyType {
+				t.Fatalf("unexpected node key PEM block %v", b)
+			}
+
+			b, _ := pem.Decode(nodeCert.Cert)
+			c, err := x509.ParseCertificate(b.Bytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if c.PublicKeyAlgorithm != tt.nodePubAlgo {
+				t.Errorf("got node public key algorithm %s, want %s", c.PublicKeyAlgorithm, tt.nodePubAlgo)
+			}
+
+			if err := c.CheckSignatureFrom(ca.cert); err != nil {
+				t.Errorf("node certificate is not signed by the CA: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadPKCS1CACert(t *testing.T) {
+	caCert, err := NewCA().GenerateCACert(&CACSRInput{CommonName: "test CA", Expiry: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// re-encode the key in the PKCS#1 format used for the RSA keys by the earlier versions
+	b, _ := pem.Decode(caCert.Key)
+	key, err := x509.ParsePKCS8PrivateKey(b.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caCert.Key = pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key.(*rsa.PrivateKey)),
+	})
+
+	ca := NewCA()
+	if err := ca.SetCACert(caCert); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ca.GenerateAndSignNodeCert(&NodeCSRInput{CommonName: "node1"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnsupportedKeyAlgorithm(t *testing.T) {
+	_, err := NewCA().GenerateCACert(&CACSRInput{KeyAlgorithm: "dsa", Expiry: time.Hour})
+	if err == nil {
+		t.Fatal("expected an error for an unsupported key algorithm")
+	}
+}
@@ -11,6 +11,8 @@ type CACSRInput struct {
 	OrganizationUnit string
 	Expiry           time.Duration
 	KeySize          int
+	// KeyAlgorithm is one of the KeyAlgorithms, RSA by default.
+	KeyAlgorithm string
 }
 
 // NodeCSRInput struct.
@@ -23,4 +25,6 @@ type NodeCSRInput struct {
 	OrganizationUnit string
 	Expiry           time.Duration
 	KeySize          int
+	// KeyAlgorithm is one of the KeyAlgorithms, the algorithm of the CA key by default.
+	KeyAlgorithm string
 }
@@ -0,0 +1,90 @@
+package cert
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net"
+	"slices"
+	"time"
+)
+
+// Info is the summary of a certificate.
+type Info struct {
+	// Name is the name of the certificate owner, a node name or "ca" for the CA certificate.
+	Name         string    `json:"name"`
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SerialNumber string    `json:"serial-number"`
+	NotBefore    time.Time `json:"not-before"`
+	NotAfter     time.Time `json:"not-after"`
+	DNSNames     []string  `json:"dns-names,omitempty"`
+	IPAddresses  []string  `json:"ip-addresses,omitempty"`
+	KeyAlgorithm string    `json:"key-algorithm"`
+	IsCA         bool      `json:"is-ca"`
+}
+
+// X509 parses the PEM encoded certificate.
+func (c *Certificate) X509() (*x509.Certificate, error) {
+	b, _ := pem.Decode(c.Cert)
+	if b == nil {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+
+	return x509.ParseCertificate(b.Bytes)
+}
+
+// Info returns the summary of the certificate.
+func (c *Certificate) Info(name string) (*Info, error) {
+	xc, err := c.X509()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{
+		Name:         name,
+		Subject:      xc.Subject.String(),
+		Issuer:       xc.Issuer.String(),
+		SerialNumber: xc.SerialNumber.String(),
+		NotBefore:    xc.NotBefore,
+		NotAfter:     xc.NotAfter,
+		DNSNames:     xc.DNSNames,
+		IsCA:         xc.IsCA,
+	}
+
+	for _, ip := range xc.IPAddresses {
+		info.IPAddresses = append(info.IPAddresses, ip.String())
+	}
+
+	info.KeyAlgorithm, err = publicKeyAlgorithm(xc.PublicKey)
+	if err != nil {
+		info.KeyAlgorithm = xc.PublicKeyAlgorithm.String()
+	}
+
+	return info, nil
+}
+
+// ExpiresWithin returns true if the certificate expires within the given duration from now.
+func (i *Info) ExpiresWithin(d time.Duration) bool {
+	return time.Now().Add(d).After(i.NotAfter)
+}
+
+// MissingIPs returns the given addresses that are not listed in the IP SANs of the certificate.
+func (i *Info) MissingIPs(ips ...string) []string {
+	var missing []string
+
+	for _, s := range ips {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			continue
+		}
+
+		if !slices.ContainsFunc(i.IPAddresses, func(a string) bool {
+			return net.ParseIP(a).Equal(ip)
+		}) {
+			missing = append(missing, s)
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,56 @@
+package cert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCertificateInfo(t *testing.T) {
+	ca := NewCA()
+
+	caCert, err := ca.GenerateCACert(&CACSRInput{
+		CommonName:   "test CA",
+		Expiry:       time.Hour,
+		KeyAlgorithm: KeyAlgorithmECDSAP256,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ca.SetCACert(caCert); err != nil {
+		t.Fatal(err)
+	}
+
+	nodeCert, err := ca.GenerateAndSignNodeCert(&NodeCSRInput{
+		CommonName: "node1.lab.io",
+		Hosts:      []string{"node1", "172.20.20.2", "3fff:172:20:20::2"},
+		Expiry:     2 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := nodeCert.Info("node1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Name != "node1" || info.IsCA || info.KeyAlgorithm != KeyAlgorithmECDSAP256 {
+		t.Errorf("unexpected certificate info %+v", info)
+	}
+
+	if d := cmp.Diff([]string{"172.20.20.2", "3fff:172:20:20::2"}, info.IPAddresses); d != "" {
+		t.Errorf("IP SANs mismatch (-want +got):\n%s", d)
+	}
+
+	if info.ExpiresWithin(time.Hour) || !info.ExpiresWithin(3*time.Hour) {
+		t.Errorf("unexpected expiry %s", info.NotAfter)
+	}
+
+	missing := info.MissingIPs("172.20.20.2", "172.20.20.5", "3fff:172:20:20:0::2", "")
+	if d := cmp.Diff([]string{"172.20.20.5"}, missing); d != "" {
+		t.Errorf("missing IPs mismatch (-want +got):\n%s", d)
+	}
+}
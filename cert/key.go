@@ -0,0 +1,124 @@
+package cert
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Key algorithms supported for the CA and node keys.
+const (
+	KeyAlgorithmRSA       = "rsa"
+	KeyAlgorithmECDSAP256 = "ecdsa-p256"
+	KeyAlgorithmECDSAP384 = "ecdsa-p384"
+	KeyAlgorithmEd25519   = "ed25519"
+
+	// DefaultKeyAlgorithm is the key algorithm used when none is set.
+	DefaultKeyAlgorithm = KeyAlgorithmRSA
+	// DefaultRSAKeySize is the size of the RSA keys used when none is set.
+	DefaultRSAKeySize = 2048
+)
+
+// KeyAlgorithms is the list of the supported key algorithms.
+var KeyAlgorithms = []string{
+	KeyAlgorithmRSA,
+	KeyAlgorithmECDSAP256,
+	KeyAlgorithmECDSAP384,
+	KeyAlgorithmEd25519,
+}
+
+// ValidateKeyAlgorithm returns an error if the key algorithm is not supported.
+// An empty algorithm is valid and means the default one.
+func ValidateKeyAlgorithm(algo string) error {
+	if algo != "" && !slices.Contains(KeyAlgorithms, algo) {
+		return fmt.Errorf("unsupported key algorithm %q, supported algorithms are: %s",
+			algo, strings.Join(KeyAlgorithms, ", "))
+	}
+
+	return nil
+}
+
+// generateKey generates a private key of the given algorithm.
+// The RSA key size is only used for the RSA keys.
+func generateKey(algo string, rsaKeySize int) (crypto.Signer, error) {
+	switch algo {
+	case KeyAlgorithmRSA, "":
+		if rsaKeySize <= 0 {
+			rsaKeySize = DefaultRSAKeySize
+		}
+
+		return rsa.GenerateKey(rand.Reader, rsaKeySize)
+	case KeyAlgorithmECDSAP256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeyAlgorithmECDSAP384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case KeyAlgorithmEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	}
+
+	return nil, ValidateKeyAlgorithm(algo)
+}
+
+// keyAlgorithm returns the algorithm of the private key.
+func keyAlgorithm(key crypto.PrivateKey) (string, error) {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return "", fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	return publicKeyAlgorithm(signer.Public())
+}
+
+// publicKeyAlgorithm returns the algorithm of the public key.
+func publicKeyAlgorithm(pub crypto.PublicKey) (string, error) {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return KeyAlgorithmRSA, nil
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return KeyAlgorithmECDSAP256, nil
+		case elliptic.P384():
+			return KeyAlgorithmECDSAP384, nil
+		}
+
+		return "", fmt.Errorf("unsupported ECDSA curve %s", k.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return KeyAlgorithmEd25519, nil
+	}
+
+	return "", fmt.Errorf("unsupported public key type %T", pub)
+}
+
+// marshalKeyPEM encodes the private key in the PKCS#8 PEM format.
+// The keys stored in the PKCS#1 format by the earlier versions are still loaded.
+func marshalKeyPEM(key crypto.Signer) ([]byte, error) {
+	b, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: b,
+	}), nil
+}
+
+// keyUsage returns the key usage of the leaf certificate with the given key.
+// Key encipherment is only applicable to the RSA keys.
+func keyUsage(key crypto.Signer) x509.KeyUsage {
+	if _, ok := key.(*rsa.PrivateKey); ok {
+		return x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	}
+
+	return x509.KeyUsageDigitalSignature
+}
@@ -0,0 +1,752 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package apiserver implements the HTTP REST API for the lab lifecycle operations.
+// The API users are authenticated with the bearer tokens and can only operate on the labs
+// they own, the ownership is tracked with the clab-owner label of the lab containers.
+// The deploy and destroy operations run as asynchronous jobs with their status and logs
+// available over the API, the exec and save operations run as jobs the requests wait for.
+package apiserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"gopkg.in/yaml.v2"
+)
+
+// apiPrefix is the path prefix of the API endpoints.
+const apiPrefix = "/api/v1"
+
+// DefaultTopologiesDir is the default directory the topologies posted in the deploy requests are stored in.
+const DefaultTopologiesDir = "/var/lib/containerlab/api"
+
+var (
+	errLabNotFound = errors.New("lab not found")
+	errNotLabOwner = errors.New("lab is owned by another user")
+)
+
+// labNameRe matches the lab names the topologies posted over the API are stored with.
+var labNameRe = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// DeployRequest is the body of the deploy request.
+// The topology is either the content of the topology file or the name of the topology file
+// stored in the topologies directory of the user, such as the topology posted in a previous deploy request.
+type DeployRequest struct {
+	TopologyFile   string   `json:"topology_file,omitempty"`
+	Topology       string   `json:"topology,omitempty"`
+	Name           string   `json:"name,omitempty"`
+	Instance       string   `json:"instance,omitempty"`
+	Reconfigure    bool     `json:"reconfigure,omitempty"`
+	NodeFilter     []string `json:"node_filter,omitempty"`
+	MaxWorkers     uint     `json:"max_workers,omitempty"`
+	SkipPostDeploy bool     `json:"skip_post_deploy,omitempty"`
+}
+
+// DestroyRequest holds the parameters of the destroy request.
+type DestroyRequest struct {
+	Cleanup     bool     `json:"cleanup,omitempty"`
+	Graceful    bool     `json:"graceful,omitempty"`
+	KeepMgmtNet bool     `json:"keep_mgmt_net,omitempty"`
+	NodeFilter  []string `json:"node_filter,omitempty"`
+	MaxWorkers  uint     `json:"max_workers,omitempty"`
+}
+
+// ExecRequest is the body of the exec request.
+// The commands are executed on the lab containers matching the labels, or on all lab containers.
+type ExecRequest struct {
+	Commands []string `json:"commands"`
+	Labels   []string `json:"labels,omitempty"`
+}
+
+// SaveRequest is the body of the save request.
+type SaveRequest struct {
+	NodeFilter []string `json:"node_filter,omitempty"`
+}
+
+// NetemRequest is the body of the request setting the link impairments of a node interface.
+type NetemRequest struct {
+	Interface  string  `json:"interface"`
+	Delay      string  `json:"delay,omitempty"`
+	Jitter     string  `json:"jitter,omitempty"`
+	Loss       float64 `json:"loss,omitempty"`
+	Rate       uint64  `json:"rate,omitempty"`
+	Corruption float64 `json:"corruption,omitempty"`
+}
+
+// Lab describes a deployed lab.
+type Lab struct {
+	Name         string                   `json:"name"`
+	TopologyFile string                   `json:"topology_file,omitempty"`
+	Owner        string                   `json:"owner"`
+	Containers   []types.ContainerDetails `json:"containers"`
+}
+
+// Server serves the lab lifecycle operations over HTTP.
+type Server struct {
+	backend Backend
+	// tokens maps the user names to their tokens.
+	tokens map[string]string
+	// topologiesDir is the directory the topologies posted in the deploy requests are stored in.
+	topologiesDir string
+	jobs          *jobQueue
+	mux           *http.ServeMux
+	cancel        context.CancelFunc
+}
+
+// Option configures the Server.
+type Option func(s *Server)
+
+// WithTopologiesDir sets the directory the topologies posted in the deploy requests are stored in,
+// the topologies are stored as <dir>/<user>/<lab-name>.clab.yml.
+func WithTopologiesDir(dir string) Option {
+	return func(s *Server) {
+		s.topologiesDir = dir
+	}
+}
+
+// CheckTopologiesDir checks the topologies directory, when it exists, is a directory
+// writable only by its owner, since the lab topologies are deployed from it.
+func CheckTopologiesDir(dir string) error {
+	fi, err := os.Lstat(dir)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		return err
+	case !fi.IsDir():
+		return fmt.Errorf("topologies directory %s is not a directory", dir)
+	case fi.Mode().Perm()&0022 != 0:
+		return fmt.Errorf("topologies directory %s is writable by other users", dir)
+	}
+
+	return nil
+}
+
+// New returns a new Server authenticating the users with the tokens,
+// the tokens map the user names to their tokens.
+// The server runs the queued jobs until it is closed.
+func New(backend Backend, tokens map[string]string, opts ...Option) *Server {
+	s := &Server{
+		backend:       backend,
+		tokens:        tokens,
+		topologiesDir: DefaultTopologiesDir,
+		jobs:          newJobQueue(),
+		mux:           http.NewServeMux(),
+	}
+
+	for _, o := range opts {
+		o(s)
+	}
+
+	log.AddHook(s.jobs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	go s.jobs.process(ctx)
+
+	s.routes()
+
+	return s
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET "+apiPrefix+"/labs", s.auth(s.listLabs))
+	s.mux.HandleFunc("POST "+apiPrefix+"/labs", s.auth(s.deployLab))
+	s.mux.HandleFunc("GET "+apiPrefix+"/labs/{lab}", s.auth(s.inspectLab))
+	s.mux.HandleFunc("DELETE "+apiPrefix+"/labs/{lab}", s.auth(s.destroyLab))
+	s.mux.HandleFunc("POST "+apiPrefix+"/labs/{lab}/exec", s.auth(s.execLab))
+	s.mux.HandleFunc("POST "+apiPrefix+"/labs/{lab}/save", s.auth(s.saveLab))
+	s.mux.HandleFunc("GET "+apiPrefix+"/labs/{lab}/nodes/{node}/netem", s.auth(s.showNetem))
+	s.mux.HandleFunc("PUT "+apiPrefix+"/labs/{lab}/nodes/{node}/netem", s.auth(s.setNetem))
+	s.mux.HandleFunc("GET "+apiPrefix+"/jobs", s.auth(s.listJobs))
+	s.mux.HandleFunc("GET "+apiPrefix+"/jobs/{id}", s.auth(s.getJob))
+	s.mux.HandleFunc("GET "+apiPrefix+"/jobs/{id}/logs", s.auth(s.jobLogs))
+}
+
+// Close stops running the queued jobs.
+func (s *Server) Close() {
+	s.cancel()
+}
+
+// ListenAndServe serves the API on the address until the context is canceled,
+// the API is served over HTTPS when the certificate and key files are provided.
+func (s *Server) ListenAndServe(ctx context.Context, addr, certFile, keyFile string) error {
+	defer s.Close()
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		log.Infof("Starting API server on %s", addr)
+
+		if certFile != "" {
+			errCh <- srv.ListenAndServeTLS(certFile, keyFile)
+			return
+		}
+
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		return srv.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		return err
+	}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// auth authenticates the user with the bearer token of the request.
+func (s *Server) auth(h func(w http.ResponseWriter, r *http.Request, user string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if ok && token != "" {
+			for user, t := range s.tokens {
+				if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+					h(w, r, user)
+					return
+				}
+			}
+		}
+
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, errors.New("invalid or missing token"))
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Debugf("failed to write API response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// writeLabError writes the error of the lab lookup with the status matching the error.
+func writeLabError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errLabNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, errNotLabOwner):
+		writeError(w, http.StatusForbidden, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+// decode decodes the JSON body of the request into v,
+// an empty body leaves v unchanged.
+func decode(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+
+	return nil
+}
+
+// ownedContainers returns the containers of the lab the user owns.
+// The lab is owned by the user when all its containers are labeled as owned by the user.
+func (s *Server) ownedContainers(ctx context.Context, lab, user string) ([]runtime.GenericContainer, error) {
+	var containers []runtime.GenericContainer
+
+	var err error
+
+	s.jobs.untrack(ctx, func() { containers, err = s.backend.Containers(ctx, lab) })
+	if err != nil {
+		return nil, err
+	}
+
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("%w: %s", errLabNotFound, lab)
+	}
+
+	for _, c := range containers {
+		if c.Labels[labels.Owner] != user {
+			return nil, fmt.Errorf("%w: %s", errNotLabOwner, lab)
+		}
+	}
+
+	return containers, nil
+}
+
+// containerDetails returns the details of the lab container.
+func containerDetails(c *runtime.GenericContainer) types.ContainerDetails {
+	d := types.ContainerDetails{
+		LabName:     c.Labels[labels.Containerlab],
+		LabPath:     c.Labels[labels.TopoFile],
+		ContainerID: c.ShortID,
+		Image:       c.Image,
+		Kind:        c.Labels[labels.NodeKind],
+		Group:       c.Labels[labels.NodeGroup],
+		State:       c.State,
+		IPv4Address: c.GetContainerIPv4(),
+		IPv6Address: c.GetContainerIPv6(),
+		Ports:       c.Ports,
+		Owner:       c.Labels[labels.Owner],
+		MgmtPorts:   c.Labels[labels.MgmtPorts],
+	}
+
+	if len(c.Names) > 0 {
+		d.Name = c.Names[0]
+	}
+
+	return d
+}
+
+// labsFromContainers groups the containers by their labs, the labs are sorted by their names.
+func labsFromContainers(containers []runtime.GenericContainer) []*Lab {
+	byName := map[string]*Lab{}
+
+	for i := range containers {
+		c := &containers[i]
+		name := c.Labels[labels.Containerlab]
+
+		lab, ok := byName[name]
+		if !ok {
+			lab = &Lab{
+				Name:         name,
+				TopologyFile: c.Labels[labels.TopoFile],
+				Owner:        c.Labels[labels.Owner],
+				Containers:   []types.ContainerDetails{},
+			}
+			byName[name] = lab
+		}
+
+		lab.Containers = append(lab.Containers, containerDetails(c))
+	}
+
+	labs := make([]*Lab, 0, len(byName))
+	for _, lab := range byName {
+		sort.Slice(lab.Containers, func(i, j int) bool {
+			return lab.Containers[i].Name < lab.Containers[j].Name
+		})
+
+		labs = append(labs, lab)
+	}
+
+	sort.Slice(labs, func(i, j int) bool { return labs[i].Name < labs[j].Name })
+
+	return labs
+}
+
+func (s *Server) listLabs(w http.ResponseWriter, r *http.Request, user string) {
+	var containers []runtime.GenericContainer
+
+	var err error
+
+	s.jobs.untrack(r.Context(), func() { containers, err = s.backend.Containers(r.Context(), "") })
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	owned := []*Lab{}
+
+	for _, lab := range labsFromContainers(containers) {
+		if lab.Owner == user {
+			owned = append(owned, lab)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, owned)
+}
+
+func (s *Server) inspectLab(w http.ResponseWriter, r *http.Request, user string) {
+	containers, err := s.ownedContainers(r.Context(), r.PathValue("lab"), user)
+	if err != nil {
+		writeLabError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, labsFromContainers(containers)[0])
+}
+
+// storeTopology stores the topology content of the deploy request in the topologies directory of the user
+// and points the request to the stored file.
+// The topologies defining the lifecycle hooks are rejected before they are stored,
+// so that the stored topologies of the deployed labs stay usable by the other operations.
+func (s *Server) storeTopology(req *DeployRequest, user string) error {
+	var t struct {
+		Name  string      `yaml:"name"`
+		Hooks interface{} `yaml:"hooks"`
+	}
+
+	if err := yaml.Unmarshal([]byte(req.Topology), &t); err != nil {
+		return fmt.Errorf("failed to read the topology: %w", err)
+	}
+
+	if t.Hooks != nil {
+		return clab.ErrHooksNotAllowed
+	}
+
+	name := req.Name
+	if name == "" {
+		name = t.Name
+	}
+
+	if !labNameRe.MatchString(name) {
+		return fmt.Errorf("invalid lab name %q", name)
+	}
+
+	dir := filepath.Join(s.topologiesDir, user)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	file := filepath.Join(dir, name+".clab.yml")
+	if err := os.WriteFile(file, []byte(req.Topology), 0600); err != nil {
+		return err
+	}
+
+	req.TopologyFile = file
+	req.Topology = ""
+
+	return nil
+}
+
+// userTopologyFile points the deploy request to the named topology file in the topologies directory of the user.
+// The paths leading out of the directory, including through the symlinks, are rejected.
+func (s *Server) userTopologyFile(req *DeployRequest, user string) error {
+	errNotFound := fmt.Errorf("topology file %q is not found in the topologies directory of the user",
+		req.TopologyFile)
+
+	if !filepath.IsLocal(req.TopologyFile) {
+		return errNotFound
+	}
+
+	dir, err := filepath.EvalSymlinks(filepath.Join(s.topologiesDir, user))
+	if err != nil {
+		return errNotFound
+	}
+
+	file, err := filepath.EvalSymlinks(filepath.Join(dir, req.TopologyFile))
+	if err != nil {
+		return errNotFound
+	}
+
+	if rel, err := filepath.Rel(dir, file); err != nil || !filepath.IsLocal(rel) {
+		return errNotFound
+	}
+
+	req.TopologyFile = file
+
+	return nil
+}
+
+func (s *Server) deployLab(w http.ResponseWriter, r *http.Request, user string) {
+	req := &DeployRequest{}
+	if err := decode(r, req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	switch {
+	case (req.TopologyFile == "") == (req.Topology == ""):
+		writeError(w, http.StatusBadRequest, errors.New("provide either the topology file or the topology content"))
+		return
+	case req.Topology != "":
+		if err := s.storeTopology(req, user); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	default:
+		if err := s.userTopologyFile(req, user); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	var lab string
+
+	var err error
+
+	s.jobs.untrack(r.Context(), func() { lab, err = s.backend.LabName(r.Context(), req) })
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := s.ownedContainers(r.Context(), lab, user); err != nil && !errors.Is(err, errLabNotFound) {
+		writeLabError(w, err)
+		return
+	}
+
+	j := s.jobs.add("deploy", lab, user, func(ctx context.Context) (interface{}, error) {
+		// the lab might have been deployed by another user while the job was queued
+		if _, err := s.ownedContainers(ctx, lab, user); err != nil && !errors.Is(err, errLabNotFound) {
+			return nil, err
+		}
+
+		containers, err := s.backend.Deploy(ctx, user, req)
+		if err != nil {
+			return nil, err
+		}
+
+		return labsFromContainers(containers), nil
+	})
+
+	writeJob(w, j)
+}
+
+// writeJob writes the queued job with the location of its status.
+func writeJob(w http.ResponseWriter, j *Job) {
+	w.Header().Set("Location", apiPrefix+"/jobs/"+j.ID)
+	writeJSON(w, http.StatusAccepted, j)
+}
+
+func (s *Server) destroyLab(w http.ResponseWriter, r *http.Request, user string) {
+	lab := r.PathValue("lab")
+
+	req := &DestroyRequest{}
+	if err := decode(r, req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	containers, err := s.ownedContainers(r.Context(), lab, user)
+	if err != nil {
+		writeLabError(w, err)
+		return
+	}
+
+	j := s.jobs.add("destroy", lab, user, func(ctx context.Context) (interface{}, error) {
+		return nil, s.backend.Destroy(ctx, containers, req)
+	})
+
+	writeJob(w, j)
+}
+
+func (s *Server) execLab(w http.ResponseWriter, r *http.Request, user string) {
+	lab := r.PathValue("lab")
+
+	req := &ExecRequest{}
+	if err := decode(r, req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if len(req.Commands) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("provide commands to execute"))
+		return
+	}
+
+	if _, err := s.ownedContainers(r.Context(), lab, user); err != nil {
+		writeLabError(w, err)
+		return
+	}
+
+	res, err := s.jobs.do(r.Context(), "exec", lab, user, func(ctx context.Context) (interface{}, error) {
+		// the lab might have been redeployed by another user while the job was queued
+		if _, err := s.ownedContainers(ctx, lab, user); err != nil {
+			return nil, err
+		}
+
+		return s.backend.Exec(ctx, lab, req)
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	out, err := res.(*exec.ExecCollection).Dump(exec.ExecFormatJSON)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintln(w, out)
+}
+
+func (s *Server) saveLab(w http.ResponseWriter, r *http.Request, user string) {
+	req := &SaveRequest{}
+	if err := decode(r, req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	lab := r.PathValue("lab")
+
+	if _, err := s.ownedContainers(r.Context(), lab, user); err != nil {
+		writeLabError(w, err)
+		return
+	}
+
+	_, err := s.jobs.do(r.Context(), "save", lab, user, func(ctx context.Context) (interface{}, error) {
+		containers, err := s.ownedContainers(ctx, lab, user)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, s.backend.Save(ctx, containers, req)
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// nodeContainer returns the name of the container of the lab node the user owns.
+func (s *Server) nodeContainer(ctx context.Context, lab, node, user string) (string, error) {
+	containers, err := s.ownedContainers(ctx, lab, user)
+	if err != nil {
+		return "", err
+	}
+
+	for _, c := range containers {
+		if c.Labels[labels.NodeName] == node && len(c.Names) > 0 {
+			return c.Names[0], nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: node %s of lab %s", errLabNotFound, node, lab)
+}
+
+func (s *Server) showNetem(w http.ResponseWriter, r *http.Request, user string) {
+	container, err := s.nodeContainer(r.Context(), r.PathValue("lab"), r.PathValue("node"), user)
+	if err != nil {
+		writeLabError(w, err)
+		return
+	}
+
+	var imps []*clab.NetemImpairment
+
+	s.jobs.untrack(r.Context(), func() { imps, err = s.backend.Impairments(r.Context(), container) })
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, imps)
+}
+
+// netemParams returns the impairments of the netem request.
+func netemParams(req *NetemRequest) (*clab.NetemParams, error) {
+	if req.Interface == "" {
+		return nil, errors.New("provide the interface to set the impairments on")
+	}
+
+	p := &clab.NetemParams{
+		Loss:       req.Loss,
+		Rate:       req.Rate,
+		Corruption: req.Corruption,
+	}
+
+	var err error
+
+	if req.Delay != "" {
+		if p.Delay, err = time.ParseDuration(req.Delay); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Jitter != "" {
+		if p.Jitter, err = time.ParseDuration(req.Jitter); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, p.Validate()
+}
+
+func (s *Server) setNetem(w http.ResponseWriter, r *http.Request, user string) {
+	req := &NetemRequest{}
+	if err := decode(r, req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	p, err := netemParams(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	container, err := s.nodeContainer(r.Context(), r.PathValue("lab"), r.PathValue("node"), user)
+	if err != nil {
+		writeLabError(w, err)
+		return
+	}
+
+	var imp *clab.NetemImpairment
+
+	s.jobs.untrack(r.Context(), func() { imp, err = s.backend.SetImpairments(r.Context(), container, req.Interface, p) })
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, imp)
+}
+
+func (s *Server) listJobs(w http.ResponseWriter, _ *http.Request, user string) {
+	writeJSON(w, http.StatusOK, s.jobs.list(user))
+}
+
+func (s *Server) getJob(w http.ResponseWriter, r *http.Request, user string) {
+	j, ok := s.jobs.get(r.PathValue("id"))
+	if !ok || j.Owner != user {
+		writeError(w, http.StatusNotFound, errors.New("job not found"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, j)
+}
+
+func (s *Server) jobLogs(w http.ResponseWriter, r *http.Request, user string) {
+	id := r.PathValue("id")
+
+	j, ok := s.jobs.get(id)
+	if !ok || j.Owner != user {
+		writeError(w, http.StatusNotFound, errors.New("job not found"))
+		return
+	}
+
+	logs, _ := s.jobs.logs(id)
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, logs)
+}
@@ -0,0 +1,319 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/clab/dependency_manager"
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// Backend performs the lab operations exposed over the API.
+type Backend interface {
+	// LabName returns the name the lab of the deploy request is deployed as.
+	LabName(ctx context.Context, req *DeployRequest) (string, error)
+	// Containers returns the containers of the lab, or of all labs when the lab name is empty.
+	Containers(ctx context.Context, lab string) ([]runtime.GenericContainer, error)
+	// Deploy deploys the lab with its nodes owned by the owner.
+	Deploy(ctx context.Context, owner string, req *DeployRequest) ([]runtime.GenericContainer, error)
+	// Destroy destroys the lab the containers belong to.
+	Destroy(ctx context.Context, containers []runtime.GenericContainer, req *DestroyRequest) error
+	// Exec executes the commands on the containers of the lab.
+	Exec(ctx context.Context, lab string, req *ExecRequest) (*exec.ExecCollection, error)
+	// Save saves the configuration of the nodes of the lab the containers belong to.
+	Save(ctx context.Context, containers []runtime.GenericContainer, req *SaveRequest) error
+	// Impairments returns the link impairments of the container interfaces.
+	Impairments(ctx context.Context, container string) ([]*clab.NetemImpairment, error)
+	// SetImpairments sets the link impairments on the container interface.
+	SetImpairments(ctx context.Context, container, iface string, p *clab.NetemParams) (*clab.NetemImpairment, error)
+}
+
+// ClabBackend is the Backend performing the lab operations with the clab.CLab methods.
+type ClabBackend struct {
+	runtime string
+	timeout time.Duration
+	debug   bool
+}
+
+// NewClabBackend returns the backend using the named container runtime.
+func NewClabBackend(rt string, timeout time.Duration, debug bool) *ClabBackend {
+	return &ClabBackend{
+		runtime: rt,
+		timeout: timeout,
+		debug:   debug,
+	}
+}
+
+// options returns the options common to all lab operations.
+func (b *ClabBackend) options(graceful bool) []clab.ClabOption {
+	return []clab.ClabOption{
+		clab.WithTimeout(b.timeout),
+		clab.WithRuntime(b.runtime,
+			&runtime.RuntimeConfig{
+				Debug:            b.debug,
+				Timeout:          b.timeout,
+				GracefulShutdown: graceful,
+			},
+		),
+		clab.WithDebug(b.debug),
+	}
+}
+
+// deployOptions returns the options of the lab of the deploy request.
+// The topology comes from the API user, so it is untrusted and confined to the directory of the topology file.
+func (b *ClabBackend) deployOptions(req *DeployRequest) []clab.ClabOption {
+	opts := append(b.options(false),
+		clab.WithUntrustedTopology(filepath.Dir(req.TopologyFile)),
+		clab.WithTopoPath(req.TopologyFile, ""),
+		clab.WithNodeFilter(req.NodeFilter),
+	)
+
+	if req.Name != "" {
+		opts = append(opts, clab.WithLabName(req.Name))
+	}
+
+	if req.Instance != "" {
+		opts = append(opts,
+			clab.WithInstance(req.Instance),
+			clab.WithAutoSubnet(clab.DefaultAutoSubnetIPv4Supernet, clab.DefaultAutoSubnetIPv6Supernet),
+		)
+	}
+
+	return opts
+}
+
+// LabName implements the Backend interface.
+func (b *ClabBackend) LabName(_ context.Context, req *DeployRequest) (string, error) {
+	c, err := clab.NewContainerLab(append(b.deployOptions(req), clab.WithSkippedBindsPathsCheck())...)
+	if err != nil {
+		return "", err
+	}
+
+	return c.Config.Name, nil
+}
+
+// Containers implements the Backend interface.
+func (b *ClabBackend) Containers(ctx context.Context, lab string) ([]runtime.GenericContainer, error) {
+	c, err := clab.NewContainerLab(b.options(false)...)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := []*types.GenericFilter{{
+		FilterType: "label",
+		Field:      labels.Containerlab,
+		Operator:   "exists",
+	}}
+
+	if lab != "" {
+		filter = []*types.GenericFilter{{
+			FilterType: "label",
+			Field:      labels.Containerlab,
+			Operator:   "=",
+			Match:      lab,
+		}}
+	}
+
+	return c.ListContainers(ctx, filter)
+}
+
+// Deploy implements the Backend interface.
+func (b *ClabBackend) Deploy(ctx context.Context, owner string, req *DeployRequest) ([]runtime.GenericContainer, error) {
+	opts := append(b.deployOptions(req),
+		clab.WithOwner(owner),
+		clab.WithDependencyManager(dependency_manager.NewDependencyManager()),
+	)
+
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	deploymentOptions, err := clab.NewDeployOptions(req.MaxWorkers)
+	if err != nil {
+		return nil, err
+	}
+
+	deploymentOptions.SetReconfigure(req.Reconfigure).
+		SetSkipPostDeploy(req.SkipPostDeploy)
+
+	return c.Deploy(ctx, deploymentOptions)
+}
+
+// labFromContainers returns the lab the containers belong to
+// with its topology file, instance and lab directory taken from the container labels.
+func (b *ClabBackend) labFromContainers(containers []runtime.GenericContainer, nodeFilter []string,
+	graceful bool,
+) (*clab.CLab, error) {
+	if len(containers) == 0 {
+		return nil, errors.New("no lab containers found")
+	}
+
+	lbls := containers[0].Labels
+
+	// the lab might have been deployed with a name overriding the topology name,
+	// the instance id is appended to the name by the instance option
+	name := strings.TrimSuffix(lbls[labels.Containerlab], "-"+lbls[labels.Instance])
+
+	opts := append(b.options(graceful),
+		clab.WithUntrustedTopology(filepath.Dir(lbls[labels.TopoFile])),
+		clab.WithTopoPath(lbls[labels.TopoFile], ""),
+		clab.WithLabName(name),
+		clab.WithInstance(lbls[labels.Instance]),
+		clab.WithNodeFilter(nodeFilter),
+		// the bind paths are irrelevant for the operations on a deployed lab
+		clab.WithSkippedBindsPathsCheck(),
+	)
+
+	// the management network of an instance is looked up to avoid creating it
+	// with the topology subnets, which are used by the other instances
+	if lbls[labels.Instance] != "" {
+		opts = append(opts, clab.WithAutoSubnet(clab.DefaultAutoSubnetIPv4Supernet,
+			clab.DefaultAutoSubnetIPv6Supernet))
+	}
+
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if labDir := filepath.Dir(lbls[labels.NodeLabDir]); lbls[labels.NodeLabDir] != "" {
+		if err := c.TopoPaths.SetLabDir(labDir); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, links.SetMgmtNetUnderlayingBridge(c.Config.Mgmt.Bridge)
+}
+
+// Destroy implements the Backend interface.
+func (b *ClabBackend) Destroy(ctx context.Context, containers []runtime.GenericContainer, req *DestroyRequest) error {
+	c, err := b.labFromContainers(containers, req.NodeFilter, req.Graceful)
+	if err != nil {
+		return err
+	}
+
+	// create management network or use existing one
+	// we call this to populate the c.Config.Mgmt.Bridge variable
+	// which is needed for the removal of the iptables rules
+	if err := c.CreateNetwork(ctx); err != nil {
+		return err
+	}
+
+	if err := c.ResolveLinks(); err != nil {
+		return err
+	}
+
+	if err := c.Destroy(ctx, req.MaxWorkers, req.KeepMgmtNet); err != nil {
+		return err
+	}
+
+	if req.Cleanup {
+		return os.RemoveAll(c.TopoPaths.TopologyLabDir())
+	}
+
+	return nil
+}
+
+// Exec implements the Backend interface.
+func (b *ClabBackend) Exec(ctx context.Context, lab string, req *ExecRequest) (*exec.ExecCollection, error) {
+	c, err := clab.NewContainerLab(b.options(false)...)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := types.FilterFromLabelStrings(
+		append([]string{fmt.Sprintf("%s=%s", labels.Containerlab, lab)}, req.Labels...))
+
+	return c.Exec(ctx, req.Commands, clab.NewExecOptions(filters))
+}
+
+// Save implements the Backend interface.
+func (b *ClabBackend) Save(ctx context.Context, containers []runtime.GenericContainer, req *SaveRequest) error {
+	c, err := b.labFromContainers(containers, req.NodeFilter, false)
+	if err != nil {
+		return err
+	}
+
+	var m sync.Mutex
+
+	var errs []error
+
+	var wg sync.WaitGroup
+
+	wg.Add(len(c.Nodes))
+
+	for _, node := range c.Nodes {
+		go func(node nodes.Node) {
+			defer wg.Done()
+
+			if err := node.SaveConfig(ctx); err != nil {
+				m.Lock()
+				errs = append(errs, fmt.Errorf("node %s: %w", node.Config().ShortName, err))
+				m.Unlock()
+			}
+		}(node)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// globalRuntime returns the initialized container runtime.
+func (b *ClabBackend) globalRuntime() (runtime.ContainerRuntime, error) {
+	_, rinit, err := clab.RuntimeInitializer(b.runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	rt := rinit()
+
+	err = rt.Init(
+		runtime.WithConfig(
+			&runtime.RuntimeConfig{
+				Timeout: b.timeout,
+			},
+		),
+	)
+
+	return rt, err
+}
+
+// Impairments implements the Backend interface.
+func (b *ClabBackend) Impairments(ctx context.Context, container string) ([]*clab.NetemImpairment, error) {
+	rt, err := b.globalRuntime()
+	if err != nil {
+		return nil, err
+	}
+
+	return clab.Impairments(ctx, rt, container)
+}
+
+// SetImpairments implements the Backend interface.
+func (b *ClabBackend) SetImpairments(ctx context.Context, container, iface string,
+	p *clab.NetemParams,
+) (*clab.NetemImpairment, error) {
+	rt, err := b.globalRuntime()
+	if err != nil {
+		return nil, err
+	}
+
+	return clab.SetImpairments(ctx, rt, container, iface, p)
+}
@@ -0,0 +1,334 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// JobStatus is the status of an asynchronous job.
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// maxFinishedJobs is the number of the finished jobs kept for their status and logs to be retrieved.
+const maxFinishedJobs = 100
+
+// Job is an asynchronous lab operation, such as deploy or destroy.
+type Job struct {
+	ID         string      `json:"id"`
+	Operation  string      `json:"operation"`
+	Lab        string      `json:"lab"`
+	Owner      string      `json:"owner"`
+	Status     JobStatus   `json:"status"`
+	Error      string      `json:"error,omitempty"`
+	Result     interface{} `json:"result,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+	StartedAt  *time.Time  `json:"started_at,omitempty"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
+
+	run func(ctx context.Context) (interface{}, error)
+	// log is the logger of the job, its entries carry the job id field.
+	log *log.Entry
+	// logs are the log messages emitted while the job runs.
+	logs strings.Builder
+	// done is closed when the job is finished.
+	done chan struct{}
+}
+
+// jobField is the log entry field carrying the id of the job the entry belongs to.
+const jobField = "job"
+
+// jobQueue runs the queued jobs one at a time.
+// The lab operations share the host state, such as the management networks and the iptables rules,
+// running them sequentially keeps them from racing and makes the log messages attributable to a job.
+// All lab operations, including exec and save, run as the queued jobs.
+//
+// The log entries carrying the job field are recorded in the logs of that job.
+// The lab operations log with the global logger, so the entries without the job field
+// are recorded in the logs of the running job, unless a backend call made outside of the queue
+// is in flight, in which case they can't be attributed and are not recorded.
+type jobQueue struct {
+	m     sync.Mutex
+	jobs  map[string]*Job
+	order []string
+	queue chan *Job
+	// running is the job being run.
+	running *Job
+	// untracked is the number of the backend calls made outside of the queue in flight.
+	untracked int
+}
+
+// jobCtxKey is the context key of the job being run.
+type jobCtxKey struct{}
+
+func newJobQueue() *jobQueue {
+	return &jobQueue{
+		jobs:  map[string]*Job{},
+		queue: make(chan *Job, 1024),
+	}
+}
+
+// add queues the operation on the lab as a new job and returns its copy.
+func (q *jobQueue) add(op, lab, owner string, run func(ctx context.Context) (interface{}, error)) *Job {
+	j := &Job{
+		ID:        uuid.NewString(),
+		Operation: op,
+		Lab:       lab,
+		Owner:     owner,
+		Status:    JobQueued,
+		CreatedAt: time.Now(),
+		run:       run,
+		done:      make(chan struct{}),
+	}
+
+	j.log = log.WithFields(log.Fields{
+		jobField: j.ID,
+		"lab":    lab,
+		"owner":  owner,
+	})
+
+	q.m.Lock()
+	q.jobs[j.ID] = j
+	q.order = append(q.order, j.ID)
+	q.prune()
+	snapshot := j.snapshot()
+	q.m.Unlock()
+
+	q.queue <- j
+
+	return snapshot
+}
+
+// do queues the operation on the lab as a new job and waits for it to finish.
+// The job keeps running when the context is canceled while waiting.
+func (q *jobQueue) do(ctx context.Context, op, lab, owner string,
+	run func(ctx context.Context) (interface{}, error),
+) (interface{}, error) {
+	id := q.add(op, lab, owner, run).ID
+
+	q.m.Lock()
+	j := q.jobs[id]
+	q.m.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-j.done:
+	}
+
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	if j.Status == JobFailed {
+		return j.Result, errors.New(j.Error)
+	}
+
+	return j.Result, nil
+}
+
+// untrack runs the backend call made outside of the queue,
+// the log entries without the job field are not recorded while it runs.
+// The calls made by the running job are tracked, they are recognized by the job in their context.
+func (q *jobQueue) untrack(ctx context.Context, f func()) {
+	if ctx.Value(jobCtxKey{}) != nil {
+		f()
+		return
+	}
+
+	q.m.Lock()
+	q.untracked++
+	q.m.Unlock()
+
+	defer func() {
+		q.m.Lock()
+		q.untracked--
+		q.m.Unlock()
+	}()
+
+	f()
+}
+
+// prune removes the oldest finished jobs above the maxFinishedJobs limit.
+func (q *jobQueue) prune() {
+	finished := 0
+	for _, id := range q.order {
+		if s := q.jobs[id].Status; s == JobSucceeded || s == JobFailed {
+			finished++
+		}
+	}
+
+	order := q.order[:0]
+
+	for _, id := range q.order {
+		j := q.jobs[id]
+		if finished > maxFinishedJobs && (j.Status == JobSucceeded || j.Status == JobFailed) {
+			delete(q.jobs, id)
+			finished--
+
+			continue
+		}
+
+		order = append(order, id)
+	}
+
+	q.order = order
+}
+
+// get returns a copy of the job with the given id.
+func (q *jobQueue) get(id string) (*Job, bool) {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	j, ok := q.jobs[id]
+	if !ok {
+		return nil, false
+	}
+
+	return j.snapshot(), true
+}
+
+// list returns the copies of the jobs of the owner in the order they were created.
+func (q *jobQueue) list(owner string) []*Job {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	jobs := []*Job{}
+
+	for _, id := range q.order {
+		if j := q.jobs[id]; j.Owner == owner {
+			jobs = append(jobs, j.snapshot())
+		}
+	}
+
+	return jobs
+}
+
+// logs returns the log messages of the job.
+func (q *jobQueue) logs(id string) (string, bool) {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	j, ok := q.jobs[id]
+	if !ok {
+		return "", false
+	}
+
+	return j.logs.String(), true
+}
+
+// snapshot returns a copy of the job fields exposed over the API,
+// must be called with the queue lock held.
+func (j *Job) snapshot() *Job {
+	return &Job{
+		ID:         j.ID,
+		Operation:  j.Operation,
+		Lab:        j.Lab,
+		Owner:      j.Owner,
+		Status:     j.Status,
+		Error:      j.Error,
+		Result:     j.Result,
+		CreatedAt:  j.CreatedAt,
+		StartedAt:  j.StartedAt,
+		FinishedAt: j.FinishedAt,
+	}
+}
+
+// process runs the queued jobs until the context is canceled.
+func (q *jobQueue) process(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case j := <-q.queue:
+			q.runJob(ctx, j)
+		}
+	}
+}
+
+func (q *jobQueue) runJob(ctx context.Context, j *Job) {
+	q.m.Lock()
+	now := time.Now()
+	j.Status = JobRunning
+	j.StartedAt = &now
+	q.running = j
+	q.m.Unlock()
+
+	j.log.Infof("Running %s job of lab %q owned by %s", j.Operation, j.Lab, j.Owner)
+
+	res, err := j.run(context.WithValue(ctx, jobCtxKey{}, j.ID))
+
+	if err != nil {
+		j.log.Errorf("%s job failed: %v", j.Operation, err)
+	}
+
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	now = time.Now()
+	j.FinishedAt = &now
+	j.Result = res
+	q.running = nil
+
+	j.Status = JobSucceeded
+	if err != nil {
+		j.Status = JobFailed
+		j.Error = err.Error()
+	}
+
+	close(j.done)
+}
+
+// Levels implements the logrus.Hook interface.
+func (*jobQueue) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire implements the logrus.Hook interface,
+// the log entries are recorded in the logs of the job they belong to.
+func (q *jobQueue) Fire(e *log.Entry) error {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	j := q.entryJob(e)
+	if j == nil {
+		return nil
+	}
+
+	line, err := e.String()
+	if err != nil {
+		return err
+	}
+
+	j.logs.WriteString(line)
+
+	return nil
+}
+
+// entryJob returns the job the log entry belongs to, or nil when the entry can't be attributed to a job,
+// must be called with the queue lock held.
+func (q *jobQueue) entryJob(e *log.Entry) *Job {
+	if id, ok := e.Data[jobField].(string); ok {
+		return q.jobs[id]
+	}
+
+	if q.untracked > 0 {
+		return nil
+	}
+
+	return q.running
+}
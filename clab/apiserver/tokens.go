@@ -0,0 +1,51 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package apiserver
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v2"
+)
+
+// minTokenLength is the minimal length of the API tokens.
+const minTokenLength = 16
+
+// LoadTokens reads the tokens file mapping the user names to their API tokens, e.g.
+//
+//	alice: 3c1d5e0a8e2b4f7d9a6c
+//	bob: 0f9e8d7c6b5a49382716
+func LoadTokens(file string) (map[string]string, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := map[string]string{}
+	if err := yaml.UnmarshalStrict(b, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to parse tokens file %s: %w", file, err)
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no tokens found in %s", file)
+	}
+
+	seen := map[string]string{}
+
+	for user, token := range tokens {
+		if len(token) < minTokenLength {
+			return nil, fmt.Errorf("token of user %q is shorter than %d characters", user, minTokenLength)
+		}
+
+		if other, ok := seen[token]; ok {
+			return nil, fmt.Errorf("users %q and %q share the same token", other, user)
+		}
+
+		seen[token] = user
+	}
+
+	return tokens, nil
+}
@@ -1,11 +1,24 @@
 package clab
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/srl-labs/containerlab/cert"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
 )
 
+// caCertName is the name the CA certificate is listed with among the node certificates.
+const caCertName = "ca"
+
 // LoadOrGenerateCA loads the CA certificate from the storage, or generates a new one if it does not exist.
 func (c *CLab) LoadOrGenerateCA(caCertInput *cert.CACSRInput) error {
 	// try loading the CA cert, and if it fails, generate a new one
@@ -19,15 +32,328 @@ func (c *CLab) LoadOrGenerateCA(caCertInput *cert.CACSRInput) error {
 		// store the root CA
 		err = c.Cert.StoreCaCert(caCertificate)
 		if err != nil {
-			return nil
+			return fmt.Errorf("failed storing the Root CA: %w", err)
 		}
 	}
 
 	// set CA cert that was either loaded or generated
 	err = c.Cert.SetCACert(caCertificate)
 	if err != nil {
-		return nil
+		return fmt.Errorf("failed setting the Root CA: %w", err)
 	}
 
 	return nil
 }
+
+// LoadCA loads the lab CA certificate from the storage without generating a new one.
+// It is used by the tools operating on the certificates of a deployed lab.
+func (c *CLab) LoadCA() error {
+	if _, err := c.initCertificateAuthority(); err != nil {
+		return err
+	}
+
+	caCertificate, err := c.Cert.LoadCaCert()
+	if err != nil {
+		return fmt.Errorf("failed loading the lab CA certificate, make sure the lab is deployed: %w", err)
+	}
+
+	return c.Cert.SetCACert(caCertificate)
+}
+
+// CertificateInfos returns the summaries of the lab CA certificate
+// and of the node certificates found in the certificate storage.
+// LoadCA must be called first.
+func (c *CLab) CertificateInfos(nodeNames []string) ([]*cert.Info, error) {
+	var infos []*cert.Info
+
+	// the CA is listed only when all nodes are requested
+	if len(nodeNames) == 0 {
+		caCert, err := c.Cert.LoadCaCert()
+		if err != nil {
+			return nil, err
+		}
+
+		info, err := caCert.Info(caCertName)
+		if err != nil {
+			return nil, err
+		}
+
+		infos = append(infos, info)
+	}
+
+	names, err := c.certNodeNames(nodeNames)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		nodeCert, err := c.Cert.LoadNodeCert(name)
+		if err != nil {
+			// the node has no certificate
+			continue
+		}
+
+		info, err := nodeCert.Info(name)
+		if err != nil {
+			return nil, fmt.Errorf("node %q: %w", name, err)
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// CertRenewOptions are the options of the node certificates renewal.
+type CertRenewOptions struct {
+	// Nodes limits the renewal to the given nodes, all nodes by default.
+	Nodes []string
+	// RenewBefore renews the certificates expiring within the given duration.
+	RenewBefore time.Duration
+	// Force renews the certificates regardless of their expiry and addresses.
+	Force bool
+	// RenewCA renews the lab CA certificate regardless of its expiry.
+	RenewCA bool
+}
+
+// CertRenewal describes a renewed certificate.
+type CertRenewal struct {
+	Node   string
+	Reason string
+	Info   *cert.Info
+	// Reloaded is true when the running node applied the renewed certificate.
+	Reloaded bool
+}
+
+// RenewCertificates re-issues the certificates of the nodes that issue certificates
+// when the certificates are missing, expire within the given duration, or do not list
+// the current management addresses of the running nodes in their SANs.
+// The lab CA is renewed when it expires within the given duration or when requested,
+// in which case the certificates of all nodes are re-issued with the new CA.
+// The renewed certificates are applied to the running nodes that support it.
+// LoadCA must be called first.
+func (c *CLab) RenewCertificates(ctx context.Context, opts *CertRenewOptions) ([]*CertRenewal, error) {
+	names, err := c.certNodeNames(opts.Nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	var renewals []*CertRenewal
+
+	caRenewal, err := c.renewCA(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if caRenewal != nil {
+		renewals = append(renewals, caRenewal)
+		// the certificates signed by the old CA are not trusted anymore
+		names, _ = c.certNodeNames(nil)
+	}
+
+	containers, err := c.ListNodesContainersIgnoreNotFound(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// management addresses of the running nodes
+	mgmtIPs := map[string]runtime.GenericMgmtIPs{}
+	for _, cnt := range containers {
+		mgmtIPs[cnt.Labels[labels.NodeName]] = cnt.NetworkSettings
+	}
+
+	for _, name := range names {
+		cfg := *c.Nodes[name].Config()
+		if cfg.Certificate == nil || cfg.Certificate.Issue == nil || !*cfg.Certificate.Issue {
+			continue
+		}
+
+		if ips, ok := mgmtIPs[name]; ok {
+			cfg.MgmtIPv4Address = ips.IPv4addr
+			cfg.MgmtIPv6Address = ips.IPv6addr
+		}
+
+		reason := "lab CA is renewed"
+		if caRenewal == nil {
+			reason, err = c.certRenewReason(&cfg, opts)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if reason == "" {
+			log.Debugf("certificate of node %q does not need renewal", name)
+			continue
+		}
+
+		log.Infof("Renewing certificate of node %q: %s", name, reason)
+
+		nodeCert, err := c.Cert.GenerateAndSignNodeCert(nodes.NodeCSRInput(&cfg, c.Config.Name))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.Cert.StoreNodeCert(name, nodeCert); err != nil {
+			return nil, err
+		}
+
+		info, err := nodeCert.Info(name)
+		if err != nil {
+			return nil, err
+		}
+
+		renewals = append(renewals, &CertRenewal{Node: name, Reason: reason, Info: info})
+	}
+
+	c.reloadCertificates(ctx, renewals, caRenewal != nil, mgmtIPs)
+
+	return renewals, nil
+}
+
+// renewCA re-issues the lab CA certificate when it expires within the renewal duration
+// or when the renewal is requested. It returns nil if the CA is not renewed.
+func (c *CLab) renewCA(opts *CertRenewOptions) (*CertRenewal, error) {
+	caCert, err := c.Cert.LoadCaCert()
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := caCert.Info(caCertName)
+	if err != nil {
+		return nil, err
+	}
+
+	var reason string
+
+	switch {
+	case opts.RenewCA:
+		reason = "renewal is requested"
+	case info.ExpiresWithin(opts.RenewBefore):
+		reason = fmt.Sprintf("certificate expires at %s", info.NotAfter.Format(time.RFC3339))
+	default:
+		return nil, nil
+	}
+
+	if c.TopoPaths.HasExternalCA() {
+		if opts.RenewCA {
+			return nil, fmt.Errorf("the external lab CA can not be renewed, replace the external CA files %s and %s",
+				c.TopoPaths.CaCertAbsFilename(), c.TopoPaths.CaKeyAbsFilename())
+		}
+
+		log.Warnf("The external lab CA %s: %s, replace it", c.TopoPaths.CaCertAbsFilename(), reason)
+
+		return nil, nil
+	}
+
+	log.Infof("Renewing the lab CA certificate: %s", reason)
+
+	caCertInput, err := c.initCertificateAuthority()
+	if err != nil {
+		return nil, err
+	}
+
+	caCert, err = c.Cert.GenerateCACert(caCertInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed generating new Root CA %v", err)
+	}
+
+	if err := c.Cert.StoreCaCert(caCert); err != nil {
+		return nil, err
+	}
+
+	if err := c.Cert.SetCACert(caCert); err != nil {
+		return nil, err
+	}
+
+	info, err = caCert.Info(caCertName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CertRenewal{Node: caCertName, Reason: reason, Info: info}, nil
+}
+
+// reloadCertificates applies the renewed certificates to the running nodes.
+// When the CA is renewed, all running nodes are asked to reload, so that the nodes
+// installing the lab CA into their trust store pick up the new CA.
+func (c *CLab) reloadCertificates(ctx context.Context, renewals []*CertRenewal, caRenewed bool,
+	running map[string]runtime.GenericMgmtIPs,
+) {
+	renewed := map[string]*CertRenewal{}
+	for _, r := range renewals {
+		renewed[r.Node] = r
+	}
+
+	names, _ := c.certNodeNames(nil)
+
+	for _, name := range names {
+		r := renewed[name]
+		if _, ok := running[name]; !ok || (r == nil && !caRenewed) {
+			continue
+		}
+
+		err := c.Nodes[name].ReloadCertificates(ctx, c.Cert, c.Config.Name)
+		switch {
+		case err == nil:
+			if r != nil {
+				r.Reloaded = true
+				log.Infof("Node %q reloaded the renewed certificate", name)
+			}
+		case errors.Is(err, nodes.ErrCertReloadNotSupported):
+			if r != nil {
+				log.Warnf("Node %q can not reload the certificate at runtime, redeploy it to use the renewed certificate", name)
+			}
+		default:
+			log.Errorf("Node %q failed to reload the renewed certificates: %v", name, err)
+		}
+	}
+}
+
+// certRenewReason returns the reason to renew the node certificate,
+// or an empty string if the certificate does not need to be renewed.
+func (c *CLab) certRenewReason(cfg *types.NodeConfig, opts *CertRenewOptions) (string, error) {
+	nodeCert, err := c.Cert.LoadNodeCert(cfg.ShortName)
+	if err != nil {
+		return "certificate is missing", nil
+	}
+
+	if opts.Force {
+		return "renewal is forced", nil
+	}
+
+	info, err := nodeCert.Info(cfg.ShortName)
+	if err != nil {
+		return "", fmt.Errorf("node %q: %w", cfg.ShortName, err)
+	}
+
+	if info.ExpiresWithin(opts.RenewBefore) {
+		return fmt.Sprintf("certificate expires at %s", info.NotAfter.Format(time.RFC3339)), nil
+	}
+
+	if missing := info.MissingIPs(cfg.MgmtIPv4Address, cfg.MgmtIPv6Address); len(missing) != 0 {
+		return fmt.Sprintf("management addresses %s are not in the certificate", strings.Join(missing, ", ")), nil
+	}
+
+	return "", nil
+}
+
+// certNodeNames returns the sorted names of the given nodes, or of all lab nodes if none are given.
+func (c *CLab) certNodeNames(nodeNames []string) ([]string, error) {
+	if len(nodeNames) == 0 {
+		names := make([]string, 0, len(c.Nodes))
+		for name := range c.Nodes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		return names, nil
+	}
+
+	for _, name := range nodeNames {
+		if _, ok := c.Nodes[name]; !ok {
+			return nil, fmt.Errorf("node %q is not found in the topology", name)
+		}
+	}
+
+	return nodeNames, nil
+}
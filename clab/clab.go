@@ -20,8 +20,10 @@ import (
 	depMgr "github.com/srl-labs/containerlab/clab/dependency_manager"
 	"github.com/srl-labs/containerlab/clab/exec"
 	errs "github.com/srl-labs/containerlab/errors"
+	"github.com/srl-labs/containerlab/labels"
 	"github.com/srl-labs/containerlab/links"
 	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/nodes/netns"
 	"github.com/srl-labs/containerlab/runtime"
 	_ "github.com/srl-labs/containerlab/runtime/all"
 	"github.com/srl-labs/containerlab/runtime/docker"
@@ -34,6 +36,9 @@ import (
 
 var ErrNodeNotFound = errors.New("node not found")
 
+// ErrHooksNotAllowed is returned when the topology defines the hooks while they are disallowed.
+var ErrHooksNotAllowed = errors.New("lifecycle hooks are not allowed in this topology")
+
 type CLab struct {
 	Config    *Config `json:"config,omitempty"`
 	TopoPaths *types.TopoPaths
@@ -48,6 +53,8 @@ type CLab struct {
 	// and ~/.ssh/*.pub files.
 	// The keys are used to enable key-based SSH access for the nodes.
 	SSHPubKeys []ssh.PublicKey
+	// IPAM holds the addresses allocated from the topology IPAM pools.
+	IPAM *types.IPAMAllocations `json:"ipam,omitempty"`
 
 	dependencyManager depMgr.DependencyManager
 	m                 *sync.RWMutex
@@ -59,6 +66,22 @@ type CLab struct {
 	// checkBindsPaths toggle enables or disables binds paths checks
 	// when set to true, bind sources are verified to exist on the host.
 	checkBindsPaths bool
+	// disallowHooks rejects the topologies defining the lifecycle hooks.
+	disallowHooks bool
+	// untrustedDir is the directory of the untrusted user files,
+	// set when the topology comes from an untrusted user.
+	untrustedDir string
+	// instance is the id of the topology instance the lab is deployed as.
+	instance string
+	// owner is the user the lab nodes are labeled as owned by.
+	owner string
+	// topoNodeNames is the sorted list of the topology node names
+	// captured before the node filter is applied.
+	topoNodeNames []string
+	// mgmtPortsOffset is the offset of the published management ports from the start of the port range.
+	mgmtPortsOffset int
+	// webhooks notifies the webhooks of the lab lifecycle events.
+	webhooks *webhookNotifier
 }
 
 type ClabOption func(c *CLab) error
@@ -82,6 +105,15 @@ func WithLabName(n string) ClabOption {
 	}
 }
 
+// WithOwner sets the owner of the lab nodes,
+// by default the owner is the user running containerlab.
+func WithOwner(owner string) ClabOption {
+	return func(c *CLab) error {
+		c.owner = owner
+		return nil
+	}
+}
+
 // WithSkippedBindsPathsCheck skips the binds paths checks.
 func WithSkippedBindsPathsCheck() ClabOption {
 	return func(c *CLab) error {
@@ -90,6 +122,16 @@ func WithSkippedBindsPathsCheck() ClabOption {
 	}
 }
 
+// WithHooksDisallowed rejects the topologies defining the lifecycle hooks,
+// since the hooks run as host commands with the privileges of containerlab,
+// the topologies of the untrusted users must not be allowed to define them.
+func WithHooksDisallowed() ClabOption {
+	return func(c *CLab) error {
+		c.disallowHooks = true
+		return nil
+	}
+}
+
 // WithManagementNetworkName sets the name of the
 // management network that is to be used.
 func WithManagementNetworkName(n string) ClabOption {
@@ -326,6 +368,8 @@ func (c *CLab) filterClabNodes(nodeFilter []string) error {
 
 	log.Infof("Applying node filter: %q", nodeFilter)
 
+	c.topoNodeNames = c.topologyNodeNames()
+
 	// filter nodes
 	for name := range c.Config.Topology.Nodes {
 		if exists := slices.Contains(nodeFilter, name); !exists {
@@ -941,8 +985,8 @@ func (c *CLab) extractDNSServers(filesys fs.FS) error {
 		return err
 	}
 
-	// no DNS Servers found, return
-	if len(DNSServers) == 0 {
+	// no DNS Servers found or nodes use the containerlab DNS server, return
+	if len(DNSServers) == 0 || c.Config.Mgmt.DNSServer {
 		return nil
 	}
 
@@ -970,8 +1014,25 @@ func (c *CLab) extractDNSServers(filesys fs.FS) error {
 }
 
 // Deploy the given topology.
-// skipcq: GO-R1005
+// The webhooks are notified of the deployment start and result.
 func (c *CLab) Deploy(ctx context.Context, options *DeployOptions) ([]runtime.GenericContainer, error) {
+	c.notifyWebhooks(types.WebhookDeployStarted, "", nil)
+
+	containers, err := c.deploy(ctx, options)
+	if err != nil {
+		c.notifyWebhooks(types.WebhookDeployFailed, "", err)
+	} else {
+		c.notifyWebhooks(types.WebhookDeployFinished, "", nil)
+	}
+
+	c.webhooks.wait()
+
+	return containers, err
+}
+
+// deploy the given topology.
+// skipcq: GO-R1005
+func (c *CLab) deploy(ctx context.Context, options *DeployOptions) ([]runtime.GenericContainer, error) {
 	var err error
 
 	err = c.ResolveLinks()
@@ -988,11 +1049,22 @@ func (c *CLab) Deploy(ctx context.Context, options *DeployOptions) ([]runtime.Ge
 		}
 	}
 
+	if err = c.runHooks(ctx, types.HookPreDeploy); err != nil {
+		return nil, err
+	}
+
 	// create management network or use existing one
 	if err = c.CreateNetwork(ctx); err != nil {
 		return nil, err
 	}
 
+	// start the DNS server for the lab node names on the management network
+	if c.Config.Mgmt.DNSServer {
+		if err = c.setupDNSServer(); err != nil {
+			return nil, err
+		}
+	}
+
 	err = links.SetMgmtNetUnderlayingBridge(c.Config.Mgmt.Bridge)
 	if err != nil {
 		return nil, err
@@ -1002,6 +1074,10 @@ func (c *CLab) Deploy(ctx context.Context, options *DeployOptions) ([]runtime.Ge
 		return nil, err
 	}
 
+	if err = c.relocateMgmtPorts(ctx); err != nil {
+		return nil, err
+	}
+
 	if err = c.loadKernelModules(); err != nil {
 		return nil, err
 	}
@@ -1020,11 +1096,11 @@ func (c *CLab) Deploy(ctx context.Context, options *DeployOptions) ([]runtime.Ge
 
 	// create an empty ansible inventory file that will get populated later
 	// we create it here first, so that bind mounts of ansible-inventory.yml file could work
-	ansibleInvFPath := c.TopoPaths.AnsibleInventoryFileAbsPath()
-	_, err = os.Create(ansibleInvFPath)
+	ansibleInvF, err := createInventoryFile(c.TopoPaths.AnsibleInventoryFileAbsPath())
 	if err != nil {
 		return nil, err
 	}
+	ansibleInvF.Close()
 
 	// in an similar fashion, create an empty topology data file
 	topoDataFPath := c.TopoPaths.TopoExportFile()
@@ -1087,6 +1163,19 @@ func (c *CLab) Deploy(ctx context.Context, options *DeployOptions) ([]runtime.Ge
 		nodesWg.Wait()
 	}
 
+	// the node health is watched while the rest of the deployment completes,
+	// the watch is canceled if the deployment fails
+	healthCtx, cancelHealth := context.WithCancelCause(ctx)
+	healthWatched := make(chan struct{})
+	go func() {
+		c.watchNodesHealth(healthCtx)
+		close(healthWatched)
+	}()
+	defer func() {
+		cancelHealth(nil)
+		<-healthWatched
+	}()
+
 	// write to log
 	execCollection.Log()
 
@@ -1110,23 +1199,49 @@ func (c *CLab) Deploy(ctx context.Context, options *DeployOptions) ([]runtime.Ge
 		return nil, err
 	}
 
-	log.Info("Adding containerlab host entries to /etc/hosts file")
-	err = c.appendHostsFileEntries(ctx)
-	if err != nil {
-		log.Errorf("failed to create hosts file: %v", err)
+	// the lab node names are resolved by the DNS server instead of the hosts file
+	if !c.Config.Mgmt.DNSServer {
+		log.Info("Adding containerlab host entries to /etc/hosts file")
+		err = c.appendHostsFileEntries(ctx)
+		if err != nil {
+			log.Errorf("failed to create hosts file: %v", err)
+		}
+	}
+
+	var pinned map[string]bool
+	if options.hostKeyScanTimeout > 0 {
+		log.Info("Pinning SSH host keys of containerlab nodes")
+		pinned = c.pinHostKeys(ctx, options.hostKeyScanTimeout)
 	}
 
 	log.Info("Adding ssh config for containerlab nodes")
-	err = c.addSSHConfig()
+	err = c.addSSHConfig(pinned)
 	if err != nil {
 		log.Errorf("failed to create ssh config file: %v", err)
 	}
 
+	c.waitNodesHealth(healthWatched, cancelHealth)
+
+	if err = c.runHooks(ctx, types.HookPostDeploy); err != nil {
+		return nil, err
+	}
+
 	return containers, nil
 }
 
 // certificateAuthoritySetup sets up the certificate authority parameters.
 func (c *CLab) certificateAuthoritySetup() error {
+	caCertInput, err := c.initCertificateAuthority()
+	if err != nil {
+		return err
+	}
+
+	return c.LoadOrGenerateCA(caCertInput)
+}
+
+// initCertificateAuthority initializes the certificate storage and the CA,
+// and returns the attributes used to generate the CA certificate.
+func (c *CLab) initCertificateAuthority() (*cert.CACSRInput, error) {
 	// init the Cert storage and CA
 	c.Cert.CertStorage = cert.NewLocalDirCertStorage(c.TopoPaths)
 	c.Cert.CA = cert.NewCA()
@@ -1134,7 +1249,8 @@ func (c *CLab) certificateAuthoritySetup() error {
 	s := c.Config.Settings
 
 	// Set defaults for the CA parameters
-	keySize := 2048
+	keySize := cert.DefaultRSAKeySize
+	keyAlgorithm := cert.DefaultKeyAlgorithm
 	validityDuration := time.Until(time.Now().AddDate(1, 0, 0)) // 1 year as default
 
 	// check that Settings.CertificateAuthority exists.
@@ -1149,6 +1265,10 @@ func (c *CLab) certificateAuthoritySetup() error {
 			keySize = s.CertificateAuthority.KeySize
 		}
 
+		if s.CertificateAuthority.KeyAlgorithm != "" {
+			keyAlgorithm = s.CertificateAuthority.KeyAlgorithm
+		}
+
 		// if external CA cert and and key are set, propagate to topopaths
 		extCACert := s.CertificateAuthority.Cert
 		extCAKey := s.CertificateAuthority.Key
@@ -1165,7 +1285,7 @@ func (c *CLab) certificateAuthoritySetup() error {
 		if extCACert != "" && extCAKey != "" {
 			err := c.TopoPaths.SetExternalCaFiles(extCACert, extCAKey)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
@@ -1177,13 +1297,15 @@ func (c *CLab) certificateAuthoritySetup() error {
 		Expiry:       validityDuration,
 		Organization: "containerlab",
 		KeySize:      keySize,
+		KeyAlgorithm: keyAlgorithm,
 	}
 
-	return c.LoadOrGenerateCA(caCertInput)
+	return caCertInput, nil
 }
 
 // Destroy the given topology.
-func (c *CLab) Destroy(ctx context.Context, maxWorkers uint, keepMgmtNet bool) error {
+// The webhooks are notified of the destruction start and result when the lab has containers to remove.
+func (c *CLab) Destroy(ctx context.Context, maxWorkers uint, keepMgmtNet bool) (err error) {
 	containers, err := c.ListNodesContainersIgnoreNotFound(ctx)
 	if err != nil {
 		return err
@@ -1193,6 +1315,22 @@ func (c *CLab) Destroy(ctx context.Context, maxWorkers uint, keepMgmtNet bool) e
 		return nil
 	}
 
+	c.notifyWebhooks(types.WebhookDestroyStarted, "", nil)
+
+	defer func() {
+		if err != nil {
+			c.notifyWebhooks(types.WebhookDestroyFailed, "", err)
+		} else {
+			c.notifyWebhooks(types.WebhookDestroyFinished, "", nil)
+		}
+
+		c.webhooks.wait()
+	}()
+
+	if err = c.runHooks(ctx, types.HookPreDestroy); err != nil {
+		return err
+	}
+
 	if maxWorkers == 0 {
 		maxWorkers = uint(len(c.Nodes))
 	}
@@ -1235,6 +1373,11 @@ func (c *CLab) Destroy(ctx context.Context, maxWorkers uint, keepMgmtNet bool) e
 		}
 	}
 
+	// stop the DNS server once the last lab using the management network is gone
+	if err = c.stopDNSServer(ctx); err != nil {
+		log.Errorf("failed to stop the DNS server: %v", err)
+	}
+
 	// delete lab management network
 	if c.Config.Mgmt.Network != "bridge" && !keepMgmtNet {
 		log.Debugf("Calling DeleteNet method. *CLab.Config.Mgmt value is: %+v", c.Config.Mgmt)
@@ -1245,10 +1388,20 @@ func (c *CLab) Destroy(ctx context.Context, maxWorkers uint, keepMgmtNet bool) e
 			}
 		}
 	}
-	return nil
+
+	// delete extra networks
+	if !keepMgmtNet {
+		if err = c.globalRuntime().DeleteExtraNets(ctx, c.extraNetworks()); err != nil {
+			log.Errorf("failed to delete extra networks: %v", err)
+		}
+	}
+
+	return c.runHooks(ctx, types.HookPostDestroy)
 }
 
 // Exec execute commands on running topology nodes.
+// The cmds are executed on all the nodes matching the filters, and the commands of the exec spec
+// on the matching nodes selected by the spec targets. The nodes are handled concurrently.
 func (c *CLab) Exec(ctx context.Context, cmds []string, options *ExecOptions) (*exec.ExecCollection, error) {
 	err := links.SetMgmtNetUnderlayingBridge(c.Config.Mgmt.Bridge)
 	if err != nil {
@@ -1260,40 +1413,102 @@ func (c *CLab) Exec(ctx context.Context, cmds []string, options *ExecOptions) (*
 		return nil, err
 	}
 
+	// netns nodes are not managed by the container runtime
+	// and are selected from the topology nodes
+	netnsNodes := c.filterNetnsNodes(ctx, options.filters)
+
 	// make sure filter returned containers
-	if len(cnts) == 0 {
+	if len(cnts) == 0 && len(netnsNodes) == 0 {
 		return nil, fmt.Errorf("filter did not match any containers")
 	}
 
-	// prepare the exec collection and the exec command
-	resultCollection := exec.NewExecCollection()
-
 	// build execs from the string input
-	var execCmds []*exec.ExecCmd
+	var execCmds []*exec.SpecCmd
 	for _, execCmdStr := range cmds {
-		execCmd, err := exec.NewExecCmdFromString(execCmdStr)
+		execCmd, err := exec.NewSpecCmd(execCmdStr, exec.CmdOptions{
+			Timeout: options.cmdTimeout,
+			Retries: &options.retries,
+		})
 		if err != nil {
 			return nil, err
 		}
 		execCmds = append(execCmds, execCmd)
 	}
 
-	// run the exec commands on all the containers matching the filter
-	for _, cnt := range cnts {
-		// iterate over the commands
-		for _, execCmd := range execCmds {
-			// execute the commands
-			execResult, err := cnt.RunExec(ctx, execCmd)
-			if err != nil {
-				// skip nodes that do not support exec
-				if err == exec.ErrRunExecNotSupported {
-					continue
-				}
-			}
+	var targets []*execTarget
+
+	for i := range cnts {
+		t := containerExecTarget(&cnts[i])
+
+		// the nodes of the topology may execute the commands other than in their container, e.g. over SSH
+		if n, ok := c.Nodes[t.node]; ok && cnts[i].Labels[labels.Containerlab] == c.Config.Name {
+			t.run = n.RunExec
+		}
 
-			resultCollection.Add(cnt.Names[0], execResult)
+		targets = append(targets, t)
+	}
+
+	for _, n := range netnsNodes {
+		targets = append(targets, nodeExecTarget(n))
+	}
+
+	for _, t := range targets {
+		t.cmds = execCmds
+		if options.spec != nil {
+			t.cmds = append(slices.Clone(execCmds), options.spec.Commands(t.labels)...)
+		}
+	}
+
+	return c.runExecTargets(ctx, targets, options)
+}
+
+// filterNetnsNodes returns the netns nodes of the topology
+// whose labels match all the provided label filters.
+func (c *CLab) filterNetnsNodes(ctx context.Context, filters []*types.GenericFilter) []nodes.Node {
+	var result []nodes.Node
+
+	for _, n := range c.Nodes {
+		if !netns.IsNetnsNode(n.Config()) {
+			continue
+		}
+
+		if n.GetContainerStatus(ctx) != runtime.Running {
+			continue
+		}
+
+		if labelsMatchFilters(n.Config().Labels, filters) {
+			result = append(result, n)
+		}
+	}
+
+	return result
+}
+
+// labelsMatchFilters returns true if the labels satisfy all the label filters.
+// Filters of a type other than label are ignored.
+func labelsMatchFilters(lbls map[string]string, filters []*types.GenericFilter) bool {
+	for _, f := range filters {
+		if f.FilterType != "label" {
+			continue
+		}
+
+		v, ok := lbls[f.Field]
+
+		switch f.Operator {
+		case "exists":
+			if !ok {
+				return false
+			}
+		case "!=":
+			if ok && v == f.Match {
+				return false
+			}
+		default:
+			if !ok || v != f.Match {
+				return false
+			}
 		}
 	}
 
-	return resultCollection, nil
+	return true
 }
@@ -398,3 +398,49 @@ func TestWithTopo(t *testing.T) {
 		})
 	}
 }
+
+func Test_labelsMatchFilters(t *testing.T) {
+	lbls := map[string]string{
+		"containerlab": "lab1",
+		"role":         "client",
+	}
+
+	tests := map[string]struct {
+		filters []string
+		want    bool
+	}{
+		"no filters": {
+			filters: nil,
+			want:    true,
+		},
+		"matching label value": {
+			filters: []string{"containerlab=lab1"},
+			want:    true,
+		},
+		"non matching label value": {
+			filters: []string{"containerlab=lab2"},
+			want:    false,
+		},
+		"existing label": {
+			filters: []string{"role"},
+			want:    true,
+		},
+		"missing label": {
+			filters: []string{"site"},
+			want:    false,
+		},
+		"one of multiple filters doesn't match": {
+			filters: []string{"containerlab=lab1", "role=server"},
+			want:    false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := labelsMatchFilters(lbls, types.FilterFromLabelStrings(tt.filters))
+			if got != tt.want {
+				t.Errorf("labelsMatchFilters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
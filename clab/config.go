@@ -44,6 +44,8 @@ type Config struct {
 	Mgmt     *types.MgmtNet  `json:"mgmt,omitempty"`
 	Settings *types.Settings `json:"settings,omitempty"`
 	Topology *types.Topology `json:"topology,omitempty"`
+	// Hooks are the host commands run at the lab lifecycle events.
+	Hooks *types.Hooks `json:"hooks,omitempty"`
 	// the debug flag value as passed via cli
 	// may be used by other packages to enable debug logging
 	Debug bool `json:"debug"`
@@ -63,6 +65,28 @@ func (c *CLab) parseTopology() error {
 		*c.Config.Prefix = defaultPrefix
 	}
 
+	c.initExtraNetworks()
+
+	if c.disallowHooks && !c.Config.Hooks.IsEmpty() {
+		return ErrHooksNotAllowed
+	}
+
+	if err := c.checkUntrustedTopology(); err != nil {
+		return err
+	}
+
+	if c.Config.Hooks != nil {
+		if err := c.Config.Hooks.InitDefaults(); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Config.Settings.InitWebhooks(); err != nil {
+		return err
+	}
+
+	c.webhooks = newWebhookNotifier(c.Config.Settings.GetWebhooks())
+
 	// initialize Nodes and Links variable
 	c.Nodes = make(map[string]nodes.Node)
 	c.Links = make(map[int]links.Link)
@@ -123,7 +147,7 @@ func (c *CLab) parseTopology() error {
 		}
 	}
 
-	return nil
+	return c.allocateIPAM()
 }
 
 // NewNode initializes a new node object.
@@ -146,6 +170,16 @@ func (c *CLab) NewNode(nodeName, nodeRuntime string, nodeDef *types.NodeDefiniti
 		return fmt.Errorf("failed to initialize node %q: %v", nodeCfg.ShortName, err)
 	}
 
+	if err := c.publishMgmtPorts(n); err != nil {
+		return fmt.Errorf("failed to publish management ports of node %q: %v", nodeCfg.ShortName, err)
+	}
+
+	// the static routes inherited from the defaults or the kind apply only to the nodes
+	// with the kernel dataplane, the routes set on a node of another dataplane are rejected by the topology checks
+	if !n.Config().KernelDataplane && len(nodeDef.GetStaticRoutes()) == 0 {
+		n.Config().StaticRoutes = nil
+	}
+
 	c.Nodes[nodeName] = n
 
 	c.addDefaultLabels(n)
@@ -203,6 +237,7 @@ func (c *CLab) createNodeCfg(nodeName string, nodeDef *types.NodeDefinition, idx
 		Certificate:     c.Config.Topology.GetCertificateConfig(nodeName),
 		Healthcheck:     c.Config.Topology.GetHealthCheckConfig(nodeName),
 		Aliases:         c.Config.Topology.GetNodeAliases(nodeName),
+		StaticRoutes:    c.Config.Topology.GetNodeStaticRoutes(nodeName),
 	}
 	var err error
 
@@ -211,6 +246,21 @@ func (c *CLab) createNodeCfg(nodeName string, nodeDef *types.NodeDefinition, idx
 		return nil, err
 	}
 
+	nodeCfg.Networks, err = c.nodeNetworks(nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeCfg.Credentials, err = c.nodeCredentials(nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeCfg.ExecConfig = c.Config.Topology.GetExecConfig(nodeName)
+	if err := nodeCfg.ExecConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("node %q: %w", nodeName, err)
+	}
+
 	// load environment variables
 	err = addEnvVarsToNodeCfg(c, nodeCfg)
 	if err != nil {
@@ -309,6 +359,35 @@ func (c *CLab) processStartupConfig(nodeCfg *types.NodeConfig) error {
 	return nil
 }
 
+// nodeCredentials returns the credentials configuration of the node.
+// The credentials not set explicitly are taken from the USERNAME, PASSWORD and SSH_KEY
+// variables of the credentials env file, and the ssh key path is resolved to an absolute path.
+func (c *CLab) nodeCredentials(nodeName string) (*types.CredentialsConfig, error) {
+	cc := c.Config.Topology.GetCredentialsConfig(nodeName)
+
+	if cc.EnvFile != "" {
+		envs, err := utils.LoadEnvVarFiles(c.TopoPaths.TopologyFileDir(), []string{cc.EnvFile})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the credentials of node %q: %w", nodeName, err)
+		}
+
+		cc = (&types.CredentialsConfig{
+			Username: envs["USERNAME"],
+			Password: envs["PASSWORD"],
+			SSHKey:   envs["SSH_KEY"],
+		}).Merge(cc)
+	}
+
+	// the ssh key of the untrusted topology may come from the credentials env file
+	if err := c.checkUntrustedPath("credentials ssh-key", cc.SSHKey); err != nil {
+		return nil, fmt.Errorf("%w: node %q: %w", ErrUntrustedTopology, nodeName, err)
+	}
+
+	cc.SSHKey = utils.ResolvePath(cc.SSHKey, c.TopoPaths.TopologyFileDir())
+
+	return cc, nil
+}
+
 // checkTopologyDefinition runs topology checks and returns any errors found.
 // This function runs after topology file is parsed and all nodes/links are initialized.
 func (c *CLab) checkTopologyDefinition(ctx context.Context) error {
@@ -319,6 +398,15 @@ func (c *CLab) checkTopologyDefinition(ctx context.Context) error {
 	if err = c.verifyRootNetNSLinks(); err != nil {
 		return err
 	}
+	if err = c.verifyEndpointAddresses(); err != nil {
+		return err
+	}
+	if err = c.verifyExtraNetworks(); err != nil {
+		return err
+	}
+	if err = c.verifyMgmtIsolation(); err != nil {
+		return err
+	}
 	for _, node := range c.Nodes {
 		err := node.CheckDeploymentConditions(ctx)
 		if err != nil {
@@ -334,6 +422,30 @@ func (c *CLab) checkTopologyDefinition(ctx context.Context) error {
 	return nil
 }
 
+// verifyEndpointAddresses makes sure that link addresses are only
+// defined on the endpoints of nodes that use the kernel dataplane.
+func (c *CLab) verifyEndpointAddresses() error {
+	for _, n := range c.Nodes {
+		if n.Config().KernelDataplane {
+			continue
+		}
+
+		for _, e := range n.GetEndpoints() {
+			if e.GetIPv4Addr().IsValid() || e.GetIPv6Addr().IsValid() {
+				return fmt.Errorf("node %q of kind %q does not support link addresses, remove the address from endpoint %q",
+					n.GetShortName(), n.Config().Kind, e.GetIfaceName())
+			}
+		}
+
+		// only the routes set on the node itself are left for the nodes without the kernel dataplane
+		if len(n.Config().StaticRoutes) > 0 {
+			return fmt.Errorf("node %q of kind %q does not support static routes", n.GetShortName(), n.Config().Kind)
+		}
+	}
+
+	return nil
+}
+
 // verifyRootNetNSLinks makes sure, that there will be no overlap in
 // interface names for Root Network Namespace bases nodes.
 func (c *CLab) verifyRootNetNSLinks() error {
@@ -559,11 +671,27 @@ func (c *CLab) addDefaultLabels(n nodes.Node) {
 	cfg.Labels[labels.NodeGroup] = cfg.Group
 	cfg.Labels[labels.NodeLabDir] = cfg.LabDir
 	cfg.Labels[labels.TopoFile] = c.TopoPaths.TopologyFilenameAbsPath()
-	owner := os.Getenv("SUDO_USER")
+	if c.instance != "" {
+		cfg.Labels[labels.Instance] = c.instance
+	}
+	if len(cfg.MgmtPorts) != 0 {
+		cfg.Labels[labels.MgmtPorts] = mgmtPortsLabel(cfg.MgmtPorts)
+	}
+	cfg.Labels[labels.Owner] = c.labOwner()
+}
+
+// labOwner returns the owner of the lab,
+// which is the user running containerlab unless set with WithOwner.
+func (c *CLab) labOwner() string {
+	owner := c.owner
+	if owner == "" {
+		owner = os.Getenv("SUDO_USER")
+	}
 	if owner == "" {
 		owner = os.Getenv("USER")
 	}
-	cfg.Labels[labels.Owner] = owner
+
+	return owner
 }
 
 // labelsToEnvVars adds labels to env vars with CLAB_LABEL_ prefix added
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/srl-labs/containerlab/clab/config/transport"
+	"github.com/srl-labs/containerlab/utils"
 )
 
 func Send(cs *NodeConfig, _ string) error {
@@ -16,22 +17,33 @@ func Send(cs *NodeConfig, _ string) error {
 	}
 
 	if ct == "ssh" {
-		ssh_cred := cs.Credentials
-		if err != nil {
-			return err
-		}
-
-		if len(ssh_cred) < 2 {
+		creds := cs.Credentials
+		if creds.GetUsername() == "" || (creds.GetPassword() == "" && creds.GetSSHKey() == "") {
 			return fmt.Errorf("SSH credentials for node %s of type %s not found, cannot configure",
 				cs.TargetNode.ShortName, cs.TargetNode.Kind)
 		}
-		tx, err = transport.NewSSHTransport(
-			cs.TargetNode,
-			transport.WithUserNamePassword(
-				ssh_cred[0],
-				ssh_cred[1]),
-			transport.HostKeyCallback(),
+
+		// the host keys are verified against the lab known_hosts file
+		// unless the lab was deployed without the host keys pinning,
+		// the nodes missing in the file are connected to with a warning
+		hostKeyOpt := transport.HostKeyCallback()
+		if utils.FileExists(cs.KnownHostsFile) {
+			hostKeyOpt = transport.WithKnownHosts(cs.KnownHostsFile)
+		}
+
+		opts := []transport.SSHTransportOption{hostKeyOpt}
+
+		// the key authentication is tried first when the ssh key is set
+		if creds.GetSSHKey() != "" {
+			opts = append(opts, transport.WithPrivateKey(creds.GetSSHKey()))
+		}
+
+		opts = append(opts, transport.WithUserNamePassword(
+			creds.GetUsername(),
+			creds.GetPassword()),
 		)
+
+		tx, err = transport.NewSSHTransport(cs.TargetNode, opts...)
 		if err != nil {
 			return err
 		}
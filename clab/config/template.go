@@ -15,6 +15,7 @@ import (
 	jT "github.com/kellerza/template"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/types"
 	"gopkg.in/yaml.v2"
 )
@@ -30,7 +31,9 @@ var DebugCount int
 
 type NodeConfig struct {
 	TargetNode  *types.NodeConfig
-	Credentials []string // Node's credentials
+	Credentials *nodes.Credentials // Node's credentials
+	// KnownHostsFile is the lab known_hosts file with the pinned SSH host keys
+	KnownHostsFile string
 	// All the variables used to render the template
 	Vars map[string]interface{}
 	// the Rendered templates
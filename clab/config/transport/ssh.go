@@ -1,9 +1,11 @@
 package transport
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net"
+	"os"
 	"runtime"
 	"strings"
 	"time"
@@ -11,6 +13,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/srl-labs/containerlab/types"
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
 )
 
 type SSHSession struct {
@@ -64,6 +67,55 @@ func WithUserNamePassword(username, password string) SSHTransportOption {
 	}
 }
 
+// WithPrivateKey adds public key authentication with the private key read from the given file.
+func WithPrivateKey(path string) SSHTransportOption {
+	return func(tx *SSHTransport) error {
+		key, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read the ssh private key: %w", err)
+		}
+
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return fmt.Errorf("failed to parse the ssh private key %s: %w", path, err)
+		}
+
+		tx.SSHConfig.Auth = append(tx.SSHConfig.Auth, ssh.PublicKeys(signer))
+		return nil
+	}
+}
+
+// WithKnownHosts verifies the host keys against the given known_hosts file.
+// The host keys of the hosts missing in the file, e.g. the nodes whose host keys
+// were not pinned on deploy, are accepted with a warning.
+func WithKnownHosts(path string) SSHTransportOption {
+	return func(tx *SSHTransport) error {
+		cb, err := knownhosts.New(path)
+		if err != nil {
+			return fmt.Errorf("failed to load the known hosts: %w", err)
+		}
+
+		tx.SSHConfig.HostKeyCallback = func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			err := cb(hostname, remote, key)
+
+			var keyErr *knownhosts.KeyError
+			if !errors.As(err, &keyErr) {
+				return err
+			}
+
+			if len(keyErr.Want) == 0 {
+				log.Warnf("Host key of %s is not pinned in %s, skipping host key verification", hostname, path)
+				return nil
+			}
+
+			return fmt.Errorf("host key of %s does not match the key pinned in %s, "+
+				"redeploy the lab if the node was recreated: %w", hostname, path, err)
+		}
+
+		return nil
+	}
+}
+
 // HostKeyCallback adds a basic username & password to a config.
 // Will initialize the config if required.
 func HostKeyCallback(callback ...ssh.HostKeyCallback) SSHTransportOption {
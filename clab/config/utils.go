@@ -11,6 +11,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/srl-labs/containerlab/clab"
 	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
 )
 
 const (
@@ -64,12 +65,13 @@ func PrepareVars(c *clab.CLab) map[string]*NodeConfig {
 			vars[vkRole] = nodeCfg.Kind
 		}
 
-		creds := c.Reg.Kind(nodeCfg.Kind).GetCredentials().Slice()
+		creds := c.Reg.Kind(nodeCfg.Kind).GetCredentials().Merge(nodeCfg.Credentials)
 
 		res[name] = &NodeConfig{
-			TargetNode:  nodeCfg,
-			Vars:        vars,
-			Credentials: creds,
+			TargetNode:     nodeCfg,
+			Vars:           vars,
+			Credentials:    creds,
+			KnownHostsFile: c.TopoPaths.KnownHostsFileAbsPath(),
 		}
 	}
 
@@ -217,7 +219,7 @@ func linkIP(link *types.Link) (string, string, error) {
 	if err != nil {
 		log.Errorf("could not create link IP from %s: %s", vkSystemIP, err)
 	}
-	return ipA.String(), ipFarEnd(ipA).String(), nil
+	return ipA.String(), utils.IPFarEnd(ipA).String(), nil
 }
 
 func ipLastOctet(in netip.Addr) int {
@@ -239,38 +241,13 @@ func ipFarEndS(in string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("invalid ip %s", in)
 	}
-	feA := ipFarEnd(ipA)
+	feA := utils.IPFarEnd(ipA)
 	if !feA.IsValid() {
 		return "", fmt.Errorf("invalid ip %s - %v", in, feA)
 	}
 	return feA.String(), nil
 }
 
-// Calculates the far end IP (first free IP in the subnet).
-func ipFarEnd(in netip.Prefix) netip.Prefix {
-	if in.Addr().Is4() && in.Bits() == 32 {
-		return netip.Prefix{}
-	}
-
-	n := in.Addr().Next()
-
-	if in.Addr().Is4() && in.Bits() <= 30 {
-		if !in.Contains(n) || !in.Contains(in.Addr().Prev()) {
-			return netip.Prefix{}
-		}
-		if !in.Contains(n.Next()) {
-			n = in.Addr().Prev()
-		}
-	}
-	if !in.Contains(n) {
-		n = in.Addr().Prev()
-	}
-	if !in.Contains(n) {
-		return netip.Prefix{}
-	}
-	return netip.PrefixFrom(n, in.Bits())
-}
-
 // GetTemplateNamesInDirs returns a list of template file names found in a list of dir `paths`
 // without traversing nested dirs
 // template names are following the pattern <some-name>__<role/kind>.tmpl.
@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
 )
 
 func TestFarEndIP(t *testing.T) {
@@ -33,7 +34,7 @@ func TestFarEndIP(t *testing.T) {
 			t.Errorf("not a valid IP prefix %s", k)
 		}
 
-		n := ipFarEnd(p)
+		n := utils.IPFarEnd(p)
 		n2, _ := ipFarEndS(k)
 
 		if !n.IsValid() && v == "" && n2 == "" {
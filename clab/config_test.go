@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -19,6 +20,7 @@ import (
 	"github.com/srl-labs/containerlab/mocks/mockruntime"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/runtime/docker"
+	"github.com/srl-labs/containerlab/types"
 	"github.com/srl-labs/containerlab/utils"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -731,3 +733,139 @@ func TestSuppressConfigInit(t *testing.T) {
 		})
 	}
 }
+
+func TestExtraNetworks(t *testing.T) {
+	c, err := NewContainerLab(WithTopoPath("test_data/topo14-networks.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]*types.NetworkAttachment{
+		"node1": {
+			{Name: "oob", Network: "oob", IPv4Address: "192.168.100.11", Interface: "eth1"},
+			{Name: "internet", Network: "clab-internet", Interface: "eth2"},
+		},
+		"node2": {
+			{Name: "internet", Network: "clab-internet", Interface: "eth1"},
+		},
+	}
+
+	for name, w := range want {
+		if d := cmp.Diff(w, c.Nodes[name].Config().Networks); d != "" {
+			t.Errorf("node %s networks mismatch (-want +got):\n%s", name, d)
+		}
+	}
+
+	if d := cmp.Diff([]string{"clab-internet", "oob"},
+		[]string{c.extraNetworks()[0].Network, c.extraNetworks()[1].Network}); d != "" {
+		t.Errorf("extra networks mismatch (-want +got):\n%s", d)
+	}
+
+	err = c.ResolveLinks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// node2 is attached to a single extra network, so its eth2 interface is free to use
+	if err := c.verifyExtraNetworks(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	c.Config.Topology.Nodes["node2"].Networks = []*types.NetworkAttachment{{Name: "oob"}, {Name: "internet"}}
+	c.Nodes["node2"].Config().Networks, _ = c.nodeNetworks("node2")
+
+	if err := c.verifyExtraNetworks(); err == nil {
+		t.Error("expected an error for the link interface clashing with the extra network interface")
+	}
+
+	// the interfaces are matched to the networks by the network name
+	c.Config.Topology.Nodes["node2"].Networks = []*types.NetworkAttachment{
+		{Name: "oob", Interface: "eth5"}, {Name: "internet", Interface: "eth1"},
+	}
+	c.Nodes["node2"].Config().Networks, _ = c.nodeNetworks("node2")
+
+	if err := c.verifyExtraNetworks(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	c.Config.Topology.Nodes["node2"].Networks = []*types.NetworkAttachment{
+		{Name: "oob", Interface: "eth1"}, {Name: "internet"},
+	}
+	c.Nodes["node2"].Config().Networks, _ = c.nodeNetworks("node2")
+
+	if err := c.verifyExtraNetworks(); err == nil {
+		t.Error("expected an error for the extra networks sharing the interface")
+	}
+
+	c.Config.Topology.Nodes["node2"].Networks = []*types.NetworkAttachment{{Name: "unknown"}}
+	if _, err := c.nodeNetworks("node2"); err == nil {
+		t.Error("expected an error for the undefined network")
+	}
+}
+
+func TestVerifyStaticRoutes(t *testing.T) {
+	routes := `
+      static-routes:
+        - prefix: 10.0.0.0/8
+          next-hop: 192.168.0.1`
+
+	tests := map[string]struct {
+		defaults string
+		srl      string
+		wantErr  bool
+	}{
+		"inherited by the kernel dataplane nodes only": {
+			defaults: routes,
+		},
+		"set on the node without the kernel dataplane": {
+			srl:     routes,
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("CLAB_LABDIR_BASE", t.TempDir())
+
+			topo := filepath.Join(t.TempDir(), "routes.clab.yml")
+			data := `name: routes
+topology:
+  defaults:` + strings.ReplaceAll(tt.defaults, "\n  ", "\n") + `
+    kind: linux
+  nodes:
+    l1: {}
+    srl:
+      kind: nokia_srlinux` + tt.srl + "\n"
+
+			if err := os.WriteFile(topo, []byte(data), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			c, err := NewContainerLab(WithTopoPath(topo, ""))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = c.verifyEndpointAddresses()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected the static routes of srl to be rejected")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(c.Nodes["l1"].Config().StaticRoutes) != 1 {
+				t.Errorf("l1 didn't inherit the default static routes")
+			}
+
+			if len(c.Nodes["srl"].Config().StaticRoutes) != 0 {
+				t.Errorf("srl inherited the default static routes")
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package clab
 
 import (
+	"time"
+
 	log "github.com/sirupsen/logrus"
 	"github.com/tklauser/numcpus"
 )
@@ -13,6 +15,9 @@ type DeployOptions struct {
 	maxWorkers         uint   // maxWorkers is the maximum number of workers for node creation.
 	exportTemplate     string // exportTemplate is the path to the export template.
 	skipLabDirFileACLs bool   // skip setting the extended File ACL entries on the lab directory.
+	// hostKeyScanTimeout is the time to wait for the nodes to become healthy before
+	// their SSH host keys are scanned, zero disables the scanning.
+	hostKeyScanTimeout time.Duration
 }
 
 // NewDeployOptions creates a new DeployOptions instance with the specified maxWorkers value.
@@ -83,6 +88,17 @@ func (d *DeployOptions) ExportTemplate() string {
 	return d.exportTemplate
 }
 
+// SetHostKeyScanTimeout sets the hostKeyScanTimeout option and returns the updated DeployOptions instance.
+func (d *DeployOptions) SetHostKeyScanTimeout(t time.Duration) *DeployOptions {
+	d.hostKeyScanTimeout = t
+	return d
+}
+
+// HostKeyScanTimeout returns the hostKeyScanTimeout option value.
+func (d *DeployOptions) HostKeyScanTimeout() time.Duration {
+	return d.hostKeyScanTimeout
+}
+
 // initWorkerCount calculates the number of workers used for node creation.
 // If maxWorkers is provided, it takes precedence.
 // If maxWorkers is not set, the number of workers is limited by the number of available CPUs
@@ -0,0 +1,262 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/miekg/dns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/clab/dnsserver"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	// dnsServerRunDir is the directory where the DNS server pid and log files are kept.
+	dnsServerRunDir = "/run/containerlab"
+	// dnsServerReadyTimeout is the time to wait for the DNS server to answer queries.
+	dnsServerReadyTimeout = 5 * time.Second
+)
+
+// dnsServerPaths returns the paths of the pid and log files of the DNS server
+// serving the given management network.
+func dnsServerPaths(network string) (pidFile, logFile string) {
+	base := filepath.Join(dnsServerRunDir, "dns-"+network)
+
+	return base + ".pid", base + ".log"
+}
+
+// dnsServerAddrs returns the listen addresses of the DNS server,
+// which are the gateway addresses of the management network.
+func (c *CLab) dnsServerAddrs() []string {
+	var addrs []string
+
+	mgmt := c.globalRuntime().Mgmt()
+
+	for _, gw := range []string{mgmt.IPv4Gw, mgmt.IPv6Gw} {
+		if ip := net.ParseIP(gw); ip != nil && !ip.IsUnspecified() {
+			addrs = append(addrs, ip.String())
+		}
+	}
+
+	return addrs
+}
+
+// labDomain returns the DNS domain of the lab node names.
+func (c *CLab) labDomain() string {
+	return c.Config.Name + "." + dnsserver.DefaultDomain
+}
+
+// setupDNSServer starts the DNS server for the management network if it is not running yet
+// and points the nodes to it, unless the nodes have the DNS servers set explicitly.
+func (c *CLab) setupDNSServer() error {
+	addrs := c.dnsServerAddrs()
+	if len(addrs) == 0 {
+		return fmt.Errorf("management network %q has no gateway addresses for the DNS server to listen on",
+			c.Config.Mgmt.Network)
+	}
+
+	if err := c.startDNSServer(addrs); err != nil {
+		return err
+	}
+
+	for _, n := range c.Nodes {
+		cfg := n.Config()
+		// nodes in container and host network modes do not use the management network
+		if strings.HasPrefix(cfg.NetworkMode, "container") || cfg.NetworkMode == "host" {
+			continue
+		}
+
+		if cfg.DNS == nil {
+			cfg.DNS = &types.DNSConfig{}
+		}
+
+		if cfg.DNS.Servers == nil {
+			cfg.DNS.Servers = addrs
+		}
+
+		if !slices.Contains(cfg.DNS.Search, c.labDomain()) {
+			cfg.DNS.Search = append(cfg.DNS.Search, c.labDomain())
+		}
+	}
+
+	return nil
+}
+
+// startDNSServer starts the DNS server as a detached containerlab process
+// listening on the given addresses. The server is shared by all labs using
+// the same management network, therefore it is not started if it is already running.
+func (c *CLab) startDNSServer(addrs []string) error {
+	pidFile, logFile := dnsServerPaths(c.Config.Mgmt.Network)
+
+	listenAddrs := make([]string, 0, len(addrs))
+	for _, a := range addrs {
+		listenAddrs = append(listenAddrs, net.JoinHostPort(a, "53"))
+	}
+
+	if pid, ok := runningPid(pidFile); ok {
+		log.Debugf("DNS server for the %s network is already running with pid %d", c.Config.Mgmt.Network, pid)
+
+		if err := waitDNSServer(listenAddrs, nil, dnsServerReadyTimeout); err != nil {
+			log.Warnf("DNS server for the %s network with pid %d does not answer queries: %v, see %s",
+				c.Config.Mgmt.Network, pid, err, logFile)
+		}
+
+		return nil
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	utils.CreateDirectory(dnsServerRunDir, 0755)
+
+	logF, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644) // skipcq: GSC-G302
+	if err != nil {
+		return err
+	}
+	defer logF.Close()
+
+	args := []string{"tools", "dns", "serve", "--runtime", c.globalRuntimeName}
+	for _, a := range listenAddrs {
+		args = append(args, "--address", a)
+	}
+
+	cmd := exec.Command(self, args...)
+	cmd.Stdout = logF
+	cmd.Stderr = logF
+	// detach the server from the containerlab process session
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start the DNS server: %w", err)
+	}
+
+	// the server exits when it fails to bind to the listen addresses
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+
+	if err := waitDNSServer(listenAddrs, exited, dnsServerReadyTimeout); err != nil {
+		_ = cmd.Process.Kill()
+
+		return fmt.Errorf("DNS server failed to start on %s: %w, see %s",
+			strings.Join(listenAddrs, ", "), err, logFile)
+	}
+
+	if err := os.WriteFile(pidFile, []byte(strconv.Itoa(cmd.Process.Pid)), 0644); err != nil { // skipcq: GSC-G306
+		return err
+	}
+
+	log.Infof("Started DNS server for the %s domain on %s, logs are in %s",
+		c.labDomain(), strings.Join(addrs, ", "), logFile)
+
+	return nil
+}
+
+// waitDNSServer waits until the DNS server answers queries on all the addresses.
+// An error is returned when the server does not answer within the timeout
+// or when the exited channel is closed, signaling that the server process has exited.
+func waitDNSServer(addrs []string, exited <-chan struct{}, timeout time.Duration) error {
+	client := &dns.Client{Timeout: 500 * time.Millisecond}
+	q := new(dns.Msg).SetQuestion(dns.Fqdn(dnsserver.DefaultDomain), dns.TypeSOA)
+
+	deadline := time.Now().Add(timeout)
+
+	for _, addr := range addrs {
+		for {
+			_, _, err := client.Exchange(q, addr)
+			if err == nil {
+				break
+			}
+
+			select {
+			case <-exited:
+				return errors.New("the server has exited")
+			default:
+			}
+
+			if time.Now().After(deadline) {
+				return fmt.Errorf("no answer from %s: %w", addr, err)
+			}
+
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	return nil
+}
+
+// stopDNSServer stops the DNS server of the management network
+// when no lab containers are attached to the network anymore.
+func (c *CLab) stopDNSServer(ctx context.Context) error {
+	pidFile, _ := dnsServerPaths(c.Config.Mgmt.Network)
+
+	pid, ok := runningPid(pidFile)
+	// the management bridge is not known before the network is created
+	if !ok || c.globalRuntime().Mgmt().Bridge == "" {
+		return nil
+	}
+
+	containers, err := c.ListContainers(ctx, []*types.GenericFilter{
+		{
+			FilterType: "label", Field: labels.NodeMgmtNetBr,
+			Operator: "=", Match: c.globalRuntime().Mgmt().Bridge,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(containers) != 0 {
+		log.Debugf("DNS server for the %s network is still used by %d containers",
+			c.Config.Mgmt.Network, len(containers))
+		return nil
+	}
+
+	log.Infof("Stopping DNS server for the %s network", c.Config.Mgmt.Network)
+
+	if err := syscall.Kill(pid, syscall.SIGTERM); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+
+	return os.Remove(pidFile)
+}
+
+// runningPid returns the pid stored in the pid file if the process with this pid is running.
+func runningPid(pidFile string) (int, bool) {
+	b, err := os.ReadFile(pidFile)
+	if err != nil {
+		return 0, false
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+
+	// signal 0 checks the process existence without signaling it
+	if err := syscall.Kill(pid, 0); err != nil && !errors.Is(err, syscall.EPERM) {
+		return 0, false
+	}
+
+	return pid, true
+}
@@ -0,0 +1,48 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/srl-labs/containerlab/clab/dnsserver"
+)
+
+func TestWaitDNSServer(t *testing.T) {
+	// reserve a free port for the server and release it right away
+	l, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.LocalAddr().String()
+	l.Close()
+
+	exited := make(chan struct{})
+	close(exited)
+
+	if err := waitDNSServer([]string{addr}, exited, time.Second); err == nil ||
+		!strings.Contains(err.Error(), "exited") {
+		t.Errorf("expected the exited server error, got %v", err)
+	}
+
+	if err := waitDNSServer([]string{addr}, nil, 200*time.Millisecond); err == nil ||
+		!strings.Contains(err.Error(), "no answer") {
+		t.Errorf("expected the no answer error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := dnsserver.New(func(context.Context) ([]*dnsserver.Record, error) { return nil, nil })
+	go func() { _ = srv.ListenAndServe(ctx, []string{addr}) }()
+
+	if err := waitDNSServer([]string{addr}, nil, 5*time.Second); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package dnsserver implements a DNS responder for the names of the lab nodes.
+// The responder answers A, AAAA and PTR queries for the <node>.<lab>.<domain> names
+// of the running lab containers and forwards all other queries to the upstream servers.
+package dnsserver
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+const (
+	// DefaultDomain is the parent domain of the lab domains.
+	DefaultDomain = "clab"
+	// defaultTTL is the TTL of the records served by the responder.
+	// It is kept short, since the lab containers come and go.
+	defaultTTL = 5
+	// defaultRefreshInterval is the interval the records are considered fresh for.
+	defaultRefreshInterval = 2 * time.Second
+)
+
+// Record is a DNS record of a lab node.
+type Record struct {
+	// Name is the fully qualified name of the node, e.g. srl1.mylab.clab.
+	Name string
+	IPv4 net.IP
+	IPv6 net.IP
+}
+
+// RecordsFunc returns the records the responder serves.
+type RecordsFunc func(ctx context.Context) ([]*Record, error)
+
+// Server is a DNS responder for the lab node names.
+type Server struct {
+	domain    string
+	upstreams []string
+	records   RecordsFunc
+	refresh   time.Duration
+
+	m        sync.Mutex
+	names    map[string]*Record
+	reverse  map[string]string
+	loadedAt time.Time
+}
+
+// Option configures the Server.
+type Option func(s *Server)
+
+// WithDomain sets the parent domain of the lab domains.
+func WithDomain(d string) Option {
+	return func(s *Server) {
+		s.domain = dns.Fqdn(strings.ToLower(d))
+	}
+}
+
+// WithUpstreams sets the upstream servers the queries for other names are forwarded to.
+// The upstream addresses are IP addresses with an optional port.
+func WithUpstreams(upstreams []string) Option {
+	return func(s *Server) {
+		for _, u := range upstreams {
+			if _, _, err := net.SplitHostPort(u); err != nil {
+				u = net.JoinHostPort(u, "53")
+			}
+
+			s.upstreams = append(s.upstreams, u)
+		}
+	}
+}
+
+// New returns a new Server serving the records returned by the records function.
+func New(records RecordsFunc, opts ...Option) *Server {
+	s := &Server{
+		domain:  dns.Fqdn(DefaultDomain),
+		records: records,
+		refresh: defaultRefreshInterval,
+	}
+
+	for _, o := range opts {
+		o(s)
+	}
+
+	return s
+}
+
+// ListenAndServe serves DNS over UDP and TCP on the given addresses until the context is canceled.
+func (s *Server) ListenAndServe(ctx context.Context, addrs []string) error {
+	var servers []*dns.Server
+
+	errCh := make(chan error, 2*len(addrs))
+
+	for _, addr := range addrs {
+		for _, proto := range []string{"udp", "tcp"} {
+			srv := &dns.Server{Addr: addr, Net: proto, Handler: s}
+			servers = append(servers, srv)
+
+			go func() {
+				log.Infof("Starting DNS server on %s/%s for the %s domain", addr, proto, s.domain)
+				errCh <- srv.ListenAndServe()
+			}()
+		}
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-errCh:
+	}
+
+	for _, srv := range servers {
+		_ = srv.Shutdown()
+	}
+
+	return err
+}
+
+// ServeDNS implements the dns.Handler interface.
+func (s *Server) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	if len(r.Question) != 1 {
+		s.reply(w, r, new(dns.Msg).SetRcode(r, dns.RcodeFormatError))
+		return
+	}
+
+	q := r.Question[0]
+	name := strings.ToLower(q.Name)
+
+	switch {
+	case dns.IsSubDomain(s.domain, name):
+		s.reply(w, r, s.answerName(r, name, q.Qtype))
+	case q.Qtype == dns.TypePTR:
+		if m := s.answerPTR(r, name); m != nil {
+			s.reply(w, r, m)
+			return
+		}
+
+		s.reply(w, r, s.forward(r))
+	default:
+		s.reply(w, r, s.forward(r))
+	}
+}
+
+// answerName answers a query for a name within the lab domain.
+func (s *Server) answerName(r *dns.Msg, name string, qtype uint16) *dns.Msg {
+	m := new(dns.Msg).SetReply(r)
+	m.Authoritative = true
+
+	rec, ok := s.lookup(name)
+	if !ok {
+		m.Rcode = dns.RcodeNameError
+		return m
+	}
+
+	hdr := func(t uint16) dns.RR_Header {
+		return dns.RR_Header{Name: r.Question[0].Name, Rrtype: t, Class: dns.ClassINET, Ttl: defaultTTL}
+	}
+
+	if rec.IPv4 != nil && (qtype == dns.TypeA || qtype == dns.TypeANY) {
+		m.Answer = append(m.Answer, &dns.A{Hdr: hdr(dns.TypeA), A: rec.IPv4})
+	}
+
+	if rec.IPv6 != nil && (qtype == dns.TypeAAAA || qtype == dns.TypeANY) {
+		m.Answer = append(m.Answer, &dns.AAAA{Hdr: hdr(dns.TypeAAAA), AAAA: rec.IPv6})
+	}
+
+	return m
+}
+
+// answerPTR answers a reverse query for the address of a lab node.
+// It returns nil when the address does not belong to a lab node.
+func (s *Server) answerPTR(r *dns.Msg, name string) *dns.Msg {
+	s.load()
+
+	s.m.Lock()
+	target, ok := s.reverse[name]
+	s.m.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	m := new(dns.Msg).SetReply(r)
+	m.Authoritative = true
+	m.Answer = append(m.Answer, &dns.PTR{
+		Hdr: dns.RR_Header{Name: r.Question[0].Name, Rrtype: dns.TypePTR, Class: dns.ClassINET, Ttl: defaultTTL},
+		Ptr: target,
+	})
+
+	return m
+}
+
+// forward forwards the query to the upstream servers and returns the first successful response.
+func (s *Server) forward(r *dns.Msg) *dns.Msg {
+	c := new(dns.Client)
+
+	for _, u := range s.upstreams {
+		resp, _, err := c.Exchange(r, u)
+		if err != nil {
+			log.Debugf("DNS query forwarding to %s failed: %v", u, err)
+			continue
+		}
+
+		return resp
+	}
+
+	return new(dns.Msg).SetRcode(r, dns.RcodeServerFailure)
+}
+
+func (*Server) reply(w dns.ResponseWriter, r, m *dns.Msg) {
+	m.Id = r.Id
+
+	if err := w.WriteMsg(m); err != nil {
+		log.Debugf("failed to write DNS response: %v", err)
+	}
+}
+
+// lookup returns the record for the fully qualified name.
+func (s *Server) lookup(name string) (*Record, bool) {
+	s.load()
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	rec, ok := s.names[name]
+
+	return rec, ok
+}
+
+// load refreshes the records if they are stale.
+func (s *Server) load() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.names != nil && time.Since(s.loadedAt) < s.refresh {
+		return
+	}
+
+	recs, err := s.records(context.Background())
+	if err != nil {
+		log.Warnf("failed to retrieve DNS records: %v", err)
+		return
+	}
+
+	s.names = make(map[string]*Record, len(recs))
+	s.reverse = make(map[string]string, len(recs))
+
+	for _, rec := range recs {
+		name := dns.Fqdn(strings.ToLower(rec.Name))
+		s.names[name] = rec
+
+		for _, ip := range []net.IP{rec.IPv4, rec.IPv6} {
+			if ip == nil {
+				continue
+			}
+
+			if arpa, err := dns.ReverseAddr(ip.String()); err == nil {
+				s.reverse[arpa] = name
+			}
+		}
+	}
+
+	s.loadedAt = time.Now()
+}
+
+// RecordsFromContainers returns the records for the lab containers.
+// The record names follow the <node>.<lab>.<domain> pattern.
+func RecordsFromContainers(containers []runtime.GenericContainer, domain string) []*Record {
+	var recs []*Record
+
+	for _, c := range containers {
+		node, lab := c.Labels[labels.NodeName], c.Labels[labels.Containerlab]
+		if node == "" || lab == "" {
+			continue
+		}
+
+		rec := &Record{
+			Name: strings.Join([]string{node, lab, strings.TrimSuffix(domain, ".")}, "."),
+			IPv4: net.ParseIP(c.NetworkSettings.IPv4addr),
+			IPv6: net.ParseIP(c.NetworkSettings.IPv6addr),
+		}
+
+		if rec.IPv4 == nil && rec.IPv6 == nil {
+			continue
+		}
+
+		recs = append(recs, rec)
+	}
+
+	return recs
+}
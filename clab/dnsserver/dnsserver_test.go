@@ -0,0 +1,128 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package dnsserver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/miekg/dns"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// recorder is a dns.ResponseWriter storing the written message.
+type recorder struct {
+	dns.ResponseWriter
+	msg *dns.Msg
+}
+
+func (r *recorder) WriteMsg(m *dns.Msg) error {
+	r.msg = m
+	return nil
+}
+
+func TestServeDNS(t *testing.T) {
+	records := func(_ context.Context) ([]*Record, error) {
+		return []*Record{
+			{Name: "srl1.lab1.clab", IPv4: net.ParseIP("172.20.20.2"), IPv6: net.ParseIP("3fff:172:20:20::2")},
+			{Name: "srl2.lab1.clab", IPv4: net.ParseIP("172.20.20.3")},
+		}, nil
+	}
+
+	s := New(records)
+
+	tests := map[string]struct {
+		name   string
+		qtype  uint16
+		rcode  int
+		answer []string
+	}{
+		"a record": {
+			name:   "srl1.lab1.clab.",
+			qtype:  dns.TypeA,
+			rcode:  dns.RcodeSuccess,
+			answer: []string{"srl1.lab1.clab.\t5\tIN\tA\t172.20.20.2"},
+		},
+		"aaaa record": {
+			name:   "SRL1.lab1.clab.",
+			qtype:  dns.TypeAAAA,
+			rcode:  dns.RcodeSuccess,
+			answer: []string{"SRL1.lab1.clab.\t5\tIN\tAAAA\t3fff:172:20:20::2"},
+		},
+		"aaaa record of a node without ipv6": {
+			name:  "srl2.lab1.clab.",
+			qtype: dns.TypeAAAA,
+			rcode: dns.RcodeSuccess,
+		},
+		"unknown node": {
+			name:  "srl3.lab1.clab.",
+			qtype: dns.TypeA,
+			rcode: dns.RcodeNameError,
+		},
+		"ipv4 ptr record": {
+			name:   "3.20.20.172.in-addr.arpa.",
+			qtype:  dns.TypePTR,
+			rcode:  dns.RcodeSuccess,
+			answer: []string{"3.20.20.172.in-addr.arpa.\t5\tIN\tPTR\tsrl2.lab1.clab."},
+		},
+		"unknown name without upstreams": {
+			name:  "example.com.",
+			qtype: dns.TypeA,
+			rcode: dns.RcodeServerFailure,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := &recorder{}
+			s.ServeDNS(w, new(dns.Msg).SetQuestion(tt.name, tt.qtype))
+
+			if w.msg.Rcode != tt.rcode {
+				t.Fatalf("got rcode %s, want %s", dns.RcodeToString[w.msg.Rcode], dns.RcodeToString[tt.rcode])
+			}
+
+			var answer []string
+			for _, rr := range w.msg.Answer {
+				answer = append(answer, rr.String())
+			}
+
+			if d := cmp.Diff(tt.answer, answer); d != "" {
+				t.Fatalf("answer mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestRecordsFromContainers(t *testing.T) {
+	containers := []runtime.GenericContainer{
+		{
+			Labels: map[string]string{labels.Containerlab: "lab1", labels.NodeName: "srl1"},
+			NetworkSettings: runtime.GenericMgmtIPs{
+				IPv4addr: "172.20.20.2",
+				IPv6addr: "3fff:172:20:20::2",
+			},
+		},
+		{
+			// container without mgmt addresses
+			Labels: map[string]string{labels.Containerlab: "lab1", labels.NodeName: "srl2"},
+		},
+		{
+			// non-containerlab container
+			NetworkSettings: runtime.GenericMgmtIPs{IPv4addr: "172.20.20.4"},
+		},
+	}
+
+	want := []*Record{
+		{Name: "srl1.lab1.clab", IPv4: net.ParseIP("172.20.20.2"), IPv6: net.ParseIP("3fff:172:20:20::2")},
+	}
+
+	got := RecordsFromContainers(containers, "clab.")
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("records mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,308 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/tklauser/numcpus"
+)
+
+const (
+	// execOutputDir is the directory in the lab directory the exec results are saved to.
+	execOutputDir = "exec"
+	// execOutputMaxSlugLen is the maximum length of the command part of the exec output file names.
+	execOutputMaxSlugLen = 64
+	// execOutputFileMode is the mode of the exec output files, as the outputs may carry the node secrets.
+	execOutputFileMode = 0600
+)
+
+// execRetryInterval is the initial interval between the retries of a failed exec command.
+var execRetryInterval = time.Second
+
+// execSlugRe matches the characters replaced in the command part of the exec output file names.
+var execSlugRe = regexp.MustCompile(`[^a-z0-9._]+`)
+
+// execTarget is a container or a netns node the exec commands are executed on.
+type execTarget struct {
+	// name is the name of the target in the exec results collection.
+	name string
+	// node is the short name of the node.
+	node string
+	// labDir is the directory of the lab the node belongs to, empty if unknown.
+	labDir string
+	labels map[string]string
+	cmds   []*exec.SpecCmd
+	run    func(ctx context.Context, cmd *exec.ExecCmd) (*exec.ExecResult, error)
+}
+
+// containerExecTarget returns the exec target of the container.
+func containerExecTarget(cnt *runtime.GenericContainer) *execTarget {
+	t := &execTarget{
+		name:   cnt.Names[0],
+		node:   cnt.Labels[labels.NodeName],
+		labels: cnt.Labels,
+		run:    cnt.RunExec,
+	}
+
+	if t.node == "" {
+		t.node = cnt.Names[0]
+	}
+
+	if d := cnt.Labels[labels.NodeLabDir]; d != "" {
+		t.labDir = filepath.Dir(d)
+	}
+
+	return t
+}
+
+// nodeExecTarget returns the exec target of the topology node.
+func nodeExecTarget(n nodes.Node) *execTarget {
+	cfg := n.Config()
+
+	return &execTarget{
+		name:   cfg.LongName,
+		node:   cfg.ShortName,
+		labDir: filepath.Dir(cfg.LabDir),
+		labels: cfg.Labels,
+		run:    n.RunExec,
+	}
+}
+
+// execTargetResult is the outcome of a command executed on the target, saved to the results file.
+type execTargetResult struct {
+	Cmd        string `json:"cmd"`
+	ReturnCode *int   `json:"return-code,omitempty"`
+	Attempts   int    `json:"attempts"`
+	StdoutFile string `json:"stdout-file,omitempty"`
+	StderrFile string `json:"stderr-file,omitempty"`
+	Error      string `json:"error,omitempty"`
+
+	result *exec.ExecResult
+}
+
+// runExecTargets executes the commands on the targets concurrently, limited by the max workers option,
+// and collects the results. The commands of a single target are executed sequentially.
+func (c *CLab) runExecTargets(ctx context.Context, targets []*execTarget, options *ExecOptions) (*exec.ExecCollection, error) {
+	workers := options.maxWorkers
+	if workers == 0 {
+		vCpus, err := numcpus.GetOnline()
+		if err != nil {
+			return nil, err
+		}
+		workers = uint(vCpus)
+	}
+
+	log.Debugf("Number of exec workers: %d", workers)
+
+	// the results of a single exec run are saved to the directory named after its start time
+	runDir := time.Now().Format("20060102-150405")
+
+	resultCollection := exec.NewExecCollection()
+	sem := make(chan struct{}, workers)
+
+	var wg sync.WaitGroup
+
+	for _, t := range targets {
+		if len(t.cmds) == 0 {
+			log.Debugf("no commands to execute on %s", t.name)
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(t *execTarget) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results := t.exec(ctx, resultCollection)
+
+			if !options.saveOutput || len(results) == 0 {
+				return
+			}
+
+			if t.labDir == "" {
+				log.Warnf("Not saving the exec results of %s: lab directory is unknown", t.name)
+				return
+			}
+
+			dir := filepath.Join(t.labDir, execOutputDir, runDir, t.node)
+			if err := saveExecResults(dir, results); err != nil {
+				log.Errorf("failed to save the exec results of %s: %v", t.name, err)
+				return
+			}
+
+			log.Infof("Saved the exec results of %s to %s", t.name, dir)
+		}(t)
+	}
+
+	wg.Wait()
+
+	return resultCollection, ctx.Err()
+}
+
+// exec executes the target commands and adds their results to the collection.
+// It returns the outcome of the executed commands in their order.
+func (t *execTarget) exec(ctx context.Context, collection *exec.ExecCollection) []*execTargetResult {
+	var results []*execTargetResult
+
+	for _, cmd := range t.cmds {
+		if ctx.Err() != nil {
+			return results
+		}
+
+		res, attempts, err := t.execCmd(ctx, cmd)
+
+		r := &execTargetResult{Cmd: cmd.ExecCmd().GetCmdString(), Attempts: attempts, result: res}
+
+		switch {
+		// skip the remaining commands on the nodes that do not support exec,
+		// keeping the results of the commands that already ran
+		case errors.Is(err, exec.ErrRunExecNotSupported):
+			log.Debugf("exec is not supported on %s", t.name)
+			return results
+		case err != nil:
+			r.Error = err.Error()
+			log.Errorf("Failed to execute command %q on the node %q: %v", r.Cmd, t.name, err)
+			results = append(results, r)
+
+			continue
+		}
+
+		r.ReturnCode = &res.ReturnCode
+		results = append(results, r)
+		collection.Add(t.name, res)
+	}
+
+	return results
+}
+
+// execCmd executes the command on the target, retrying it with an exponential backoff
+// when it fails to execute, times out or returns a non zero return code.
+// The result of the last attempt is returned along with the number of the attempts made.
+func (t *execTarget) execCmd(ctx context.Context, cmd *exec.SpecCmd) (*exec.ExecResult, int, error) {
+	var res *exec.ExecResult
+
+	attempts := 0
+
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = execRetryInterval
+	b.MaxElapsedTime = 0
+
+	err := backoff.Retry(func() error {
+		attempts++
+		res = nil
+
+		cmdCtx := ctx
+		if cmd.Timeout > 0 {
+			var cancel context.CancelFunc
+			cmdCtx, cancel = context.WithTimeout(ctx, cmd.Timeout)
+			defer cancel()
+		}
+
+		r, err := t.run(cmdCtx, cmd.ExecCmd())
+
+		switch {
+		case ctx.Err() != nil:
+			return backoff.Permanent(ctx.Err())
+		case errors.Is(err, exec.ErrRunExecNotSupported):
+			return backoff.Permanent(err)
+		case errors.Is(err, context.DeadlineExceeded):
+			err = fmt.Errorf("timed out after %s", cmd.Timeout)
+		}
+
+		if err == nil && r == nil {
+			err = fmt.Errorf("no result returned")
+		}
+
+		if err != nil {
+			log.Debugf("attempt %d of command %q on %s failed: %v", attempts, cmd.Cmd, t.name, err)
+			return err
+		}
+
+		res = r
+
+		if r.ReturnCode != 0 {
+			log.Debugf("attempt %d of command %q on %s returned %d", attempts, cmd.Cmd, t.name, r.ReturnCode)
+			return fmt.Errorf("return code %d", r.ReturnCode)
+		}
+
+		return nil
+	}, backoff.WithContext(backoff.WithMaxRetries(b, uint64(cmd.GetRetries())), ctx))
+
+	// the non zero return code is reported in the result
+	if res != nil {
+		return res, attempts, nil
+	}
+
+	return nil, attempts, err
+}
+
+// saveExecResults writes the outputs of the commands to the files in the directory,
+// and their outcome to the results.json file.
+// The output files are named after the position of the command and the command itself.
+func saveExecResults(dir string, results []*execTargetResult) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	for i, r := range results {
+		if r.result == nil {
+			continue
+		}
+
+		r.StdoutFile = execOutputFileName(i, r.Cmd, ".txt")
+		if err := os.WriteFile(filepath.Join(dir, r.StdoutFile), r.result.GetStdOutByteSlice(), execOutputFileMode); err != nil {
+			return err
+		}
+
+		if r.result.Stderr == "" {
+			continue
+		}
+
+		r.StderrFile = execOutputFileName(i, r.Cmd, ".stderr.txt")
+		if err := os.WriteFile(filepath.Join(dir, r.StderrFile), r.result.GetStdErrByteSlice(), execOutputFileMode); err != nil {
+			return err
+		}
+	}
+
+	b, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, "results.json"), b, execOutputFileMode)
+}
+
+// execOutputFileName returns the name of the output file of the i-th command.
+func execOutputFileName(i int, cmd, suffix string) string {
+	slug := strings.Trim(execSlugRe.ReplaceAllString(strings.ToLower(cmd), "-"), "-.")
+	if len(slug) > execOutputMaxSlugLen {
+		slug = strings.TrimRight(slug[:execOutputMaxSlugLen], "-.")
+	}
+
+	if slug == "" {
+		slug = "cmd"
+	}
+
+	return fmt.Sprintf("%02d-%s%s", i+1, slug, suffix)
+}
@@ -0,0 +1,205 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package exec
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/srl-labs/containerlab/labels"
+	"gopkg.in/yaml.v2"
+)
+
+// Spec maps the lab nodes to the commands executed on them, it is read from an exec spec file.
+type Spec struct {
+	// Defaults are the timeout and retries of the commands that don't set them.
+	Defaults *CmdOptions   `yaml:"defaults"`
+	Targets  []*SpecTarget `yaml:"targets"`
+}
+
+// SpecTarget selects the nodes its commands are executed on.
+// A node is selected when it matches all the selectors that are set,
+// the target without selectors selects all the nodes.
+type SpecTarget struct {
+	// Nodes are the short names of the selected nodes.
+	Nodes []string `yaml:"nodes"`
+	// Kinds are the kinds of the selected nodes.
+	Kinds []string `yaml:"kinds"`
+	// Labels are the labels the selected nodes must have.
+	Labels   map[string]string `yaml:"labels"`
+	Commands []*SpecCmd        `yaml:"commands"`
+}
+
+// CmdOptions are the execution settings of a command.
+type CmdOptions struct {
+	// Timeout is the time a single attempt of the command is allowed to run for, zero means no timeout.
+	Timeout time.Duration `yaml:"timeout"`
+	// Retries is the number of times a failed command is retried with an exponential backoff.
+	Retries *int `yaml:"retries"`
+}
+
+// SpecCmd is a command of the exec spec.
+// It is defined either as a string or as a map with the cmd and the execution settings.
+type SpecCmd struct {
+	Cmd        string `yaml:"cmd"`
+	CmdOptions `yaml:",inline"`
+
+	execCmd *ExecCmd
+}
+
+// NewSpecCmd creates a SpecCmd for a string-based command.
+func NewSpecCmd(cmd string, opts CmdOptions) (*SpecCmd, error) {
+	s := &SpecCmd{Cmd: cmd, CmdOptions: opts}
+	if err := s.init(&CmdOptions{}); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// UnmarshalYAML allows the command to be defined as a plain string.
+func (s *SpecCmd) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var cmd string
+	if err := unmarshal(&cmd); err == nil {
+		s.Cmd = cmd
+		return nil
+	}
+
+	type specCmd SpecCmd
+
+	return unmarshal((*specCmd)(s))
+}
+
+// ExecCmd returns the parsed command.
+func (s *SpecCmd) ExecCmd() *ExecCmd {
+	return s.execCmd
+}
+
+// GetRetries returns the number of retries of the command.
+func (s *SpecCmd) GetRetries() int {
+	if s.Retries == nil {
+		return 0
+	}
+
+	return *s.Retries
+}
+
+// init parses the command and sets its unset execution settings to the defaults.
+func (s *SpecCmd) init(defaults *CmdOptions) error {
+	if s.Cmd == "" {
+		return fmt.Errorf("command should have cmd set")
+	}
+
+	var err error
+	if s.execCmd, err = NewExecCmdFromString(s.Cmd); err != nil {
+		return fmt.Errorf("failed to parse command %q: %w", s.Cmd, err)
+	}
+
+	if s.Timeout == 0 {
+		s.Timeout = defaults.Timeout
+	}
+
+	if s.Retries == nil {
+		s.Retries = defaults.Retries
+	}
+
+	if s.Timeout < 0 || s.GetRetries() < 0 {
+		return fmt.Errorf("command %q: timeout and retries can't be negative", s.Cmd)
+	}
+
+	return nil
+}
+
+// LoadSpec reads the exec spec from the file and validates it.
+// The defaults are the execution settings of the commands that are set
+// neither on the commands nor in the spec defaults.
+func LoadSpec(file string, defaults CmdOptions) (*Spec, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Spec{}
+	if err := yaml.UnmarshalStrict(b, s); err != nil {
+		return nil, fmt.Errorf("failed to parse exec spec file %s: %w", file, err)
+	}
+
+	if err := s.init(defaults); err != nil {
+		return nil, fmt.Errorf("exec spec file %s: %w", file, err)
+	}
+
+	return s, nil
+}
+
+// init validates the spec targets and sets the defaults of their commands.
+func (s *Spec) init(defaults CmdOptions) error {
+	if len(s.Targets) == 0 {
+		return fmt.Errorf("no targets defined")
+	}
+
+	if s.Defaults != nil {
+		if s.Defaults.Timeout != 0 {
+			defaults.Timeout = s.Defaults.Timeout
+		}
+
+		if s.Defaults.Retries != nil {
+			defaults.Retries = s.Defaults.Retries
+		}
+	}
+
+	for i, t := range s.Targets {
+		if t == nil || len(t.Commands) == 0 {
+			return fmt.Errorf("target #%d has no commands", i+1)
+		}
+
+		for _, c := range t.Commands {
+			if c == nil {
+				return fmt.Errorf("target #%d has an empty command", i+1)
+			}
+
+			if err := c.init(&defaults); err != nil {
+				return fmt.Errorf("target #%d: %w", i+1, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Commands returns the commands of all the targets matching the node with the given labels,
+// in the order they are defined in the spec.
+// The node name and kind are taken from the containerlab labels of the node.
+func (s *Spec) Commands(lbls map[string]string) []*SpecCmd {
+	var cmds []*SpecCmd
+
+	for _, t := range s.Targets {
+		if t.matches(lbls) {
+			cmds = append(cmds, t.Commands...)
+		}
+	}
+
+	return cmds
+}
+
+// matches returns true if the node with the given labels matches all the target selectors.
+func (t *SpecTarget) matches(lbls map[string]string) bool {
+	if len(t.Nodes) != 0 && !slices.Contains(t.Nodes, lbls[labels.NodeName]) {
+		return false
+	}
+
+	if len(t.Kinds) != 0 && !slices.Contains(t.Kinds, lbls[labels.NodeKind]) {
+		return false
+	}
+
+	for k, v := range t.Labels {
+		if lv, ok := lbls[k]; !ok || lv != v {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,144 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package exec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/labels"
+)
+
+const testSpec = `defaults:
+  timeout: 1m
+targets:
+  - commands:
+      - uptime
+  - kinds: [nokia_srlinux]
+    commands:
+      - sr_cli "show version"
+      - cmd: sr_cli "tools system tech-support"
+        timeout: 10m
+        retries: 2
+  - nodes: [srl1, client1]
+    labels:
+      role: spine
+    commands:
+      - cmd: ip -br a
+        retries: 1
+`
+
+func loadTestSpec(t *testing.T, data string, defaults CmdOptions) (*Spec, error) {
+	t.Helper()
+
+	f := filepath.Join(t.TempDir(), "spec.yml")
+	if err := os.WriteFile(f, []byte(data), 0644); err != nil { // skipcq: GSC-G306
+		t.Fatal(err)
+	}
+
+	return LoadSpec(f, defaults)
+}
+
+func TestLoadSpec(t *testing.T) {
+	retries := 5
+
+	s, err := loadTestSpec(t, testSpec, CmdOptions{Timeout: time.Second, Retries: &retries})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type cmd struct {
+		Cmd     []string
+		Timeout time.Duration
+		Retries int
+	}
+
+	tests := map[string]struct {
+		labels map[string]string
+		want   []cmd
+	}{
+		"srl1-spine": {
+			labels: map[string]string{labels.NodeName: "srl1", labels.NodeKind: "nokia_srlinux", "role": "spine"},
+			want: []cmd{
+				{[]string{"uptime"}, time.Minute, 5},
+				{[]string{"sr_cli", "show version"}, time.Minute, 5},
+				{[]string{"sr_cli", "tools system tech-support"}, 10 * time.Minute, 2},
+				{[]string{"ip", "-br", "a"}, time.Minute, 1},
+			},
+		},
+		"srl2-spine": {
+			labels: map[string]string{labels.NodeName: "srl2", labels.NodeKind: "nokia_srlinux", "role": "spine"},
+			want: []cmd{
+				{[]string{"uptime"}, time.Minute, 5},
+				{[]string{"sr_cli", "show version"}, time.Minute, 5},
+				{[]string{"sr_cli", "tools system tech-support"}, 10 * time.Minute, 2},
+			},
+		},
+		"client1-no-role": {
+			labels: map[string]string{labels.NodeName: "client1", labels.NodeKind: "linux"},
+			want: []cmd{
+				{[]string{"uptime"}, time.Minute, 5},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []cmd
+			for _, c := range s.Commands(tt.labels) {
+				got = append(got, cmd{c.ExecCmd().GetCmd(), c.Timeout, c.GetRetries()})
+			}
+
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("commands mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestLoadSpecErrors(t *testing.T) {
+	tests := map[string]struct {
+		data    string
+		wantErr string
+	}{
+		"no-targets": {
+			data:    "defaults:\n  retries: 1\n",
+			wantErr: "no targets defined",
+		},
+		"no-commands": {
+			data:    "targets:\n  - nodes: [srl1]\n",
+			wantErr: "target #1 has no commands",
+		},
+		"no-cmd": {
+			data:    "targets:\n  - commands:\n      - timeout: 1s\n",
+			wantErr: "command should have cmd set",
+		},
+		"negative-retries": {
+			data:    "targets:\n  - commands:\n      - {cmd: ls, retries: -1}\n",
+			wantErr: "timeout and retries can't be negative",
+		},
+		"unbalanced-quotes": {
+			data:    "targets:\n  - commands:\n      - sr_cli \"show version\n",
+			wantErr: "failed to parse command",
+		},
+		"unknown-field": {
+			data:    "targets:\n  - commands:\n      - {command: ls}\n",
+			wantErr: "field command not found",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := loadTestSpec(t, tt.data, CmdOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -1,9 +1,26 @@
 package clab
 
-import "github.com/srl-labs/containerlab/types"
+import (
+	"time"
+
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/types"
+)
 
 type ExecOptions struct {
 	filters []*types.GenericFilter
+	// spec maps the nodes to the commands executed on them in addition to the commands
+	// executed on all the nodes.
+	spec *exec.Spec
+	// cmdTimeout is the time a single attempt of a command is allowed to run for, zero means no timeout.
+	cmdTimeout time.Duration
+	// retries is the number of times a failed command is retried.
+	retries int
+	// maxWorkers is the maximum number of nodes the commands are executed on concurrently,
+	// zero means the number of online CPUs.
+	maxWorkers uint
+	// saveOutput indicates whether the results are written to the per node files in the lab directory.
+	saveOutput bool
 }
 
 func NewExecOptions(filters []*types.GenericFilter) *ExecOptions {
@@ -15,3 +32,34 @@ func NewExecOptions(filters []*types.GenericFilter) *ExecOptions {
 func (e *ExecOptions) AddFilters(f ...*types.GenericFilter) {
 	e.filters = append(e.filters, f...)
 }
+
+// SetSpec sets the exec spec and returns the updated ExecOptions instance.
+func (e *ExecOptions) SetSpec(s *exec.Spec) *ExecOptions {
+	e.spec = s
+	return e
+}
+
+// SetCmdTimeout sets the per command timeout and returns the updated ExecOptions instance.
+func (e *ExecOptions) SetCmdTimeout(d time.Duration) *ExecOptions {
+	e.cmdTimeout = d
+	return e
+}
+
+// SetRetries sets the number of retries of the failed commands and returns the updated ExecOptions instance.
+func (e *ExecOptions) SetRetries(i int) *ExecOptions {
+	e.retries = i
+	return e
+}
+
+// SetMaxWorkers sets the maximum number of nodes the commands are executed on concurrently
+// and returns the updated ExecOptions instance.
+func (e *ExecOptions) SetMaxWorkers(i uint) *ExecOptions {
+	e.maxWorkers = i
+	return e
+}
+
+// SetSaveOutput sets the saveOutput option and returns the updated ExecOptions instance.
+func (e *ExecOptions) SetSaveOutput(b bool) *ExecOptions {
+	e.saveOutput = b
+	return e
+}
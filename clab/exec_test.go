@@ -0,0 +1,212 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/clab/exec"
+)
+
+func TestExecTargets(t *testing.T) {
+	execRetryInterval = time.Millisecond
+	defer func() { execRetryInterval = time.Second }()
+
+	newCmd := func(cmd string, timeout time.Duration, retries int) *exec.SpecCmd {
+		c, err := exec.NewSpecCmd(cmd, exec.CmdOptions{Timeout: timeout, Retries: &retries})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return c
+	}
+
+	calls := map[string]int{}
+
+	// run fails the first attempts of the flaky commands and blocks the slow one until it times out
+	run := func(ctx context.Context, cmd *exec.ExecCmd) (*exec.ExecResult, error) {
+		s := cmd.GetCmdString()
+		calls[s]++
+
+		res := exec.NewExecResult(cmd)
+
+		switch s {
+		case "show tech":
+			if calls[s] < 3 {
+				res.SetReturnCode(1)
+				res.SetStdErr([]byte("not ready"))
+
+				return res, nil
+			}
+		case "flaky":
+			if calls[s] < 2 {
+				return nil, errors.New("connection reset")
+			}
+		case "sleep":
+			<-ctx.Done()
+			return nil, ctx.Err()
+		case "false":
+			res.SetReturnCode(1)
+			return res, nil
+		}
+
+		res.SetStdOut([]byte("output of " + s))
+
+		return res, nil
+	}
+
+	labDir := t.TempDir()
+
+	tgt := &execTarget{
+		name:   "clab-test-node1",
+		node:   "node1",
+		labDir: labDir,
+		cmds: []*exec.SpecCmd{
+			newCmd("show tech", 0, 2),
+			newCmd("flaky", 0, 1),
+			newCmd("sleep", 10*time.Millisecond, 1),
+			newCmd("false", 0, 1),
+		},
+		run: run,
+	}
+
+	c := &CLab{}
+
+	coll, err := c.runExecTargets(context.Background(), []*execTarget{tgt}, NewExecOptions(nil).SetSaveOutput(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d := cmp.Diff(map[string]int{"show tech": 3, "flaky": 2, "sleep": 2, "false": 2}, calls); d != "" {
+		t.Errorf("attempts mismatch (-want +got):\n%s", d)
+	}
+
+	out, err := coll.Dump(exec.ExecFormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dumped map[string][]*exec.ExecResult
+	if err := json.Unmarshal([]byte(out), &dumped); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dumped[tgt.name]) != 3 {
+		t.Fatalf("expected 3 results of %s, got %s", tgt.name, out)
+	}
+
+	runDirs, err := filepath.Glob(filepath.Join(labDir, execOutputDir, "*", "node1"))
+	if err != nil || len(runDirs) != 1 {
+		t.Fatalf("expected a single exec output directory, got %v: %v", runDirs, err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(runDirs[0], "results.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var results []*execTargetResult
+	if err := json.Unmarshal(b, &results); err != nil {
+		t.Fatal(err)
+	}
+
+	zero, one := 0, 1
+
+	want := []*execTargetResult{
+		{Cmd: "show tech", ReturnCode: &zero, Attempts: 3, StdoutFile: "01-show-tech.txt"},
+		{Cmd: "flaky", ReturnCode: &zero, Attempts: 2, StdoutFile: "02-flaky.txt"},
+		{Cmd: "sleep", Attempts: 2, Error: "timed out after 10ms"},
+		{Cmd: "false", ReturnCode: &one, Attempts: 2, StdoutFile: "04-false.txt"},
+	}
+
+	if d := cmp.Diff(want, results, cmp.AllowUnexported(execTargetResult{})); d != "" {
+		t.Errorf("saved results mismatch (-want +got):\n%s", d)
+	}
+
+	stdout, err := os.ReadFile(filepath.Join(runDirs[0], "01-show-tech.txt"))
+	if err != nil || string(stdout) != "output of show tech" {
+		t.Errorf("unexpected stdout file content %q: %v", stdout, err)
+	}
+
+	for _, f := range []string{"results.json", "01-show-tech.txt", "04-false.txt"} {
+		fi, err := os.Stat(filepath.Join(runDirs[0], f))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if fi.Mode().Perm() != execOutputFileMode {
+			t.Errorf("expected mode %o of %s, got %o", execOutputFileMode, f, fi.Mode().Perm())
+		}
+	}
+}
+
+func TestExecTargetNotSupported(t *testing.T) {
+	newCmd := func(cmd string) *exec.SpecCmd {
+		c, err := exec.NewSpecCmd(cmd, exec.CmdOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return c
+	}
+
+	var calls []string
+
+	tgt := &execTarget{
+		name: "clab-test-node1",
+		node: "node1",
+		cmds: []*exec.SpecCmd{newCmd("uptime"), newCmd("unsupported"), newCmd("hostname")},
+		run: func(_ context.Context, cmd *exec.ExecCmd) (*exec.ExecResult, error) {
+			calls = append(calls, cmd.GetCmdString())
+
+			if cmd.GetCmdString() == "unsupported" {
+				return nil, exec.ErrRunExecNotSupported
+			}
+
+			return exec.NewExecResult(cmd), nil
+		},
+	}
+
+	results := tgt.exec(context.Background(), exec.NewExecCollection())
+
+	if d := cmp.Diff([]string{"uptime", "unsupported"}, calls); d != "" {
+		t.Errorf("executed commands mismatch (-want +got):\n%s", d)
+	}
+
+	if len(results) != 1 || results[0].Cmd != "uptime" {
+		t.Errorf("expected the result of the command executed before the unsupported one, got %+v", results)
+	}
+}
+
+func TestExecOutputFileName(t *testing.T) {
+	tests := map[string]struct {
+		i      int
+		cmd    string
+		suffix string
+		want   string
+	}{
+		"simple":  {i: 0, cmd: "uptime", suffix: ".txt", want: "01-uptime.txt"},
+		"quoted":  {i: 9, cmd: `sr_cli "show version | as json"`, suffix: ".txt", want: "10-sr_cli-show-version-as-json.txt"},
+		"stderr":  {i: 1, cmd: "ip -br a", suffix: ".stderr.txt", want: "02-ip-br-a.stderr.txt"},
+		"path":    {i: 2, cmd: "cat /etc/os-release", suffix: ".txt", want: "03-cat-etc-os-release.txt"},
+		"long":    {i: 0, cmd: "echo aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", suffix: ".txt", want: "01-echo-aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa.txt"},
+		"symbols": {i: 0, cmd: "|| ..", suffix: ".txt", want: "01-cmd.txt"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := execOutputFileName(tt.i, tt.cmd, tt.suffix); got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -40,6 +40,8 @@ type TopologyExport struct {
 	Type        string                       `json:"type"`
 	Clab        *CLab                        `json:"clab,omitempty"`
 	NodeConfigs map[string]*types.NodeConfig `json:"nodeconfigs,omitempty"`
+	// Links are the lab links with the runtime details of their endpoints.
+	Links []*LinkExport `json:"links,omitempty"`
 }
 
 //go:embed export_templates/auto.tmpl
@@ -49,7 +51,7 @@ var defaultExportTemplate string
 var fullExportTemplate string
 
 // exportTopologyDataWithTemplate generates and writes topology data file to w using a template.
-func (c *CLab) exportTopologyDataWithTemplate(_ context.Context, w io.Writer, p string) error {
+func (c *CLab) exportTopologyDataWithTemplate(ctx context.Context, w io.Writer, p string) error {
 	name := "export"
 	if p != "" {
 		name = filepath.Base(p)
@@ -88,6 +90,7 @@ func (c *CLab) exportTopologyDataWithTemplate(_ context.Context, w io.Writer, p
 		Type:        "clab",
 		Clab:        c,
 		NodeConfigs: make(map[string]*types.NodeConfig),
+		Links:       c.linkExports(ctx),
 	}
 
 	for _, n := range c.Nodes {
@@ -0,0 +1,133 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/nodes/state"
+	"github.com/vishvananda/netlink"
+)
+
+// LinkExport is the exported representation of a deployed link.
+type LinkExport struct {
+	Type  string           `json:"type"`
+	MTU   int              `json:"mtu,omitempty"`
+	A     *EndpointExport  `json:"a"`
+	Z     *EndpointExport  `json:"z"`
+	Vxlan *VxlanLinkExport `json:"vxlan,omitempty"`
+}
+
+// EndpointExport is the exported representation of a link endpoint
+// with the runtime details of its interface.
+type EndpointExport struct {
+	Node      string `json:"node"`
+	Interface string `json:"interface"`
+	Alias     string `json:"alias,omitempty"`
+	MAC       string `json:"mac"`
+	MTU       int    `json:"mtu,omitempty"`
+	IPv4      string `json:"ipv4,omitempty"`
+	IPv6      string `json:"ipv6,omitempty"`
+	// IfIndex is the index of the interface in the network namespace it resides in.
+	IfIndex int `json:"ifindex,omitempty"`
+	// NetNSPath is the path of the network namespace the interface resides in.
+	NetNSPath string `json:"netns-path,omitempty"`
+	Peer      string `json:"peer"`
+}
+
+// VxlanLinkExport holds the tunnel parameters of the vxlan links.
+type VxlanLinkExport struct {
+	Remote          string `json:"remote"`
+	VNI             int    `json:"vni"`
+	UDPPort         int    `json:"udp-port"`
+	ParentInterface string `json:"parent-interface"`
+}
+
+// linkExports returns the exported representation of the lab links ordered by the link index.
+// The runtime details of the endpoint interfaces are gathered from the deployed nodes.
+func (c *CLab) linkExports(ctx context.Context) []*LinkExport {
+	idxs := c.sortedLinkIndexes()
+	exports := make([]*LinkExport, 0, len(idxs))
+
+	for _, i := range idxs {
+		l := c.Links[i]
+
+		eps := l.GetEndpoints()
+		if len(eps) != 2 {
+			continue
+		}
+
+		le := &LinkExport{
+			Type: string(l.GetType()),
+			MTU:  l.GetMTU(),
+			A:    endpointExport(ctx, eps[0], "z"),
+			Z:    endpointExport(ctx, eps[1], "a"),
+		}
+
+		for _, ep := range eps {
+			if vxlanEp, ok := ep.(*links.EndpointVxlan); ok {
+				le.Vxlan = &VxlanLinkExport{
+					Remote:          vxlanEp.GetRemote().String(),
+					VNI:             vxlanEp.GetVNI(),
+					UDPPort:         vxlanEp.GetUDPPort(),
+					ParentInterface: vxlanEp.GetParentIface(),
+				}
+			}
+		}
+
+		exports = append(exports, le)
+	}
+
+	return exports
+}
+
+// endpointExport returns the exported representation of the endpoint.
+// The MAC address, MTU and the interface index are taken from the deployed interface when available.
+func endpointExport(ctx context.Context, ep links.Endpoint, peer string) *EndpointExport {
+	e := &EndpointExport{
+		Node:      ep.GetNode().GetShortName(),
+		Interface: ep.GetIfaceName(),
+		Alias:     ep.GetIfaceAlias(),
+		MAC:       ep.GetMac().String(),
+		Peer:      peer,
+	}
+
+	if ep.GetIPv4Addr().IsValid() {
+		e.IPv4 = ep.GetIPv4Addr().String()
+	}
+
+	if ep.GetIPv6Addr().IsValid() {
+		e.IPv6 = ep.GetIPv6Addr().String()
+	}
+
+	if ep.GetIfaceName() == "" || ep.GetNode().GetState() != state.Deployed {
+		return e
+	}
+
+	err := ep.GetNode().ExecFunction(ctx, func(netns ns.NetNS) error {
+		l, err := netlink.LinkByName(ep.GetIfaceName())
+		if err != nil {
+			return err
+		}
+
+		e.IfIndex = l.Attrs().Index
+		e.NetNSPath = netns.Path()
+		e.MTU = l.Attrs().MTU
+
+		if mac := l.Attrs().HardwareAddr; len(mac) != 0 {
+			e.MAC = mac.String()
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Debugf("failed to get the runtime details of the endpoint %s: %v", ep, err)
+	}
+
+	return e
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExportLinks(t *testing.T) {
+	c, err := NewContainerLab(WithTopoPath("test_data/topo12.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ResolveLinks(); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := c.exportTopologyDataWithTemplate(context.Background(), &b, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	var export struct {
+		Links []*LinkExport `json:"links"`
+	}
+
+	if err := json.Unmarshal(b.Bytes(), &export); err != nil {
+		t.Fatalf("exported topology data is not valid JSON: %v\n%s", err, b.String())
+	}
+
+	if len(export.Links) != 2 {
+		t.Fatalf("expected 2 links, got %d", len(export.Links))
+	}
+
+	for _, l := range export.Links {
+		// the MAC addresses are generated, they are only checked to be set
+		if l.A.MAC == "" || l.Z.MAC == "" {
+			t.Errorf("expected the MAC addresses of the link endpoints to be set: %+v %+v", l.A, l.Z)
+		}
+
+		l.A.MAC, l.Z.MAC = "", ""
+	}
+
+	want := &LinkExport{
+		Type: "veth",
+		MTU:  9500,
+		A:    &EndpointExport{Node: "node1", Interface: "eth1", Peer: "z"},
+		Z:    &EndpointExport{Node: "node2", Interface: "eth1", Peer: "a"},
+	}
+
+	if diff := cmp.Diff(want, export.Links[0]); diff != "" {
+		t.Errorf("link diff: (-want +got)\n%s", diff)
+	}
+}
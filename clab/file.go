@@ -38,6 +38,10 @@ func (c *CLab) LoadTopologyFromFile(topo, varsFile string) error {
 		return err
 	}
 
+	if c.untrustedDir != "" {
+		return c.loadUntrustedTopology()
+	}
+
 	// load the topology file/template
 	topologyTemplate, err := template.New(c.TopoPaths.TopologyFilenameBase()).
 		Funcs(gomplate.CreateFuncs(context.Background(), new(data.Data))).
@@ -86,6 +90,22 @@ func (c *CLab) LoadTopologyFromFile(topo, varsFile string) error {
 	return nil
 }
 
+// loadUntrustedTopology loads the untrusted topology file as is,
+// since the template functions and the env vars expansion read the host files and the environment of containerlab.
+func (c *CLab) loadUntrustedTopology() error {
+	b, err := os.ReadFile(c.TopoPaths.TopologyFilenameAbsPath())
+	if err != nil {
+		return err
+	}
+
+	err = yaml.UnmarshalStrict(b, c.Config)
+	if err != nil {
+		return fmt.Errorf("%w\nConsult with release notes to see if any fields were changed/removed", err)
+	}
+
+	return nil
+}
+
 func readTemplateVariables(topo, varsFile string) (interface{}, error) {
 	var templateVars interface{}
 	// variable file is not explicitly set
@@ -10,12 +10,14 @@ import (
 	"html/template"
 	"io"
 	"io/fs"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/awalterschulze/gographviz"
 	"github.com/creack/pty"
@@ -45,6 +47,10 @@ type Link struct {
 type TopoData struct {
 	Name string
 	Data template.JS
+	// Live enables the live status updates of the topology view.
+	Live bool
+	// Edit enables the topology editor that writes the edited topology back to the topology file.
+	Edit bool
 }
 
 // noListFs embeds the http.Dir to override the Open method of a filesystem
@@ -55,7 +61,9 @@ type noListFs struct {
 
 var g *gographviz.Graph
 
-// GenerateDotGraph generates a graph of the lab topology.
+// GenerateDotGraph generates a graph of the lab topology in dot format
+// along with its SVG and PNG renderings. The renderings are made by Graphviz
+// when the dot command is available, and by the native diagram renderer otherwise.
 func (c *CLab) GenerateDotGraph() error {
 	log.Info("Generating lab graph...")
 	g = gographviz.NewGraph()
@@ -124,26 +132,31 @@ func (c *CLab) GenerateDotGraph() error {
 	utils.CreateFile(dotfile, g.String())
 	log.Infof("Created %s", dotfile)
 
-	pngfile := c.TopoPaths.GraphFilename(".png")
+	if !commandExists("dot") {
+		return c.GenerateDiagrams(DiagramFormatSVG, DiagramFormatPNG)
+	}
 
-	// Only try to create png
-	if commandExists("dot") {
-		err := generatePngFromDot(dotfile, pngfile)
-		if err != nil {
+	for _, format := range []string{DiagramFormatSVG, DiagramFormatPNG} {
+		outfile := c.TopoPaths.GraphFilename("." + format)
+
+		if err := renderDot(dotfile, outfile, format); err != nil {
 			return err
 		}
-		log.Info("Created ", pngfile)
+
+		log.Infof("Created %s", outfile)
 	}
+
 	return nil
 }
 
-// generatePngFromDot generated PNG from the provided dot file.
-func generatePngFromDot(dotfile string, outfile string) (err error) {
-	_, err = exec.Command("dot", "-o", outfile, "-Tpng", dotfile).CombinedOutput()
+// renderDot renders the dot file in the given format with Graphviz.
+func renderDot(dotfile, outfile, format string) error {
+	out, err := exec.Command("dot", "-o", outfile, "-T"+format, dotfile).CombinedOutput()
 	if err != nil {
-		log.Errorf("failed to generate png (%v) from dot file (%v), with error (%v)", outfile, dotfile, err)
-		return fmt.Errorf("failed to generate png (%v) from dot file (%v), with error (%v)", outfile, dotfile, err)
+		return fmt.Errorf("failed to generate %s (%v) from dot file (%v), with error (%v): %s",
+			format, outfile, dotfile, err, out)
 	}
+
 	return nil
 }
 
@@ -230,10 +243,15 @@ func (c *CLab) GenerateMermaidGraph(direction string) error {
 		return err
 	}
 
+	nodes, lnks := c.graphElements()
+
+	for _, n := range nodes {
+		fc.AddNode(n.name, n.group)
+	}
+
 	// Process the links between Nodes
-	for _, link := range c.Links {
-		eps := link.GetEndpoints()
-		fc.AddEdge(eps[0].GetNode().GetShortName(), eps[1].GetNode().GetShortName())
+	for _, l := range lnks {
+		fc.AddEdge(l.nodeA, l.ifaceA, l.nodeB, l.ifaceB)
 	}
 
 	// create graph directory
@@ -259,7 +277,10 @@ var defaultTemplate string
 //go:embed graph_templates/nextui/static
 var defaultStatic embed.FS
 
-func (c *CLab) ServeTopoGraph(tmpl, staticDir, srv string, topoD TopoData) error {
+// ServeTopoGraph serves the topology view on srv.
+// When the live updates are enabled, the status of the lab nodes and links
+// is pushed to the view over WebSocket every updateInterval.
+func (c *CLab) ServeTopoGraph(tmpl, staticDir, srv string, topoD TopoData, updateInterval time.Duration) error {
 	var t *template.Template
 
 	if tmpl == "" {
@@ -291,6 +312,27 @@ func (c *CLab) ServeTopoGraph(tmpl, staticDir, srv string, topoD TopoData) error
 	fs := http.FileServer(noListFs{staticFS})
 	http.Handle("/static/", http.StripPrefix("/static/", fs))
 
+	if topoD.Live {
+		http.Handle(graphStatusPath, c.graphStatusHandler(updateInterval))
+		http.Handle(graphNodesPath, c.graphNodeHandler())
+	}
+
+	if topoD.Edit {
+		// the editor writes to the topology file without authentication,
+		// so it is only reachable from the host it runs on
+		host, _, err := net.SplitHostPort(srv)
+		if err != nil {
+			return err
+		}
+
+		if !isLoopbackHost(host) {
+			return fmt.Errorf("the topology editor can only be served on a loopback address, e.g. --srv %s",
+				DefaultGraphEditorAddress)
+		}
+
+		http.Handle(graphEditorPath, newGraphEditor(c))
+	}
+
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		_ = t.Execute(w, topoD)
 	})
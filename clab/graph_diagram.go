@@ -0,0 +1,124 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/internal/diagram"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// diagram formats rendered without the external tools.
+const (
+	DiagramFormatSVG = "svg"
+	DiagramFormatPNG = "png"
+)
+
+// GenerateDiagrams renders the diagram of the lab topology in the given formats to the graph directory.
+func (c *CLab) GenerateDiagrams(formats ...string) error {
+	d := c.buildDiagram()
+	d.Layout()
+
+	utils.CreateDirectory(c.TopoPaths.TopologyLabDir(), 0755)
+	utils.CreateDirectory(c.TopoPaths.GraphDir(), 0755)
+
+	for _, format := range formats {
+		var render func(io.Writer) error
+
+		switch format {
+		case DiagramFormatSVG:
+			render = d.RenderSVG
+		case DiagramFormatPNG:
+			render = d.RenderPNG
+		default:
+			return fmt.Errorf("unsupported diagram format %q", format)
+		}
+
+		fname := c.TopoPaths.GraphFilename("." + format)
+
+		f, err := os.Create(fname)
+		if err != nil {
+			return err
+		}
+
+		err = render(f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to render the %s diagram: %w", format, err)
+		}
+
+		log.Infof("Created %s", fname)
+	}
+
+	return nil
+}
+
+// buildDiagram returns the diagram of the lab nodes and links.
+// The nodes with the position set keep it in the diagram,
+// the host and other special link nodes are added as the nodes without a group.
+func (c *CLab) buildDiagram() *diagram.Diagram {
+	d := diagram.New(c.Config.Name)
+
+	nodes, lnks := c.graphElements()
+
+	for _, gn := range nodes {
+		n := &diagram.Node{
+			Name:  gn.name,
+			Group: gn.group,
+			Kind:  gn.kind,
+		}
+
+		if gn.position != "" {
+			x, y, err := parsePosition(gn.position)
+			if err != nil {
+				log.Warnf("ignoring the position of node %q: %v", gn.name, err)
+			} else {
+				n.X, n.Y, n.Fixed = x, y, true
+			}
+		}
+
+		d.AddNode(n)
+	}
+
+	for _, l := range lnks {
+		d.AddEdge(&diagram.Edge{
+			Source:      l.nodeA,
+			Target:      l.nodeB,
+			SourceLabel: l.ifaceA,
+			TargetLabel: l.ifaceB,
+		})
+	}
+
+	return d
+}
+
+// parsePosition parses the node position in the "x,y" format.
+func parsePosition(p string) (float64, float64, error) {
+	xs, ys, found := strings.Cut(p, ",")
+	if !found {
+		return 0, 0, fmt.Errorf("position %q is not in the x,y format", p)
+	}
+
+	x, err := strconv.ParseFloat(strings.TrimSpace(xs), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid x coordinate of position %q: %w", p, err)
+	}
+
+	y, err := strconv.ParseFloat(strings.TrimSpace(ys), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid y coordinate of position %q: %w", p, err)
+	}
+
+	return x, y, nil
+}
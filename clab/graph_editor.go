@@ -0,0 +1,637 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/links"
+	yamlv2 "gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
+)
+
+// graphEditorPath is the path prefix of the topology editor API.
+const graphEditorPath = "/api/editor/"
+
+// DefaultGraphEditorAddress is the address the topology view is served at when the editor is enabled.
+const DefaultGraphEditorAddress = "127.0.0.1:50080"
+
+// EditorKind is a node kind the nodes can be added with in the topology editor.
+type EditorKind struct {
+	Name string `json:"name"`
+	// InterfacePattern is the regular expression the interface names of the kind have to match.
+	InterfacePattern string `json:"interface_pattern,omitempty"`
+}
+
+// EditorNode is a node of the topology edited in the topology editor.
+type EditorNode struct {
+	Name  string `json:"name"`
+	Kind  string `json:"kind"`
+	Image string `json:"image,omitempty"`
+	Group string `json:"group,omitempty"`
+	// Position is the position of the node in the x,y format.
+	Position string `json:"position,omitempty"`
+}
+
+// EditorTopology is the topology edited in the topology editor.
+// Only the links connecting the lab nodes are edited,
+// the other links are kept in the topology file as long as their nodes exist.
+type EditorTopology struct {
+	Name  string        `json:"name"`
+	Nodes []*EditorNode `json:"nodes"`
+	Links []Link        `json:"links"`
+}
+
+// graphEditor serves the topology editor API and writes the edited topology back to the topology file.
+type graphEditor struct {
+	c  *CLab
+	mu sync.Mutex
+	// topo is the topology as it was last loaded or saved,
+	// only the node attributes changed since then are written to the topology file.
+	topo *EditorTopology
+}
+
+func newGraphEditor(c *CLab) *graphEditor {
+	return &graphEditor{c: c, topo: c.editorTopology()}
+}
+
+// topology returns the topology as it was last loaded or saved.
+func (e *graphEditor) topology() *EditorTopology {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.topo
+}
+
+// editorTopology returns the lab topology in the editor representation.
+func (c *CLab) editorTopology() *EditorTopology {
+	t := &EditorTopology{
+		Name:  c.Config.Name,
+		Nodes: []*EditorNode{},
+		Links: []Link{},
+	}
+
+	for _, name := range c.sortedNodeNames() {
+		cfg := c.Nodes[name].Config()
+		t.Nodes = append(t.Nodes, &EditorNode{
+			Name:     name,
+			Kind:     cfg.Kind,
+			Image:    cfg.Image,
+			Group:    cfg.Group,
+			Position: cfg.Position,
+		})
+	}
+
+	for _, i := range c.sortedLinkIndexes() {
+		eps := c.Links[i].GetEndpoints()
+		if len(eps) != 2 {
+			continue
+		}
+
+		_, okA := c.Nodes[eps[0].GetNode().GetShortName()]
+		_, okZ := c.Nodes[eps[1].GetNode().GetShortName()]
+
+		if !okA || !okZ {
+			continue
+		}
+
+		t.Links = append(t.Links, Link{
+			Source:         eps[0].GetNode().GetShortName(),
+			SourceEndpoint: eps[0].GetIfaceDisplayName(),
+			Target:         eps[1].GetNode().GetShortName(),
+			TargetEndpoint: eps[1].GetIfaceDisplayName(),
+		})
+	}
+
+	return t
+}
+
+// editorKinds returns the registered node kinds with their interface patterns.
+func (c *CLab) editorKinds() []EditorKind {
+	var kinds []EditorKind
+
+	for _, k := range c.Reg.GetRegisteredNodeKindNames() {
+		ek := EditorKind{Name: k}
+		if re := c.Reg.Kind(k).GetInterfaceRegexp(); re != nil {
+			ek.InterfacePattern = re.String()
+		}
+
+		kinds = append(kinds, ek)
+	}
+
+	return kinds
+}
+
+// validateEditorTopology checks that the nodes of the edited topology are of the registered kinds
+// and that the links connect the existing nodes with the interface names valid for their kinds.
+func (c *CLab) validateEditorTopology(t *EditorTopology) error {
+	nodes := map[string]*EditorNode{}
+
+	for _, n := range t.Nodes {
+		if n.Name == "" || strings.ContainsAny(n.Name, ": \t") {
+			return fmt.Errorf("invalid node name %q", n.Name)
+		}
+
+		if _, exists := nodes[n.Name]; exists {
+			return fmt.Errorf("duplicate node name %q", n.Name)
+		}
+
+		if c.Reg.Kind(n.Kind) == nil {
+			return fmt.Errorf("node %q has an unknown kind %q", n.Name, n.Kind)
+		}
+
+		if n.Position != "" {
+			if _, _, err := parsePosition(n.Position); err != nil {
+				return fmt.Errorf("node %q: %w", n.Name, err)
+			}
+		}
+
+		nodes[n.Name] = n
+	}
+
+	endpoints := map[string]struct{}{}
+
+	for _, l := range t.Links {
+		for _, ep := range [][2]string{{l.Source, l.SourceEndpoint}, {l.Target, l.TargetEndpoint}} {
+			n, ok := nodes[ep[0]]
+			if !ok {
+				return fmt.Errorf("link %s:%s - %s:%s refers to an unknown node %q",
+					l.Source, l.SourceEndpoint, l.Target, l.TargetEndpoint, ep[0])
+			}
+
+			if ep[1] == "" {
+				return fmt.Errorf("link of node %q has an empty interface name", ep[0])
+			}
+
+			if err := c.Reg.Kind(n.Kind).CheckInterfaceName(ep[1]); err != nil {
+				return fmt.Errorf("node %q of kind %q: %w", ep[0], n.Kind, err)
+			}
+
+			key := ep[0] + ":" + ep[1]
+			if _, exists := endpoints[key]; exists {
+				return fmt.Errorf("endpoint %s is used by more than one link", key)
+			}
+
+			endpoints[key] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// save validates the edited topology and writes it back to the topology file.
+func (e *graphEditor) save(t *EditorTopology) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.c.validateEditorTopology(t); err != nil {
+		return err
+	}
+
+	path := e.c.TopoPaths.TopologyFilenameAbsPath()
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if bytes.Contains(raw, []byte("{{")) {
+		return fmt.Errorf("topology file %s is a template and can't be edited", path)
+	}
+
+	b, err := editTopologyYAML(raw, t, editorNodesByName(e.topo.Nodes))
+	if err != nil {
+		return err
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	// the original topology is kept in the backup file in case the edit is not wanted
+	bakPath := e.c.TopoPaths.TopologyEditBakFileAbsPath()
+	if err := os.WriteFile(bakPath, raw, fi.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to back up the topology file to %s: %w", bakPath, err)
+	}
+
+	err = os.WriteFile(path, b, fi.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	t.Name = e.topo.Name
+	e.topo = t
+
+	log.Infof("Saved the edited topology to %s, the previous version is backed up to %s", path, bakPath)
+
+	return nil
+}
+
+// editTopologyYAML applies the edited topology to the topology file content.
+// The nodes and their attributes not managed by the editor are kept as is,
+// the node attributes are only set when they were changed compared to the base nodes.
+// The document is edited as a YAML node tree, so that the comments and anchors are kept.
+func editTopologyYAML(raw []byte, t *EditorTopology, base map[string]*EditorNode) ([]byte, error) {
+	var doc yaml.Node
+
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("topology file is not a YAML mapping")
+	}
+
+	topo := ownMapping(yamlMappingGet(doc.Content[0], "topology"))
+	yamlMappingSet(doc.Content[0], "topology", topo)
+
+	nodes := ownMapping(yamlMappingGet(topo, "nodes"))
+	yamlMappingSet(topo, "nodes", nodes)
+
+	edited := editorNodesByName(t.Nodes)
+	existing := map[string]bool{}
+
+	var content []*yaml.Node
+
+	for i := 0; i+1 < len(nodes.Content); i += 2 {
+		k, v := nodes.Content[i], nodes.Content[i+1]
+		existing[k.Value] = true
+
+		n, ok := edited[k.Value]
+		if !ok {
+			continue
+		}
+
+		content = append(content, k, editNodeYAML(v, n, base[k.Value]))
+	}
+
+	for _, n := range t.Nodes {
+		if !existing[n.Name] {
+			content = append(content, yamlScalar(n.Name), editNodeYAML(nil, n, nil))
+		}
+	}
+
+	nodes.Content = content
+
+	lnks, err := editLinksYAML(yamlMappingGet(topo, "links"), t, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lnks) > 0 {
+		seq := yamlMappingGet(topo, "links")
+		if seq == nil || seq.Kind != yaml.SequenceNode {
+			seq = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			yamlMappingSet(topo, "links", seq)
+		}
+
+		seq.Content = lnks
+	} else {
+		yamlMappingDelete(topo, "links")
+	}
+
+	var buf bytes.Buffer
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// editNodeYAML sets the node attributes managed by the editor that differ from the base node.
+// It returns the node definition, which is a new mapping when the node was defined with an alias or empty.
+func editNodeYAML(v *yaml.Node, n, base *EditorNode) *yaml.Node {
+	if base == nil {
+		base = &EditorNode{}
+	}
+
+	if v == nil {
+		v = ownMapping(nil)
+	}
+
+	for _, a := range [][3]string{
+		{"kind", n.Kind, base.Kind},
+		{"image", n.Image, base.Image},
+		{"group", n.Group, base.Group},
+		{"position", n.Position, base.Position},
+	} {
+		if a[1] == a[2] {
+			continue
+		}
+
+		v = ownMapping(v)
+
+		if a[1] == "" {
+			yamlMappingDelete(v, a[0])
+		} else {
+			yamlMappingSet(v, a[0], yamlScalar(a[1]))
+		}
+	}
+
+	return v
+}
+
+// editLinksYAML returns the links of the edited topology.
+// The links between the existing nodes are kept in their original form when they are still present
+// in the edited topology, the new links are added in the brief format.
+// The links of the other types are kept unless they refer to a deleted node.
+func editLinksYAML(v *yaml.Node, t *EditorTopology, existing map[string]bool) ([]*yaml.Node, error) {
+	var old []*yaml.Node
+	if v != nil && v.Kind == yaml.SequenceNode {
+		old = v.Content
+	}
+
+	nodes := editorNodesByName(t.Nodes)
+
+	wanted := map[string]bool{}
+	for _, l := range t.Links {
+		wanted[editorLinkKey(l.Source, l.SourceEndpoint, l.Target, l.TargetEndpoint)] = true
+	}
+
+	var lnks []*yaml.Node
+
+	kept := map[string]bool{}
+
+	for _, item := range old {
+		// the aliases are resolved by decoding the link, the link definition is parsed by the yaml.v2 decoder
+		var raw interface{}
+		if err := item.Decode(&raw); err != nil {
+			return nil, err
+		}
+
+		b, err := yamlv2.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		var ld links.LinkDefinition
+		if err := yamlv2.Unmarshal(b, &ld); err != nil {
+			return nil, err
+		}
+
+		veth, ok := ld.Link.(*links.LinkVEthRaw)
+		if !ok {
+			if !linkRefersToDeletedNode(b, existing, nodes) {
+				lnks = append(lnks, item)
+			}
+
+			continue
+		}
+
+		if len(veth.Endpoints) != 2 {
+			lnks = append(lnks, item)
+			continue
+		}
+
+		k := editorLinkKey(veth.Endpoints[0].Node, veth.Endpoints[0].Iface,
+			veth.Endpoints[1].Node, veth.Endpoints[1].Iface)
+		if wanted[k] && !kept[k] {
+			kept[k] = true
+			lnks = append(lnks, item)
+		}
+	}
+
+	for _, l := range t.Links {
+		k := editorLinkKey(l.Source, l.SourceEndpoint, l.Target, l.TargetEndpoint)
+		if kept[k] {
+			continue
+		}
+
+		kept[k] = true
+
+		eps := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		for _, ep := range []string{l.Source + ":" + l.SourceEndpoint, l.Target + ":" + l.TargetEndpoint} {
+			n := yamlScalar(ep)
+			n.Style = yaml.DoubleQuotedStyle
+			eps.Content = append(eps.Content, n)
+		}
+
+		lnks = append(lnks, &yaml.Node{
+			Kind:    yaml.MappingNode,
+			Tag:     "!!map",
+			Content: []*yaml.Node{yamlScalar("endpoints"), eps},
+		})
+	}
+
+	return lnks, nil
+}
+
+// linkRefersToDeletedNode checks if any of the node names the link definition refers to
+// belongs to a node deleted in the editor.
+func linkRefersToDeletedNode(b []byte, existing map[string]bool, nodes map[string]*EditorNode) bool {
+	// the non-veth links refer to a single node either in the brief or in the extended format
+	var l struct {
+		Endpoints []interface{} `yaml:"endpoints"`
+		Endpoint  struct {
+			Node string `yaml:"node"`
+		} `yaml:"endpoint"`
+	}
+
+	if err := yamlv2.Unmarshal(b, &l); err != nil {
+		return false
+	}
+
+	names := []string{l.Endpoint.Node}
+
+	for _, ep := range l.Endpoints {
+		if s, ok := ep.(string); ok {
+			names = append(names, strings.SplitN(s, ":", 2)[0])
+		}
+	}
+
+	for _, name := range names {
+		if _, ok := nodes[name]; existing[name] && !ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// editorLinkKey returns the key identifying the link regardless of the order of its endpoints.
+func editorLinkKey(nodeA, ifaceA, nodeZ, ifaceZ string) string {
+	eps := []string{nodeA + ":" + ifaceA, nodeZ + ":" + ifaceZ}
+	sort.Strings(eps)
+
+	return strings.Join(eps, " ")
+}
+
+func editorNodesByName(nodes []*EditorNode) map[string]*EditorNode {
+	m := make(map[string]*EditorNode, len(nodes))
+	for _, n := range nodes {
+		m[n.Name] = n
+	}
+
+	return m
+}
+
+// yamlMappingGet returns the value of the key of the mapping, nil if the key is not set.
+func yamlMappingGet(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// yamlMappingSet sets the value of the key of the mapping, appending the key when it is not set.
+// The comments of the replaced value are kept.
+func yamlMappingSet(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			old := m.Content[i+1]
+			if old != value {
+				value.HeadComment, value.LineComment, value.FootComment =
+					old.HeadComment, old.LineComment, old.FootComment
+				m.Content[i+1] = value
+			}
+
+			return
+		}
+	}
+
+	m.Content = append(m.Content, yamlScalar(key), value)
+}
+
+// yamlMappingDelete removes the key from the mapping.
+func yamlMappingDelete(m *yaml.Node, key string) {
+	if m == nil {
+		return
+	}
+
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// ownMapping returns the mapping the values can be set in without affecting the other parts
+// of the document: the aliased mappings are merged into a new mapping, so that the anchored
+// values are kept intact, and the empty values are replaced with a new mapping.
+func ownMapping(v *yaml.Node) *yaml.Node {
+	switch {
+	case v == nil || v.Kind == yaml.ScalarNode && v.Tag == "!!null":
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	case v.Kind == yaml.AliasNode:
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{{Kind: yaml.ScalarNode, Tag: "!!merge", Value: "<<"}, v}}
+	}
+
+	return v
+}
+
+// yamlScalar returns the string scalar node.
+func yamlScalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// ServeHTTP serves the topology editor API:
+// the kinds the nodes can be added with, the topology and its saving.
+func (e *graphEditor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := checkEditorRequest(r); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	var v interface{}
+
+	switch p := strings.TrimPrefix(r.URL.Path, graphEditorPath); {
+	case p == "kinds" && r.Method == http.MethodGet:
+		v = e.c.editorKinds()
+
+	case p == "topology" && r.Method == http.MethodGet:
+		v = e.topology()
+
+	case p == "topology" && r.Method == http.MethodPost:
+		var t EditorTopology
+		if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := e.save(&t); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		v = map[string]string{"file": e.c.TopoPaths.TopologyFilenameAbsPath()}
+
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// checkEditorRequest rejects the editor requests that may be issued by other sites on behalf of the user:
+// the requests with a non-loopback host, which are the DNS rebinding attempts, and the state changing
+// cross-origin requests, including the ones sent by the HTML forms that can't set the JSON content type.
+func checkEditorRequest(r *http.Request) error {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(r.Host); err == nil {
+		host = h
+	}
+
+	if !isLoopbackHost(host) {
+		return fmt.Errorf("host %q is not a loopback address", r.Host)
+	}
+
+	if r.Method == http.MethodGet {
+		return nil
+	}
+
+	if o := r.Header.Get("Origin"); o != "" {
+		u, err := url.Parse(o)
+		if err != nil || u.Host != r.Host {
+			return fmt.Errorf("origin %q is not allowed", o)
+		}
+	}
+
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "application/json" {
+		return fmt.Errorf("content type %q is not allowed, application/json is expected", r.Header.Get("Content-Type"))
+	}
+
+	return nil
+}
+
+// isLoopbackHost checks if the host name or address refers to the loopback interface.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+
+	return ip != nil && ip.IsLoopback()
+}
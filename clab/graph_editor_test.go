@@ -0,0 +1,352 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v2"
+)
+
+const editorTopo = `name: editor
+
+topology:
+  nodes:
+    node1:
+      kind: ceos
+      suppress-startup-config: true
+    node2:
+      kind: ceos
+    node3:
+      kind: linux
+      image: alpine
+
+  links:
+    - endpoints: ["node1:eth1", "node2:eth1"]
+    - endpoints: ["node2:eth2", "node3:eth1"]
+    - endpoints: ["node3:eth2", "host:node3-eth2"]
+`
+
+func newEditorTestLab(t *testing.T) *CLab {
+	t.Helper()
+
+	topo := filepath.Join(t.TempDir(), "editor.clab.yml")
+	if err := os.WriteFile(topo, []byte(editorTopo), 0644); err != nil { // skipcq: GSC-G306
+		t.Fatal(err)
+	}
+
+	c, err := NewContainerLab(WithTopoPath(topo, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ResolveLinks(); err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestEditorTopology(t *testing.T) {
+	c := newEditorTestLab(t)
+
+	got := c.editorTopology()
+
+	want := []Link{
+		{Source: "node1", SourceEndpoint: "eth1", Target: "node2", TargetEndpoint: "eth1"},
+		{Source: "node2", SourceEndpoint: "eth2", Target: "node3", TargetEndpoint: "eth1"},
+	}
+	if diff := cmp.Diff(want, got.Links); diff != "" {
+		t.Errorf("links diff: (-want +got)\n%s", diff)
+	}
+
+	if len(got.Nodes) != 3 || got.Nodes[2].Image != "alpine" {
+		t.Errorf("unexpected nodes %+v", got.Nodes)
+	}
+}
+
+func TestGraphEditorSave(t *testing.T) {
+	c := newEditorTestLab(t)
+	e := newGraphEditor(c)
+
+	edited := &EditorTopology{
+		Nodes: []*EditorNode{
+			{Name: "node1", Kind: "ceos", Position: "10,20"},
+			{Name: "node2", Kind: "ceos", Group: "spine"},
+			{Name: "node4", Kind: "linux", Image: "alpine", Position: "100,20"},
+		},
+		Links: []Link{
+			{Source: "node2", SourceEndpoint: "eth1", Target: "node1", TargetEndpoint: "eth1"},
+			{Source: "node2", SourceEndpoint: "eth3", Target: "node4", TargetEndpoint: "eth1"},
+		},
+	}
+
+	if err := e.save(edited); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(c.TopoPaths.TopologyFilenameAbsPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Name     string
+		Topology struct {
+			Nodes map[string]map[string]interface{}
+			Links []map[string][]string
+		}
+	}
+
+	if err := yaml.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	wantNodes := map[string]map[string]interface{}{
+		"node1": {"kind": "ceos", "suppress-startup-config": true, "position": "10,20"},
+		"node2": {"kind": "ceos", "group": "spine"},
+		"node4": {"kind": "linux", "image": "alpine", "position": "100,20"},
+	}
+	if diff := cmp.Diff(wantNodes, got.Topology.Nodes); diff != "" {
+		t.Errorf("nodes diff: (-want +got)\n%s", diff)
+	}
+
+	// the links of node3 are removed along with it
+	wantLinks := []map[string][]string{
+		{"endpoints": {"node1:eth1", "node2:eth1"}},
+		{"endpoints": {"node2:eth3", "node4:eth1"}},
+	}
+	if diff := cmp.Diff(wantLinks, got.Topology.Links); diff != "" {
+		t.Errorf("links diff: (-want +got)\n%s", diff)
+	}
+
+	if got.Name != "editor" {
+		t.Errorf("expected the lab name to be kept, got %q", got.Name)
+	}
+
+	bak, err := os.ReadFile(c.TopoPaths.TopologyEditBakFileAbsPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(bak) != editorTopo {
+		t.Errorf("expected the original topology in the backup file, got:\n%s", bak)
+	}
+
+	// the attributes changed since the last save are written only
+	edited = &EditorTopology{
+		Nodes: []*EditorNode{
+			{Name: "node1", Kind: "ceos"},
+			{Name: "node2", Kind: "ceos", Group: "spine"},
+			{Name: "node4", Kind: "linux", Image: "alpine", Position: "100,20"},
+		},
+		Links: edited.Links,
+	}
+
+	if err := e.save(edited); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err = os.ReadFile(c.TopoPaths.TopologyFilenameAbsPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(b), "10,20") {
+		t.Errorf("expected the position of node1 to be removed:\n%s", b)
+	}
+}
+
+func TestValidateEditorTopology(t *testing.T) {
+	c := newEditorTestLab(t)
+
+	nodes := []*EditorNode{
+		{Name: "node1", Kind: "ceos"},
+		{Name: "node2", Kind: "nokia_srlinux"},
+	}
+
+	tests := map[string]struct {
+		topo    *EditorTopology
+		wantErr string
+	}{
+		"valid": {
+			topo: &EditorTopology{
+				Nodes: nodes,
+				Links: []Link{{Source: "node1", SourceEndpoint: "eth1", Target: "node2", TargetEndpoint: "ethernet-1/1"}},
+			},
+		},
+		"unknown kind": {
+			topo:    &EditorTopology{Nodes: []*EditorNode{{Name: "node1", Kind: "foo"}}},
+			wantErr: `unknown kind "foo"`,
+		},
+		"invalid interface name": {
+			topo: &EditorTopology{
+				Nodes: nodes,
+				Links: []Link{{Source: "node1", SourceEndpoint: "Gi1", Target: "node2", TargetEndpoint: "e1-1"}},
+			},
+			wantErr: `interface name "Gi1" doesn't match`,
+		},
+		"unknown node": {
+			topo: &EditorTopology{
+				Nodes: nodes,
+				Links: []Link{{Source: "node1", SourceEndpoint: "eth1", Target: "node3", TargetEndpoint: "eth1"}},
+			},
+			wantErr: `unknown node "node3"`,
+		},
+		"endpoint used twice": {
+			topo: &EditorTopology{
+				Nodes: nodes,
+				Links: []Link{
+					{Source: "node1", SourceEndpoint: "eth1", Target: "node2", TargetEndpoint: "e1-1"},
+					{Source: "node1", SourceEndpoint: "eth1", Target: "node2", TargetEndpoint: "e1-2"},
+				},
+			},
+			wantErr: "endpoint node1:eth1 is used by more than one link",
+		},
+		"invalid position": {
+			topo:    &EditorTopology{Nodes: []*EditorNode{{Name: "node1", Kind: "ceos", Position: "1"}}},
+			wantErr: "not in the x,y format",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := c.validateEditorTopology(tc.topo)
+
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Errorf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestEditTopologyYAMLKeepsCommentsAndAnchors(t *testing.T) {
+	raw := `# lab comment
+name: editor
+
+x-ceos: &ceos
+  kind: ceos
+
+topology:
+  nodes:
+    # the spine node
+    node1: *ceos
+    node2:
+      <<: *ceos
+      image: ceos:4.32 # pinned image
+  links:
+    - endpoints: ["node1:eth1", "node2:eth1"]
+`
+
+	base := map[string]*EditorNode{
+		"node1": {Name: "node1", Kind: "ceos"},
+		"node2": {Name: "node2", Kind: "ceos", Image: "ceos:4.32"},
+	}
+
+	edited := &EditorTopology{
+		Nodes: []*EditorNode{
+			{Name: "node1", Kind: "ceos", Position: "10,20"},
+			{Name: "node2", Kind: "ceos", Image: "ceos:4.32"},
+		},
+		Links: []Link{
+			{Source: "node1", SourceEndpoint: "eth1", Target: "node2", TargetEndpoint: "eth1"},
+		},
+	}
+
+	b, err := editTopologyYAML([]byte(raw), edited, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"# lab comment",
+		"# the spine node",
+		"# pinned image",
+		"x-ceos: &ceos",
+		"<<: *ceos",
+		"position: 10,20",
+	} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("expected %q in the edited topology:\n%s", want, b)
+		}
+	}
+
+	var got struct {
+		Topology struct {
+			Nodes map[string]map[string]interface{}
+		}
+	}
+
+	if err := yaml.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	wantNodes := map[string]map[string]interface{}{
+		"node1": {"kind": "ceos", "position": "10,20"},
+		"node2": {"kind": "ceos", "image": "ceos:4.32"},
+	}
+	if diff := cmp.Diff(wantNodes, got.Topology.Nodes); diff != "" {
+		t.Errorf("nodes diff: (-want +got)\n%s", diff)
+	}
+}
+
+func TestCheckEditorRequest(t *testing.T) {
+	tests := map[string]struct {
+		method      string
+		host        string
+		origin      string
+		contentType string
+		wantErr     bool
+	}{
+		"get from loopback": {
+			method: http.MethodGet, host: "127.0.0.1:50080",
+		},
+		"get with rebound host": {
+			method: http.MethodGet, host: "evil.example.com:50080", wantErr: true,
+		},
+		"post from same origin": {
+			method: http.MethodPost, host: "localhost:50080",
+			origin: "http://localhost:50080", contentType: "application/json",
+		},
+		"post without origin": {
+			method: http.MethodPost, host: "[::1]:50080", contentType: "application/json",
+		},
+		"post from other origin": {
+			method: http.MethodPost, host: "127.0.0.1:50080",
+			origin: "http://evil.example.com", contentType: "application/json", wantErr: true,
+		},
+		"post of a form": {
+			method: http.MethodPost, host: "127.0.0.1:50080",
+			origin: "http://127.0.0.1:50080", contentType: "text/plain", wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, "http://"+tc.host+graphEditorPath+"topology", nil)
+			if tc.origin != "" {
+				r.Header.Set("Origin", tc.origin)
+			}
+
+			if tc.contentType != "" {
+				r.Header.Set("Content-Type", tc.contentType)
+			}
+
+			err := checkEditorRequest(r)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("expected error %t, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/internal/cytoscape"
+	"github.com/srl-labs/containerlab/internal/d2"
+	"github.com/srl-labs/containerlab/internal/graphml"
+	"github.com/srl-labs/containerlab/internal/plantuml"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// graphNode is a node of the exported graph.
+type graphNode struct {
+	name  string
+	kind  string
+	image string
+	group string
+	// position is the "x,y" position of the node set in the topology
+	position string
+}
+
+// graphLink is a link of the exported graph.
+type graphLink struct {
+	nodeA, ifaceA string
+	nodeB, ifaceB string
+	labels        map[string]string
+	vars          map[string]interface{}
+}
+
+// graphElements returns the lab nodes and links the graphs are exported with, ordered by the node names
+// and the link indexes. The host and other special link nodes are added as the nodes of their own kind.
+func (c *CLab) graphElements() ([]graphNode, []graphLink) {
+	var nodes []graphNode
+
+	var lnks []graphLink
+
+	added := map[string]bool{}
+
+	for _, name := range c.sortedNodeNames() {
+		cfg := c.Nodes[name].Config()
+		nodes = append(nodes, graphNode{
+			name:     cfg.ShortName,
+			kind:     cfg.Kind,
+			image:    cfg.Image,
+			group:    cfg.Group,
+			position: cfg.Position,
+		})
+		added[cfg.ShortName] = true
+	}
+
+	for _, i := range c.sortedLinkIndexes() {
+		l := c.Links[i]
+
+		eps := l.GetEndpoints()
+		if len(eps) != 2 {
+			continue
+		}
+
+		for _, ep := range eps {
+			if name := ep.GetNode().GetShortName(); !added[name] {
+				nodes = append(nodes, graphNode{name: name, kind: name})
+				added[name] = true
+			}
+		}
+
+		lnks = append(lnks, graphLink{
+			nodeA:  eps[0].GetNode().GetShortName(),
+			ifaceA: eps[0].GetIfaceDisplayName(),
+			nodeB:  eps[1].GetNode().GetShortName(),
+			ifaceB: eps[1].GetIfaceDisplayName(),
+			labels: l.GetLabels(),
+			vars:   jsonCompatibleMap(l.GetVars()),
+		})
+	}
+
+	return nodes, lnks
+}
+
+// label returns the labels and variables of the link as the comma separated key=value pairs.
+func (l *graphLink) label() string {
+	var pairs []string
+
+	for k, v := range l.labels {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	for k, v := range l.vars {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, v))
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ", ")
+}
+
+// jsonCompatibleMap converts the nested maps with the interface keys
+// the YAML decoder produces to the maps with the string keys.
+func jsonCompatibleMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+
+	r := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		r[k] = jsonCompatible(v)
+	}
+
+	return r
+}
+
+func jsonCompatible(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		r := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			r[fmt.Sprint(k)] = jsonCompatible(val)
+		}
+
+		return r
+	case map[string]interface{}:
+		return jsonCompatibleMap(v)
+	case []interface{}:
+		r := make([]interface{}, len(v))
+		for i, val := range v {
+			r[i] = jsonCompatible(val)
+		}
+
+		return r
+	default:
+		return v
+	}
+}
+
+// writeGraphFile writes the graph of the format to the graph directory of the lab.
+func (c *CLab) writeGraphFile(ext, format, content string) error {
+	utils.CreateDirectory(c.TopoPaths.TopologyLabDir(), 0755)
+	utils.CreateDirectory(c.TopoPaths.GraphDir(), 0755)
+
+	fname := c.TopoPaths.GraphFilename(ext)
+
+	if err := utils.CreateFile(fname, content); err != nil {
+		return err
+	}
+
+	log.Infof("Created %s diagram file: %s", format, fname)
+
+	return nil
+}
+
+// GenerateD2Graph generates the graph of the lab topology in D2 format.
+func (c *CLab) GenerateD2Graph() error {
+	d := d2.NewDiagram()
+	d.SetTitle(c.Config.Name)
+
+	nodes, lnks := c.graphElements()
+
+	for _, n := range nodes {
+		d.AddNode(n.name, n.kind, n.group)
+	}
+
+	for _, l := range lnks {
+		d.AddEdge(l.nodeA, l.ifaceA, l.nodeB, l.ifaceB, l.label())
+	}
+
+	var w strings.Builder
+	d.Generate(&w)
+
+	return c.writeGraphFile(".d2", "D2", w.String())
+}
+
+// GeneratePlantUMLGraph generates the graph of the lab topology in PlantUML nwdiag format.
+func (c *CLab) GeneratePlantUMLGraph() error {
+	d := plantuml.NewNwDiag()
+	d.SetTitle(c.Config.Name)
+
+	nodes, lnks := c.graphElements()
+
+	for _, n := range nodes {
+		d.AddNode(n.name, n.kind, n.group)
+	}
+
+	for _, l := range lnks {
+		d.AddLink(l.nodeA, l.ifaceA, l.nodeB, l.ifaceB, l.label())
+	}
+
+	var w strings.Builder
+	d.Generate(&w)
+
+	return c.writeGraphFile(".puml", "PlantUML", w.String())
+}
+
+// GenerateCytoscapeGraph generates the graph of the lab topology in Cytoscape.js JSON format.
+func (c *CLab) GenerateCytoscapeGraph() error {
+	g := cytoscape.NewGraph(c.Config.Name)
+
+	nodes, lnks := c.graphElements()
+
+	for _, n := range nodes {
+		g.AddNode(n.name, n.kind, n.image, n.group)
+	}
+
+	for _, l := range lnks {
+		g.AddEdge(l.nodeA, l.ifaceA, l.nodeB, l.ifaceB, l.labels, l.vars)
+	}
+
+	var w bytes.Buffer
+	if err := g.Generate(&w); err != nil {
+		return err
+	}
+
+	return c.writeGraphFile(".cyjs", "Cytoscape", w.String())
+}
+
+// GenerateGraphMLGraph generates the graph of the lab topology in GraphML format.
+// The labels and variables of the links are serialized as JSON.
+func (c *CLab) GenerateGraphMLGraph() error {
+	g := graphml.NewGraphML(c.Config.Name)
+
+	nodes, lnks := c.graphElements()
+
+	for _, n := range nodes {
+		g.AddNode(n.name, n.kind, n.image, n.group)
+	}
+
+	for _, l := range lnks {
+		var labels, vars string
+
+		if len(l.labels) > 0 {
+			b, err := json.Marshal(l.labels)
+			if err != nil {
+				return err
+			}
+
+			labels = string(b)
+		}
+
+		if len(l.vars) > 0 {
+			b, err := json.Marshal(l.vars)
+			if err != nil {
+				return err
+			}
+
+			vars = string(b)
+		}
+
+		g.AddEdge(l.nodeA, l.ifaceA, l.nodeB, l.ifaceB, labels, vars)
+	}
+
+	var w bytes.Buffer
+	if err := g.Generate(&w); err != nil {
+		return err
+	}
+
+	return c.writeGraphFile(".graphml", "GraphML", w.String())
+}
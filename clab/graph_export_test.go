@@ -0,0 +1,169 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const graphExportTopo = `name: export
+
+topology:
+  nodes:
+    node1:
+      kind: ceos
+      group: spine
+    node2:
+      kind: ceos
+      group: leaf
+    node3:
+      kind: linux
+      image: alpine
+
+  links:
+    - endpoints: ["node1:eth1", "node2:eth1"]
+      labels:
+        role: fabric
+      vars:
+        ip:
+          node1: 10.0.0.0/31
+    - endpoints: ["node2:eth2", "node3:eth1"]
+    - endpoints: ["node3:eth2", "host:node3-eth2"]
+`
+
+func newGraphExportTestLab(t *testing.T) *CLab {
+	t.Helper()
+
+	topo := filepath.Join(t.TempDir(), "export.clab.yml")
+	if err := os.WriteFile(topo, []byte(graphExportTopo), 0644); err != nil { // skipcq: GSC-G306
+		t.Fatal(err)
+	}
+
+	c, err := NewContainerLab(WithTopoPath(topo, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ResolveLinks(); err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestGraphElements(t *testing.T) {
+	c := newGraphExportTestLab(t)
+
+	nodes, lnks := c.graphElements()
+
+	wantNodes := []graphNode{
+		{name: "node1", kind: "ceos", group: "spine"},
+		{name: "node2", kind: "ceos", group: "leaf"},
+		{name: "node3", kind: "linux", image: "alpine"},
+		{name: "host", kind: "host"},
+	}
+
+	if d := cmp.Diff(wantNodes, nodes, cmp.AllowUnexported(graphNode{})); d != "" {
+		t.Errorf("nodes mismatch (-want +got):\n%s", d)
+	}
+
+	if len(lnks) != 3 {
+		t.Fatalf("got %d links, want 3", len(lnks))
+	}
+
+	if got, want := lnks[0].label(), "ip=map[node1:10.0.0.0/31], role=fabric"; got != want {
+		t.Errorf("got link label %q, want %q", got, want)
+	}
+
+	// the vars decoded from YAML must be serializable to JSON
+	b, err := json.Marshal(lnks[0].vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := string(b), `{"ip":{"node1":"10.0.0.0/31"}}`; got != want {
+		t.Errorf("got vars %s, want %s", got, want)
+	}
+}
+
+func TestGenerateGraphExports(t *testing.T) {
+	t.Setenv("CLAB_LABDIR_BASE", t.TempDir())
+
+	c := newGraphExportTestLab(t)
+
+	tests := map[string]struct {
+		generate func() error
+		ext      string
+		want     []string
+	}{
+		"mermaid": {
+			generate: func() error { return c.GenerateMermaidGraph("TD") },
+			ext:      ".mermaid",
+			want: []string{
+				`subgraph group1 ["leaf"]`,
+				`node1---|"eth1 - eth1"|node2`,
+			},
+		},
+		"d2": {
+			generate: c.GenerateD2Graph,
+			ext:      ".d2",
+			want: []string{
+				`"spine": {`,
+				`"spine"."node1" -- "leaf"."node2": "ip=map[node1:10.0.0.0/31], role=fabric" {`,
+				`target-arrowhead.label: "node3-eth2"`,
+			},
+		},
+		"plantuml": {
+			generate: c.GeneratePlantUMLGraph,
+			ext:      ".puml",
+			want: []string{
+				"nwdiag {",
+				`node3 [address = "eth2"];`,
+				`description = "spine";`,
+			},
+		},
+		"cytoscape": {
+			generate: c.GenerateCytoscapeGraph,
+			ext:      ".cyjs",
+			want: []string{
+				`"source_endpoint": "eth1"`,
+				`"role": "fabric"`,
+			},
+		},
+		"graphml": {
+			generate: c.GenerateGraphMLGraph,
+			ext:      ".graphml",
+			want: []string{
+				`<data key="group">spine</data>`,
+				`<data key="vars">{&#34;ip&#34;:{&#34;node1&#34;:&#34;10.0.0.0/31&#34;}}</data>`,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tc.generate(); err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := os.ReadFile(c.TopoPaths.GraphFilename(tc.ext))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, w := range tc.want {
+				if !strings.Contains(string(b), w) {
+					t.Errorf("%s output doesn't contain %q:\n%s", name, w, b)
+				}
+			}
+		})
+	}
+}
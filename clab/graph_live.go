@@ -0,0 +1,429 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// graphStatusPath is the path of the WebSocket endpoint pushing the lab status.
+	graphStatusPath = "/ws"
+	// graphNodesPath is the path prefix of the node details endpoint.
+	graphNodesPath = "/api/nodes/"
+)
+
+// graph node statuses the topology view colours the nodes by.
+const (
+	GraphNodeStatusHealthy   = "healthy"
+	GraphNodeStatusUnhealthy = "unhealthy"
+	GraphNodeStatusRunning   = "running"
+	GraphNodeStatusStopped   = "stopped"
+	GraphNodeStatusMissing   = "missing"
+)
+
+// GraphStatus is the live status of the lab nodes and links pushed to the topology view.
+type GraphStatus struct {
+	Time  time.Time          `json:"time"`
+	Nodes []*GraphNodeStatus `json:"nodes"`
+	Links []*GraphLinkStatus `json:"links"`
+}
+
+// GraphNodeStatus is the live status of a lab node.
+type GraphNodeStatus struct {
+	Name string `json:"name"`
+	// Status is one of the GraphNodeStatus* values derived from the container state and health.
+	Status string `json:"status"`
+	// State is the container state and status as reported by the runtime.
+	State string `json:"state"`
+}
+
+// GraphLinkStatus is the live status of a lab link.
+type GraphLinkStatus struct {
+	Source         string `json:"source"`
+	SourceEndpoint string `json:"source_endpoint"`
+	Target         string `json:"target"`
+	TargetEndpoint string `json:"target_endpoint"`
+	// OperState is "up" when the interfaces of both endpoints are up, "down" when any of them
+	// is down and "unknown" when the state of the interfaces can't be retrieved.
+	OperState string `json:"oper_state"`
+	// rates in bits per second measured on the source endpoint,
+	// or on the target endpoint if the source one can't be read
+	SourceToTargetBps float64 `json:"source_to_target_bps"`
+	TargetToSourceBps float64 `json:"target_to_source_bps"`
+}
+
+// GraphInterfaceStatus is the live status of a node interface.
+type GraphInterfaceStatus struct {
+	Name      string  `json:"name"`
+	Peer      string  `json:"peer,omitempty"`
+	OperState string  `json:"oper_state"`
+	MTU       int     `json:"mtu,omitempty"`
+	MAC       string  `json:"mac,omitempty"`
+	RxBytes   uint64  `json:"rx_bytes"`
+	TxBytes   uint64  `json:"tx_bytes"`
+	RxBps     float64 `json:"rx_bps"`
+	TxBps     float64 `json:"tx_bps"`
+}
+
+// GraphNodeDetails are the details of a lab node shown when the node is clicked in the topology view.
+type GraphNodeDetails struct {
+	types.ContainerDetails
+	Status     string                  `json:"status"`
+	Interfaces []*GraphInterfaceStatus `json:"interfaces"`
+}
+
+// ifaceSample is a sample of the interface counters.
+type ifaceSample struct {
+	operState string
+	mtu       int
+	mac       string
+	rxBytes   uint64
+	txBytes   uint64
+	at        time.Time
+}
+
+// graphStatusSampler samples the status of the lab nodes and links.
+// The rates of the interfaces are computed against the previous sample.
+type graphStatusSampler struct {
+	mu   sync.Mutex
+	c    *CLab
+	prev map[string]*ifaceSample
+	cur  map[string]*ifaceSample
+}
+
+func newGraphStatusSampler(c *CLab) *graphStatusSampler {
+	return &graphStatusSampler{
+		c:    c,
+		prev: map[string]*ifaceSample{},
+	}
+}
+
+// sample returns the current status of the lab nodes and links.
+func (s *graphStatusSampler) sample(ctx context.Context) *GraphStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cur = map[string]*ifaceSample{}
+	defer func() { s.prev = s.cur }()
+
+	st := &GraphStatus{
+		Time:  time.Now(),
+		Nodes: []*GraphNodeStatus{},
+		Links: []*GraphLinkStatus{},
+	}
+
+	containers := s.c.labContainers(ctx)
+
+	for _, name := range s.c.sortedNodeNames() {
+		status, state := s.c.graphNodeStatus(ctx, name, containers)
+		st.Nodes = append(st.Nodes, &GraphNodeStatus{
+			Name:   name,
+			Status: status,
+			State:  state,
+		})
+	}
+
+	for _, i := range s.c.sortedLinkIndexes() {
+		eps := s.c.Links[i].GetEndpoints()
+		if len(eps) != 2 {
+			continue
+		}
+
+		ls := &GraphLinkStatus{
+			Source:         eps[0].GetNode().GetShortName(),
+			SourceEndpoint: eps[0].GetIfaceDisplayName(),
+			Target:         eps[1].GetNode().GetShortName(),
+			TargetEndpoint: eps[1].GetIfaceDisplayName(),
+		}
+
+		a := s.endpointStatus(ctx, eps[0])
+		z := s.endpointStatus(ctx, eps[1])
+
+		ls.OperState = linkOperState(a, z)
+
+		switch {
+		case a != nil:
+			ls.SourceToTargetBps, ls.TargetToSourceBps = a.TxBps, a.RxBps
+		case z != nil:
+			ls.SourceToTargetBps, ls.TargetToSourceBps = z.RxBps, z.TxBps
+		}
+
+		st.Links = append(st.Links, ls)
+	}
+
+	return st
+}
+
+// nodeDetails returns the details of the lab node with its interfaces, nil if the node is not in the lab.
+func (s *graphStatusSampler) nodeDetails(ctx context.Context, name string) *GraphNodeDetails {
+	n, ok := s.c.Nodes[name]
+	if !ok {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cur = map[string]*ifaceSample{}
+	defer func() {
+		for k, v := range s.cur {
+			s.prev[k] = v
+		}
+	}()
+
+	containers := s.c.labContainers(ctx)
+
+	d := &GraphNodeDetails{
+		ContainerDetails: buildGraphNode(n),
+		Interfaces:       []*GraphInterfaceStatus{},
+	}
+
+	d.Status, d.State = s.c.graphNodeStatus(ctx, name, containers)
+
+	if cont, ok := containers[name]; ok {
+		d.ContainerID = cont.ShortID
+		d.IPv4Address = cont.GetContainerIPv4()
+		d.IPv6Address = cont.GetContainerIPv6()
+		d.Ports = cont.Ports
+	}
+
+	for _, ep := range n.GetEndpoints() {
+		is := s.endpointStatus(ctx, ep)
+		if is == nil {
+			is = &GraphInterfaceStatus{
+				Name:      ep.GetIfaceDisplayName(),
+				OperState: "unknown",
+			}
+		}
+
+		for _, peer := range ep.GetLink().GetEndpoints() {
+			if peer != ep {
+				is.Peer = peer.GetNode().GetShortName() + ":" + peer.GetIfaceDisplayName()
+			}
+		}
+
+		d.Interfaces = append(d.Interfaces, is)
+	}
+
+	return d
+}
+
+// endpointStatus returns the status of the endpoint interface, nil if it can't be retrieved.
+func (s *graphStatusSampler) endpointStatus(ctx context.Context, ep links.Endpoint) *GraphInterfaceStatus {
+	if ep.GetIfaceName() == "" {
+		return nil
+	}
+
+	cur := &ifaceSample{at: time.Now()}
+
+	err := ep.GetNode().ExecFunction(ctx, func(_ ns.NetNS) error {
+		l, err := netlink.LinkByName(ep.GetIfaceName())
+		if err != nil {
+			return err
+		}
+
+		attrs := l.Attrs()
+		cur.operState = attrs.OperState.String()
+		cur.mtu = attrs.MTU
+		cur.mac = attrs.HardwareAddr.String()
+
+		if attrs.Statistics != nil {
+			cur.rxBytes = attrs.Statistics.RxBytes
+			cur.txBytes = attrs.Statistics.TxBytes
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Debugf("failed to read the status of the endpoint %s: %v", ep, err)
+		return nil
+	}
+
+	key := ep.GetNode().GetShortName() + ":" + ep.GetIfaceName()
+	s.cur[key] = cur
+
+	is := &GraphInterfaceStatus{
+		Name:      ep.GetIfaceDisplayName(),
+		OperState: cur.operState,
+		MTU:       cur.mtu,
+		MAC:       cur.mac,
+		RxBytes:   cur.rxBytes,
+		TxBytes:   cur.txBytes,
+	}
+
+	is.RxBps, is.TxBps = ifaceRates(s.prev[key], cur)
+
+	return is
+}
+
+// ifaceRates returns the receive and transmit rates in bits per second between the samples.
+// The rates are zero when there is no previous sample or the counters were reset.
+func ifaceRates(prev, cur *ifaceSample) (float64, float64) {
+	if prev == nil || cur == nil {
+		return 0, 0
+	}
+
+	elapsed := cur.at.Sub(prev.at).Seconds()
+	if elapsed <= 0 || cur.rxBytes < prev.rxBytes || cur.txBytes < prev.txBytes {
+		return 0, 0
+	}
+
+	return float64(cur.rxBytes-prev.rxBytes) * 8 / elapsed,
+		float64(cur.txBytes-prev.txBytes) * 8 / elapsed
+}
+
+// linkOperState returns the oper state of the link given the status of its endpoints.
+// The endpoints which state can't be retrieved, like the remote end of the vxlan tunnels, are ignored.
+func linkOperState(eps ...*GraphInterfaceStatus) string {
+	state := "unknown"
+
+	for _, ep := range eps {
+		if ep == nil {
+			continue
+		}
+
+		switch ep.OperState {
+		// veth interfaces may report the unknown oper state while being operational
+		case "up", "unknown":
+			if state == "unknown" {
+				state = "up"
+			}
+		default:
+			state = "down"
+		}
+	}
+
+	return state
+}
+
+// labContainers returns the containers of the lab keyed by the node names.
+func (c *CLab) labContainers(ctx context.Context) map[string]*runtime.GenericContainer {
+	containers, err := c.ListContainers(ctx, []*types.GenericFilter{{
+		FilterType: "label", Match: c.Config.Name,
+		Field: labels.Containerlab, Operator: "=",
+	}})
+	if err != nil {
+		log.Debugf("failed to list the lab containers: %v", err)
+	}
+
+	m := make(map[string]*runtime.GenericContainer, len(containers))
+	for i := range containers {
+		m[containers[i].Labels[labels.NodeName]] = &containers[i]
+	}
+
+	return m
+}
+
+// graphNodeStatus returns the status of the node and the container state reported by the runtime.
+func (c *CLab) graphNodeStatus(ctx context.Context, name string,
+	containers map[string]*runtime.GenericContainer,
+) (string, string) {
+	cont, ok := containers[name]
+	if !ok {
+		return GraphNodeStatusMissing, "N/A"
+	}
+
+	state := cont.State + "/" + cont.Status
+
+	if !strings.EqualFold(cont.State, "running") {
+		return GraphNodeStatusStopped, state
+	}
+
+	healthy, err := c.Nodes[name].IsHealthy(ctx)
+
+	return nodeHealthStatus(healthy, err), state
+}
+
+// nodeHealthStatus returns the status of the running node given the result of its health check.
+// The error means the node has no health check defined.
+func nodeHealthStatus(healthy bool, err error) string {
+	switch {
+	case err != nil:
+		return GraphNodeStatusRunning
+	case healthy:
+		return GraphNodeStatusHealthy
+	}
+
+	return GraphNodeStatusUnhealthy
+}
+
+// sortedLinkIndexes returns the indexes of the lab links in the ascending order.
+func (c *CLab) sortedLinkIndexes() []int {
+	idxs := make([]int, 0, len(c.Links))
+	for i := range c.Links {
+		idxs = append(idxs, i)
+	}
+
+	sort.Ints(idxs)
+
+	return idxs
+}
+
+// graphStatusHandler returns the WebSocket handler pushing the lab status to the topology view every interval.
+func (c *CLab) graphStatusHandler(interval time.Duration) websocket.Handler {
+	return func(ws *websocket.Conn) {
+		defer ws.Close()
+
+		ctx, cancel := context.WithCancel(ws.Request().Context())
+		defer cancel()
+
+		// the view doesn't send anything, reading detects the closed connection
+		go func() {
+			_, _ = io.Copy(io.Discard, ws)
+			cancel()
+		}()
+
+		s := newGraphStatusSampler(c)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := websocket.JSON.Send(ws, s.sample(ctx)); err != nil {
+				log.Debugf("stopped sending the lab status to %s: %v", ws.Request().RemoteAddr, err)
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// graphNodeHandler serves the details of the node named in the request path.
+func (c *CLab) graphNodeHandler() http.HandlerFunc {
+	s := newGraphStatusSampler(c)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		d := s.nodeDetails(r.Context(), strings.TrimPrefix(r.URL.Path, graphNodesPath))
+		if d == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(d)
+	}
+}
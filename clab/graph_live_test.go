@@ -0,0 +1,82 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIfaceRates(t *testing.T) {
+	now := time.Now()
+
+	tests := map[string]struct {
+		prev, cur      *ifaceSample
+		wantRx, wantTx float64
+	}{
+		"no previous sample": {
+			cur: &ifaceSample{rxBytes: 1000, txBytes: 1000, at: now},
+		},
+		"rates": {
+			prev:   &ifaceSample{rxBytes: 1000, txBytes: 2000, at: now.Add(-2 * time.Second)},
+			cur:    &ifaceSample{rxBytes: 3000, txBytes: 2500, at: now},
+			wantRx: 8000,
+			wantTx: 2000,
+		},
+		"counters reset": {
+			prev: &ifaceSample{rxBytes: 1000, txBytes: 2000, at: now.Add(-2 * time.Second)},
+			cur:  &ifaceSample{rxBytes: 10, txBytes: 10, at: now},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rx, tx := ifaceRates(tc.prev, tc.cur)
+			if rx != tc.wantRx || tx != tc.wantTx {
+				t.Errorf("got rx %v tx %v, want rx %v tx %v", rx, tx, tc.wantRx, tc.wantTx)
+			}
+		})
+	}
+}
+
+func TestLinkOperState(t *testing.T) {
+	up := &GraphInterfaceStatus{OperState: "up"}
+	down := &GraphInterfaceStatus{OperState: "down"}
+	unknown := &GraphInterfaceStatus{OperState: "unknown"}
+
+	tests := map[string]struct {
+		eps  []*GraphInterfaceStatus
+		want string
+	}{
+		"both up":              {eps: []*GraphInterfaceStatus{up, up}, want: "up"},
+		"one down":             {eps: []*GraphInterfaceStatus{up, down}, want: "down"},
+		"veth unknown state":   {eps: []*GraphInterfaceStatus{unknown, up}, want: "up"},
+		"one end not readable": {eps: []*GraphInterfaceStatus{nil, up}, want: "up"},
+		"none readable":        {eps: []*GraphInterfaceStatus{nil, nil}, want: "unknown"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := linkOperState(tc.eps...); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNodeHealthStatus(t *testing.T) {
+	if got := nodeHealthStatus(false, errors.New("no health information")); got != GraphNodeStatusRunning {
+		t.Errorf("node without healthcheck: got %q", got)
+	}
+
+	if got := nodeHealthStatus(true, nil); got != GraphNodeStatusHealthy {
+		t.Errorf("healthy node: got %q", got)
+	}
+
+	if got := nodeHealthStatus(false, nil); got != GraphNodeStatusUnhealthy {
+		t.Errorf("unhealthy node: got %q", got)
+	}
+}
@@ -2,6 +2,10 @@
 
     data = JSON.parse(data)
     var activeLayout = ''
+    // live status updates are enabled by the template for the deployed labs
+    var liveUpdates = typeof live !== 'undefined' && live
+    // the topology editor is enabled by the template in the edit mode
+    var editMode = typeof edit !== 'undefined' && edit
     var defaultIconType = 'router'
 
     // when group property is not set in containerlab
@@ -299,6 +303,10 @@
     });
 
     topo.on('ready', function () {
+        if (editMode) {
+            loadEditor();
+            return;
+        }
         topo.data(data);
     });
 
@@ -336,6 +344,416 @@
         topo.activateLayout('hierarchicalLayout');
     }
 
+    // colours of the nodes and links by their live status
+    var nodeStatusColors = {
+        healthy: '#22c55e',
+        running: '#0386d2',
+        unhealthy: '#f59e0b',
+        stopped: '#ef4444',
+        missing: '#9ca3af',
+    };
+    var linkStateColors = {
+        up: '#22c55e',
+        down: '#ef4444',
+        unknown: '#DBEAFE',
+    };
+    var nodeDetailsName = '';
+
+    var escapeHTML = function (v) {
+        var div = document.createElement('div');
+        div.textContent = v === undefined || v === null ? '' : String(v);
+        return div.innerHTML;
+    };
+
+    var formatRate = function (bps) {
+        var units = ['bps', 'Kbps', 'Mbps', 'Gbps'];
+        var i = 0;
+        while (bps >= 1000 && i < units.length - 1) {
+            bps /= 1000;
+            i++;
+        }
+        return bps.toFixed(i ? 1 : 0) + ' ' + units[i];
+    };
+
+    var linkKey = function (source, sourceEndpoint, target, targetEndpoint) {
+        return [source, sourceEndpoint, target, targetEndpoint].join('|');
+    };
+
+    // updateStatus colours the nodes and links by their status
+    // and shows the link rates next to the endpoint labels
+    var updateStatus = function (status) {
+        status.nodes.forEach(function (n) {
+            var node = topo.getNode(n.name);
+            if (!node) {
+                return;
+            }
+            node.color(nodeStatusColors[n.status] || nodeStatusColors.missing);
+            node.model().set('state', n.state);
+        });
+
+        var links = {};
+        status.links.forEach(function (l) {
+            links[linkKey(l.source, l.source_endpoint, l.target, l.target_endpoint)] = l;
+        });
+
+        topo.eachLink(function (link) {
+            var d = link.model()._data;
+            var l = links[linkKey(d.source, d.source_endpoint, d.target, d.target_endpoint)];
+            if (!l) {
+                return;
+            }
+            link.color(linkStateColors[l.oper_state] || linkStateColors.unknown);
+            link.sourcelabel(d.source_endpoint + (l.source_to_target_bps > 0 ? ' ' + formatRate(l.source_to_target_bps) : ''));
+            link.targetlabel(d.target_endpoint + (l.target_to_source_bps > 0 ? ' ' + formatRate(l.target_to_source_bps) : ''));
+            link.update();
+        });
+
+        if (nodeDetailsName) {
+            showNodeDetails(nodeDetailsName);
+        }
+    };
+
+    // connectStatus receives the live status of the lab, reconnecting when the connection is lost
+    var connectStatus = function () {
+        var proto = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+        var ws = new WebSocket(proto + '//' + window.location.host + '/ws');
+        ws.onmessage = function (e) {
+            updateStatus(JSON.parse(e.data));
+        };
+        ws.onclose = function () {
+            setTimeout(connectStatus, 5000);
+        };
+    };
+
+    var renderNodeDetails = function (d) {
+        var rows = [
+            ['Kind', d.kind],
+            ['Image', d.image],
+            ['Group', d.group],
+            ['State', d.state],
+            ['Status', d.status],
+            ['Container ID', d.container_id],
+            ['IPv4', d.ipv4_address],
+            ['IPv6', d.ipv6_address],
+        ];
+        (d.ports || []).forEach(function (p) {
+            rows.push(['Port', p.host_ip + ':' + p.host_port + ' -> ' + p.port + '/' + p.protocol]);
+        });
+
+        var html = '<table>';
+        rows.forEach(function (r) {
+            if (r[1]) {
+                html += '<tr><td class="font-semibold">' + escapeHTML(r[0]) + '</td><td>' + escapeHTML(r[1]) + '</td></tr>';
+            }
+        });
+        html += '</table>';
+
+        if (d.interfaces && d.interfaces.length) {
+            html += '<div class="font-bold pt-2">Interfaces</div><table>';
+            html += '<tr class="font-semibold"><td>Name</td><td>Peer</td><td>State</td><td>Rx</td><td>Tx</td></tr>';
+            d.interfaces.forEach(function (i) {
+                html += '<tr><td>' + escapeHTML(i.name) + '</td><td>' + escapeHTML(i.peer) + '</td><td>' +
+                    escapeHTML(i.oper_state) + '</td><td>' + formatRate(i.rx_bps) + '</td><td>' +
+                    formatRate(i.tx_bps) + '</td></tr>';
+            });
+            html += '</table>';
+        }
+
+        document.getElementById('node-details-name').textContent = d.name;
+        document.getElementById('node-details-content').innerHTML = html;
+        document.getElementById('node-details').style.display = 'block';
+    };
+
+    // showNodeDetails shows the details of the node, the live ones are fetched from the server
+    showNodeDetails = function (name) {
+        nodeDetailsName = name;
+        if (!liveUpdates) {
+            var node = topo.getNode(name);
+            if (node) {
+                renderNodeDetails(node.model()._data);
+            }
+            return;
+        }
+        fetch('api/nodes/' + encodeURIComponent(name))
+            .then(function (r) {
+                return r.json();
+            })
+            .then(renderNodeDetails)
+            .catch(function (err) {
+                console.log('failed to fetch the node details', err);
+            });
+    };
+
+    closeNodeDetails = function () {
+        nodeDetailsName = '';
+        document.getElementById('node-details').style.display = 'none';
+    };
+
+    topo.on('clickNode', function (sender, node) {
+        if (editMode && editorClickNode(node.model().get('name'))) {
+            return;
+        }
+        showNodeDetails(node.model().get('name'));
+    });
+
+    // the state of the topology editor
+    var editor = {
+        kinds: {},
+        nodes: {},
+        links: [],
+        // names of the nodes whose position is saved
+        positioned: {},
+        // the selected node name or link
+        selected: null,
+        // linking is set while a link is drawn, holding the source node name once clicked
+        linking: null,
+    };
+
+    var setEditorStatus = function (msg) {
+        document.getElementById('editor-status').textContent = msg;
+    };
+
+    // interfaceRegExp converts the kind's interface pattern to a regular expression matching whole names
+    var interfaceRegExp = function (pattern) {
+        try {
+            return new RegExp('^(?:' + pattern.replace(/\(\?P</g, '(?<') + ')$');
+        } catch (err) {
+            return null;
+        }
+    };
+
+    var checkInterface = function (nodeName, ifName) {
+        if (!ifName) {
+            return 'interface name of node ' + nodeName + ' is empty';
+        }
+        var re = editor.kinds[editor.nodes[nodeName].kind];
+        if (re && !re.test(ifName)) {
+            return 'interface name ' + ifName + ' does not match the interface pattern of kind ' + editor.nodes[nodeName].kind;
+        }
+        var used = editor.links.some(function (l) {
+            return (l.source === nodeName && l.source_endpoint === ifName) ||
+                (l.target === nodeName && l.target_endpoint === ifName);
+        });
+        if (used) {
+            return 'interface ' + nodeName + ':' + ifName + ' is already used by another link';
+        }
+        return '';
+    };
+
+    var nodeData = function (n) {
+        var d = {
+            name: n.name,
+            kind: n.kind,
+            image: n.image || 'N/A',
+            group: n.group || 'N/A',
+        };
+        if (n.position) {
+            var p = n.position.split(',');
+            d.x = parseFloat(p[0]);
+            d.y = parseFloat(p[1]);
+        }
+        return d;
+    };
+
+    // loadEditor loads the kinds and the editable topology from the server
+    var loadEditor = function () {
+        document.getElementById('editor').style.display = 'flex';
+
+        Promise.all([
+            fetch('api/editor/kinds').then(function (r) { return r.json(); }),
+            fetch('api/editor/topology').then(function (r) { return r.json(); }),
+        ]).then(function (res) {
+            var select = document.getElementById('editor-node-kind');
+            res[0].forEach(function (k) {
+                editor.kinds[k.name] = k.interface_pattern ? interfaceRegExp(k.interface_pattern) : null;
+                var opt = document.createElement('option');
+                opt.value = k.name;
+                opt.textContent = k.name;
+                select.appendChild(opt);
+            });
+
+            var t = res[1];
+            var allPositioned = t.nodes.length > 0;
+            t.nodes.forEach(function (n) {
+                editor.nodes[n.name] = n;
+                if (n.position) {
+                    editor.positioned[n.name] = true;
+                } else {
+                    allPositioned = false;
+                }
+            });
+            editor.links = t.links;
+
+            // the saved positions are kept when all the nodes have them
+            if (allPositioned) {
+                topo.graph().dataProcessor('');
+            }
+
+            topo.data({
+                nodes: t.nodes.map(nodeData),
+                links: t.links.map(function (l) { return Object.assign({}, l); }),
+            });
+        }).catch(function (err) {
+            setEditorStatus('failed to load the topology: ' + err);
+        });
+    };
+
+    addEditorNode = function () {
+        var name = document.getElementById('editor-node-name').value.trim();
+        var kind = document.getElementById('editor-node-kind').value;
+        if (!name || /[:\s]/.test(name)) {
+            setEditorStatus('invalid node name');
+            return;
+        }
+        if (editor.nodes[name]) {
+            setEditorStatus('node ' + name + ' already exists');
+            return;
+        }
+
+        // new nodes are placed to the right of the existing ones
+        var x = 0, y = 0, first = true;
+        topo.eachNode(function (node) {
+            var p = node.model().position();
+            if (first || p.x > x) {
+                x = p.x;
+            }
+            if (first || p.y < y) {
+                y = p.y;
+            }
+            first = false;
+        });
+
+        var n = {
+            name: name,
+            kind: kind,
+            image: document.getElementById('editor-node-image').value.trim(),
+            group: document.getElementById('editor-node-group').value.trim(),
+            position: Math.round(first ? 0 : x + 120) + ',' + Math.round(y),
+        };
+        editor.nodes[name] = n;
+        editor.positioned[name] = true;
+        topo.addNode(nodeData(n));
+        document.getElementById('editor-node-name').value = '';
+        setEditorStatus('added node ' + name);
+    };
+
+    startEditorLink = function () {
+        editor.linking = '';
+        setEditorStatus('click the source node of the link');
+    };
+
+    // editorClickNode handles the node clicks while a link is drawn and selects the node otherwise.
+    // Returns true when the click was consumed by the editor.
+    var editorClickNode = function (name) {
+        if (editor.linking === null) {
+            editor.selected = name;
+            return false;
+        }
+
+        if (editor.linking === '') {
+            editor.linking = name;
+            setEditorStatus('click the target node of the link');
+            return true;
+        }
+
+        var source = editor.linking;
+        editor.linking = null;
+
+        var l = {
+            source: source,
+            source_endpoint: (window.prompt('Interface of node ' + source) || '').trim(),
+            target: name,
+            target_endpoint: (window.prompt('Interface of node ' + name) || '').trim(),
+        };
+        var err = checkInterface(l.source, l.source_endpoint) || checkInterface(l.target, l.target_endpoint);
+        if (!err && l.source === l.target && l.source_endpoint === l.target_endpoint) {
+            err = 'the link endpoints are the same';
+        }
+        if (err) {
+            setEditorStatus(err);
+            return true;
+        }
+
+        editor.links.push(l);
+        topo.addLink(Object.assign({}, l));
+        setEditorStatus('added link ' + l.source + ':' + l.source_endpoint + ' - ' + l.target + ':' + l.target_endpoint);
+        return true;
+    };
+
+    deleteEditorSelection = function () {
+        var sel = editor.selected;
+        editor.selected = null;
+
+        if (typeof sel === 'string' && editor.nodes[sel]) {
+            editor.links = editor.links.filter(function (l) {
+                return l.source !== sel && l.target !== sel;
+            });
+            delete editor.nodes[sel];
+            delete editor.positioned[sel];
+            topo.removeNode(sel);
+            closeNodeDetails();
+            setEditorStatus('deleted node ' + sel);
+            return;
+        }
+
+        if (sel && typeof sel === 'object') {
+            var d = sel.model()._data;
+            editor.links = editor.links.filter(function (l) {
+                return linkKey(l.source, l.source_endpoint, l.target, l.target_endpoint) !==
+                    linkKey(d.source, d.source_endpoint, d.target, d.target_endpoint);
+            });
+            topo.removeLink(sel.id());
+            setEditorStatus('deleted link ' + d.source + ':' + d.source_endpoint + ' - ' + d.target + ':' + d.target_endpoint);
+            return;
+        }
+
+        setEditorStatus('click a node or a link to select it first');
+    };
+
+    // saveEditorTopology sends the edited topology to the server that writes it to the topology file
+    saveEditorTopology = function () {
+        var nodes = Object.keys(editor.nodes).sort().map(function (name) {
+            var n = Object.assign({}, editor.nodes[name]);
+            var node = topo.getNode(name);
+            if (editor.positioned[name] && node) {
+                var p = node.model().position();
+                n.position = Math.round(p.x) + ',' + Math.round(p.y);
+            }
+            return n;
+        });
+
+        fetch('api/editor/topology', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ nodes: nodes, links: editor.links }),
+        }).then(function (r) {
+            return r.text().then(function (body) {
+                if (!r.ok) {
+                    throw new Error(body);
+                }
+                setEditorStatus('saved to ' + JSON.parse(body).file);
+            });
+        }).catch(function (err) {
+            setEditorStatus('failed to save the topology: ' + err.message);
+        });
+    };
+
+    if (editMode) {
+        topo.on('dragNodeEnd', function (sender, node) {
+            editor.positioned[node.model().get('name')] = true;
+        });
+
+        topo.on('clickLink', function (sender, link) {
+            editor.selected = link;
+            var d = link.model()._data;
+            setEditorStatus('selected link ' + d.source + ':' + d.source_endpoint + ' - ' + d.target + ':' + d.target_endpoint);
+        });
+    }
+
+    if (liveUpdates) {
+        connectStatus();
+    }
+
     window.onresize = adaptToContainer;
     var app = new nx.ui.Application();
     app.container(document.getElementById('clab-topology'));
@@ -0,0 +1,91 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// hookWaitDelay is the time the output of a killed hook is waited for,
+// the processes the hook started in the background might keep its output open.
+const hookWaitDelay = 5 * time.Second
+
+// hookEnv returns the environment variables passing the lab context to the hooks of the event.
+func (c *CLab) hookEnv(event types.HookEvent) []string {
+	return []string{
+		"CLAB_HOOK_EVENT=" + string(event),
+		"CLAB_LAB_NAME=" + c.Config.Name,
+		"CLAB_LAB_DIR=" + c.TopoPaths.TopologyLabDir(),
+		"CLAB_TOPOLOGY_FILE=" + c.TopoPaths.TopologyFilenameAbsPath(),
+		"CLAB_TOPOLOGY_DATA=" + c.TopoPaths.TopoExportFile(),
+	}
+}
+
+// runHooks runs the hooks of the lifecycle event one after another.
+// A failed hook stops the run and fails it, unless the failure is ignored by the hook policy.
+func (c *CLab) runHooks(ctx context.Context, event types.HookEvent) error {
+	hooks := c.Config.Hooks.Get(event)
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	env := append(os.Environ(), c.hookEnv(event)...)
+
+	for _, h := range hooks {
+		log.Infof("Running %s hook: %s", event, h.Command)
+
+		err := runHook(ctx, h, env, c.TopoPaths.TopologyFileDir())
+		if err == nil {
+			continue
+		}
+
+		if h.OnFailure == types.HookFailurePolicyIgnore {
+			log.Warnf("%s hook %q failed: %v", event, h.Command, err)
+			continue
+		}
+
+		return fmt.Errorf("%s hook %q failed: %w", event, h.Command, err)
+	}
+
+	return nil
+}
+
+// runHook runs the hook command with the shell in the directory
+// and logs its output.
+func runHook(ctx context.Context, h *types.Hook, env []string, dir string) error {
+	ctx, cancel := context.WithTimeout(ctx, h.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", h.Command)
+	cmd.Env = env
+	cmd.Dir = dir
+	// run the hook in its own process group to kill the processes it started along with it
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = hookWaitDelay
+
+	out, err := cmd.CombinedOutput()
+	if o := strings.TrimSpace(string(out)); o != "" {
+		log.Infof("%s", o)
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", h.Timeout)
+	}
+
+	return err
+}
@@ -0,0 +1,174 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func newHooksTestLab(t *testing.T, hooks string, opts ...ClabOption) (*CLab, error) {
+	t.Helper()
+
+	t.Setenv("CLAB_LABDIR_BASE", t.TempDir())
+
+	topo := filepath.Join(t.TempDir(), "hooks.clab.yml")
+	data := "name: hooks\n\ntopology:\n  nodes:\n    node1:\n      kind: linux\n\n" + hooks
+	if err := os.WriteFile(topo, []byte(data), 0644); err != nil { // skipcq: GSC-G306
+		t.Fatal(err)
+	}
+
+	return NewContainerLab(append([]ClabOption{WithTopoPath(topo, "")}, opts...)...)
+}
+
+func TestHooksInit(t *testing.T) {
+	tests := map[string]struct {
+		hooks   string
+		opts    []ClabOption
+		want    *types.Hooks
+		wantErr string
+	}{
+		"defaults": {
+			hooks: `hooks:
+  pre-deploy:
+    - command: echo pre
+  post-destroy:
+    - command: echo post
+      timeout: 5s
+      on-failure: ignore
+`,
+			want: &types.Hooks{
+				PreDeploy: []*types.Hook{
+					{Command: "echo pre", Timeout: time.Minute, OnFailure: types.HookFailurePolicyFail},
+				},
+				PostDestroy: []*types.Hook{
+					{Command: "echo post", Timeout: 5 * time.Second, OnFailure: types.HookFailurePolicyIgnore},
+				},
+			},
+		},
+		"no-hooks": {},
+		"no-hooks-disallowed": {
+			opts: []ClabOption{WithHooksDisallowed()},
+		},
+		"disallowed": {
+			hooks: `hooks:
+  post-deploy:
+    - command: echo post
+`,
+			opts:    []ClabOption{WithHooksDisallowed()},
+			wantErr: ErrHooksNotAllowed.Error(),
+		},
+		"invalid-policy": {
+			hooks: `hooks:
+  post-deploy:
+    - command: echo post
+      on-failure: retry
+`,
+			wantErr: `invalid on-failure policy "retry"`,
+		},
+		"no-command": {
+			hooks: `hooks:
+  pre-destroy:
+    - timeout: 5s
+`,
+			wantErr: "pre-destroy hook #1 has no command",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := newHooksTestLab(t, tt.hooks, tt.opts...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if d := cmp.Diff(tt.want, c.Config.Hooks); d != "" {
+				t.Errorf("hooks mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestRunHooks(t *testing.T) {
+	// the env vars are escaped with $$ to not be expanded when the topology is parsed
+	out := filepath.Join(t.TempDir(), "out")
+
+	c, err := newHooksTestLab(t, `hooks:
+  pre-deploy:
+    - command: "false"
+      on-failure: ignore
+    - command: echo "$$CLAB_HOOK_EVENT $$CLAB_LAB_NAME $$CLAB_LAB_DIR $$CLAB_TOPOLOGY_DATA $(pwd)" > `+out+`
+  post-deploy:
+    - command: exit 3
+    - command: touch `+out+`.not-run
+  pre-destroy:
+    - command: sleep 10
+      timeout: 100ms
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	if err := c.runHooks(ctx, types.HookPreDeploy); err != nil {
+		t.Fatalf("pre-deploy hooks failed: %v", err)
+	}
+
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		"pre-deploy",
+		"hooks",
+		c.TopoPaths.TopologyLabDir(),
+		c.TopoPaths.TopoExportFile(),
+		c.TopoPaths.TopologyFileDir(),
+	}, " ")
+	if got := strings.TrimSpace(string(b)); got != want {
+		t.Errorf("hook environment mismatch, want %q, got %q", want, got)
+	}
+
+	err = c.runHooks(ctx, types.HookPostDeploy)
+	if err == nil || !strings.Contains(err.Error(), `post-deploy hook "exit 3" failed`) {
+		t.Errorf("expected post-deploy hook failure, got %v", err)
+	}
+
+	if _, err := os.Stat(out + ".not-run"); !os.IsNotExist(err) {
+		t.Errorf("hook after the failed one should not run")
+	}
+
+	start := time.Now()
+
+	err = c.runHooks(ctx, types.HookPreDestroy)
+	if err == nil || !strings.Contains(err.Error(), "timed out after 100ms") {
+		t.Errorf("expected pre-destroy hook timeout, got %v", err)
+	}
+
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("timed out hook was not stopped")
+	}
+
+	if err := c.runHooks(ctx, types.HookPostDestroy); err != nil {
+		t.Errorf("running no hooks failed: %v", err)
+	}
+}
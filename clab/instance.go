@@ -0,0 +1,314 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/big"
+	"net/netip"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+)
+
+const (
+	// DefaultAutoSubnetIPv4Supernet is the default supernet the IPv4 management subnets are picked from.
+	DefaultAutoSubnetIPv4Supernet = "172.20.0.0/16"
+	// DefaultAutoSubnetIPv6Supernet is the default supernet the IPv6 management subnets are picked from.
+	DefaultAutoSubnetIPv6Supernet = "3fff:172:20::/48"
+
+	// maxAutoSubnetCandidates limits the number of candidate subnets checked for availability.
+	maxAutoSubnetCandidates = 1 << 16
+)
+
+var instanceIDRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// WithInstance makes the lab an instance of the topology identified by the instance id,
+// so that several instances of the same topology can run on the same host.
+// The instance id is appended to the lab name and to the management and extra network names.
+// Must be called after WithTopoPath.
+func WithInstance(id string) ClabOption {
+	return func(c *CLab) error {
+		if id == "" {
+			return nil
+		}
+
+		if !instanceIDRe.MatchString(id) {
+			return fmt.Errorf("invalid instance id %q, only letters, digits, dashes and underscores are allowed", id)
+		}
+
+		if c.Config.Mgmt.Bridge != "" {
+			return fmt.Errorf("management bridge %q can not be shared by the lab instances, remove it from the topology",
+				c.Config.Mgmt.Bridge)
+		}
+
+		c.instance = id
+		c.Config.Name = c.Config.Name + "-" + id
+		c.Config.Mgmt.Network = c.Config.Mgmt.Network + "-" + id
+
+		// with an empty prefix the container names would be the same for all instances
+		if c.Config.Prefix != nil && *c.Config.Prefix == "" {
+			*c.Config.Prefix = "__lab-name"
+		}
+
+		for name, n := range c.Config.Topology.Networks {
+			if n == nil {
+				continue
+			}
+
+			if n.IPv4Subnet != "" || n.IPv6Subnet != "" || n.Bridge != "" {
+				return fmt.Errorf("network %q with static subnets or bridge name can not be shared by the lab instances", name)
+			}
+
+			if n.Network == "" {
+				n.Network = name
+			}
+			n.Network = n.Network + "-" + id
+		}
+
+		return nil
+	}
+}
+
+// WithAutoSubnet picks the management subnets that do not overlap with the existing
+// container runtime networks from the given supernets. The size of the picked subnets
+// matches the size of the management subnets of the topology, and the static management
+// addresses of the nodes are moved to the picked subnets keeping their offsets.
+// If the management network exists already, its subnets are used.
+// Must be called after WithTopoPath and WithRuntime.
+func WithAutoSubnet(v4Supernet, v6Supernet string) ClabOption {
+	return func(c *CLab) error {
+		v4Super, err := netip.ParsePrefix(v4Supernet)
+		if err != nil || !v4Super.Addr().Is4() {
+			return fmt.Errorf("invalid ipv4 supernet %q", v4Supernet)
+		}
+
+		v6Super, err := netip.ParsePrefix(v6Supernet)
+		if err != nil || !v6Super.Addr().Is6() {
+			return fmt.Errorf("invalid ipv6 supernet %q", v6Supernet)
+		}
+
+		return c.autoSubnet(context.Background(), v4Super.Masked(), v6Super.Masked())
+	}
+}
+
+// autoSubnet sets the management subnets to the subnets that are free in the container runtime.
+func (c *CLab) autoSubnet(ctx context.Context, v4Super, v6Super netip.Prefix) error {
+	v4, v6, err := parseIPAMPool(&types.IPAMPool{
+		IPv4Subnet: c.Config.Mgmt.IPv4Subnet,
+		IPv6Subnet: c.Config.Mgmt.IPv6Subnet,
+	}, "management")
+	if err != nil {
+		return err
+	}
+
+	nets, err := c.globalRuntime().ListNets(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list container networks: %w", err)
+	}
+
+	var newV4, newV6 netip.Prefix
+
+	var used []netip.Prefix
+	for _, n := range nets {
+		if n.Network == c.Config.Mgmt.Network {
+			// the network exists already, e.g. when the lab is redeployed
+			newV4, _ = netip.ParsePrefix(n.IPv4Subnet)
+			newV6, _ = netip.ParsePrefix(n.IPv6Subnet)
+
+			return c.rebaseMgmtSubnets(v4, v6, newV4.Masked(), newV6.Masked())
+		}
+
+		for _, s := range []string{n.IPv4Subnet, n.IPv6Subnet} {
+			if p, err := netip.ParsePrefix(s); err == nil {
+				used = append(used, p.Masked())
+			}
+		}
+	}
+
+	newV4, newV6, err = freeSubnets(v4Super, v6Super, v4.Bits(), v6.Bits(), used, c.Config.Mgmt.Network,
+		v4.IsValid(), v6.IsValid())
+	if err != nil {
+		return err
+	}
+
+	var subnets []string
+	for _, p := range []netip.Prefix{newV4, newV6} {
+		if p.IsValid() {
+			subnets = append(subnets, p.String())
+		}
+	}
+
+	log.Infof("Using management network %s with subnets %s", c.Config.Mgmt.Network, strings.Join(subnets, ", "))
+
+	return c.rebaseMgmtSubnets(v4, v6, newV4, newV6)
+}
+
+// freeSubnets returns the first pair of subnets of the given sizes carved out of the supernets
+// that do not overlap with the used subnets. The search starts at the offset derived from the seed,
+// so that concurrent deployments of different instances are unlikely to pick the same subnets.
+func freeSubnets(v4Super, v6Super netip.Prefix, v4Bits, v6Bits int, used []netip.Prefix,
+	seed string, withV4, withV6 bool,
+) (v4, v6 netip.Prefix, err error) {
+	count := maxAutoSubnetCandidates
+	if withV4 {
+		count = min(count, subnetCount(v4Super, v4Bits))
+	}
+	if withV6 {
+		count = min(count, subnetCount(v6Super, v6Bits))
+	}
+
+	if count == 0 {
+		return v4, v6, fmt.Errorf("management subnets do not fit into supernets %s and %s", v4Super, v6Super)
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(seed))
+	start := int(h.Sum32() % uint32(count))
+
+	for i := range count {
+		idx := (start + i) % count
+
+		if withV4 {
+			if v4, err = subnetAt(v4Super, v4Bits, idx); err != nil {
+				return v4, v6, err
+			}
+			if overlaps(v4, used) {
+				continue
+			}
+		}
+
+		if withV6 {
+			if v6, err = subnetAt(v6Super, v6Bits, idx); err != nil {
+				return v4, v6, err
+			}
+			if overlaps(v6, used) {
+				continue
+			}
+		}
+
+		return v4, v6, nil
+	}
+
+	return netip.Prefix{}, netip.Prefix{}, fmt.Errorf("no free management subnets left in supernets %s and %s",
+		v4Super, v6Super)
+}
+
+// subnetCount returns the number of subnets of the given size in the supernet,
+// capped by the maximum number of candidates.
+func subnetCount(super netip.Prefix, bits int) int {
+	if bits < super.Bits() {
+		return 0
+	}
+
+	if bits-super.Bits() >= 16 {
+		return maxAutoSubnetCandidates
+	}
+
+	return 1 << (bits - super.Bits())
+}
+
+// overlaps returns true if the prefix overlaps with any of the used prefixes.
+func overlaps(p netip.Prefix, used []netip.Prefix) bool {
+	for _, u := range used {
+		if p.Overlaps(u) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rebaseMgmtSubnets replaces the management subnets and moves the management gateways,
+// ranges and the static management addresses of the nodes to the new subnets.
+func (c *CLab) rebaseMgmtSubnets(oldV4, oldV6, newV4, newV6 netip.Prefix) error {
+	mgmt := c.Config.Mgmt
+
+	type rebase struct {
+		addr     *string
+		old, new netip.Prefix
+	}
+
+	rebases := []rebase{
+		{&mgmt.IPv4Gw, oldV4, newV4},
+		{&mgmt.IPv6Gw, oldV6, newV6},
+		{&mgmt.IPv4Range, oldV4, newV4},
+		{&mgmt.IPv6Range, oldV6, newV6},
+	}
+
+	for _, n := range c.Config.Topology.Nodes {
+		if n == nil {
+			continue
+		}
+
+		rebases = append(rebases,
+			rebase{&n.MgmtIPv4, oldV4, newV4},
+			rebase{&n.MgmtIPv6, oldV6, newV6},
+		)
+	}
+
+	for _, r := range rebases {
+		if *r.addr == "" || !r.old.IsValid() || !r.new.IsValid() {
+			continue
+		}
+
+		a, err := rebaseAddr(*r.addr, r.old, r.new)
+		if err != nil {
+			return err
+		}
+
+		*r.addr = a
+	}
+
+	if newV4.IsValid() {
+		mgmt.IPv4Subnet = newV4.String()
+	}
+
+	if newV6.IsValid() {
+		mgmt.IPv6Subnet = newV6.String()
+	}
+
+	return nil
+}
+
+// rebaseAddr moves the address or prefix from the old subnet to the new one keeping its offset.
+func rebaseAddr(s string, oldP, newP netip.Prefix) (string, error) {
+	addr, err := netip.ParseAddr(s)
+	isPrefix := err != nil
+
+	var bits int
+	if isPrefix {
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid address %q", s)
+		}
+
+		addr, bits = p.Addr(), p.Bits()
+	}
+
+	if !oldP.Contains(addr) {
+		return "", fmt.Errorf("address %s does not belong to the management subnet %s", s, oldP)
+	}
+
+	offset := new(big.Int).Sub(
+		new(big.Int).SetBytes(addr.AsSlice()),
+		new(big.Int).SetBytes(oldP.Addr().AsSlice()),
+	)
+
+	a := new(big.Int).SetBytes(newP.Addr().AsSlice())
+	a.Add(a, offset)
+
+	newAddr, _ := netip.AddrFromSlice(a.FillBytes(make([]byte, newP.Addr().BitLen()/8)))
+
+	if isPrefix {
+		return netip.PrefixFrom(newAddr, bits).String(), nil
+	}
+
+	return newAddr.String(), nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/srl-labs/containerlab/mocks/mockruntime"
+	"github.com/srl-labs/containerlab/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestInstanceAutoSubnet(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	crMock := mockruntime.NewMockContainerRuntime(mockCtrl)
+	crMock.EXPECT().ListNets(gomock.Any()).Return([]*types.Network{
+		{Network: "bridge", IPv4Subnet: "172.17.0.0/16"},
+		{Network: "clab-ci-0", IPv4Subnet: "172.30.0.0/24"},
+		{Network: "clab-ci-2", IPv4Subnet: "172.30.1.0/24"},
+		{Network: "clab-ci-3", IPv4Subnet: "172.30.2.0/24"},
+	}, nil)
+
+	withMockRuntime := func(c *CLab) error {
+		c.globalRuntimeName = "mock"
+		c.Runtimes["mock"] = crMock
+		return nil
+	}
+
+	c, err := NewContainerLab(
+		WithTopoPath("test_data/topo1.yml", ""),
+		WithManagementIpv4Subnet("172.100.100.0/24"),
+		WithManagementIpv6Subnet(""),
+		WithInstance("ci-1"),
+		withMockRuntime,
+		WithAutoSubnet("172.30.0.0/22", DefaultAutoSubnetIPv6Supernet),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Config.Name != "topo1-ci-1" || c.Config.Mgmt.Network != "clab-ci-1" {
+		t.Fatalf("unexpected lab name %q or network %q", c.Config.Name, c.Config.Mgmt.Network)
+	}
+
+	// the only subnet of the supernet which is not used by the other networks
+	if c.Config.Mgmt.IPv4Subnet != "172.30.3.0/24" {
+		t.Errorf("unexpected mgmt subnet %q", c.Config.Mgmt.IPv4Subnet)
+	}
+
+	if got := c.Nodes["node1"].Config().MgmtIPv4Address; got != "172.30.3.11" {
+		t.Errorf("unexpected node1 mgmt address %q", got)
+	}
+
+	if got := c.Nodes["node1"].Config().LongName; got != "clab-topo1-ci-1-node1" {
+		t.Errorf("unexpected node1 container name %q", got)
+	}
+}
+
+func TestFreeSubnets(t *testing.T) {
+	used := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/24"),
+		netip.MustParsePrefix("10.0.1.0/24"),
+		netip.MustParsePrefix("2001:db8:0:2::/64"),
+	}
+
+	v4, v6, err := freeSubnets(netip.MustParsePrefix("10.0.0.0/22"), netip.MustParsePrefix("2001:db8::/48"),
+		24, 64, used, "", true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first two v4 and the third v6 candidates are used
+	if v4.String() != "10.0.3.0/24" || v6.String() != "2001:db8:0:3::/64" {
+		t.Errorf("got %s and %s", v4, v6)
+	}
+
+	_, _, err = freeSubnets(netip.MustParsePrefix("10.0.0.0/23"), netip.Prefix{},
+		24, 0, used, "", true, false)
+	if err == nil {
+		t.Error("expected the supernet to be exhausted")
+	}
+}
+
+func TestRebaseAddr(t *testing.T) {
+	tests := map[string]struct {
+		addr    string
+		old     string
+		new     string
+		want    string
+		wantErr bool
+	}{
+		"ipv4 address": {
+			addr: "172.20.20.11", old: "172.20.20.0/24", new: "172.20.33.0/24",
+			want: "172.20.33.11",
+		},
+		"ipv4 range": {
+			addr: "172.20.20.128/25", old: "172.20.20.0/24", new: "172.20.33.0/24",
+			want: "172.20.33.128/25",
+		},
+		"ipv6 address": {
+			addr: "3fff:172:20:20::11", old: "3fff:172:20:20::/64", new: "3fff:172:20:ab::/64",
+			want: "3fff:172:20:ab::11",
+		},
+		"address outside of the subnet": {
+			addr: "10.0.0.1", old: "172.20.20.0/24", new: "172.20.33.0/24",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := rebaseAddr(tt.addr, netip.MustParsePrefix(tt.old), netip.MustParsePrefix(tt.new))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("rebaseAddr() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("rebaseAddr() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
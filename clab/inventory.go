@@ -6,22 +6,30 @@ package clab
 
 import (
 	_ "embed"
+	"fmt"
 	"io"
 	"os"
 	"sort"
 	"text/template"
 
 	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
 )
 
 //go:embed inventory_ansible.go.tpl
 var ansibleInvT string
 
+// inventoryFileMode is the mode of the inventory files, as they carry the node credentials.
+const inventoryFileMode = 0600
+
 // AnsibleInventoryNode represents the data structure used to generate the ansible inventory file.
-// It embeds the NodeConfig struct and adds the Username and Password fields extracted from
-// the node registry.
+// It embeds the NodeConfig struct and adds the Username, Password and SSHKey fields
+// set when the node credentials differ from the credentials of its kind.
 type AnsibleInventoryNode struct {
 	*types.NodeConfig
+	Username string
+	Password string
+	SSHKey   string
 }
 
 // KindProps is the kind properties structure used to generate the ansible inventory file.
@@ -42,10 +50,44 @@ type AnsibleInventory struct {
 	Groups map[string][]*AnsibleInventoryNode
 }
 
+// InventoryGenerator generates an inventory of the lab nodes for an external tool.
+type InventoryGenerator interface {
+	// Name returns the name of the inventory.
+	Name() string
+	// Generate writes the inventory of the lab to the lab directory.
+	Generate(c *CLab) error
+}
+
+// inventoryGenerators are the inventory generators run on deploy.
+var inventoryGenerators = []InventoryGenerator{
+	&ansibleInventoryGenerator{},
+	&nornirInventoryGenerator{},
+	&netboxInventoryGenerator{},
+}
+
+// RegisterInventoryGenerator adds the inventory generator to the generators run on deploy.
+func RegisterInventoryGenerator(g InventoryGenerator) {
+	inventoryGenerators = append(inventoryGenerators, g)
+}
+
 // GenerateInventories generate various inventory files and writes it to a lab location.
 func (c *CLab) GenerateInventories() error {
-	ansibleInvFPath := c.TopoPaths.AnsibleInventoryFileAbsPath()
-	f, err := os.Create(ansibleInvFPath)
+	for _, g := range inventoryGenerators {
+		if err := g.Generate(c); err != nil {
+			return fmt.Errorf("failed to generate the %s inventory: %w", g.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// ansibleInventoryGenerator generates the Ansible inventory file.
+type ansibleInventoryGenerator struct{}
+
+func (*ansibleInventoryGenerator) Name() string { return "ansible" }
+
+func (*ansibleInventoryGenerator) Generate(c *CLab) error {
+	f, err := createInventoryFile(c.TopoPaths.AnsibleInventoryFileAbsPath())
 	if err != nil {
 		return err
 	}
@@ -58,6 +100,28 @@ func (c *CLab) GenerateInventories() error {
 	return f.Close()
 }
 
+// createInventoryFile creates or truncates the inventory file, making it accessible
+// only by its owner, which is the user that called containerlab via sudo.
+func createInventoryFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, inventoryFileMode)
+	if err != nil {
+		return nil, err
+	}
+
+	// the mode of an existing file is kept by OpenFile
+	if err := f.Chmod(inventoryFileMode); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if err := utils.SetUIDAndGID(path); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
+
 // generateAnsibleInventory generates and writes ansible inventory file to w.
 func (c *CLab) generateAnsibleInventory(w io.Writer) error {
 	inv := AnsibleInventory{
@@ -78,17 +142,27 @@ func (c *CLab) generateAnsibleInventory(w io.Writer) error {
 		inv.Kinds[n.Config().Kind] = kindProps
 
 		// add username and password to kind properties
-		// assumption is that all nodes of the same kind have the same credentials
-		nodeRegEntry := c.Reg.Kind(n.Config().Kind)
-		if nodeRegEntry != nil {
-			kindProps.Username = nodeRegEntry.GetCredentials().GetUsername()
-			kindProps.Password = nodeRegEntry.GetCredentials().GetPassword()
+		// the kind properties carry the default credentials of the kind
+		// and the nodes with different credentials get them as host variables
+		kindCreds := c.Reg.Kind(n.Config().Kind).GetCredentials()
+		kindProps.Username = kindCreds.GetUsername()
+		kindProps.Password = kindCreds.GetPassword()
+
+		nodeCreds := kindCreds.Merge(n.Config().Credentials)
+		if nodeCreds.GetUsername() != kindProps.Username {
+			ansibleNode.Username = nodeCreds.GetUsername()
+		}
+
+		if nodeCreds.GetPassword() != kindProps.Password {
+			ansibleNode.Password = nodeCreds.GetPassword()
 		}
 
-		// add network_os to the node
-		kindProps.setNetworkOS(n.Config().Kind)
-		// add ansible_connection to the node
-		kindProps.setAnsibleConnection(n.Config().Kind)
+		ansibleNode.SSHKey = nodeCreds.GetSSHKey()
+
+		// add network_os and ansible_connection to the node
+		platform := c.kindPlatform(n.Config().Kind)
+		kindProps.NetworkOS = platform.ansibleNetworkOS
+		kindProps.AnsibleConn = platform.ansibleConn
 
 		inv.Nodes[n.Config().Kind] = append(inv.Nodes[n.Config().Kind], ansibleNode)
 
@@ -124,22 +198,66 @@ func (c *CLab) generateAnsibleInventory(w io.Writer) error {
 	return err
 }
 
-// setNetworkOS sets the network_os variable for the kind.
-func (n *KindProps) setNetworkOS(kind string) {
-	switch kind {
-	case "nokia_srlinux", "srl":
-		n.NetworkOS = "nokia.srlinux.srlinux"
-	case "nokia_sros", "vr-sros":
-		n.NetworkOS = "nokia.sros.md"
-	}
+// kindPlatform is the platform of a node kind as known to the automation tools.
+type kindPlatform struct {
+	// ansibleNetworkOS is the ansible network_os of the kind.
+	ansibleNetworkOS string
+	// ansibleConn is the ansible_connection of the kind.
+	ansibleConn string
+	// nornir is the platform of the scrapli and netmiko Nornir connection plugins.
+	nornir string
+}
+
+var (
+	srlPlatform   = kindPlatform{"nokia.srlinux.srlinux", "ansible.netcommon.httpapi", "nokia_srl"}
+	srosPlatform  = kindPlatform{"nokia.sros.md", "ansible.netcommon.network_cli", "nokia_sros"}
+	eosPlatform   = kindPlatform{nornir: "arista_eos"}
+	iosxrPlatform = kindPlatform{nornir: "cisco_iosxr"}
+	iosxePlatform = kindPlatform{nornir: "cisco_iosxe"}
+	nxosPlatform  = kindPlatform{nornir: "cisco_nxos"}
+	junosPlatform = kindPlatform{nornir: "juniper_junos"}
+	vrpPlatform   = kindPlatform{nornir: "huawei_vrp"}
+	linuxPlatform = kindPlatform{nornir: "linux"}
+)
+
+// kindPlatforms are the platforms of the node kinds keyed by the kind name.
+var kindPlatforms = map[string]kindPlatform{
+	"nokia_srlinux":         srlPlatform,
+	"nokia_sros":            srosPlatform,
+	"arista_ceos":           eosPlatform,
+	"arista_veos":           eosPlatform,
+	"cisco_xrd":             iosxrPlatform,
+	"cisco_xrv":             iosxrPlatform,
+	"cisco_xrv9k":           iosxrPlatform,
+	"cisco_c8000":           iosxrPlatform,
+	"cisco_csr1000v":        iosxePlatform,
+	"cisco_c8000v":          iosxePlatform,
+	"cisco_cat9kv":          iosxePlatform,
+	"cisco_iol":             iosxePlatform,
+	"cisco_n9kv":            nxosPlatform,
+	"juniper_crpd":          junosPlatform,
+	"juniper_vmx":           junosPlatform,
+	"juniper_vqfx":          junosPlatform,
+	"juniper_vsrx":          junosPlatform,
+	"juniper_vjunosrouter":  junosPlatform,
+	"juniper_vjunosswitch":  junosPlatform,
+	"juniper_vjunosevolved": junosPlatform,
+	"huawei_vrp":            vrpPlatform,
+	"linux":                 linuxPlatform,
 }
 
-// setAnsibleConnection sets the ansible_connection variable for the kind.
-func (n *KindProps) setAnsibleConnection(kind string) {
-	switch kind {
-	case "nokia_srlinux", "srl":
-		n.AnsibleConn = "ansible.netcommon.httpapi"
-	case "nokia_sros", "vr-sros":
-		n.AnsibleConn = "ansible.netcommon.network_cli"
+// kindPlatform returns the platform of the kind, which may be referred to by any of its names.
+// The zero platform is returned for the kinds unknown to the automation tools.
+func (c *CLab) kindPlatform(kind string) kindPlatform {
+	if p, ok := kindPlatforms[kind]; ok {
+		return p
 	}
+
+	for _, name := range c.Reg.Kind(kind).GetKindNames() {
+		if p, ok := kindPlatforms[name]; ok {
+			return p
+		}
+	}
+
+	return kindPlatform{}
 }
@@ -34,6 +34,15 @@ all:
         {{.LongName}}:
         {{- if not (eq (index .Labels "ansible-no-host-var") "true") }}
           ansible_host: {{.MgmtIPv4Address}}
+        {{- end }}
+        {{- if .Username }}
+          ansible_user: {{.Username}}
+        {{- end }}
+        {{- if .Password }}
+          ansible_password: {{.Password}}
+        {{- end }}
+        {{- if .SSHKey }}
+          ansible_ssh_private_key_file: {{.SSHKey}}
         {{- end -}}
       {{- end}}
 {{- end}}
@@ -0,0 +1,364 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	netboxBundleFileName = "netbox.json"
+	// netboxInterfaceType is the type of the node interfaces, the physical type allows cabling them.
+	netboxInterfaceType = "other"
+	netboxStatusActive  = "active"
+	netboxRoleColor     = "9e9e9e"
+	netboxCableEndType  = "dcim.interface"
+	netboxManufacturer  = "Generic"
+)
+
+// netboxManufacturers maps the vendor prefixes of the kind names to the manufacturer names.
+var netboxManufacturers = map[string]string{
+	"arista":     "Arista",
+	"checkpoint": "Check Point",
+	"cisco":      "Cisco",
+	"dell":       "Dell",
+	"fortinet":   "Fortinet",
+	"huawei":     "Huawei",
+	"ipinfusion": "IP Infusion",
+	"juniper":    "Juniper",
+	"nokia":      "Nokia",
+}
+
+var netboxSlugRegexp = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// NetBoxSite is a NetBox site, the lab nodes are placed in the site named after the lab.
+type NetBoxSite struct {
+	Name   string `json:"name"`
+	Slug   string `json:"slug"`
+	Status string `json:"status"`
+}
+
+// NetBoxManufacturer is a NetBox manufacturer of the device types.
+type NetBoxManufacturer struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// NetBoxDeviceRole is a NetBox device role, derived from the node group or kind.
+type NetBoxDeviceRole struct {
+	Name  string `json:"name"`
+	Slug  string `json:"slug"`
+	Color string `json:"color"`
+}
+
+// NetBoxDeviceType is a NetBox device type, derived from the node type or kind.
+type NetBoxDeviceType struct {
+	Manufacturer string `json:"manufacturer"`
+	Model        string `json:"model"`
+	Slug         string `json:"slug"`
+}
+
+// NetBoxDevice is a NetBox device representing a lab node.
+type NetBoxDevice struct {
+	Name         string `json:"name"`
+	Role         string `json:"role"`
+	Manufacturer string `json:"manufacturer"`
+	DeviceType   string `json:"device_type"`
+	Site         string `json:"site"`
+	Status       string `json:"status"`
+}
+
+// NetBoxInterface is a NetBox interface of a device.
+type NetBoxInterface struct {
+	Device  string `json:"device"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+	MTU     int    `json:"mtu,omitempty"`
+}
+
+// NetBoxCable is a NetBox cable connecting the interfaces of two devices.
+type NetBoxCable struct {
+	SideADevice string `json:"side_a_device"`
+	SideAType   string `json:"side_a_type"`
+	SideAName   string `json:"side_a_name"`
+	SideBDevice string `json:"side_b_device"`
+	SideBType   string `json:"side_b_type"`
+	SideBName   string `json:"side_b_name"`
+	Status      string `json:"status"`
+}
+
+// NetBoxImport is the bundle of the NetBox objects representing the lab.
+// The objects are listed in the order they are to be imported in.
+type NetBoxImport struct {
+	Sites         []*NetBoxSite         `json:"sites"`
+	Manufacturers []*NetBoxManufacturer `json:"manufacturers"`
+	DeviceRoles   []*NetBoxDeviceRole   `json:"device_roles"`
+	DeviceTypes   []*NetBoxDeviceType   `json:"device_types"`
+	Devices       []*NetBoxDevice       `json:"devices"`
+	Interfaces    []*NetBoxInterface    `json:"interfaces"`
+	Cables        []*NetBoxCable        `json:"cables"`
+}
+
+// netboxInventoryGenerator generates the NetBox import files.
+type netboxInventoryGenerator struct{}
+
+func (*netboxInventoryGenerator) Name() string { return "netbox" }
+
+func (*netboxInventoryGenerator) Generate(c *CLab) error {
+	dir := c.TopoPaths.NetBoxImportDir()
+	utils.CreateDirectory(dir, 0755)
+
+	imp := c.netboxImport()
+
+	b, err := json.MarshalIndent(imp, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(dir, netboxBundleFileName), b, 0644) // skipcq: GSC-G306
+	if err != nil {
+		return err
+	}
+
+	for name, records := range imp.csvRecords() {
+		if err := writeCSV(filepath.Join(dir, name), records); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// netboxImport builds the NetBox objects of the lab nodes and the links between them.
+// The links to the host and the management network are not represented as cables.
+func (c *CLab) netboxImport() *NetBoxImport {
+	site := &NetBoxSite{
+		Name:   c.Config.Name,
+		Slug:   netboxSlug(c.Config.Name),
+		Status: netboxStatusActive,
+	}
+
+	imp := &NetBoxImport{
+		Sites:      []*NetBoxSite{site},
+		Interfaces: []*NetBoxInterface{},
+		Cables:     []*NetBoxCable{},
+	}
+
+	manufacturers := map[string]bool{}
+	roles := map[string]bool{}
+	types := map[string]bool{}
+
+	for _, name := range c.sortedNodeNames() {
+		cfg := c.Nodes[name].Config()
+
+		manufacturer := netboxKindManufacturer(append([]string{cfg.Kind}, c.Reg.Kind(cfg.Kind).GetKindNames()...))
+		if !manufacturers[manufacturer] {
+			manufacturers[manufacturer] = true
+			imp.Manufacturers = append(imp.Manufacturers, &NetBoxManufacturer{
+				Name: manufacturer,
+				Slug: netboxSlug(manufacturer),
+			})
+		}
+
+		role := cfg.Group
+		if role == "" {
+			role = cfg.Kind
+		}
+
+		if !roles[role] {
+			roles[role] = true
+			imp.DeviceRoles = append(imp.DeviceRoles, &NetBoxDeviceRole{
+				Name:  role,
+				Slug:  netboxSlug(role),
+				Color: netboxRoleColor,
+			})
+		}
+
+		model := cfg.NodeType
+		if model == "" {
+			model = cfg.Kind
+		}
+
+		if !types[manufacturer+"/"+model] {
+			types[manufacturer+"/"+model] = true
+			imp.DeviceTypes = append(imp.DeviceTypes, &NetBoxDeviceType{
+				Manufacturer: manufacturer,
+				Model:        model,
+				Slug:         netboxSlug(model),
+			})
+		}
+
+		imp.Devices = append(imp.Devices, &NetBoxDevice{
+			Name:         cfg.ShortName,
+			Role:         role,
+			Manufacturer: manufacturer,
+			DeviceType:   model,
+			Site:         site.Name,
+			Status:       netboxStatusActive,
+		})
+
+		for _, ep := range c.Nodes[name].GetEndpoints() {
+			imp.Interfaces = append(imp.Interfaces, &NetBoxInterface{
+				Device:  cfg.ShortName,
+				Name:    ep.GetIfaceDisplayName(),
+				Type:    netboxInterfaceType,
+				Enabled: true,
+				MTU:     ep.GetLink().GetMTU(),
+			})
+		}
+	}
+
+	sort.SliceStable(imp.Interfaces, func(i, j int) bool {
+		if imp.Interfaces[i].Device != imp.Interfaces[j].Device {
+			return imp.Interfaces[i].Device < imp.Interfaces[j].Device
+		}
+		return imp.Interfaces[i].Name < imp.Interfaces[j].Name
+	})
+
+	for _, i := range c.sortedLinkIndexes() {
+		eps := c.Links[i].GetEndpoints()
+		if len(eps) != 2 {
+			continue
+		}
+
+		a, b := eps[0].GetNode().GetShortName(), eps[1].GetNode().GetShortName()
+		if _, ok := c.Nodes[a]; !ok {
+			continue
+		}
+
+		if _, ok := c.Nodes[b]; !ok {
+			continue
+		}
+
+		imp.Cables = append(imp.Cables, &NetBoxCable{
+			SideADevice: a,
+			SideAType:   netboxCableEndType,
+			SideAName:   eps[0].GetIfaceDisplayName(),
+			SideBDevice: b,
+			SideBType:   netboxCableEndType,
+			SideBName:   eps[1].GetIfaceDisplayName(),
+			Status:      netboxStatusActive,
+		})
+	}
+
+	return imp
+}
+
+// csvRecords returns the CSV records of the NetBox objects keyed by the CSV file names.
+// The first record of each file is the header.
+func (imp *NetBoxImport) csvRecords() map[string][][]string {
+	records := map[string][][]string{
+		"sites.csv":         {{"name", "slug", "status"}},
+		"manufacturers.csv": {{"name", "slug"}},
+		"device-roles.csv":  {{"name", "slug", "color"}},
+		"device-types.csv":  {{"manufacturer", "model", "slug"}},
+		"devices.csv":       {{"name", "role", "manufacturer", "device_type", "site", "status"}},
+		"interfaces.csv":    {{"device", "name", "type", "enabled", "mtu"}},
+		"cables.csv": {{
+			"side_a_device", "side_a_type", "side_a_name",
+			"side_b_device", "side_b_type", "side_b_name", "status",
+		}},
+	}
+
+	for _, s := range imp.Sites {
+		records["sites.csv"] = append(records["sites.csv"], []string{s.Name, s.Slug, s.Status})
+	}
+
+	for _, m := range imp.Manufacturers {
+		records["manufacturers.csv"] = append(records["manufacturers.csv"], []string{m.Name, m.Slug})
+	}
+
+	for _, r := range imp.DeviceRoles {
+		records["device-roles.csv"] = append(records["device-roles.csv"], []string{r.Name, r.Slug, r.Color})
+	}
+
+	for _, t := range imp.DeviceTypes {
+		records["device-types.csv"] = append(records["device-types.csv"], []string{t.Manufacturer, t.Model, t.Slug})
+	}
+
+	for _, d := range imp.Devices {
+		records["devices.csv"] = append(records["devices.csv"],
+			[]string{d.Name, d.Role, d.Manufacturer, d.DeviceType, d.Site, d.Status})
+	}
+
+	for _, i := range imp.Interfaces {
+		mtu := ""
+		if i.MTU > 0 {
+			mtu = strconv.Itoa(i.MTU)
+		}
+
+		records["interfaces.csv"] = append(records["interfaces.csv"],
+			[]string{i.Device, i.Name, i.Type, strconv.FormatBool(i.Enabled), mtu})
+	}
+
+	for _, c := range imp.Cables {
+		records["cables.csv"] = append(records["cables.csv"], []string{
+			c.SideADevice, c.SideAType, c.SideAName,
+			c.SideBDevice, c.SideBType, c.SideBName, c.Status,
+		})
+	}
+
+	return records
+}
+
+// sortedNodeNames returns the names of the lab nodes in the alphabetical order.
+func (c *CLab) sortedNodeNames() []string {
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// netboxKindManufacturer returns the manufacturer derived from the vendor prefix
+// of the first of the kind names that has one.
+func netboxKindManufacturer(kindNames []string) string {
+	for _, kind := range kindNames {
+		vendor, _, found := strings.Cut(strings.TrimPrefix(kind, "vr-"), "_")
+		if !found {
+			continue
+		}
+
+		if m, ok := netboxManufacturers[vendor]; ok {
+			return m
+		}
+	}
+
+	return netboxManufacturer
+}
+
+// netboxSlug returns the NetBox slug of the name.
+func netboxSlug(name string) string {
+	return strings.Trim(netboxSlugRegexp.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// writeCSV writes the CSV records to the file.
+func writeCSV(path string, records [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if err := w.WriteAll(records); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,171 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"path/filepath"
+
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/utils"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	nornirHostsFileName    = "hosts.yaml"
+	nornirGroupsFileName   = "groups.yaml"
+	nornirDefaultsFileName = "defaults.yaml"
+)
+
+// NornirInventoryEntry is a host, group or defaults entry of the Nornir SimpleInventory.
+type NornirInventoryEntry struct {
+	Hostname string            `yaml:"hostname,omitempty"`
+	Port     int               `yaml:"port,omitempty"`
+	Username string            `yaml:"username,omitempty"`
+	Password string            `yaml:"password,omitempty"`
+	Platform string            `yaml:"platform,omitempty"`
+	Groups   []string          `yaml:"groups,omitempty"`
+	Data     map[string]string `yaml:"data,omitempty"`
+	// connection plugin options keyed by the plugin name
+	ConnectionOptions map[string]*NornirConnectionOptions `yaml:"connection_options,omitempty"`
+}
+
+// NornirConnectionOptions are the options of a Nornir connection plugin.
+type NornirConnectionOptions struct {
+	Extras map[string]any `yaml:"extras,omitempty"`
+}
+
+// nornirSSHKeyOptions returns the options of the scrapli and netmiko connection plugins
+// authenticating with the given SSH private key.
+func nornirSSHKeyOptions(keyPath string) map[string]*NornirConnectionOptions {
+	return map[string]*NornirConnectionOptions{
+		"scrapli": {Extras: map[string]any{"auth_private_key": keyPath}},
+		"netmiko": {Extras: map[string]any{"use_keys": true, "key_file": keyPath}},
+	}
+}
+
+// NornirInventory represents the Nornir SimpleInventory of the lab.
+type NornirInventory struct {
+	// hosts keyed by the node container names
+	Hosts map[string]*NornirInventoryEntry
+	// groups of the node kinds and user-defined node groups
+	Groups   map[string]*NornirInventoryEntry
+	Defaults *NornirInventoryEntry
+}
+
+// nornirInventoryGenerator generates the Nornir SimpleInventory files.
+type nornirInventoryGenerator struct{}
+
+func (*nornirInventoryGenerator) Name() string { return "nornir" }
+
+func (*nornirInventoryGenerator) Generate(c *CLab) error {
+	dir := c.TopoPaths.NornirInventoryDir()
+	utils.CreateDirectory(dir, 0755)
+
+	inv := c.nornirInventory()
+
+	files := map[string]any{
+		nornirHostsFileName:    inv.Hosts,
+		nornirGroupsFileName:   inv.Groups,
+		nornirDefaultsFileName: inv.Defaults,
+	}
+
+	for name, v := range files {
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		f, err := createInventoryFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+
+		if _, err := f.Write(b); err != nil {
+			f.Close()
+			return err
+		}
+
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nornirInventory builds the Nornir SimpleInventory of the lab nodes.
+// The kind groups carry the default credentials and platform of the kind
+// and the hosts with different credentials get them set on the host level.
+func (c *CLab) nornirInventory() *NornirInventory {
+	inv := &NornirInventory{
+		Hosts:  map[string]*NornirInventoryEntry{},
+		Groups: map[string]*NornirInventoryEntry{},
+		Defaults: &NornirInventoryEntry{
+			Data: map[string]string{"lab": c.Config.Name},
+		},
+	}
+
+	for _, n := range c.Nodes {
+		cfg := n.Config()
+		entry := c.Reg.Kind(cfg.Kind)
+		kindCreds := entry.GetCredentials()
+
+		inv.Groups[cfg.Kind] = &NornirInventoryEntry{
+			Username: kindCreds.GetUsername(),
+			Password: kindCreds.GetPassword(),
+			Platform: c.kindPlatform(cfg.Kind).nornir,
+		}
+
+		host := &NornirInventoryEntry{
+			Hostname: cfg.MgmtIPv4Address,
+			Groups:   []string{cfg.Kind},
+			Data: map[string]string{
+				"short-name": cfg.ShortName,
+				"kind":       cfg.Kind,
+			},
+		}
+
+		if host.Hostname == "" {
+			host.Hostname = cfg.MgmtIPv6Address
+		}
+
+		if p := entry.GetMgmtPorts()[nodes.MgmtServiceSSH]; p != nodes.DefaultMgmtPorts[nodes.MgmtServiceSSH] {
+			host.Port = p
+		}
+
+		nodeCreds := kindCreds.Merge(cfg.Credentials)
+		if nodeCreds.GetUsername() != kindCreds.GetUsername() {
+			host.Username = nodeCreds.GetUsername()
+		}
+
+		if nodeCreds.GetPassword() != kindCreds.GetPassword() {
+			host.Password = nodeCreds.GetPassword()
+		}
+
+		if key := nodeCreds.GetSSHKey(); key != "" {
+			host.ConnectionOptions = nornirSSHKeyOptions(key)
+		}
+
+		if cfg.NodeType != "" {
+			host.Data["type"] = cfg.NodeType
+		}
+
+		if cfg.Image != "" {
+			host.Data["image"] = cfg.Image
+		}
+
+		if cfg.Group != "" {
+			host.Groups = append(host.Groups, cfg.Group)
+
+			if _, ok := inv.Groups[cfg.Group]; !ok {
+				inv.Groups[cfg.Group] = &NornirInventoryEntry{}
+			}
+		}
+
+		inv.Hosts[cfg.LongName] = host
+	}
+
+	return inv
+}
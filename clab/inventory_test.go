@@ -5,6 +5,8 @@
 package clab
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -12,6 +14,11 @@ import (
 )
 
 func TestGenerateAnsibleInventory(t *testing.T) {
+	keyPath, err := filepath.Abs("test_data/keys/id_ed25519")
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	tests := map[string]struct {
 		got  string
 		want string
@@ -77,6 +84,37 @@ func TestGenerateAnsibleInventory(t *testing.T) {
         clab-topo8_ansible_groups-node1:
           ansible_host: 172.100.100.11`,
 		},
+		"credentials": {
+			got: "test_data/topo16-credentials.yml",
+			want: `all:
+  vars:
+    # The generated inventory is assumed to be used from the clab host.
+    # Hence no http proxy should be used. Therefore we make sure the http
+    # module does not attempt using any global http proxy.
+    ansible_httpapi_use_proxy: false
+  children:
+    nokia_srlinux:
+      vars:
+        ansible_network_os: nokia.srlinux.srlinux
+        # default connection type for nodes of this kind
+        # feel free to override this in your inventory
+        ansible_connection: ansible.netcommon.httpapi
+        ansible_user: admin
+        ansible_password: NokiaSrl1!
+      hosts:
+        clab-topo16-node1:
+          ansible_host: 172.100.100.11
+          ansible_ssh_private_key_file: ` + keyPath + `
+        clab-topo16-node2:
+          ansible_host: 172.100.100.12
+          ansible_password: secret
+          ansible_ssh_private_key_file: ` + keyPath + `
+        clab-topo16-node3:
+          ansible_host: 172.100.100.13
+          ansible_user: clab
+          ansible_password: fromenvfile
+          ansible_ssh_private_key_file: ` + keyPath,
+		},
 	}
 
 	for name, tc := range tests {
@@ -101,3 +139,165 @@ func TestGenerateAnsibleInventory(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateNornirInventory(t *testing.T) {
+	c, err := NewContainerLab(WithTopoPath("test_data/topo16-credentials.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inv := c.nornirInventory()
+
+	keyPath, err := filepath.Abs("test_data/keys/id_ed25519")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantGroups := map[string]*NornirInventoryEntry{
+		"nokia_srlinux": {
+			Username: "admin",
+			Password: "NokiaSrl1!",
+			Platform: "nokia_srl",
+		},
+	}
+	if diff := cmp.Diff(wantGroups, inv.Groups); diff != "" {
+		t.Errorf("groups diff: (-want +got)\n%s", diff)
+	}
+
+	wantHosts := map[string]*NornirInventoryEntry{
+		"clab-topo16-node1": {
+			Hostname:          "172.100.100.11",
+			Groups:            []string{"nokia_srlinux"},
+			Data:              map[string]string{"short-name": "node1", "kind": "nokia_srlinux", "type": "ixrd2l"},
+			ConnectionOptions: nornirSSHKeyOptions(keyPath),
+		},
+		"clab-topo16-node2": {
+			Hostname:          "172.100.100.12",
+			Password:          "secret",
+			Groups:            []string{"nokia_srlinux"},
+			Data:              map[string]string{"short-name": "node2", "kind": "nokia_srlinux", "type": "ixrd2l"},
+			ConnectionOptions: nornirSSHKeyOptions(keyPath),
+		},
+		"clab-topo16-node3": {
+			Hostname:          "172.100.100.13",
+			Username:          "clab",
+			Password:          "fromenvfile",
+			Groups:            []string{"nokia_srlinux"},
+			Data:              map[string]string{"short-name": "node3", "kind": "nokia_srlinux", "type": "ixrd2l"},
+			ConnectionOptions: nornirSSHKeyOptions(keyPath),
+		},
+	}
+	if diff := cmp.Diff(wantHosts, inv.Hosts); diff != "" {
+		t.Errorf("hosts diff: (-want +got)\n%s", diff)
+	}
+}
+
+func TestGenerateNetBoxImport(t *testing.T) {
+	c, err := NewContainerLab(WithTopoPath("test_data/topo12.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ResolveLinks(); err != nil {
+		t.Fatal(err)
+	}
+
+	imp := c.netboxImport()
+
+	wantManufacturers := []*NetBoxManufacturer{
+		{Name: "Arista", Slug: "arista"},
+		{Name: "Generic", Slug: "generic"},
+	}
+	if diff := cmp.Diff(wantManufacturers, imp.Manufacturers); diff != "" {
+		t.Errorf("manufacturers diff: (-want +got)\n%s", diff)
+	}
+
+	if len(imp.Devices) != 4 {
+		t.Errorf("expected 4 devices, got %d", len(imp.Devices))
+	}
+
+	var ifaces []string
+	for _, i := range imp.Interfaces {
+		ifaces = append(ifaces, i.Device+":"+i.Name)
+	}
+
+	wantIfaces := []string{"node1:eth1", "node2:eth1", "node2:eth2", "node3:eth1"}
+	if diff := cmp.Diff(wantIfaces, ifaces); diff != "" {
+		t.Errorf("interfaces diff: (-want +got)\n%s", diff)
+	}
+
+	wantCables := []*NetBoxCable{
+		{
+			SideADevice: "node1", SideAType: netboxCableEndType, SideAName: "eth1",
+			SideBDevice: "node2", SideBType: netboxCableEndType, SideBName: "eth1",
+			Status: netboxStatusActive,
+		},
+		{
+			SideADevice: "node2", SideAType: netboxCableEndType, SideAName: "eth2",
+			SideBDevice: "node3", SideBType: netboxCableEndType, SideBName: "eth1",
+			Status: netboxStatusActive,
+		},
+	}
+	if diff := cmp.Diff(wantCables, imp.Cables); diff != "" {
+		t.Errorf("cables diff: (-want +got)\n%s", diff)
+	}
+
+	records := imp.csvRecords()
+	if got := len(records["devices.csv"]); got != 5 {
+		t.Errorf("expected the header and 4 device records, got %d records", got)
+	}
+}
+
+func TestCreateInventoryFile(t *testing.T) {
+	t.Setenv("SUDO_UID", "")
+	os.Unsetenv("SUDO_UID")
+
+	path := filepath.Join(t.TempDir(), "inventory.yml")
+	if err := os.WriteFile(path, []byte("stale content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := createInventoryFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fi.Mode().Perm() != inventoryFileMode {
+		t.Errorf("expected mode %o, got %o", inventoryFileMode, fi.Mode().Perm())
+	}
+
+	if fi.Size() != 0 {
+		t.Errorf("expected the existing file to be truncated, got %d bytes", fi.Size())
+	}
+}
+
+func TestKindPlatform(t *testing.T) {
+	c, err := NewContainerLab(WithTopoPath("test_data/topo16-credentials.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]kindPlatform{
+		"nokia_srlinux": srlPlatform,
+		"srl":           srlPlatform,
+		"vr-sros":       srosPlatform,
+		"ceos":          eosPlatform,
+		"vr-cisco_n9kv": nxosPlatform,
+		"bridge":        {},
+	}
+
+	for kind, want := range tests {
+		if got := c.kindPlatform(kind); got != want {
+			t.Errorf("kind %q: expected platform %+v, got %+v", kind, want, got)
+		}
+	}
+}
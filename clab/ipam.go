@@ -0,0 +1,311 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/types"
+)
+
+const (
+	// node variables populated by IPAM.
+	ipamVarLoopbackIPv4 = "clab_loopback_ipv4"
+	ipamVarLoopbackIPv6 = "clab_loopback_ipv6"
+	// ipamVarInterfaces is a map of interface names to the addresses allocated for them.
+	ipamVarInterfaces = "clab_interfaces"
+
+	// link variables populated by IPAM.
+	// Each variable is a list of two addresses, one per link endpoint.
+	ipamVarLinkIPv4 = "clab_link_ipv4"
+	ipamVarLinkIPv6 = "clab_link_ipv6"
+
+	ipamP2PIPv4Bits      = 31
+	ipamP2PIPv6Bits      = 127
+	ipamLoopbackIPv4Bits = 32
+	ipamLoopbackIPv6Bits = 128
+)
+
+// allocateIPAM allocates the loopback addresses for the nodes and the subnets for
+// the point-to-point links from the IPAM pools defined in the topology.
+// The allocation depends only on the topology definition: nodes are numbered in the
+// alphabetical order of their names and links in the order they are defined.
+// The allocated addresses are stored in the node and link variables.
+func (c *CLab) allocateIPAM() error {
+	ipam := c.Config.Topology.IPAM
+	if ipam == nil {
+		return nil
+	}
+
+	c.IPAM = &types.IPAMAllocations{
+		Loopbacks: map[string]*types.IPAMAddresses{},
+	}
+
+	if err := c.allocateLoopbacks(ipam.Loopback); err != nil {
+		return err
+	}
+
+	return c.allocateP2PLinks(ipam.P2P)
+}
+
+// allocateLoopbacks allocates a loopback address per node from the loopback pool.
+func (c *CLab) allocateLoopbacks(pool *types.IPAMPool) error {
+	v4, v6, err := parseIPAMPool(pool, "loopback")
+	if err != nil || (!v4.IsValid() && !v6.IsValid()) {
+		return err
+	}
+
+	// the addresses are indexed in all topology nodes, so that the node filter doesn't change them
+	for idx, nodeName := range c.topologyNodeNames() {
+		if _, ok := c.Config.Topology.Nodes[nodeName]; !ok {
+			continue
+		}
+
+		addrs := &types.IPAMAddresses{}
+
+		// the first address of the pool is not allocated
+		if v4.IsValid() {
+			p, err := subnetAt(v4, ipamLoopbackIPv4Bits, idx+1)
+			if err != nil {
+				return fmt.Errorf("loopback ipv4 pool: %w", err)
+			}
+			addrs.IPv4 = p.String()
+		}
+
+		if v6.IsValid() {
+			p, err := subnetAt(v6, ipamLoopbackIPv6Bits, idx+1)
+			if err != nil {
+				return fmt.Errorf("loopback ipv6 pool: %w", err)
+			}
+			addrs.IPv6 = p.String()
+		}
+
+		c.IPAM.Loopbacks[nodeName] = addrs
+
+		n, ok := c.Nodes[nodeName]
+		if !ok {
+			continue
+		}
+
+		vars := nodeVars(n.Config())
+		setVarIfUnset(vars, ipamVarLoopbackIPv4, addrs.IPv4)
+		setVarIfUnset(vars, ipamVarLoopbackIPv6, addrs.IPv6)
+	}
+
+	return nil
+}
+
+// allocateP2PLinks allocates a /31 and /127 subnet per veth link from the p2p pool.
+// Links connected to bridge-like nodes are not point-to-point and are skipped,
+// but they still consume a subnet to keep the allocation stable.
+func (c *CLab) allocateP2PLinks(pool *types.IPAMPool) error {
+	v4, v6, err := parseIPAMPool(pool, "p2p")
+	if err != nil || (!v4.IsValid() && !v6.IsValid()) {
+		return err
+	}
+
+	idx := 0
+	for _, ld := range c.Config.Topology.Links {
+		l, ok := ld.Link.(*links.LinkVEthRaw)
+		if !ok || len(l.Endpoints) != 2 {
+			continue
+		}
+
+		linkIdx := idx
+		idx++
+
+		if c.isBridgeEndpoint(l.Endpoints[0]) || c.isBridgeEndpoint(l.Endpoints[1]) {
+			continue
+		}
+
+		// links of the nodes excluded by the node filter are not deployed
+		if c.isFilteredNode(l.Endpoints[0].Node) || c.isFilteredNode(l.Endpoints[1].Node) {
+			continue
+		}
+
+		alloc := &types.IPAMLink{
+			A: &types.IPAMEndpoint{Node: l.Endpoints[0].Node, Interface: l.Endpoints[0].Iface},
+			Z: &types.IPAMEndpoint{Node: l.Endpoints[1].Node, Interface: l.Endpoints[1].Iface},
+		}
+
+		if v4.IsValid() {
+			a, z, err := p2pAddresses(v4, ipamP2PIPv4Bits, linkIdx)
+			if err != nil {
+				return fmt.Errorf("p2p ipv4 pool: %w", err)
+			}
+			alloc.A.IPv4, alloc.Z.IPv4 = a, z
+			setLinkVarIfUnset(&l.LinkCommonParams, ipamVarLinkIPv4, []string{a, z})
+		}
+
+		if v6.IsValid() {
+			a, z, err := p2pAddresses(v6, ipamP2PIPv6Bits, linkIdx)
+			if err != nil {
+				return fmt.Errorf("p2p ipv6 pool: %w", err)
+			}
+			alloc.A.IPv6, alloc.Z.IPv6 = a, z
+			setLinkVarIfUnset(&l.LinkCommonParams, ipamVarLinkIPv6, []string{a, z})
+		}
+
+		c.applyEndpointAllocation(l.Endpoints[0], &alloc.A.IPAMAddresses)
+		c.applyEndpointAllocation(l.Endpoints[1], &alloc.Z.IPAMAddresses)
+
+		c.IPAM.Links = append(c.IPAM.Links, alloc)
+	}
+
+	return nil
+}
+
+// isFilteredNode returns true if the topology node is excluded by the node filter.
+func (c *CLab) isFilteredNode(name string) bool {
+	_, ok := c.Config.Topology.Nodes[name]
+
+	return !ok && slices.Contains(c.topologyNodeNames(), name)
+}
+
+// isBridgeEndpoint returns true if the endpoint belongs to a bridge-like node.
+func (c *CLab) isBridgeEndpoint(e *links.EndpointRaw) bool {
+	n, ok := c.Nodes[e.Node]
+
+	return ok && n.GetLinkEndpointType() == links.LinkEndpointTypeBridge
+}
+
+// applyEndpointAllocation stores the addresses allocated for an endpoint in the variables
+// of the endpoint's node. Nodes with the kernel dataplane also get the addresses
+// configured on the interface, unless the endpoint has its addresses set explicitly.
+func (c *CLab) applyEndpointAllocation(e *links.EndpointRaw, addrs *types.IPAMAddresses) {
+	n, ok := c.Nodes[e.Node]
+	if !ok {
+		return
+	}
+
+	vars := nodeVars(n.Config())
+
+	ifaces, ok := vars[ipamVarInterfaces].(map[string]interface{})
+	if !ok {
+		if _, exists := vars[ipamVarInterfaces]; exists {
+			log.Warnf("node %s: variable %s is set by the user, IPAM allocations are not added to it",
+				e.Node, ipamVarInterfaces)
+			return
+		}
+
+		ifaces = map[string]interface{}{}
+		vars[ipamVarInterfaces] = ifaces
+	}
+
+	iface := map[string]interface{}{}
+	setVarIfUnset(iface, "ipv4", addrs.IPv4)
+	setVarIfUnset(iface, "ipv6", addrs.IPv6)
+	ifaces[e.Iface] = iface
+
+	if !n.Config().KernelDataplane {
+		return
+	}
+
+	if e.IPv4 == "" {
+		e.IPv4 = addrs.IPv4
+	}
+
+	if e.IPv6 == "" {
+		e.IPv6 = addrs.IPv6
+	}
+}
+
+// nodeVars returns the variables of a node, initializing them if needed.
+func nodeVars(cfg *types.NodeConfig) map[string]interface{} {
+	if cfg.Config == nil {
+		cfg.Config = &types.ConfigDispatcher{}
+	}
+
+	if cfg.Config.Vars == nil {
+		cfg.Config.Vars = map[string]interface{}{}
+	}
+
+	return cfg.Config.Vars
+}
+
+// setVarIfUnset sets the non-empty value v for the key k, unless the key is already set.
+func setVarIfUnset(vars map[string]interface{}, k, v string) {
+	if v == "" {
+		return
+	}
+
+	if _, ok := vars[k]; !ok {
+		vars[k] = v
+	}
+}
+
+// setLinkVarIfUnset sets the link variable k to v, unless the variable is already set by the user.
+func setLinkVarIfUnset(l *links.LinkCommonParams, k string, v []string) {
+	if l.Vars == nil {
+		l.Vars = map[string]interface{}{}
+	}
+
+	if _, ok := l.Vars[k]; !ok {
+		l.Vars[k] = v
+	}
+}
+
+// parseIPAMPool parses the subnets of the IPAM pool and verifies their address families.
+func parseIPAMPool(pool *types.IPAMPool, name string) (v4, v6 netip.Prefix, err error) {
+	if pool == nil {
+		return v4, v6, nil
+	}
+
+	if pool.IPv4Subnet != "" {
+		v4, err = netip.ParsePrefix(pool.IPv4Subnet)
+		if err != nil || !v4.Addr().Is4() {
+			return v4, v6, fmt.Errorf("invalid ipv4-subnet %q of the ipam %s pool", pool.IPv4Subnet, name)
+		}
+		v4 = v4.Masked()
+	}
+
+	if pool.IPv6Subnet != "" {
+		v6, err = netip.ParsePrefix(pool.IPv6Subnet)
+		if err != nil || !v6.Addr().Is6() {
+			return v4, v6, fmt.Errorf("invalid ipv6-subnet %q of the ipam %s pool", pool.IPv6Subnet, name)
+		}
+		v6 = v6.Masked()
+	}
+
+	return v4, v6, nil
+}
+
+// p2pAddresses returns the addresses of both ends of the idx-th point-to-point subnet of the pool.
+func p2pAddresses(pool netip.Prefix, bits, idx int) (a, z string, err error) {
+	p, err := subnetAt(pool, bits, idx)
+	if err != nil {
+		return "", "", err
+	}
+
+	return p.String(), netip.PrefixFrom(p.Addr().Next(), bits).String(), nil
+}
+
+// subnetAt returns the idx-th subnet of the given prefix length carved out of the pool.
+func subnetAt(pool netip.Prefix, bits, idx int) (netip.Prefix, error) {
+	if pool.Bits() > bits {
+		return netip.Prefix{}, fmt.Errorf("pool %s is smaller than /%d", pool, bits)
+	}
+
+	i := big.NewInt(int64(idx))
+
+	size := new(big.Int).Lsh(big.NewInt(1), uint(bits-pool.Bits()))
+	if i.Cmp(size) >= 0 {
+		return netip.Prefix{}, fmt.Errorf("pool %s is exhausted", pool)
+	}
+
+	addrBits := pool.Addr().BitLen()
+
+	a := new(big.Int).SetBytes(pool.Addr().AsSlice())
+	a.Add(a, new(big.Int).Lsh(i, uint(addrBits-bits)))
+
+	addr, _ := netip.AddrFromSlice(a.FillBytes(make([]byte, addrBits/8)))
+
+	return netip.PrefixFrom(addr, bits), nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestAllocateIPAM(t *testing.T) {
+	c, err := NewContainerLab(WithTopoPath("test_data/topo13-ipam.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &types.IPAMAllocations{
+		Loopbacks: map[string]*types.IPAMAddresses{
+			"br":  {IPv4: "10.255.0.1/32"},
+			"l1":  {IPv4: "10.255.0.2/32"},
+			"l2":  {IPv4: "10.255.0.3/32"},
+			"srl": {IPv4: "10.255.0.4/32"},
+		},
+		Links: []*types.IPAMLink{
+			{
+				A: &types.IPAMEndpoint{
+					Node: "l1", Interface: "eth1",
+					IPAMAddresses: types.IPAMAddresses{IPv4: "10.0.0.0/31", IPv6: "2001:db8::/127"},
+				},
+				Z: &types.IPAMEndpoint{
+					Node: "l2", Interface: "eth1",
+					IPAMAddresses: types.IPAMAddresses{IPv4: "10.0.0.1/31", IPv6: "2001:db8::1/127"},
+				},
+			},
+			{
+				A: &types.IPAMEndpoint{
+					Node: "srl", Interface: "e1-1",
+					IPAMAddresses: types.IPAMAddresses{IPv4: "10.0.0.2/31", IPv6: "2001:db8::2/127"},
+				},
+				Z: &types.IPAMEndpoint{
+					Node: "l1", Interface: "eth2",
+					IPAMAddresses: types.IPAMAddresses{IPv4: "10.0.0.3/31", IPv6: "2001:db8::3/127"},
+				},
+			},
+		},
+	}
+
+	if d := cmp.Diff(want, c.IPAM); d != "" {
+		t.Fatalf("IPAM allocations mismatch (-want +got):\n%s", d)
+	}
+
+	srlVars := c.Nodes["srl"].Config().Config.Vars
+	if srlVars[ipamVarLoopbackIPv4] != "10.255.0.4/32" {
+		t.Errorf("unexpected srl loopback var %v", srlVars[ipamVarLoopbackIPv4])
+	}
+
+	wantIfaces := map[string]interface{}{
+		"e1-1": map[string]interface{}{"ipv4": "10.0.0.2/31", "ipv6": "2001:db8::2/127"},
+	}
+	if d := cmp.Diff(wantIfaces, srlVars[ipamVarInterfaces]); d != "" {
+		t.Errorf("srl interfaces var mismatch (-want +got):\n%s", d)
+	}
+
+	l := c.Config.Topology.Links[1].Link.(*links.LinkVEthRaw)
+	if d := cmp.Diff([]string{"10.0.0.2/31", "10.0.0.3/31"}, l.Vars[ipamVarLinkIPv4]); d != "" {
+		t.Errorf("link var mismatch (-want +got):\n%s", d)
+	}
+
+	// addresses are configured only on the endpoints of the kernel dataplane nodes
+	if l.Endpoints[0].IPv4 != "" || l.Endpoints[1].IPv4 != "10.0.0.3/31" {
+		t.Errorf("unexpected endpoint addresses %q and %q", l.Endpoints[0].IPv4, l.Endpoints[1].IPv4)
+	}
+
+	// links to bridges are not point-to-point and get no addresses
+	bl := c.Config.Topology.Links[2].Link.(*links.LinkVEthRaw)
+	if bl.Vars != nil || bl.Endpoints[1].IPv4 != "" {
+		t.Errorf("unexpected allocation for the bridge link: %v", bl.Vars)
+	}
+}
+
+func TestAllocateIPAMNodeFilter(t *testing.T) {
+	full, err := NewContainerLab(WithTopoPath("test_data/topo13-ipam.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewContainerLab(
+		WithTopoPath("test_data/topo13-ipam.yml", ""),
+		WithNodeFilter([]string{"l1", "srl"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &types.IPAMAllocations{
+		Loopbacks: map[string]*types.IPAMAddresses{
+			"l1":  full.IPAM.Loopbacks["l1"],
+			"srl": full.IPAM.Loopbacks["srl"],
+		},
+		// the link to the excluded l2 node is not deployed
+		Links: full.IPAM.Links[1:],
+	}
+
+	if d := cmp.Diff(want, c.IPAM); d != "" {
+		t.Fatalf("filtered IPAM allocations mismatch (-want +got):\n%s", d)
+	}
+
+	srlVars := c.Nodes["srl"].Config().Config.Vars
+	if srlVars[ipamVarLoopbackIPv4] != "10.255.0.4/32" {
+		t.Errorf("unexpected srl loopback var %v", srlVars[ipamVarLoopbackIPv4])
+	}
+}
+
+func Test_subnetAt(t *testing.T) {
+	tests := map[string]struct {
+		pool    string
+		bits    int
+		idx     int
+		want    string
+		wantErr bool
+	}{
+		"first ipv4 p2p subnet": {
+			pool: "10.0.0.0/24",
+			bits: 31,
+			idx:  0,
+			want: "10.0.0.0/31",
+		},
+		"ipv4 subnet crossing an octet": {
+			pool: "10.0.0.0/16",
+			bits: 31,
+			idx:  200,
+			want: "10.0.1.144/31",
+		},
+		"ipv6 subnet": {
+			pool: "2001:db8::/64",
+			bits: 127,
+			idx:  8,
+			want: "2001:db8::10/127",
+		},
+		"exhausted pool": {
+			pool:    "10.0.0.0/30",
+			bits:    31,
+			idx:     2,
+			wantErr: true,
+		},
+		"pool smaller than the subnet": {
+			pool:    "10.0.0.0/31",
+			bits:    30,
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := subnetAt(netip.MustParsePrefix(tt.pool), tt.bits, tt.idx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("subnetAt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("subnetAt() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	// hostKeyScanConnTimeout is the timeout of a single connection to the SSH server of a node.
+	hostKeyScanConnTimeout = 5 * time.Second
+	// hostKeyScanHealthInterval is the interval the health of the nodes is checked at.
+	hostKeyScanHealthInterval = 2 * time.Second
+)
+
+// hostKeyAlgorithms are the algorithms the SSH host keys of the nodes are scanned for.
+var hostKeyAlgorithms = []string{
+	ssh.KeyAlgoED25519,
+	ssh.KeyAlgoECDSA256,
+	ssh.KeyAlgoECDSA384,
+	ssh.KeyAlgoECDSA521,
+	ssh.KeyAlgoRSASHA512,
+}
+
+// errHostKeyReceived aborts the SSH handshake once the host key is received.
+var errHostKeyReceived = errors.New("host key received")
+
+// pinHostKeys scans the SSH host keys of the lab nodes once they are healthy and writes them
+// to the lab known_hosts file. The nodes that are not healthy within the timeout
+// or do not run an SSH server are skipped with a warning, as their host keys are not verified.
+// Returns the names of the nodes with the pinned host keys.
+func (c *CLab) pinHostKeys(ctx context.Context, timeout time.Duration) map[string]bool {
+	pinned := map[string]bool{}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		lines = map[string][]string{}
+	)
+
+	for name, n := range c.Nodes {
+		addrs, hostAddr := c.hostKeyAddresses(n)
+		if hostAddr == "" {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(name string, n nodes.Node) {
+			defer wg.Done()
+
+			keys, err := nodeHostKeys(ctx, n, hostAddr)
+			if err != nil {
+				log.Warnf("SSH host key of node %q is not pinned, its host key is not verified by the SSH config: %v",
+					name, err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			for _, k := range keys {
+				lines[name] = append(lines[name], knownhosts.Line(addrs, k))
+			}
+
+			pinned[name] = true
+		}(name, n)
+	}
+
+	wg.Wait()
+
+	if err := writeKnownHosts(c.TopoPaths.KnownHostsFileAbsPath(), lines); err != nil {
+		log.Errorf("failed to write the known_hosts file: %v", err)
+		return map[string]bool{}
+	}
+
+	return pinned
+}
+
+// hostKeyAddresses returns the addresses the node SSH service is reached at,
+// and the address its host keys are scanned at.
+// The scan address is empty for the nodes without the management address.
+func (c *CLab) hostKeyAddresses(n nodes.Node) ([]string, string) {
+	cfg := n.Config()
+
+	port := strconv.Itoa(c.Reg.Kind(cfg.Kind).GetMgmtPorts()[nodes.MgmtServiceSSH])
+
+	var addrs []string
+	for _, h := range []string{cfg.LongName, cfg.MgmtIPv4Address, cfg.MgmtIPv6Address} {
+		if h != "" {
+			addrs = append(addrs, net.JoinHostPort(h, port))
+		}
+	}
+
+	if p, ok := cfg.MgmtPorts[nodes.MgmtServiceSSH]; ok && c.Config.Mgmt.PortPublishing != nil {
+		addrs = append(addrs, net.JoinHostPort(c.publishedHost(), strconv.Itoa(p)))
+	}
+
+	switch {
+	case cfg.MgmtIPv4Address != "":
+		return addrs, net.JoinHostPort(cfg.MgmtIPv4Address, port)
+	case cfg.MgmtIPv6Address != "":
+		return addrs, net.JoinHostPort(cfg.MgmtIPv6Address, port)
+	}
+
+	return nil, ""
+}
+
+// nodeHostKeys waits for the node to become healthy and returns the SSH host keys it offers at addr.
+// The nodes without the healthcheck are scanned right away.
+func nodeHostKeys(ctx context.Context, n nodes.Node, addr string) ([]ssh.PublicKey, error) {
+	for {
+		healthy, err := n.IsHealthy(ctx)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("node is not healthy: %w", ctx.Err())
+		}
+
+		if err != nil || healthy {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("node is not healthy: %w", ctx.Err())
+		case <-time.After(hostKeyScanHealthInterval):
+		}
+	}
+
+	return scanHostKeys(addr)
+}
+
+// scanHostKeys returns the host keys of the supported algorithms the SSH server at addr offers.
+func scanHostKeys(addr string) ([]ssh.PublicKey, error) {
+	var keys []ssh.PublicKey
+
+	for _, algo := range hostKeyAlgorithms {
+		var key ssh.PublicKey
+
+		_, err := ssh.Dial("tcp", addr, &ssh.ClientConfig{
+			User:              "clab",
+			HostKeyAlgorithms: []string{algo},
+			HostKeyCallback: func(_ string, _ net.Addr, k ssh.PublicKey) error {
+				key = k
+				return errHostKeyReceived
+			},
+			Timeout: hostKeyScanConnTimeout,
+		})
+		if key != nil {
+			keys = append(keys, key)
+			continue
+		}
+
+		// the server is not reachable, there is no point in trying the other algorithms
+		var opErr *net.OpError
+		if errors.As(err, &opErr) {
+			return nil, err
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no host keys of the supported algorithms offered by %s", addr)
+	}
+
+	return keys, nil
+}
+
+// writeKnownHosts writes the known_hosts lines of the nodes sorted by the node names to the file.
+func writeKnownHosts(path string, lines map[string][]string) error {
+	names := make([]string, 0, len(lines))
+	for name := range lines {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "# %s\n", name)
+
+		for _, l := range lines[name] {
+			b.WriteString(l + "\n")
+		}
+	}
+
+	return os.WriteFile(path, []byte(b.String()), 0644) // skipcq: GSC-G306
+}
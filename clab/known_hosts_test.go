@@ -0,0 +1,109 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// startSSHServer starts an SSH server offering the ed25519 host key
+// and returns its address and the host key.
+func startSSHServer(t *testing.T) (string, ssh.PublicKey) {
+	t.Helper()
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &ssh.ServerConfig{NoClientAuth: true}
+	cfg.AddHostKey(signer)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				_, _, _, _ = ssh.NewServerConn(conn, cfg)
+			}()
+		}
+	}()
+
+	return l.Addr().String(), signer.PublicKey()
+}
+
+func TestScanHostKeys(t *testing.T) {
+	addr, hostKey := startSSHServer(t)
+
+	keys, err := scanHostKeys(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != 1 || string(keys[0].Marshal()) != string(hostKey.Marshal()) {
+		t.Fatalf("expected the ed25519 host key only, got %d keys", len(keys))
+	}
+
+	// the pinned key is accepted and any other key is rejected
+	path := filepath.Join(t.TempDir(), "known_hosts")
+
+	err = writeKnownHosts(path, map[string][]string{
+		"node1": {knownhosts.Line([]string{"clab-test-node1:22", addr}, keys[0])},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cb, err := knownhosts.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote, _ := net.ResolveTCPAddr("tcp", addr)
+	if err := cb("clab-test-node1:22", remote, hostKey); err != nil {
+		t.Errorf("pinned host key is rejected: %v", err)
+	}
+
+	_, otherKey := startSSHServer(t)
+	if err := cb("clab-test-node1:22", remote, otherKey); err == nil {
+		t.Error("unknown host key is accepted")
+	}
+}
+
+func TestScanHostKeysUnreachable(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := l.Addr().String()
+	l.Close()
+
+	if _, err := scanHostKeys(addr); err == nil {
+		t.Error("expected an error scanning the closed port")
+	}
+}
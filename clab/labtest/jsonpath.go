@@ -0,0 +1,167 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package labtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// pathStep is a step of the JSONPath expression selecting the member by key,
+// the array element by index or all the children with the wildcard.
+type pathStep struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseJSONPath parses the JSONPath expression.
+// The supported subset is the root $, the dot and bracket child members .key and ['key'],
+// the array indexes [0] and [-1] and the wildcards .* and [*].
+func parseJSONPath(path string) ([]pathStep, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("json path %q should start with $", path)
+	}
+
+	var steps []pathStep
+
+	rest := path[1:]
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			return nil, fmt.Errorf("json path %q: recursive descent is not supported", path)
+		case rest[0] == '.':
+			rest = rest[1:]
+
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+
+			key := rest[:end]
+			rest = rest[end:]
+
+			switch key {
+			case "":
+				return nil, fmt.Errorf("json path %q: empty member name", path)
+			case "*":
+				steps = append(steps, pathStep{wildcard: true})
+			default:
+				steps = append(steps, pathStep{key: key})
+			}
+		case rest[0] == '[':
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("json path %q: unclosed bracket", path)
+			}
+
+			sel := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+
+			step, err := parseBracket(sel)
+			if err != nil {
+				return nil, fmt.Errorf("json path %q: %w", path, err)
+			}
+
+			steps = append(steps, step)
+		default:
+			return nil, fmt.Errorf("json path %q: unexpected %q", path, rest)
+		}
+	}
+
+	return steps, nil
+}
+
+// parseBracket parses the selector in the brackets.
+func parseBracket(sel string) (pathStep, error) {
+	if sel == "*" {
+		return pathStep{wildcard: true}, nil
+	}
+
+	if len(sel) >= 2 && (sel[0] == '\'' || sel[0] == '"') && sel[len(sel)-1] == sel[0] {
+		return pathStep{key: sel[1 : len(sel)-1]}, nil
+	}
+
+	i, err := strconv.Atoi(sel)
+	if err != nil {
+		return pathStep{}, fmt.Errorf("unsupported selector [%s]", sel)
+	}
+
+	return pathStep{index: i, isIndex: true}, nil
+}
+
+// selectJSON returns the values the parsed JSONPath expression selects from the document.
+// The missing members and the out of range indexes select nothing.
+func selectJSON(doc interface{}, steps []pathStep) []interface{} {
+	values := []interface{}{doc}
+
+	for _, s := range steps {
+		var next []interface{}
+
+		for _, v := range values {
+			switch v := v.(type) {
+			case map[string]interface{}:
+				switch {
+				case s.wildcard:
+					keys := make([]string, 0, len(v))
+					for k := range v {
+						keys = append(keys, k)
+					}
+
+					sort.Strings(keys)
+
+					for _, k := range keys {
+						next = append(next, v[k])
+					}
+				case !s.isIndex:
+					if child, ok := v[s.key]; ok {
+						next = append(next, child)
+					}
+				}
+			case []interface{}:
+				switch {
+				case s.wildcard:
+					next = append(next, v...)
+				case s.isIndex:
+					i := s.index
+					if i < 0 {
+						i += len(v)
+					}
+
+					if i >= 0 && i < len(v) {
+						next = append(next, v[i])
+					}
+				}
+			}
+		}
+
+		values = next
+	}
+
+	return values
+}
+
+// jsonValueString returns the string form of the JSON value the expectations are matched with,
+// the strings are not quoted and the objects and arrays are encoded as JSON.
+func jsonValueString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	}
+
+	b, _ := json.Marshal(v)
+
+	return string(b)
+}
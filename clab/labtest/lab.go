@@ -0,0 +1,162 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package labtest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/vishvananda/netlink"
+)
+
+// clabLab runs the tests against the nodes of the containerlab lab.
+type clabLab struct {
+	c *clab.CLab
+}
+
+// NewClabLab returns the Lab running the tests against the nodes of the deployed lab,
+// the lab links must be resolved for the interface aliases to be recognized.
+func NewClabLab(c *clab.CLab) Lab {
+	return &clabLab{c: c}
+}
+
+func (l *clabLab) node(name string) (nodes.Node, error) {
+	n, ok := l.c.Nodes[name]
+	if !ok {
+		return nil, fmt.Errorf("node %q is not found in lab %q", name, l.c.Config.Name)
+	}
+
+	return n, nil
+}
+
+// Exec runs the command on the node.
+func (l *clabLab) Exec(ctx context.Context, node string, cmd *exec.ExecCmd) (*exec.ExecResult, error) {
+	n, err := l.node(node)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.RunExec(ctx, cmd)
+}
+
+// InterfaceState returns the operational state of the node interface.
+// The interface is looked up by the name or the alias it has in the topology links,
+// the interfaces not used in the links are looked up in the node network namespace by name.
+func (l *clabLab) InterfaceState(ctx context.Context, node, iface string) (string, error) {
+	n, err := l.node(node)
+	if err != nil {
+		return "", err
+	}
+
+	name := iface
+	for _, ep := range n.GetEndpoints() {
+		if ep.GetIfaceName() == iface || ep.GetIfaceAlias() == iface {
+			name = ep.GetIfaceName()
+			break
+		}
+	}
+
+	var state string
+
+	err = n.ExecFunction(ctx, func(_ ns.NetNS) error {
+		link, err := netlink.LinkByName(name)
+		if err != nil {
+			return err
+		}
+
+		state = link.Attrs().OperState.String()
+
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get the state of interface %s:%s: %w", node, iface, err)
+	}
+
+	return state, nil
+}
+
+// NodeAddress returns the address of the node the from node reaches over the lab links.
+// It is the address of the node interface linked to the from node, or the address of the node loopback interface,
+// the IPv4 addresses are preferred. The addresses are looked up in the node network namespace,
+// so the addresses configured by the network OS in its own namespaces are not found.
+func (l *clabLab) NodeAddress(ctx context.Context, from, node string) (string, error) {
+	n, err := l.node(node)
+	if err != nil {
+		return "", err
+	}
+
+	var ifaces []string
+
+	for _, ep := range n.GetEndpoints() {
+		if ep.GetLink() == nil {
+			continue
+		}
+
+		for _, peer := range ep.GetLink().GetEndpoints() {
+			if peer != ep && peer.GetNode().GetShortName() == from {
+				ifaces = append(ifaces, ep.GetIfaceName())
+			}
+		}
+	}
+
+	ifaces = append(ifaces, "lo")
+
+	var addr string
+
+	err = n.ExecFunction(ctx, func(_ ns.NetNS) error {
+		for _, name := range ifaces {
+			link, err := netlink.LinkByName(name)
+			if err != nil {
+				continue
+			}
+
+			addrs, err := netlink.AddrList(link, netlink.FAMILY_ALL)
+			if err != nil {
+				return err
+			}
+
+			if addr = preferredAddress(addrs); addr != "" {
+				return nil
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get the addresses of node %q: %w", node, err)
+	}
+
+	if addr == "" {
+		return "", fmt.Errorf("node %q has no address on the links to %q nor on its loopback interface, "+
+			"use the IP address as the ping destination", node, from)
+	}
+
+	return addr, nil
+}
+
+// preferredAddress returns the first global unicast IPv4 address, or the first IPv6 one if there are no IPv4 addresses.
+func preferredAddress(addrs []netlink.Addr) string {
+	var v6 string
+
+	for _, a := range addrs {
+		if !a.IP.IsGlobalUnicast() {
+			continue
+		}
+
+		if a.IP.To4() != nil {
+			return a.IP.String()
+		}
+
+		if v6 == "" {
+			v6 = a.IP.String()
+		}
+	}
+
+	return v6
+}
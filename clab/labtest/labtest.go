@@ -0,0 +1,283 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package labtest runs the declarative test suites against the deployed labs
+// and reports their results in the JUnit XML and JSON formats.
+package labtest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DefaultInterval is the time waited between the attempts of a retried test.
+	DefaultInterval = 2 * time.Second
+	// DefaultTimeout is the time a single attempt of a test is allowed to run for.
+	DefaultTimeout = 30 * time.Second
+	// DefaultPingCount is the number of the echo requests sent by the ping test.
+	DefaultPingCount = 3
+	// DefaultInterfaceState is the operational state the interface test expects.
+	DefaultInterfaceState = "up"
+)
+
+// Suite is a set of tests defined in a test file.
+type Suite struct {
+	// Name is the suite name, the test file name without the extension by default.
+	Name string `yaml:"name"`
+	// Defaults are the retry settings applied to the tests that don't set them.
+	Defaults *Retry  `yaml:"defaults"`
+	Tests    []*Test `yaml:"tests"`
+}
+
+// Retry are the settings of the test attempts.
+type Retry struct {
+	// Retries is the number of times a failed test is retried.
+	Retries *int `yaml:"retries"`
+	// Interval is the time waited between the attempts.
+	Interval time.Duration `yaml:"interval"`
+	// Timeout is the time a single attempt is allowed to run for.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Test is a single assertion against the lab.
+// Exactly one of the exec, ping and interface checks is set.
+type Test struct {
+	Name      string          `yaml:"name"`
+	Exec      *ExecCheck      `yaml:"exec"`
+	Expect    *Expect         `yaml:"expect"`
+	Ping      *PingCheck      `yaml:"ping"`
+	Interface *InterfaceCheck `yaml:"interface"`
+	Retry     `yaml:",inline"`
+}
+
+// ExecCheck runs a command on a node, its result is matched with the test expectations.
+type ExecCheck struct {
+	Node string `yaml:"node"`
+	Cmd  string `yaml:"cmd"`
+}
+
+// Expect are the expectations the result of the exec check is matched with.
+type Expect struct {
+	// ReturnCode is the expected return code of the command, 0 by default.
+	ReturnCode *int `yaml:"return-code"`
+	// Stdout is the regular expression the stdout of the command must match.
+	Stdout string `yaml:"stdout"`
+	// Stderr is the regular expression the stderr of the command must match.
+	Stderr string `yaml:"stderr"`
+	// JSON are the expectations of the values in the JSON stdout of the command.
+	JSON []*JSONExpect `yaml:"json"`
+
+	stdout *regexp.Regexp
+	stderr *regexp.Regexp
+}
+
+// JSONExpect is the expectation of the values the JSONPath expression selects from the command output.
+// Every selected value must be equal to Equals and match the Matches regular expression when they are set.
+type JSONExpect struct {
+	Path    string  `yaml:"path"`
+	Equals  *string `yaml:"equals"`
+	Matches string  `yaml:"matches"`
+
+	matches *regexp.Regexp
+}
+
+// PingCheck pings the destination from a node.
+type PingCheck struct {
+	From string `yaml:"from"`
+	// To is the destination address or the name of the node whose address on the links to the From node,
+	// or its loopback address, is pinged.
+	To    string `yaml:"to"`
+	Count int    `yaml:"count"`
+}
+
+// InterfaceCheck checks the operational state of a node interface.
+type InterfaceCheck struct {
+	Node string `yaml:"node"`
+	// Name is the interface name or alias as used in the topology links.
+	Name  string `yaml:"name"`
+	State string `yaml:"state"`
+}
+
+// LoadSuite reads the test suite from the file and validates it.
+func LoadSuite(file string) (*Suite, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Suite{}
+	if err := yaml.UnmarshalStrict(b, s); err != nil {
+		return nil, fmt.Errorf("failed to parse test file %s: %w", file, err)
+	}
+
+	if s.Name == "" {
+		base := filepath.Base(file)
+		s.Name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+
+	if err := s.init(); err != nil {
+		return nil, fmt.Errorf("test file %s: %w", file, err)
+	}
+
+	return s, nil
+}
+
+// init validates the suite tests and sets their defaults.
+func (s *Suite) init() error {
+	if len(s.Tests) == 0 {
+		return fmt.Errorf("no tests defined")
+	}
+
+	defaults := s.Defaults
+	if defaults == nil {
+		defaults = &Retry{}
+	}
+
+	for i, t := range s.Tests {
+		if t == nil {
+			return fmt.Errorf("test #%d is empty", i+1)
+		}
+
+		if t.Name == "" {
+			return fmt.Errorf("test #%d has no name", i+1)
+		}
+
+		if err := t.init(defaults); err != nil {
+			return fmt.Errorf("test %q: %w", t.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (t *Test) init(defaults *Retry) error {
+	checks := 0
+	for _, set := range []bool{t.Exec != nil, t.Ping != nil, t.Interface != nil} {
+		if set {
+			checks++
+		}
+	}
+
+	if checks != 1 {
+		return fmt.Errorf("exactly one of exec, ping and interface checks should be set")
+	}
+
+	if t.Expect != nil && t.Exec == nil {
+		return fmt.Errorf("expect is only supported with the exec check")
+	}
+
+	t.Retry.setDefaults(defaults)
+
+	if *t.Retries < 0 || t.Interval < 0 || t.Timeout < 0 {
+		return fmt.Errorf("retries, interval and timeout can't be negative")
+	}
+
+	switch {
+	case t.Exec != nil:
+		if t.Exec.Node == "" || t.Exec.Cmd == "" {
+			return fmt.Errorf("exec check should have node and cmd set")
+		}
+
+		if t.Expect == nil {
+			t.Expect = &Expect{}
+		}
+
+		return t.Expect.init()
+	case t.Ping != nil:
+		if t.Ping.From == "" || t.Ping.To == "" {
+			return fmt.Errorf("ping check should have from and to set")
+		}
+
+		if t.Ping.Count <= 0 {
+			t.Ping.Count = DefaultPingCount
+		}
+	case t.Interface != nil:
+		if t.Interface.Node == "" || t.Interface.Name == "" {
+			return fmt.Errorf("interface check should have node and name set")
+		}
+
+		if t.Interface.State == "" {
+			t.Interface.State = DefaultInterfaceState
+		}
+	}
+
+	return nil
+}
+
+// setDefaults sets the unset retry settings to the defaults,
+// and the ones unset in the defaults as well to the package defaults.
+func (r *Retry) setDefaults(defaults *Retry) {
+	if r.Retries == nil {
+		r.Retries = defaults.Retries
+	}
+
+	if r.Retries == nil {
+		r.Retries = new(int)
+	}
+
+	if r.Interval == 0 {
+		r.Interval = defaults.Interval
+	}
+
+	if r.Interval == 0 {
+		r.Interval = DefaultInterval
+	}
+
+	if r.Timeout == 0 {
+		r.Timeout = defaults.Timeout
+	}
+
+	if r.Timeout == 0 {
+		r.Timeout = DefaultTimeout
+	}
+}
+
+// init compiles the regular expressions of the expectations.
+func (e *Expect) init() error {
+	if e.ReturnCode == nil {
+		e.ReturnCode = new(int)
+	}
+
+	var err error
+
+	if e.stdout, err = compileRegexp(e.Stdout); err != nil {
+		return fmt.Errorf("invalid stdout expression: %w", err)
+	}
+
+	if e.stderr, err = compileRegexp(e.Stderr); err != nil {
+		return fmt.Errorf("invalid stderr expression: %w", err)
+	}
+
+	for _, j := range e.JSON {
+		if j == nil || j.Path == "" {
+			return fmt.Errorf("json expectation should have path set")
+		}
+
+		if _, err := parseJSONPath(j.Path); err != nil {
+			return err
+		}
+
+		if j.matches, err = compileRegexp(j.Matches); err != nil {
+			return fmt.Errorf("invalid json matches expression: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// compileRegexp compiles the expression, returns nil for the empty one.
+func compileRegexp(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, nil
+	}
+
+	return regexp.Compile(expr)
+}
@@ -0,0 +1,305 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package labtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/clab/exec"
+)
+
+// fakeLab is a Lab returning the canned results.
+type fakeLab struct {
+	// results are the exec results by the node and the command,
+	// the ones with several results are returned in order for the retries.
+	results map[string][]*exec.ExecResult
+	ifaces  map[string]string
+	addrs   map[string]string
+}
+
+func (l *fakeLab) Exec(_ context.Context, node string, cmd *exec.ExecCmd) (*exec.ExecResult, error) {
+	key := node + ": " + cmd.GetCmdString()
+
+	res := l.results[key]
+	if len(res) == 0 {
+		return nil, fmt.Errorf("unexpected exec %q", key)
+	}
+
+	if len(res) > 1 {
+		l.results[key] = res[1:]
+	}
+
+	return res[0], nil
+}
+
+func (l *fakeLab) InterfaceState(_ context.Context, node, iface string) (string, error) {
+	s, ok := l.ifaces[node+":"+iface]
+	if !ok {
+		return "", fmt.Errorf("interface %s:%s not found", node, iface)
+	}
+
+	return s, nil
+}
+
+func (l *fakeLab) NodeAddress(_ context.Context, from, node string) (string, error) {
+	return l.addrs[from+"->"+node], nil
+}
+
+const testSuite = `name: bgp
+defaults:
+  interval: 1ms
+tests:
+  - name: bgp established
+    exec:
+      node: srl1
+      cmd: sr_cli show bgp
+    expect:
+      stdout: established
+      json:
+        - path: $.peers[*].state
+          equals: established
+        - path: $['peers'][0].uptime
+          matches: ^[0-9]+$
+    retries: 2
+  - name: wrong return code
+    exec:
+      node: srl1
+      cmd: "false"
+  - name: ping client2
+    ping:
+      from: client1
+      to: client2
+      count: 1
+  - name: e1-1 up
+    interface:
+      node: srl1
+      name: e1-1
+  - name: e1-2 up
+    interface:
+      node: srl1
+      name: e1-2
+`
+
+func loadTestSuite(t *testing.T, data string) (*Suite, error) {
+	t.Helper()
+
+	f := filepath.Join(t.TempDir(), "suite.yml")
+	if err := os.WriteFile(f, []byte(data), 0644); err != nil { // skipcq: GSC-G306
+		t.Fatal(err)
+	}
+
+	return LoadSuite(f)
+}
+
+func TestLoadSuite(t *testing.T) {
+	s, err := loadTestSuite(t, testSuite)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bgp := s.Tests[0]
+	if *bgp.Retries != 2 || bgp.Interval != time.Millisecond || bgp.Timeout != DefaultTimeout ||
+		*bgp.Expect.ReturnCode != 0 {
+		t.Errorf("unexpected bgp test retry settings %+v", bgp.Retry)
+	}
+
+	if *s.Tests[1].Retries != 0 || s.Tests[2].Ping.Count != 1 || s.Tests[3].Interface.State != "up" {
+		t.Errorf("unexpected test defaults")
+	}
+
+	tests := map[string]struct {
+		data    string
+		wantErr string
+	}{
+		"no-check": {
+			data:    "tests:\n  - name: t1\n",
+			wantErr: "exactly one of exec, ping and interface checks should be set",
+		},
+		"two-checks": {
+			data:    "tests:\n  - name: t1\n    ping: {from: a, to: b}\n    interface: {node: a, name: e1}\n",
+			wantErr: "exactly one of exec, ping and interface checks should be set",
+		},
+		"expect-without-exec": {
+			data:    "tests:\n  - name: t1\n    ping: {from: a, to: b}\n    expect: {stdout: x}\n",
+			wantErr: "expect is only supported with the exec check",
+		},
+		"bad-regexp": {
+			data:    "tests:\n  - name: t1\n    exec: {node: a, cmd: ls}\n    expect: {stdout: '('}\n",
+			wantErr: "invalid stdout expression",
+		},
+		"bad-json-path": {
+			data:    "tests:\n  - name: t1\n    exec: {node: a, cmd: ls}\n    expect: {json: [{path: peers}]}\n",
+			wantErr: `json path "peers" should start with $`,
+		},
+		"unknown-field": {
+			data:    "tests:\n  - name: t1\n    exec: {node: a, command: ls}\n",
+			wantErr: "field command not found",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := loadTestSuite(t, tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	s, err := loadTestSuite(t, testSuite)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lab := &fakeLab{
+		results: map[string][]*exec.ExecResult{
+			"srl1: sr_cli show bgp": {
+				{Stdout: `{"peers": [{"state": "connect", "uptime": 0}]}`},
+				{Stdout: `{"peers": [{"state": "established", "uptime": 10}, {"state": "established", "uptime": 3}]}`},
+			},
+			"srl1: false":                    {{ReturnCode: 1, Stderr: "failed"}},
+			"client1: ping -c 1 172.20.20.3": {{Stdout: "1 packets received"}},
+		},
+		ifaces: map[string]string{"srl1:e1-1": "up", "srl1:e1-2": "down"},
+		addrs:  map[string]string{"client1->client2": "172.20.20.3"},
+	}
+
+	res := Run(context.Background(), lab, s)
+
+	if res.Name != "bgp" || res.Tests != 5 || res.Failures != 2 {
+		t.Fatalf("unexpected suite result %+v", res)
+	}
+
+	type result struct {
+		Name     string
+		Passed   bool
+		Attempts int
+		Failure  string
+	}
+
+	var got []result
+	for _, r := range res.Results {
+		got = append(got, result{r.Name, r.Passed, r.Attempts, r.Failure})
+	}
+
+	want := []result{
+		{"bgp established", true, 2, ""},
+		{"wrong return code", false, 1, "return code 1, expected 0"},
+		{"ping client2", true, 1, ""},
+		{"e1-1 up", true, 1, ""},
+		{"e1-2 up", false, 1, "interface srl1:e1-2 is down, expected up"},
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("results mismatch (-want +got):\n%s", d)
+	}
+
+	var junit bytes.Buffer
+	if err := WriteJUnit(&junit, []*SuiteResult{res}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{
+		`<testsuites name="containerlab" tests="5" failures="2"`,
+		`<testsuite name="bgp" tests="5" failures="2" errors="0" skipped="0"`,
+		`<testcase name="e1-2 up" classname="bgp"`,
+		`<failure message="return code 1, expected 0" type="AssertionError">`,
+		`<system-out>failed</system-out>`,
+	} {
+		if !strings.Contains(junit.String(), s) {
+			t.Errorf("JUnit report doesn't contain %q:\n%s", s, junit.String())
+		}
+	}
+
+	var text bytes.Buffer
+	if err := WriteFailures(&text, []*SuiteResult{res}); err != nil {
+		t.Fatal(err)
+	}
+
+	wantText := `FAIL: bgp/wrong return code (1 attempt(s))
+  return code 1, expected 0
+  output:
+    failed
+FAIL: bgp/e1-2 up (1 attempt(s))
+  interface srl1:e1-2 is down, expected up
+  output:
+    down
+`
+	if d := cmp.Diff(wantText, text.String()); d != "" {
+		t.Errorf("failures report mismatch (-want +got):\n%s", d)
+	}
+
+	var js bytes.Buffer
+	if err := WriteJSON(&js, []*SuiteResult{res}); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded []*SuiteResult
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded) != 1 || decoded[0].Failures != 2 || len(decoded[0].Results) != 5 {
+		t.Errorf("unexpected JSON report %s", js.String())
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(`{
+		"peers": [{"addr": "10.0.0.1", "up": true}, {"addr": "10.0.0.3", "up": false}],
+		"stats": {"in": 5, "out": 7.5},
+		"my-key": null
+	}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string][]string{
+		"$.peers[0].addr":  {"10.0.0.1"},
+		"$.peers[-1].addr": {"10.0.0.3"},
+		"$.peers[*].up":    {"true", "false"},
+		"$['stats'].*":     {"5", "7.5"},
+		`$["my-key"]`:      {"null"},
+		"$.peers[5].addr":  nil,
+		"$.missing":        nil,
+		"$.peers[1]":       {`{"addr":"10.0.0.3","up":false}`},
+		"$.stats[0]":       nil,
+	}
+
+	for path, want := range tests {
+		t.Run(path, func(t *testing.T) {
+			steps, err := parseJSONPath(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, v := range selectJSON(doc, steps) {
+				got = append(got, jsonValueString(v))
+			}
+
+			if d := cmp.Diff(want, got); d != "" {
+				t.Errorf("selected values mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+
+	for _, path := range []string{"peers", "$..addr", "$.peers[", "$.peers[a]", "$.peers."} {
+		if _, err := parseJSONPath(path); err == nil {
+			t.Errorf("expected error parsing %q", path)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package labtest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// junitTestSuites is the root element of the JUnit XML report.
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Time     string            `xml:"time,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Errors    int              `xml:"errors,attr"`
+	Skipped   int              `xml:"skipped,attr"`
+	Time      string           `xml:"time,attr"`
+	Timestamp string           `xml:"timestamp,attr"`
+	Cases     []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitTime formats the time in seconds the way the JUnit reports do.
+func junitTime(s float64) string {
+	return fmt.Sprintf("%.3f", s)
+}
+
+// WriteJUnit writes the suite results as the JUnit XML report.
+func WriteJUnit(w io.Writer, results []*SuiteResult) error {
+	report := &junitTestSuites{Name: "containerlab"}
+
+	var total float64
+
+	for _, s := range results {
+		suite := &junitTestSuite{
+			Name:      s.Name,
+			Tests:     s.Tests,
+			Failures:  s.Failures,
+			Time:      junitTime(s.Time),
+			Timestamp: s.Timestamp.UTC().Format(time.RFC3339),
+		}
+
+		for _, r := range s.Results {
+			tc := &junitTestCase{
+				Name:      r.Name,
+				ClassName: s.Name,
+				Time:      junitTime(r.Time),
+				SystemOut: r.Output,
+			}
+
+			if !r.Passed {
+				tc.Failure = &junitFailure{
+					Message: r.Failure,
+					Type:    "AssertionError",
+					Text:    fmt.Sprintf("%s\nattempts: %d", r.Failure, r.Attempts),
+				}
+			}
+
+			suite.Cases = append(suite.Cases, tc)
+		}
+
+		report.Tests += s.Tests
+		report.Failures += s.Failures
+		total += s.Time
+
+		report.Suites = append(report.Suites, suite)
+	}
+
+	report.Time = junitTime(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// WriteFailures writes the details of the failed tests as text,
+// the failure and the output of the last attempt of each failed test.
+func WriteFailures(w io.Writer, results []*SuiteResult) error {
+	var b strings.Builder
+
+	for _, s := range results {
+		for _, r := range s.Results {
+			if r.Passed {
+				continue
+			}
+
+			fmt.Fprintf(&b, "FAIL: %s/%s (%d attempt(s))\n  %s\n", s.Name, r.Name, r.Attempts, r.Failure)
+
+			if r.Output != "" {
+				b.WriteString("  output:\n")
+
+				for _, line := range strings.Split(r.Output, "\n") {
+					fmt.Fprintf(&b, "    %s\n", line)
+				}
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// WriteJSON writes the suite results as JSON.
+func WriteJSON(w io.Writer, results []*SuiteResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(results)
+}
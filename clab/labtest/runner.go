@@ -0,0 +1,252 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package labtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/clab/exec"
+)
+
+// Lab is the deployed lab the tests are run against.
+type Lab interface {
+	// Exec runs the command on the node.
+	Exec(ctx context.Context, node string, cmd *exec.ExecCmd) (*exec.ExecResult, error)
+	// InterfaceState returns the operational state of the node interface, such as up or down.
+	InterfaceState(ctx context.Context, node, iface string) (string, error)
+	// NodeAddress returns the IP address of the node the from node reaches over the lab links.
+	NodeAddress(ctx context.Context, from, node string) (string, error)
+}
+
+// SuiteResult is the result of the test suite run.
+type SuiteResult struct {
+	Name      string    `json:"name"`
+	Tests     int       `json:"tests"`
+	Failures  int       `json:"failures"`
+	Timestamp time.Time `json:"timestamp"`
+	// Time is the time the suite took to run in seconds.
+	Time    float64       `json:"time"`
+	Results []*TestResult `json:"results"`
+}
+
+// TestResult is the result of a test.
+type TestResult struct {
+	Name     string `json:"name"`
+	Passed   bool   `json:"passed"`
+	Attempts int    `json:"attempts"`
+	// Time is the time the test took to run in seconds, including the retries.
+	Time float64 `json:"time"`
+	// Failure is the reason the last attempt of the failed test failed.
+	Failure string `json:"failure,omitempty"`
+	// Output is the output of the last attempt.
+	Output string `json:"output,omitempty"`
+}
+
+// Run runs the suite tests one after another against the lab.
+func Run(ctx context.Context, lab Lab, s *Suite) *SuiteResult {
+	res := &SuiteResult{
+		Name:      s.Name,
+		Tests:     len(s.Tests),
+		Timestamp: time.Now(),
+		Results:   []*TestResult{},
+	}
+
+	for _, t := range s.Tests {
+		r := runTest(ctx, lab, t)
+		if r.Passed {
+			log.Infof("PASS: %s/%s (%d attempt(s), %.2fs)", s.Name, t.Name, r.Attempts, r.Time)
+		} else {
+			res.Failures++
+			log.Errorf("FAIL: %s/%s (%d attempt(s), %.2fs): %s", s.Name, t.Name, r.Attempts, r.Time, r.Failure)
+		}
+
+		res.Results = append(res.Results, r)
+	}
+
+	res.Time = time.Since(res.Timestamp).Seconds()
+
+	return res
+}
+
+// runTest runs the test check until it passes or the retries are exhausted.
+func runTest(ctx context.Context, lab Lab, t *Test) *TestResult {
+	r := &TestResult{Name: t.Name}
+	start := time.Now()
+
+	defer func() {
+		r.Time = time.Since(start).Seconds()
+	}()
+
+	for {
+		r.Attempts++
+
+		output, err := runCheck(ctx, lab, t)
+		r.Output = output
+
+		if err == nil {
+			r.Passed = true
+			r.Failure = ""
+
+			return r
+		}
+
+		r.Failure = err.Error()
+
+		if r.Attempts > *t.Retries {
+			return r
+		}
+
+		log.Debugf("test %q attempt %d failed: %v", t.Name, r.Attempts, err)
+
+		select {
+		case <-ctx.Done():
+			return r
+		case <-time.After(t.Interval):
+		}
+	}
+}
+
+// runCheck runs a single attempt of the test check and returns its output.
+func runCheck(ctx context.Context, lab Lab, t *Test) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.Timeout)
+	defer cancel()
+
+	switch {
+	case t.Exec != nil:
+		return runExecCheck(ctx, lab, t.Exec, t.Expect)
+	case t.Ping != nil:
+		return runPingCheck(ctx, lab, t.Ping)
+	case t.Interface != nil:
+		return runInterfaceCheck(ctx, lab, t.Interface)
+	}
+
+	return "", fmt.Errorf("no check defined")
+}
+
+func runExecCheck(ctx context.Context, lab Lab, c *ExecCheck, e *Expect) (string, error) {
+	cmd, err := exec.NewExecCmdFromString(c.Cmd)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := lab.Exec(ctx, c.Node, cmd)
+	if err != nil {
+		return "", err
+	}
+
+	return execOutput(res), e.match(res)
+}
+
+func runPingCheck(ctx context.Context, lab Lab, c *PingCheck) (string, error) {
+	addr := c.To
+	if net.ParseIP(addr) == nil {
+		var err error
+		if addr, err = lab.NodeAddress(ctx, c.From, c.To); err != nil {
+			return "", err
+		}
+	}
+
+	cmd := exec.NewExecCmdFromSlice([]string{"ping", "-c", strconv.Itoa(c.Count), addr})
+
+	res, err := lab.Exec(ctx, c.From, cmd)
+	if err != nil {
+		return "", err
+	}
+
+	if res.ReturnCode != 0 {
+		return execOutput(res), fmt.Errorf("ping %s from %s failed with return code %d", addr, c.From, res.ReturnCode)
+	}
+
+	return execOutput(res), nil
+}
+
+func runInterfaceCheck(ctx context.Context, lab Lab, c *InterfaceCheck) (string, error) {
+	state, err := lab.InterfaceState(ctx, c.Node, c.Name)
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.EqualFold(state, c.State) {
+		return state, fmt.Errorf("interface %s:%s is %s, expected %s", c.Node, c.Name, state, c.State)
+	}
+
+	return state, nil
+}
+
+// execOutput returns the stdout and stderr of the command.
+func execOutput(res *exec.ExecResult) string {
+	out := string(res.Stdout)
+	if res.Stderr != "" {
+		out = strings.TrimRight(out, "\n") + "\n" + res.Stderr
+	}
+
+	return strings.TrimSpace(out)
+}
+
+// match returns an error describing the first expectation the command result doesn't meet.
+func (e *Expect) match(res *exec.ExecResult) error {
+	if res.ReturnCode != *e.ReturnCode {
+		return fmt.Errorf("return code %d, expected %d", res.ReturnCode, *e.ReturnCode)
+	}
+
+	if e.stdout != nil && !e.stdout.MatchString(string(res.Stdout)) {
+		return fmt.Errorf("stdout doesn't match %q", e.Stdout)
+	}
+
+	if e.stderr != nil && !e.stderr.MatchString(res.Stderr) {
+		return fmt.Errorf("stderr doesn't match %q", e.Stderr)
+	}
+
+	if len(e.JSON) == 0 {
+		return nil
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal([]byte(res.Stdout), &doc); err != nil {
+		return fmt.Errorf("stdout is not valid JSON: %w", err)
+	}
+
+	for _, j := range e.JSON {
+		if err := j.match(doc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// match returns an error if the values selected from the document don't meet the expectation.
+func (j *JSONExpect) match(doc interface{}) error {
+	steps, err := parseJSONPath(j.Path)
+	if err != nil {
+		return err
+	}
+
+	values := selectJSON(doc, steps)
+	if len(values) == 0 {
+		return fmt.Errorf("json path %s selected no values", j.Path)
+	}
+
+	for _, v := range values {
+		s := jsonValueString(v)
+
+		if j.Equals != nil && s != *j.Equals {
+			return fmt.Errorf("json path %s value %q is not equal to %q", j.Path, s, *j.Equals)
+		}
+
+		if j.matches != nil && !j.matches.MatchString(s) {
+			return fmt.Errorf("json path %s value %q doesn't match %q", j.Path, s, j.Matches)
+		}
+	}
+
+	return nil
+}
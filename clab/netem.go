@@ -0,0 +1,179 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	gotc "github.com/florianl/go-tc"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/internal/tc"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/vishvananda/netlink"
+)
+
+// NetemParams are the link impairments set on an interface.
+type NetemParams struct {
+	Delay  time.Duration
+	Jitter time.Duration
+	// Loss is the random packet loss in percents.
+	Loss float64
+	// Rate is the link rate limit in kbit.
+	Rate uint64
+	// Corruption is the random packet corruption probability in percents.
+	Corruption float64
+}
+
+// Validate checks the impairments are within their ranges.
+func (p *NetemParams) Validate() error {
+	if p.Loss < 0 || p.Loss > 100 {
+		return fmt.Errorf("packet loss must be in the range between 0 and 100")
+	}
+
+	if p.Jitter != 0 && p.Delay == 0 {
+		return fmt.Errorf("jitter cannot be set without setting delay")
+	}
+
+	return nil
+}
+
+// NetemImpairment is the state of the link impairments of an interface.
+type NetemImpairment struct {
+	Interface string `json:"interface"`
+	Alias     string `json:"alias,omitempty"`
+	// Set is false when no netem queue discipline is set on the interface.
+	Set        bool    `json:"set"`
+	Delay      string  `json:"delay,omitempty"`
+	Jitter     string  `json:"jitter,omitempty"`
+	Loss       float64 `json:"packet_loss"`
+	Rate       int     `json:"rate"`
+	Corruption float64 `json:"corruption"`
+}
+
+// SetImpairments sets the link impairments on the interface of the container
+// and returns the resulting impairments of the interface.
+func SetImpairments(ctx context.Context, rt runtime.ContainerRuntime, container, iface string,
+	p *NetemParams,
+) (*NetemImpairment, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	var imp *NetemImpairment
+
+	err := doInNodeTC(ctx, rt, container, func(tcnl *gotc.Tc) error {
+		netemIfLink, err := netlink.LinkByName(links.SanitiseInterfaceName(iface))
+		if err != nil {
+			return err
+		}
+
+		link, err := net.InterfaceByName(netemIfLink.Attrs().Name)
+		if err != nil {
+			return err
+		}
+
+		qdisc, err := tc.SetImpairments(tcnl, container, link, p.Delay, p.Jitter, p.Loss, p.Rate, p.Corruption)
+		if err != nil {
+			return err
+		}
+
+		imp = qdiscToImpairment(qdisc)
+
+		return nil
+	})
+
+	return imp, err
+}
+
+// Impairments returns the link impairments of the container interfaces.
+func Impairments(ctx context.Context, rt runtime.ContainerRuntime, container string) ([]*NetemImpairment, error) {
+	var imps []*NetemImpairment
+
+	err := doInNodeTC(ctx, rt, container, func(tcnl *gotc.Tc) error {
+		qdiscs, err := tc.Impairments(tcnl)
+		if err != nil {
+			return err
+		}
+
+		for i := range qdiscs {
+			imps = append(imps, qdiscToImpairment(&qdiscs[i]))
+		}
+
+		return nil
+	})
+
+	return imps, err
+}
+
+// doInNodeTC runs f in the network namespace of the container with the tc client opened for it.
+func doInNodeTC(ctx context.Context, rt runtime.ContainerRuntime, container string, f func(tcnl *gotc.Tc) error) error {
+	// retrieve the containers NSPath
+	nodeNsPath, err := rt.GetNSPath(ctx, container)
+	if err != nil {
+		return err
+	}
+
+	nodeNs, err := ns.GetNS(nodeNsPath)
+	if err != nil {
+		return err
+	}
+	defer nodeNs.Close()
+
+	tcnl, err := tc.NewTC(int(nodeNs.Fd()))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := tcnl.Close(); err != nil {
+			log.Errorf("could not close rtnetlink socket: %v\n", err)
+		}
+	}()
+
+	return nodeNs.Do(func(_ ns.NetNS) error {
+		return f(tcnl)
+	})
+}
+
+// qdiscToImpairment converts the qdisc to the impairment of its interface,
+// must be called in the namespace of the interface.
+func qdiscToImpairment(qdisc *gotc.Object) *NetemImpairment {
+	imp := &NetemImpairment{}
+
+	link, err := netlink.LinkByIndex(int(qdisc.Ifindex))
+	if err != nil {
+		log.Errorf("could not get netlink interface by index: %v", err)
+	} else {
+		imp.Interface = link.Attrs().Name
+		imp.Alias = link.Attrs().Alias
+	}
+
+	// netem is not set when the qdisc is not set for an interface
+	if qdisc.Netem == nil {
+		return imp
+	}
+
+	imp.Set = true
+
+	if qdisc.Netem.Latency64 != nil {
+		imp.Delay = (time.Duration(*qdisc.Netem.Latency64) * time.Nanosecond).String()
+	}
+
+	if qdisc.Netem.Jitter64 != nil {
+		imp.Jitter = (time.Duration(*qdisc.Netem.Jitter64) * time.Nanosecond).String()
+	}
+
+	imp.Loss = float64(qdisc.Netem.Qopt.Loss) / float64(math.MaxUint32) * 100
+	imp.Rate = int(qdisc.Netem.Rate.Rate * 8 / 1000)
+	imp.Corruption = float64(qdisc.Netem.Corrupt.Probability) / float64(math.MaxUint32) * 100
+
+	return imp
+}
@@ -2,8 +2,14 @@ package clab
 
 import (
 	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
 
 	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/runtime/docker"
+	"github.com/srl-labs/containerlab/types"
 )
 
 func (c *CLab) CreateNetwork(ctx context.Context) error {
@@ -12,6 +18,11 @@ func (c *CLab) CreateNetwork(ctx context.Context) error {
 		return err
 	}
 
+	// create extra networks the nodes can be attached to
+	if err := c.globalRuntime().CreateExtraNets(ctx, c.extraNetworks()); err != nil {
+		return err
+	}
+
 	// save mgmt bridge name as a label
 	for _, n := range c.Nodes {
 		n.Config().Labels[labels.NodeMgmtNetBr] = c.globalRuntime().Mgmt().Bridge
@@ -19,3 +30,139 @@ func (c *CLab) CreateNetwork(ctx context.Context) error {
 
 	return nil
 }
+
+// initExtraNetworks sets the runtime network names of the extra networks defined in the topology.
+// Unless set explicitly, the runtime network name matches the name of the network in the topology.
+func (c *CLab) initExtraNetworks() {
+	for name, n := range c.Config.Topology.Networks {
+		if n == nil {
+			n = &types.Network{}
+			c.Config.Topology.Networks[name] = n
+		}
+
+		if n.Network == "" {
+			n.Network = name
+		}
+	}
+}
+
+// extraNetworks returns the extra networks defined in the topology sorted by name.
+func (c *CLab) extraNetworks() []*types.Network {
+	names := make([]string, 0, len(c.Config.Topology.Networks))
+	for name := range c.Config.Topology.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	nets := make([]*types.Network, 0, len(names))
+	for _, name := range names {
+		nets = append(nets, c.Config.Topology.Networks[name])
+	}
+
+	return nets
+}
+
+// nodeNetworks returns the extra networks the node is attached to
+// with the runtime network names resolved from the network definitions.
+func (c *CLab) nodeNetworks(nodeName string) ([]*types.NetworkAttachment, error) {
+	var res []*types.NetworkAttachment
+
+	for i, a := range c.Config.Topology.GetNodeNetworks(nodeName) {
+		n, ok := c.Config.Topology.Networks[a.Name]
+		if !ok {
+			return nil, fmt.Errorf("node %q is attached to network %q which is not defined in the topology networks",
+				nodeName, a.Name)
+		}
+
+		iface := a.Interface
+		if iface == "" {
+			iface = fmt.Sprintf("eth%d", i+1)
+		}
+
+		res = append(res, &types.NetworkAttachment{
+			Name:        a.Name,
+			Network:     n.Network,
+			IPv4Address: a.IPv4Address,
+			IPv6Address: a.IPv6Address,
+			Interface:   iface,
+		})
+	}
+
+	return res, nil
+}
+
+// verifyExtraNetworks makes sure that the nodes attached to the extra networks
+// use the management network and that their link interfaces do not clash
+// with the interfaces of the extra networks, which are looked up by the network name.
+func (c *CLab) verifyExtraNetworks() error {
+	for _, n := range c.Nodes {
+		cfg := n.Config()
+		if len(cfg.Networks) == 0 {
+			continue
+		}
+
+		if nm := strings.SplitN(cfg.NetworkMode, ":", 2)[0]; nm != "" && nm != "bridge" {
+			return fmt.Errorf("node %q with network-mode %q can not be attached to extra networks",
+				cfg.ShortName, cfg.NetworkMode)
+		}
+
+		// names of the extra networks keyed by their interface name
+		netIfaces := map[string]string{}
+
+		for _, a := range cfg.Networks {
+			if a.Interface == "eth0" {
+				return fmt.Errorf("node %q: interface eth0 of the management network can not be used by the extra network %q",
+					cfg.ShortName, a.Name)
+			}
+
+			if other, ok := netIfaces[a.Interface]; ok {
+				return fmt.Errorf("node %q: interface %q is used by both extra networks %q and %q",
+					cfg.ShortName, a.Interface, other, a.Name)
+			}
+
+			netIfaces[a.Interface] = a.Name
+		}
+
+		for _, e := range n.GetEndpoints() {
+			if name, ok := netIfaces[e.GetIfaceName()]; ok {
+				return fmt.Errorf("node %q: interface %q is used by the extra network %q, use another interface for the link",
+					cfg.ShortName, e.GetIfaceName(), name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifyMgmtIsolation makes sure that the management network isolation policy
+// can be applied to the management network.
+func (c *CLab) verifyMgmtIsolation() error {
+	iso := c.Config.Mgmt.Isolation
+	if iso == nil {
+		return nil
+	}
+
+	if !iso.Enabled {
+		if len(iso.EgressAllow) != 0 {
+			return fmt.Errorf("management network isolation must be enabled to restrict the egress traffic")
+		}
+
+		return nil
+	}
+
+	if c.globalRuntimeName != docker.RuntimeName {
+		return fmt.Errorf("management network isolation is not supported by the %s runtime", c.globalRuntimeName)
+	}
+
+	if c.Config.Mgmt.Network == "bridge" {
+		return fmt.Errorf("the default docker network %q can not be isolated", c.Config.Mgmt.Network)
+	}
+
+	for _, s := range iso.EgressAllow {
+		if _, err := netip.ParsePrefix(s); err != nil {
+			return fmt.Errorf("invalid egress-allow prefix %q, an IPv4 or IPv6 prefix is expected", s)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,293 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-connections/nat"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// publishMgmtPorts publishes the management services of the node on the host ports
+// allocated from the port range of the management network.
+// Each node gets a block of ports, one port per service, and the blocks are allocated
+// in the alphabetical order of the node names, so that a node keeps its ports
+// as long as the set of nodes in the topology does not change.
+// The blocks of the lab start at the management ports offset in the port range.
+func (c *CLab) publishMgmtPorts(n nodes.Node) error {
+	pp := c.Config.Mgmt.PortPublishing
+	if pp == nil {
+		return nil
+	}
+
+	first, last, err := parsePortRange(pp.PortRange)
+	if err != nil {
+		return err
+	}
+
+	services, err := mgmtServices(pp.Services)
+	if err != nil {
+		return err
+	}
+
+	cfg := n.Config()
+
+	idx := slices.Index(c.topologyNodeNames(), cfg.ShortName)
+	if idx < 0 || !publishesPorts(cfg) {
+		return nil
+	}
+
+	containerPorts := c.Reg.Kind(cfg.Kind).GetMgmtPorts()
+
+	for i, s := range services {
+		hostPort := first + c.mgmtPortsOffset + idx*len(services) + i
+		if hostPort > last {
+			return fmt.Errorf("port range %s is exhausted, node %q needs ports up to %d",
+				pp.PortRange, cfg.ShortName, first+c.mgmtPortsOffset+(idx+1)*len(services)-1)
+		}
+
+		port, err := nat.NewPort("tcp", strconv.Itoa(containerPorts[s]))
+		if err != nil {
+			return err
+		}
+
+		// ports published by the user take precedence
+		if _, ok := cfg.PortBindings[port]; ok {
+			continue
+		}
+
+		if cfg.PortSet == nil {
+			cfg.PortSet = nat.PortSet{}
+		}
+		if cfg.PortBindings == nil {
+			cfg.PortBindings = nat.PortMap{}
+		}
+		if cfg.MgmtPorts == nil {
+			cfg.MgmtPorts = map[string]int{}
+		}
+
+		cfg.PortSet[port] = struct{}{}
+		cfg.PortBindings[port] = []nat.PortBinding{{HostIP: pp.HostIP, HostPort: strconv.Itoa(hostPort)}}
+		cfg.MgmtPorts[s] = hostPort
+	}
+
+	return nil
+}
+
+// relocateMgmtPorts moves the management ports of the lab nodes to the first block of the port range
+// that has no ports in use, when any of the ports allocated to the lab is in use,
+// e.g. by another instance of the same topology.
+// A port is in use when it is published by a container of another lab or can not be bound on the host.
+func (c *CLab) relocateMgmtPorts(ctx context.Context) error {
+	pp := c.Config.Mgmt.PortPublishing
+	if pp == nil {
+		return nil
+	}
+
+	first, last, err := parsePortRange(pp.PortRange)
+	if err != nil {
+		return err
+	}
+
+	services, err := mgmtServices(pp.Services)
+	if err != nil {
+		return err
+	}
+
+	own, others, err := c.publishedHostPorts(ctx)
+	if err != nil {
+		log.Warnf("Failed to list the host ports published by the containers: %v", err)
+	}
+
+	inUse := func(p int) bool {
+		return others[p] || (!own[p] && !hostPortFree(pp.HostIP, p))
+	}
+
+	blockLen := len(c.topologyNodeNames()) * len(services)
+
+	offset, err := freePortBlock(first, last, c.mgmtPortsOffset, blockLen, inUse)
+	if err != nil {
+		return fmt.Errorf("port range %s: %w", pp.PortRange, err)
+	}
+
+	if offset == c.mgmtPortsOffset {
+		return nil
+	}
+
+	log.Infof("Management ports %d-%d are in use, publishing the management ports on ports %d-%d",
+		first+c.mgmtPortsOffset, first+c.mgmtPortsOffset+blockLen-1, first+offset, first+offset+blockLen-1)
+
+	c.mgmtPortsOffset = offset
+
+	for _, n := range c.Nodes {
+		cfg := n.Config()
+		if len(cfg.MgmtPorts) == 0 {
+			continue
+		}
+
+		// the port bindings of the management services are replaced, the user defined ones are kept
+		containerPorts := c.Reg.Kind(cfg.Kind).GetMgmtPorts()
+		for s := range cfg.MgmtPorts {
+			delete(cfg.PortBindings, nat.Port(strconv.Itoa(containerPorts[s])+"/tcp"))
+		}
+
+		cfg.MgmtPorts = nil
+
+		if err := c.publishMgmtPorts(n); err != nil {
+			return fmt.Errorf("failed to publish management ports of node %q: %v", cfg.ShortName, err)
+		}
+
+		cfg.Labels[labels.MgmtPorts] = mgmtPortsLabel(cfg.MgmtPorts)
+	}
+
+	return nil
+}
+
+// publishedHostPorts returns the host ports published by the containers of the lab
+// and by the containers of the other labs or not managed by containerlab.
+func (c *CLab) publishedHostPorts(ctx context.Context) (own, others map[int]bool, err error) {
+	own, others = map[int]bool{}, map[int]bool{}
+
+	containers, err := c.ListContainers(ctx, nil)
+
+	for _, cnt := range containers {
+		ports := others
+		if cnt.Labels[labels.Containerlab] == c.Config.Name {
+			ports = own
+		}
+
+		for _, p := range cnt.Ports {
+			if p.HostPort != 0 {
+				ports[p.HostPort] = true
+			}
+		}
+	}
+
+	return own, others, err
+}
+
+// freePortBlock returns the offset of the first block of blockLen ports in the first-last port range
+// that has no ports in use. The blocks are looked up starting at the given offset,
+// in the steps of the block length.
+func freePortBlock(first, last, offset, blockLen int, inUse func(int) bool) (int, error) {
+	for ; first+offset+blockLen-1 <= last; offset += blockLen {
+		free := true
+
+		for p := first + offset; p < first+offset+blockLen; p++ {
+			if inUse(p) {
+				free = false
+				break
+			}
+		}
+
+		if free {
+			return offset, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no block of %d free ports left", blockLen)
+}
+
+// hostPortFree returns true if the TCP port can be bound on the host address.
+func hostPortFree(hostIP string, port int) bool {
+	l, err := net.Listen("tcp", net.JoinHostPort(hostIP, strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+
+	_ = l.Close()
+
+	return true
+}
+
+// publishesPorts returns true if the node is a container with its own network namespace
+// attached to the management network.
+func publishesPorts(cfg *types.NodeConfig) bool {
+	nm := strings.ToLower(cfg.NetworkMode)
+
+	return !cfg.IsRootNamespaceBased && !cfg.SkipUniquenessCheck &&
+		nm != "host" && nm != "none" && !strings.HasPrefix(nm, "container:")
+}
+
+// topologyNodeNames returns the sorted names of the topology nodes,
+// including the nodes excluded by the node filter.
+func (c *CLab) topologyNodeNames() []string {
+	if c.topoNodeNames != nil {
+		return c.topoNodeNames
+	}
+
+	names := make([]string, 0, len(c.Config.Topology.Nodes))
+	for name := range c.Config.Topology.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// publishedHost returns the host name the management ports are published on.
+// It is the host address of the port publishing settings if set,
+// otherwise the host name, since the ports are published on all host addresses.
+func (c *CLab) publishedHost() string {
+	if ip := net.ParseIP(c.Config.Mgmt.PortPublishing.HostIP); ip != nil && !ip.IsUnspecified() {
+		return ip.String()
+	}
+
+	if h, err := os.Hostname(); err == nil {
+		return h
+	}
+
+	return "localhost"
+}
+
+// parsePortRange parses the port range in the <first>-<last> format.
+func parsePortRange(r string) (first, last int, err error) {
+	start, end, err := nat.ParsePortRangeToInt(r)
+	if err != nil || start == 0 || end < start {
+		return 0, 0, fmt.Errorf("invalid port range %q, expected <first>-<last>", r)
+	}
+
+	return start, end, nil
+}
+
+// mgmtServices validates the names of the published services.
+// All management services are published when no services are given.
+func mgmtServices(services []string) ([]string, error) {
+	if len(services) == 0 {
+		return nodes.MgmtServices, nil
+	}
+
+	for _, s := range services {
+		if !slices.Contains(nodes.MgmtServices, s) {
+			return nil, fmt.Errorf("unknown management service %q, supported services are %s",
+				s, strings.Join(nodes.MgmtServices, ", "))
+		}
+	}
+
+	return services, nil
+}
+
+// mgmtPortsLabel returns the value of the label listing the published management ports.
+func mgmtPortsLabel(ports map[string]int) string {
+	entries := make([]string, 0, len(ports))
+	for _, s := range nodes.MgmtServices {
+		if p, ok := ports[s]; ok {
+			entries = append(entries, s+"="+strconv.Itoa(p))
+		}
+	}
+
+	return strings.Join(entries, ",")
+}
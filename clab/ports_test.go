@@ -0,0 +1,143 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/mocks/mockruntime"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPublishMgmtPorts(t *testing.T) {
+	c, err := NewContainerLab(WithTopoPath("test_data/topo15-ports.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]map[string]int{
+		"a1": {"ssh": 50000, "gnmi": 50001},
+		"br": nil,
+		// ceos serves gnmi on a kind specific port
+		"c1": {"ssh": 50004, "gnmi": 50005},
+		"l2": nil,
+		// ssh port is published by the user
+		"l3": {"gnmi": 50009},
+	}
+
+	for name, ports := range want {
+		if d := cmp.Diff(ports, c.Nodes[name].Config().MgmtPorts); d != "" {
+			t.Errorf("node %s mgmt ports mismatch (-want +got):\n%s", name, d)
+		}
+	}
+
+	c1 := c.Nodes["c1"].Config()
+	if b := c1.PortBindings[nat.Port("6030/tcp")]; len(b) != 1 || b[0].HostPort != "50005" {
+		t.Errorf("unexpected c1 gnmi port bindings %v", b)
+	}
+
+	if l := c1.Labels[labels.MgmtPorts]; l != "ssh=50004,gnmi=50005" {
+		t.Errorf("unexpected c1 mgmt ports label %q", l)
+	}
+
+	l3 := c.Nodes["l3"].Config()
+	if b := l3.PortBindings[nat.Port("22/tcp")]; len(b) != 1 || b[0].HostPort != "8022" {
+		t.Errorf("user defined port binding is overwritten: %v", b)
+	}
+}
+
+func TestPublishMgmtPortsExhausted(t *testing.T) {
+	c, err := NewContainerLab(WithTopoPath("test_data/topo15-ports.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Config.Mgmt.PortPublishing.PortRange = "50000-50008"
+
+	// l3 is the fifth node and needs ports 50008 and 50009
+	if err := c.publishMgmtPorts(c.Nodes["l3"]); err == nil {
+		t.Error("expected the port range to be exhausted")
+	}
+}
+
+func TestRelocateMgmtPorts(t *testing.T) {
+	c, err := NewContainerLab(WithTopoPath("test_data/topo15-ports.yml", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first block of the lab is published by a container of another instance,
+	// the second block has a port bound on the host
+	crMock := mockruntime.NewMockContainerRuntime(gomock.NewController(t))
+	crMock.EXPECT().ListContainers(gomock.Any(), gomock.Any()).Return([]runtime.GenericContainer{
+		{
+			Labels: map[string]string{labels.Containerlab: "topo15-other"},
+			Ports:  []*types.GenericPortBinding{{HostPort: 50003, ContainerPort: 22}},
+		},
+	}, nil)
+	c.Runtimes = map[string]runtime.ContainerRuntime{"mock": crMock}
+
+	c.Config.Mgmt.PortPublishing.HostIP = "127.0.0.1"
+
+	l, err := net.Listen("tcp", "127.0.0.1:50012")
+	if err != nil {
+		t.Skipf("port 50012 is not available: %v", err)
+	}
+	defer l.Close()
+
+	if err := c.relocateMgmtPorts(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]map[string]int{
+		"a1": {"ssh": 50020, "gnmi": 50021},
+		"c1": {"ssh": 50024, "gnmi": 50025},
+		"l3": {"gnmi": 50029},
+	}
+
+	for name, ports := range want {
+		if d := cmp.Diff(ports, c.Nodes[name].Config().MgmtPorts); d != "" {
+			t.Errorf("node %s mgmt ports mismatch (-want +got):\n%s", name, d)
+		}
+	}
+
+	c1 := c.Nodes["c1"].Config()
+	if b := c1.PortBindings[nat.Port("6030/tcp")]; len(b) != 1 || b[0].HostPort != "50025" {
+		t.Errorf("unexpected c1 gnmi port bindings %v", b)
+	}
+
+	if l := c1.Labels[labels.MgmtPorts]; l != "ssh=50024,gnmi=50025" {
+		t.Errorf("unexpected c1 mgmt ports label %q", l)
+	}
+
+	l3 := c.Nodes["l3"].Config()
+	if b := l3.PortBindings[nat.Port("22/tcp")]; len(b) != 1 || b[0].HostPort != "8022" {
+		t.Errorf("user defined port binding is overwritten: %v", b)
+	}
+}
+
+func TestFreePortBlock(t *testing.T) {
+	used := map[int]bool{101: true, 103: true}
+	inUse := func(p int) bool { return used[p] }
+
+	if off, err := freePortBlock(100, 120, 0, 2, inUse); err != nil || off != 4 {
+		t.Errorf("want offset 4, got %d: %v", off, err)
+	}
+
+	if off, err := freePortBlock(100, 120, 0, 1, inUse); err != nil || off != 0 {
+		t.Errorf("want offset 0, got %d: %v", off, err)
+	}
+
+	if _, err := freePortBlock(100, 104, 0, 3, inUse); err == nil {
+		t.Error("expected no free block error")
+	}
+}
@@ -0,0 +1,315 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/utils"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// reachabilityWorkers is the number of the pings run concurrently.
+	reachabilityWorkers = 16
+	// linkVarIP is the link variable of the configuration engine holding the link addresses.
+	linkVarIP = "ip"
+)
+
+// pingRTTRe matches the summary line of the iputils and busybox ping output,
+// the capture group is the average round-trip time in milliseconds.
+var pingRTTRe = regexp.MustCompile(`(?:rtt|round-trip) min/avg/max(?:/mdev)? = [\d.]+/([\d.]+)/`)
+
+// ReachabilityResult is the result of pinging a destination address from a lab node.
+type ReachabilityResult struct {
+	Source               string `json:"source"`
+	SourceInterface      string `json:"source_interface,omitempty"`
+	Destination          string `json:"destination"`
+	DestinationInterface string `json:"destination_interface,omitempty"`
+	Address              string `json:"address"`
+	Reachable            bool   `json:"reachable"`
+	// Latency is the average round-trip time in milliseconds.
+	Latency float64 `json:"latency_ms,omitempty"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// reachabilityCheck is a ping of the address from the source node.
+type reachabilityCheck struct {
+	src    string
+	result *ReachabilityResult
+}
+
+// LinkReachability pings across the lab links whose both endpoints have known IP addresses,
+// from the first endpoint of the link to the second one. The results are in the order of the links.
+func (c *CLab) LinkReachability(ctx context.Context, count int) []*ReachabilityResult {
+	var checks []*reachabilityCheck
+
+	for _, idx := range c.sortedLinkIndexes() {
+		eps := c.Links[idx].GetEndpoints()
+		if len(eps) != 2 || !c.isLabNode(eps[0]) || !c.isLabNode(eps[1]) {
+			continue
+		}
+
+		addrs := make([]netip.Addr, 2)
+		known := true
+
+		for i, ep := range eps {
+			addrs[i] = c.endpointAddress(ctx, c.Links[idx], i, ep)
+			if !addrs[i].IsValid() {
+				log.Infof("Skipping link %s: no IP address known for %s:%s", linkName(eps), ep.GetNode().GetShortName(),
+					ep.GetIfaceDisplayName())
+
+				known = false
+
+				break
+			}
+		}
+
+		if !known {
+			continue
+		}
+
+		checks = append(checks, &reachabilityCheck{
+			src: eps[0].GetNode().GetShortName(),
+			result: &ReachabilityResult{
+				Source:               eps[0].GetNode().GetShortName(),
+				SourceInterface:      eps[0].GetIfaceDisplayName(),
+				Destination:          eps[1].GetNode().GetShortName(),
+				DestinationInterface: eps[1].GetIfaceDisplayName(),
+				Address:              addrs[1].String(),
+			},
+		})
+	}
+
+	return c.runReachabilityChecks(ctx, checks, count)
+}
+
+// LoopbackReachability pings the loopback address of every lab node from every other node.
+// The loopback addresses are the ones allocated by IPAM or discovered on the lo interface of the nodes,
+// the nodes without a known loopback address are skipped.
+func (c *CLab) LoopbackReachability(ctx context.Context, count int) []*ReachabilityResult {
+	names := c.sortedNodeNames()
+
+	loopbacks := map[string]netip.Addr{}
+
+	for _, name := range names {
+		if addr := c.loopbackAddress(ctx, name); addr.IsValid() {
+			loopbacks[name] = addr
+		} else {
+			log.Infof("Skipping node %s: no loopback address known", name)
+		}
+	}
+
+	var checks []*reachabilityCheck
+
+	for _, src := range names {
+		if _, ok := loopbacks[src]; !ok {
+			continue
+		}
+
+		for _, dst := range names {
+			addr, ok := loopbacks[dst]
+			if !ok || src == dst {
+				continue
+			}
+
+			checks = append(checks, &reachabilityCheck{
+				src: src,
+				result: &ReachabilityResult{
+					Source:      src,
+					Destination: dst,
+					Address:     addr.String(),
+				},
+			})
+		}
+	}
+
+	return c.runReachabilityChecks(ctx, checks, count)
+}
+
+// runReachabilityChecks runs the pings concurrently and returns their results in the order of the checks.
+func (c *CLab) runReachabilityChecks(ctx context.Context, checks []*reachabilityCheck, count int) []*ReachabilityResult {
+	results := make([]*ReachabilityResult, 0, len(checks))
+	sem := make(chan struct{}, reachabilityWorkers)
+
+	var wg sync.WaitGroup
+
+	for _, chk := range checks {
+		results = append(results, chk.result)
+
+		wg.Add(1)
+
+		go func(chk *reachabilityCheck) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			c.ping(ctx, chk.src, chk.result, count)
+		}(chk)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// ping pings the result address from the node and records the outcome in the result.
+func (c *CLab) ping(ctx context.Context, node string, r *ReachabilityResult, count int) {
+	cmd := exec.NewExecCmdFromSlice([]string{"ping", "-c", strconv.Itoa(count), "-W", "1", r.Address})
+
+	res, err := c.Nodes[node].RunExec(ctx, cmd)
+	switch {
+	case err != nil:
+		r.Error = err.Error()
+	case res.ReturnCode != 0:
+		r.Error = fmt.Sprintf("ping failed with return code %d", res.ReturnCode)
+	default:
+		r.Reachable = true
+		r.Latency = pingLatency(string(res.Stdout))
+	}
+}
+
+// pingLatency returns the average round-trip time in milliseconds from the ping output, 0 if it is not found.
+func pingLatency(out string) float64 {
+	m := pingRTTRe.FindStringSubmatch(out)
+	if m == nil {
+		return 0
+	}
+
+	rtt, _ := strconv.ParseFloat(m[1], 64)
+
+	return rtt
+}
+
+// isLabNode returns true if the endpoint belongs to a node of the lab, as opposed to
+// the host, mgmt-net and other special nodes.
+func (c *CLab) isLabNode(ep links.Endpoint) bool {
+	_, ok := c.Nodes[ep.GetNode().GetShortName()]
+	return ok
+}
+
+// linkName returns the name of the link made of the endpoints for logging.
+func linkName(eps []links.Endpoint) string {
+	return fmt.Sprintf("%s:%s <--> %s:%s", eps[0].GetNode().GetShortName(), eps[0].GetIfaceDisplayName(),
+		eps[1].GetNode().GetShortName(), eps[1].GetIfaceDisplayName())
+}
+
+// endpointAddress returns the IP address of the i-th endpoint of the link.
+// The address is taken from the endpoint addresses, the IPAM link variables or
+// the ip link variable of the configuration engine, and is otherwise discovered on the
+// endpoint interface. IPv4 addresses are preferred over IPv6 ones.
+func (c *CLab) endpointAddress(ctx context.Context, l links.Link, i int, ep links.Endpoint) netip.Addr {
+	for _, p := range []netip.Prefix{ep.GetIPv4Addr(), ep.GetIPv6Addr()} {
+		if p.IsValid() {
+			return p.Addr()
+		}
+	}
+
+	vars := l.GetVars()
+
+	for _, k := range []string{ipamVarLinkIPv4, ipamVarLinkIPv6, linkVarIP} {
+		if addr := linkVarAddress(vars[k], i); addr.IsValid() {
+			return addr
+		}
+	}
+
+	return discoverAddress(ctx, ep.GetNode(), ep.GetIfaceName())
+}
+
+// linkVarAddress returns the address of the i-th endpoint from the link variable value,
+// which is either a list of the endpoint prefixes or the prefix of the first endpoint
+// the far end prefix is calculated from.
+func linkVarAddress(v interface{}, i int) netip.Addr {
+	var s string
+
+	switch v := v.(type) {
+	case []string:
+		if len(v) == 2 {
+			s = v[i]
+		}
+	case []interface{}:
+		if len(v) == 2 {
+			s = fmt.Sprint(v[i])
+		}
+	case string:
+		s = v
+	}
+
+	p, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Addr{}
+	}
+
+	if _, ok := v.(string); ok && i == 1 {
+		p = utils.IPFarEnd(p)
+	}
+
+	return p.Addr()
+}
+
+// loopbackAddress returns the loopback address of the node allocated by IPAM,
+// or discovered on its lo interface.
+func (c *CLab) loopbackAddress(ctx context.Context, name string) netip.Addr {
+	if c.IPAM != nil {
+		if lo := c.IPAM.Loopbacks[name]; lo != nil {
+			for _, s := range []string{lo.IPv4, lo.IPv6} {
+				if p, err := netip.ParsePrefix(s); err == nil {
+					return p.Addr()
+				}
+			}
+		}
+	}
+
+	return discoverAddress(ctx, c.Nodes[name], "lo")
+}
+
+// discoverAddress returns the global unicast address of the interface in the node network namespace,
+// an IPv4 address is preferred over an IPv6 one.
+func discoverAddress(ctx context.Context, n links.Node, iface string) netip.Addr {
+	var found netip.Addr
+
+	err := n.ExecFunction(ctx, func(_ ns.NetNS) error {
+		l, err := netlink.LinkByName(iface)
+		if err != nil {
+			return err
+		}
+
+		addrs, err := netlink.AddrList(l, netlink.FAMILY_ALL)
+		if err != nil {
+			return err
+		}
+
+		for _, a := range addrs {
+			addr, ok := netip.AddrFromSlice(a.IP)
+			if !ok {
+				continue
+			}
+
+			addr = addr.Unmap()
+			if !addr.IsGlobalUnicast() || (found.IsValid() && !(addr.Is4() && found.Is6())) {
+				continue
+			}
+
+			found = addr
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Debugf("failed to discover the addresses of %s:%s: %v", n.GetShortName(), iface, err)
+	}
+
+	return found
+}
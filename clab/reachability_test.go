@@ -0,0 +1,121 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const reachabilityTopo = `name: reach
+
+topology:
+  nodes:
+    client1:
+      kind: linux
+    client2:
+      kind: linux
+    srl1:
+      kind: nokia_srlinux
+    srl2:
+      kind: nokia_srlinux
+
+  links:
+    - endpoints: ["client1:eth1@10.0.0.1/31", "client2:eth1@10.0.0.0/31"]
+    - endpoints: ["srl1:e1-1", "srl2:e1-1"]
+      vars:
+        ip: 192.168.0.0/31
+    - endpoints: ["srl1:e1-2", "client1:eth2"]
+      vars:
+        clab_link_ipv6: ["2001:db8::/127", "2001:db8::1/127"]
+`
+
+func TestEndpointAddress(t *testing.T) {
+	topo := filepath.Join(t.TempDir(), "reach.clab.yml")
+	if err := os.WriteFile(topo, []byte(reachabilityTopo), 0644); err != nil { // skipcq: GSC-G306
+		t.Fatal(err)
+	}
+
+	c, err := NewContainerLab(WithTopoPath(topo, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ResolveLinks(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][2]string{
+		{"10.0.0.1", "10.0.0.0"},
+		{"192.168.0.0", "192.168.0.1"},
+		{"2001:db8::", "2001:db8::1"},
+	}
+
+	for i, idx := range c.sortedLinkIndexes() {
+		l := c.Links[idx]
+
+		for j, ep := range l.GetEndpoints() {
+			if got := c.endpointAddress(context.Background(), l, j, ep).String(); got != want[i][j] {
+				t.Errorf("link %d endpoint %d: want address %s, got %s", i, j, want[i][j], got)
+			}
+		}
+	}
+}
+
+func TestLinkVarAddress(t *testing.T) {
+	tests := map[string]struct {
+		v    interface{}
+		i    int
+		want netip.Addr
+	}{
+		"string-a":       {v: "10.0.0.5/30", i: 0, want: netip.MustParseAddr("10.0.0.5")},
+		"string-z":       {v: "10.0.0.5/30", i: 1, want: netip.MustParseAddr("10.0.0.6")},
+		"list":           {v: []interface{}{"10.0.0.1/31", "10.0.0.0/31"}, i: 1, want: netip.MustParseAddr("10.0.0.0")},
+		"string-list":    {v: []string{"2001:db8::/127", "2001:db8::1/127"}, i: 0, want: netip.MustParseAddr("2001:db8::")},
+		"short-list":     {v: []interface{}{"10.0.0.1/31"}, i: 0},
+		"not-an-address": {v: "foo", i: 0},
+		"unset":          {v: nil, i: 0},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := linkVarAddress(tt.v, tt.i); got != tt.want {
+				t.Errorf("want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPingLatency(t *testing.T) {
+	tests := map[string]struct {
+		out  string
+		want float64
+	}{
+		"iputils": {
+			out: `3 packets transmitted, 3 received, 0% packet loss, time 2030ms
+rtt min/avg/max/mdev = 0.043/0.061/0.084/0.017 ms`,
+			want: 0.061,
+		},
+		"busybox": {
+			out: `3 packets transmitted, 3 packets received, 0% packet loss
+round-trip min/avg/max = 0.101/0.122/0.140 ms`,
+			want: 0.122,
+		},
+		"no-summary": {
+			out: "1 packets transmitted, 0 packets received, 100% packet loss",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := pingLatency(tt.out); got != tt.want {
+				t.Errorf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
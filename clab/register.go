@@ -23,6 +23,7 @@ import (
 	k8s_kind "github.com/srl-labs/containerlab/nodes/k8s_kind"
 	keysight_ixiacone "github.com/srl-labs/containerlab/nodes/keysight_ixiacone"
 	linux "github.com/srl-labs/containerlab/nodes/linux"
+	netns "github.com/srl-labs/containerlab/nodes/netns"
 	ovs "github.com/srl-labs/containerlab/nodes/ovs"
 	rare "github.com/srl-labs/containerlab/nodes/rare"
 	sonic "github.com/srl-labs/containerlab/nodes/sonic"
@@ -64,6 +65,7 @@ func (c *CLab) RegisterNodes() {
 	ipinfusion_ocnos.Register(c.Reg)
 	keysight_ixiacone.Register(c.Reg)
 	linux.Register(c.Reg)
+	netns.Register(c.Reg)
 	ovs.Register(c.Reg)
 	sonic.Register(c.Reg)
 	srl.Register(c.Reg)
@@ -5,9 +5,36 @@ Host {{ .Name }}
 	{{-  if ne .Username ""}}
 	User {{ .Username }}
 	{{- end }}
+	{{-  if ne .SSHKey ""}}
+	IdentityFile {{ .SSHKey }}
+	{{- end }}
+	{{- if .KnownHostsFile }}
+	StrictHostKeyChecking=yes
+	UserKnownHostsFile={{ .KnownHostsFile }}
+	{{- else }}
 	StrictHostKeyChecking=no
 	UserKnownHostsFile=/dev/null
+	{{- end }}
 	{{- if ne .SSHConfig.PubkeyAuthentication "" }}
 	PubkeyAuthentication={{ .SSHConfig.PubkeyAuthentication.String }}
 	{{- end }}
-{{ end }}
\ No newline at end of file
+{{ if .PublishedPort }}
+# {{ .Name }} SSH service published on the host port
+Host {{ .Name }}-published
+	HostName {{ .PublishedHost }}
+	Port {{ .PublishedPort }}
+	{{-  if ne .Username ""}}
+	User {{ .Username }}
+	{{- end }}
+	{{-  if ne .SSHKey ""}}
+	IdentityFile {{ .SSHKey }}
+	{{- end }}
+	{{- if .KnownHostsFile }}
+	StrictHostKeyChecking=yes
+	UserKnownHostsFile={{ .KnownHostsFile }}
+	{{- else }}
+	StrictHostKeyChecking=no
+	UserKnownHostsFile=/dev/null
+	{{- end }}
+{{ end }}
+{{- end }}
\ No newline at end of file
@@ -7,6 +7,7 @@ import (
 	"text/template"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/types"
 	"github.com/srl-labs/containerlab/utils"
 	"golang.org/x/mod/semver"
@@ -22,9 +23,17 @@ type SSHConfigTmpl struct {
 // SSHConfigNodeTmpl represents values for a single node
 // in the sshconfig template.
 type SSHConfigNodeTmpl struct {
-	Name      string
-	Username  string
+	Name     string
+	Username string
+	// SSHKey is the path to the private key used to access the node
+	SSHKey    string
 	SSHConfig *types.SSHConfig
+	// KnownHostsFile is the lab known_hosts file set when the host keys of the node are pinned
+	KnownHostsFile string
+	// PublishedHost and PublishedPort are the host address and port
+	// the SSH service of the node is published on.
+	PublishedHost string
+	PublishedPort int
 }
 
 // sshConfigTemplate is the SSH config template.
@@ -43,7 +52,8 @@ func (c *CLab) RemoveSSHConfig(topoPaths *types.TopoPaths) error {
 }
 
 // addSSHConfig adds the lab specific ssh config file.
-func (c *CLab) addSSHConfig() error {
+// The host keys of the pinned nodes are verified against the lab known_hosts file.
+func (c *CLab) addSSHConfig(pinned map[string]bool) error {
 	sshConfigDir := path.Dir(c.TopoPaths.SSHConfigPath())
 	if !utils.FileOrDirExists(sshConfigDir) {
 		log.Debugf("ssh config directory %s does not exist, skipping ssh config generation", sshConfigDir)
@@ -63,15 +73,25 @@ func (c *CLab) addSSHConfig() error {
 
 	// add the data for all nodes to the template input
 	for _, n := range c.Nodes {
-		// get the Kind from the KindRegistry and and extract
-		// the kind registered Username
-		NodeRegistryEntry := c.Reg.Kind(n.Config().Kind)
+		// get the Kind from the KindRegistry and merge the kind registered
+		// credentials with the node credentials set in the topology
+		creds := c.Reg.Kind(n.Config().Kind).GetCredentials().Merge(n.Config().Credentials)
 		nodeData := SSHConfigNodeTmpl{
 			Name:      n.Config().LongName,
-			Username:  NodeRegistryEntry.GetCredentials().GetUsername(),
+			Username:  creds.GetUsername(),
+			SSHKey:    creds.GetSSHKey(),
 			SSHConfig: n.GetSSHConfig(),
 		}
 
+		if pinned[n.Config().ShortName] {
+			nodeData.KnownHostsFile = c.TopoPaths.KnownHostsFileAbsPath()
+		}
+
+		if p, ok := n.Config().MgmtPorts[nodes.MgmtServiceSSH]; ok {
+			nodeData.PublishedHost = c.publishedHost()
+			nodeData.PublishedPort = p
+		}
+
 		// if we couldn't parse the ssh version we assume we can't use unbound option
 		// or if the version is lower than 8.9
 		// and the node has the PubkeyAuthentication set to unbound
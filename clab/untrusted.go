@@ -0,0 +1,275 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// ErrUntrustedTopology is returned when the untrusted topology defines the host access.
+var ErrUntrustedTopology = errors.New("topology is not allowed to access the host")
+
+// untrustedKinds are the node kinds running on the host or attaching the host interfaces and bridges.
+var untrustedKinds = map[string]struct{}{
+	"host":          {},
+	"netns":         {},
+	"ext-container": {},
+	"bridge":        {},
+	"ovs-bridge":    {},
+	"k8s-kind":      {},
+}
+
+// untrustedLinkTypes are the link types attaching the host interfaces or reaching the remote hosts.
+var untrustedLinkTypes = map[links.LinkType]struct{}{
+	links.LinkTypeHost:        {},
+	links.LinkTypeMacVLan:     {},
+	links.LinkTypeVxlan:       {},
+	links.LinkTypeVxlanStitch: {},
+}
+
+// WithUntrustedTopology marks the topology as coming from an untrusted user, whose files live in dir.
+// The topology is not rendered as a template, the environment variables are not expanded in it,
+// and the topology is rejected when it defines the hooks, the webhooks, the host kinds and links,
+// the host network mode or the host paths outside of dir and the lab directory.
+// The option must precede the WithTopoPath option.
+func WithUntrustedTopology(dir string) ClabOption {
+	return func(c *CLab) error {
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+
+		c.untrustedDir = dir
+		c.disallowHooks = true
+
+		return nil
+	}
+}
+
+// checkUntrustedTopology returns an error listing the host access defined by the untrusted topology.
+func (c *CLab) checkUntrustedTopology() error {
+	if c.untrustedDir == "" {
+		return nil
+	}
+
+	var errs []error
+
+	if len(c.Config.Settings.GetWebhooks()) != 0 {
+		errs = append(errs, errors.New("webhooks are not allowed"))
+	}
+
+	if s := c.Config.Settings; s != nil && s.CertificateAuthority != nil {
+		errs = append(errs,
+			c.checkUntrustedPath("certificate authority cert", s.CertificateAuthority.Cert),
+			c.checkUntrustedPath("certificate authority key", s.CertificateAuthority.Key),
+		)
+	}
+
+	for i, l := range c.Config.Topology.Links {
+		if l == nil || l.Link == nil {
+			continue
+		}
+
+		if _, ok := untrustedLinkTypes[l.Link.GetType()]; ok {
+			errs = append(errs, fmt.Errorf("link #%d of type %q is not allowed", i+1, l.Link.GetType()))
+		}
+	}
+
+	names := make([]string, 0, len(c.Config.Topology.Nodes))
+	for name := range c.Config.Topology.Nodes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := c.checkUntrustedNode(name); err != nil {
+			errs = append(errs, fmt.Errorf("node %q: %w", name, err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("%w: %w", ErrUntrustedTopology, err)
+	}
+
+	return nil
+}
+
+// checkUntrustedNode returns an error listing the host access defined for the node of the untrusted topology.
+func (c *CLab) checkUntrustedNode(name string) error {
+	t := c.Config.Topology
+	var errs []error
+
+	if kind := t.GetNodeKind(name); kind != "" {
+		if _, ok := untrustedKinds[kind]; ok {
+			errs = append(errs, fmt.Errorf("kind %q is not allowed", kind))
+		}
+	}
+
+	if nm := t.GetNodeNetworkMode(name); nm != "" && nm != "none" {
+		errs = append(errs, fmt.Errorf("network-mode %q is not allowed", nm))
+	}
+
+	if t.GetNodeSandbox(name) != "" || t.GetNodeKernel(name) != "" {
+		errs = append(errs, errors.New("sandbox and kernel are not allowed"))
+	}
+
+	binds, err := t.GetNodeBinds(name)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	nodeDir := c.TopoPaths.NodeDir(name)
+	r := strings.NewReplacer(clabDirVar, c.TopoPaths.TopologyLabDir(), nodeDirVar, nodeDir)
+
+	for _, b := range binds {
+		elems := strings.Split(b, ":")
+		if len(elems) == 1 {
+			// anonymous volume
+			continue
+		}
+
+		errs = append(errs, c.checkUntrustedPath("bind", r.Replace(elems[0])))
+	}
+
+	switch p := t.GetNodeStartupConfig(name); {
+	case strings.Count(p, "\n") >= 1:
+		// embedded config is written to the lab directory
+	case utils.IsHttpURL(p, false):
+		errs = append(errs, fmt.Errorf("startup-config URL %q is not allowed", p))
+	default:
+		errs = append(errs, c.checkUntrustedPath("startup-config", p))
+	}
+
+	errs = append(errs, c.checkUntrustedPath("license", t.GetNodeLicense(name)))
+
+	for _, f := range t.GetNodeEnvFiles(name) {
+		errs = append(errs, c.checkUntrustedPath("env-file", f))
+	}
+
+	cc := t.GetCredentialsConfig(name)
+	errs = append(errs,
+		c.checkUntrustedPath("credentials env-file", cc.EnvFile),
+		c.checkUntrustedPath("credentials ssh-key", cc.SSHKey),
+	)
+
+	stages, err := t.GetStages(name)
+	if err != nil {
+		errs = append(errs, err)
+	} else if hostExecs(stages) {
+		errs = append(errs, errors.New("stage execs on the host are not allowed"))
+	}
+
+	if extras := t.GetNodeExtras(name); extras != nil {
+		for _, a := range extras.SRLAgents {
+			if utils.IsHttpURL(a, false) {
+				errs = append(errs, fmt.Errorf("srl-agents URL %q is not allowed", a))
+				continue
+			}
+			// the agents are copied from the path relative to the working directory
+			abs, err := filepath.Abs(a)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			errs = append(errs, c.checkUntrustedPath("srl-agents", abs))
+		}
+
+		for _, f := range extras.CeosCopyToFlash {
+			errs = append(errs, c.checkUntrustedPath("ceos-copy-to-flash", f))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// hostExecs reports whether any of the stages runs the commands on the host.
+func hostExecs(s *types.Stages) bool {
+	var bases []*types.StageBase
+
+	if s.Create != nil {
+		bases = append(bases, &s.Create.StageBase)
+	}
+
+	if s.CreateLinks != nil {
+		bases = append(bases, &s.CreateLinks.StageBase)
+	}
+
+	if s.Configure != nil {
+		bases = append(bases, &s.Configure.StageBase)
+	}
+
+	if s.Healthy != nil {
+		bases = append(bases, &s.Healthy.StageBase)
+	}
+
+	if s.Exit != nil {
+		bases = append(bases, &s.Exit.StageBase)
+	}
+
+	for _, b := range bases {
+		for _, e := range b.Execs {
+			if e != nil && e.Target == types.CommandTargetHost {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// checkUntrustedPath returns an error when the host path p of the untrusted topology
+// resolves outside of the untrusted dir and the lab directory.
+func (c *CLab) checkUntrustedPath(field, p string) error {
+	if p == "" || c.untrustedDir == "" {
+		return nil
+	}
+
+	resolved := evalExistingSymlinks(utils.ResolvePath(p, c.TopoPaths.TopologyFileDir()))
+
+	for _, dir := range []string{c.untrustedDir, c.TopoPaths.TopologyLabDir()} {
+		dir = evalExistingSymlinks(dir)
+
+		if rel, err := filepath.Rel(dir, resolved); err == nil && filepath.IsLocal(rel) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s path %q is outside of %s", field, p, c.untrustedDir)
+}
+
+// evalExistingSymlinks returns the path with the symlinks of its longest existing prefix evaluated,
+// so that the path not created yet can't escape the directory through a symlinked parent.
+func evalExistingSymlinks(p string) string {
+	p = filepath.Clean(p)
+
+	var rest []string
+
+	for {
+		if r, err := filepath.EvalSymlinks(p); err == nil {
+			return filepath.Join(append([]string{r}, rest...)...)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return p
+		}
+
+		parent := filepath.Dir(p)
+		if parent == p {
+			return filepath.Join(append([]string{p}, rest...)...)
+		}
+
+		rest = append([]string{filepath.Base(p)}, rest...)
+		p = parent
+	}
+}
@@ -0,0 +1,387 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUntrustedTopology(t *testing.T) {
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		topo    string
+		wantErr string
+	}{
+		"allowed": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+      binds:
+        - data:/data
+        - __clabNodeDir__/cfg:/cfg
+        - /anonymous
+      startup-config: startup.cfg
+      env-files:
+        - node.env
+    node2:
+      kind: linux
+      network-mode: none
+      startup-config: |
+        hostname node2
+        interface eth1
+      exec:
+        - ip link
+  links:
+    - endpoints: [node1:eth1, node2:eth1]
+`,
+		},
+		"kind host": {
+			topo: `  nodes:
+    node1:
+      kind: host
+`,
+			wantErr: `kind "host" is not allowed`,
+		},
+		"kind netns with exec": {
+			topo: `  nodes:
+    node1:
+      kind: netns
+      exec:
+        - touch /tmp/pwned
+`,
+			wantErr: `kind "netns" is not allowed`,
+		},
+		"kind ext-container": {
+			topo: `  nodes:
+    node1:
+      kind: ext-container
+`,
+			wantErr: `kind "ext-container" is not allowed`,
+		},
+		"kind bridge": {
+			topo: `  nodes:
+    node1:
+      kind: bridge
+`,
+			wantErr: `kind "bridge" is not allowed`,
+		},
+		"host kind from defaults": {
+			topo: `  defaults:
+    kind: host
+  nodes:
+    node1: {}
+`,
+			wantErr: `kind "host" is not allowed`,
+		},
+		"network-mode host": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+      network-mode: host
+`,
+			wantErr: `network-mode "host" is not allowed`,
+		},
+		"bind of host path": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+      binds:
+        - /etc:/host-etc
+`,
+			wantErr: `bind path "/etc" is outside of`,
+		},
+		"bind escaping the dir": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+      binds:
+        - ../:/up
+`,
+			wantErr: `bind path "../" is outside of`,
+		},
+		"bind escaping the lab dir": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+      binds:
+        - __clabDir__/../../:/up
+`,
+			wantErr: `clab-untrusted/../../" is outside of`,
+		},
+		"bind through symlink": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+      binds:
+        - link/new:/new
+`,
+			wantErr: `bind path "link/new" is outside of`,
+		},
+		"bind from defaults": {
+			topo: `  defaults:
+    binds:
+      - /:/host
+  nodes:
+    node1:
+      kind: linux
+`,
+			wantErr: `bind path "/" is outside of`,
+		},
+		"startup-config outside": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+      startup-config: ` + filepath.Join(outside, "secret") + `
+`,
+			wantErr: "startup-config path",
+		},
+		"startup-config URL": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+      startup-config: http://169.254.169.254/latest/meta-data
+`,
+			wantErr: `startup-config URL "http://169.254.169.254/latest/meta-data" is not allowed`,
+		},
+		"license outside": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+      license: /etc/shadow
+`,
+			wantErr: `license path "/etc/shadow" is outside of`,
+		},
+		"env-files outside": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+      env-files:
+        - /proc/self/environ
+`,
+			wantErr: `env-file path "/proc/self/environ" is outside of`,
+		},
+		"credentials ssh-key outside": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+      credentials:
+        ssh-key: ~/.ssh/id_rsa
+`,
+			wantErr: `credentials ssh-key path "~/.ssh/id_rsa" is outside of`,
+		},
+		"credentials env-file outside": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+      credentials:
+        env-file: /root/creds.env
+`,
+			wantErr: `credentials env-file path "/root/creds.env" is outside of`,
+		},
+		"credentials ssh-key from env-file": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+      credentials:
+        env-file: creds.env
+`,
+			wantErr: `credentials ssh-key path "/root/.ssh/id_rsa" is outside of`,
+		},
+		"stage exec on host": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+      stages:
+        create:
+          exec:
+            - command: touch /tmp/pwned
+              target: host
+`,
+			wantErr: "stage execs on the host are not allowed",
+		},
+		"srl-agents outside": {
+			topo: `  nodes:
+    node1:
+      kind: nokia_srlinux
+      extras:
+        srl-agents:
+          - /etc/passwd
+`,
+			wantErr: `srl-agents path "/etc/passwd" is outside of`,
+		},
+		"ceos-copy-to-flash outside": {
+			topo: `  nodes:
+    node1:
+      kind: arista_ceos
+      extras:
+        ceos-copy-to-flash:
+          - /etc/passwd
+`,
+			wantErr: `ceos-copy-to-flash path "/etc/passwd" is outside of`,
+		},
+		"host link": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+  links:
+    - endpoints: [node1:eth1, host:veth-node1]
+`,
+			wantErr: `link #1 of type "host" is not allowed`,
+		},
+		"macvlan link": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+  links:
+    - endpoints: [node1:eth1, macvlan:eth0]
+`,
+			wantErr: `link #1 of type "macvlan" is not allowed`,
+		},
+		"vxlan link": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+  links:
+    - type: vxlan
+      endpoint:
+        node: node1
+        interface: eth1
+      remote: 10.0.0.1
+      vni: 100
+      udp-port: 4789
+`,
+			wantErr: `link #1 of type "vxlan" is not allowed`,
+		},
+		"webhooks": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+settings:
+  webhooks:
+    - url: http://127.0.0.1:8080/internal
+`,
+			wantErr: "webhooks are not allowed",
+		},
+		"external CA outside": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+settings:
+  certificate-authority:
+    cert: /etc/ca.pem
+    key: /etc/ca.key
+`,
+			wantErr: `certificate authority key path "/etc/ca.key" is outside of`,
+		},
+		"hooks": {
+			topo: `  nodes:
+    node1:
+      kind: linux
+hooks:
+  post-deploy:
+    - command: touch /tmp/pwned
+`,
+			wantErr: ErrHooksNotAllowed.Error(),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("CLAB_LABDIR_BASE", t.TempDir())
+
+			dir := t.TempDir()
+			for _, f := range []string{"data", "startup.cfg", "node.env"} {
+				if err := os.WriteFile(filepath.Join(dir, f), nil, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := os.WriteFile(filepath.Join(dir, "creds.env"), []byte("SSH_KEY=/root/.ssh/id_rsa\n"), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := os.Symlink(outside, filepath.Join(dir, "link")); err != nil {
+				t.Fatal(err)
+			}
+
+			topo := filepath.Join(dir, "untrusted.clab.yml")
+			if err := os.WriteFile(topo, []byte("name: untrusted\ntopology:\n"+tt.topo), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			// the node dir bind source is created on deploy
+			_, err := NewContainerLab(WithUntrustedTopology(dir), WithTopoPath(topo, ""), WithSkippedBindsPathsCheck())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected error %q, got nil", tt.wantErr)
+			}
+
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+			}
+
+			if strings.HasPrefix(name, "kind") || strings.HasPrefix(name, "bind") {
+				if !errors.Is(err, ErrUntrustedTopology) {
+					t.Fatalf("expected %v, got %v", ErrUntrustedTopology, err)
+				}
+			}
+		})
+	}
+}
+
+func TestUntrustedTopologyNotRendered(t *testing.T) {
+	t.Setenv("CLAB_LABDIR_BASE", t.TempDir())
+	t.Setenv("CLAB_TEST_SECRET", "secret")
+
+	dir := t.TempDir()
+	topo := filepath.Join(dir, "untrusted.clab.yml")
+	data := `name: untrusted
+topology:
+  nodes:
+    node1:
+      kind: linux
+      image: ${CLAB_TEST_SECRET}
+      env:
+        __IMPORT_ENVS: "true"
+    node2:
+      kind: linux
+      image: '{{ env.Getenv "CLAB_TEST_SECRET" }}'
+`
+	if err := os.WriteFile(topo, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewContainerLab(WithUntrustedTopology(dir), WithTopoPath(topo, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := c.Config.Topology.Nodes["node1"].Image; got != "${CLAB_TEST_SECRET}" {
+		t.Errorf("node1 image = %q, want the env var unexpanded", got)
+	}
+
+	if got := c.Config.Topology.Nodes["node2"].Image; got != `{{ env.Getenv "CLAB_TEST_SECRET" }}` {
+		t.Errorf("node2 image = %q, want the template unrendered", got)
+	}
+
+	if _, ok := c.Nodes["node1"].Config().Env["CLAB_TEST_SECRET"]; ok {
+		t.Error("node1 env imports the containerlab environment")
+	}
+}
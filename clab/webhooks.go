@@ -0,0 +1,369 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+)
+
+const (
+	// WebhookEventHeader is the header carrying the event type of the webhook request.
+	WebhookEventHeader = "X-Clab-Event"
+	// WebhookDeliveryHeader is the header carrying the unique id of the event.
+	WebhookDeliveryHeader = "X-Clab-Delivery"
+	// WebhookSignatureHeader is the header carrying the HMAC-SHA256 signature of the request body
+	// in the sha256=<hex digest> format, it is set when the webhook has a secret.
+	WebhookSignatureHeader = "X-Clab-Signature-256"
+)
+
+// webhookRetryInterval is the initial interval between the retries of a failed webhook request.
+var webhookRetryInterval = time.Second
+
+// WebhookEvent is the lab lifecycle event posted to the webhooks as JSON.
+type WebhookEvent struct {
+	ID    string                 `json:"id"`
+	Event types.WebhookEventType `json:"event"`
+	Time  time.Time              `json:"time"`
+	Lab   string                 `json:"lab"`
+	Owner string                 `json:"owner,omitempty"`
+	// Host is the name of the container host the lab runs on.
+	Host     string `json:"host,omitempty"`
+	Topology string `json:"topology,omitempty"`
+	// Node is the name of the node of the node-healthy and node-unhealthy events.
+	Node string `json:"node,omitempty"`
+	// Error is the reason of the failed and unhealthy events.
+	Error string `json:"error,omitempty"`
+}
+
+// WebhookSignature returns the signature of the webhook request body made with the secret,
+// the receivers compare it with the value of the WebhookSignatureHeader.
+func WebhookSignature(secret string, body []byte) string {
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write(body)
+
+	return "sha256=" + hex.EncodeToString(m.Sum(nil))
+}
+
+// webhookNotifier posts the lab events to the webhooks.
+// The events are delivered in the background, in order for each webhook.
+type webhookNotifier struct {
+	targets []*webhookTarget
+	// pending are the events being delivered.
+	pending sync.WaitGroup
+}
+
+// webhookTarget is the delivery queue of a webhook.
+type webhookTarget struct {
+	hook   *types.Webhook
+	client *http.Client
+
+	m       sync.Mutex
+	queue   []*WebhookEvent
+	running bool
+}
+
+// newWebhookNotifier returns the notifier of the webhooks, nil when there are no webhooks.
+func newWebhookNotifier(hooks []*types.Webhook) *webhookNotifier {
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	n := &webhookNotifier{}
+	for _, h := range hooks {
+		n.targets = append(n.targets, &webhookTarget{
+			hook:   h,
+			client: &http.Client{Timeout: h.Timeout},
+		})
+	}
+
+	return n
+}
+
+// subscribed returns true if any of the webhooks is notified of the event type.
+func (n *webhookNotifier) subscribed(e types.WebhookEventType) bool {
+	if n == nil {
+		return false
+	}
+
+	for _, t := range n.targets {
+		if t.hook.Subscribed(e) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// notify queues the event for the delivery to the webhooks subscribed to it.
+func (n *webhookNotifier) notify(e *WebhookEvent) {
+	if n == nil {
+		return
+	}
+
+	for _, t := range n.targets {
+		if !t.hook.Subscribed(e.Event) {
+			continue
+		}
+
+		n.pending.Add(1)
+
+		t.m.Lock()
+		t.queue = append(t.queue, e)
+		if !t.running {
+			t.running = true
+			go t.deliverQueued(&n.pending)
+		}
+		t.m.Unlock()
+	}
+}
+
+// wait waits for the queued events to be delivered.
+func (n *webhookNotifier) wait() {
+	if n == nil {
+		return
+	}
+
+	n.pending.Wait()
+}
+
+// deliverQueued delivers the queued events one by one until the queue is empty.
+func (t *webhookTarget) deliverQueued(pending *sync.WaitGroup) {
+	for {
+		t.m.Lock()
+		if len(t.queue) == 0 {
+			t.running = false
+			t.m.Unlock()
+
+			return
+		}
+
+		e := t.queue[0]
+		t.queue = t.queue[1:]
+		t.m.Unlock()
+
+		if err := t.deliver(e); err != nil {
+			log.Warnf("failed to notify webhook %s of %s event: %v", t.redactedURL(), e.Event, err)
+		}
+
+		pending.Done()
+	}
+}
+
+// deliver posts the event to the webhook, retrying the failed requests with an exponential backoff.
+func (t *webhookTarget) deliver(e *WebhookEvent) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = webhookRetryInterval
+	b.MaxElapsedTime = 0
+
+	return backoff.Retry(func() error {
+		return t.post(e, body)
+	}, backoff.WithMaxRetries(b, uint64(*t.hook.Retries)))
+}
+
+// post makes a single webhook request.
+// The client errors other than 408 and 429 are not retried.
+func (t *webhookTarget) post(e *WebhookEvent, body []byte) error {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, t.hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return backoff.Permanent(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "containerlab")
+	req.Header.Set(WebhookEventHeader, string(e.Event))
+	req.Header.Set(WebhookDeliveryHeader, e.ID)
+	if t.hook.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, WebhookSignature(t.hook.Secret, body))
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	err = fmt.Errorf("unexpected response status %s", resp.Status)
+
+	if resp.StatusCode < 500 && resp.StatusCode != http.StatusRequestTimeout &&
+		resp.StatusCode != http.StatusTooManyRequests {
+		return backoff.Permanent(err)
+	}
+
+	return err
+}
+
+// redactedURL returns the webhook URL with the password removed for logging.
+func (t *webhookTarget) redactedURL() string {
+	u, err := url.Parse(t.hook.URL)
+	if err != nil {
+		return t.hook.URL
+	}
+
+	return u.Redacted()
+}
+
+// notifyWebhooks notifies the webhooks of the lab event,
+// the node and the error are set for the node and the failed events.
+func (c *CLab) notifyWebhooks(event types.WebhookEventType, node string, err error) {
+	if !c.webhooks.subscribed(event) {
+		return
+	}
+
+	host, _ := os.Hostname()
+
+	e := &WebhookEvent{
+		ID:       uuid.NewString(),
+		Event:    event,
+		Time:     time.Now().UTC(),
+		Lab:      c.Config.Name,
+		Owner:    c.labOwner(),
+		Host:     host,
+		Topology: c.TopoPaths.TopologyFilenameAbsPath(),
+		Node:     node,
+	}
+
+	if err != nil {
+		e.Error = err.Error()
+	}
+
+	c.webhooks.notify(e)
+}
+
+// nodesHealthWaitTimeout is the time the deployment waits for the nodes health watch
+// once the rest of the deployment is complete.
+var nodesHealthWaitTimeout = time.Minute
+
+// errNodesHealthWaitExpired is the cause the health watch is canceled with
+// when the deployment stops waiting for it.
+var errNodesHealthWaitExpired = errors.New("node did not turn healthy before the deployment stopped waiting for it")
+
+// waitNodesHealth waits for the nodes health watch to complete for up to nodesHealthWaitTimeout,
+// then cancels the watch with the nodes still watched notified as unhealthy.
+func (c *CLab) waitNodesHealth(watched <-chan struct{}, cancel context.CancelCauseFunc) {
+	timer := time.NewTimer(nodesHealthWaitTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-watched:
+		return
+	case <-timer.C:
+	}
+
+	log.Warnf("Nodes didn't turn healthy within %s, not waiting for them any longer", nodesHealthWaitTimeout)
+
+	cancel(errNodesHealthWaitExpired)
+	<-watched
+}
+
+// watchNodesHealth notifies the webhooks when the nodes with the health checks turn healthy,
+// or of the nodes that don't turn healthy within the time their health checks take to fail.
+// When the context is canceled with errNodesHealthWaitExpired the nodes still watched are notified as unhealthy,
+// nothing is notified for them when the context is canceled otherwise.
+func (c *CLab) watchNodesHealth(ctx context.Context) {
+	if !c.webhooks.subscribed(types.WebhookNodeHealthy) && !c.webhooks.subscribed(types.WebhookNodeUnhealthy) {
+		return
+	}
+
+	var wg sync.WaitGroup
+
+	for _, name := range c.sortedNodeNames() {
+		n := c.Nodes[name]
+
+		hc := n.Config().Healthcheck
+		if hc == nil {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(name string, timeout time.Duration) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			ticker := time.NewTicker(time.Second)
+			defer ticker.Stop()
+
+			for {
+				healthy, err := n.IsHealthy(ctx)
+				if err == nil && healthy {
+					c.notifyWebhooks(types.WebhookNodeHealthy, name, nil)
+
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					if cause := context.Cause(ctx); errors.Is(cause, errNodesHealthWaitExpired) {
+						c.notifyWebhooks(types.WebhookNodeUnhealthy, name, cause)
+
+						return
+					}
+
+					if errors.Is(ctx.Err(), context.Canceled) {
+						return
+					}
+
+					c.notifyWebhooks(types.WebhookNodeUnhealthy, name,
+						fmt.Errorf("node did not turn healthy within %s", timeout))
+
+					return
+				case <-ticker.C:
+				}
+			}
+		}(name, healthcheckFailTime(hc))
+	}
+
+	wg.Wait()
+}
+
+// healthcheckFailTime returns the time it takes the health check to mark the failing container unhealthy,
+// the docker defaults are used for the unset health check parameters.
+func healthcheckFailTime(hc *types.HealthcheckConfig) time.Duration {
+	interval, timeout, retries := hc.GetIntervalDuration(), hc.GetTimeoutDuration(), hc.Retries
+	if interval == 0 {
+		interval = 30 * time.Second
+	}
+
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	if retries == 0 {
+		retries = 3
+	}
+
+	return hc.GetStartPeriodDuration() + time.Duration(retries)*(interval+timeout)
+}
@@ -0,0 +1,304 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/mocks/mocknodes"
+	"github.com/srl-labs/containerlab/types"
+	"go.uber.org/mock/gomock"
+)
+
+func newWebhooksTestLab(t *testing.T, settings string) (*CLab, error) {
+	t.Helper()
+
+	topo := filepath.Join(t.TempDir(), "webhooks.clab.yml")
+	data := "name: webhooks\n\ntopology:\n  nodes:\n    node1:\n      kind: linux\n\n" + settings
+	if err := os.WriteFile(topo, []byte(data), 0644); err != nil { // skipcq: GSC-G306
+		t.Fatal(err)
+	}
+
+	return NewContainerLab(WithTopoPath(topo, ""), WithOwner("alice"))
+}
+
+func TestWebhooksInit(t *testing.T) {
+	zero, three := 0, 3
+
+	tests := map[string]struct {
+		settings string
+		want     []*types.Webhook
+		wantErr  string
+	}{
+		"defaults": {
+			settings: `settings:
+  webhooks:
+    - url: http://bot.example.com/clab
+    - url: https://booking.example.com/hook
+      secret: s3cret
+      events: [deploy-finished, destroy-finished]
+      timeout: 2s
+      retries: 0
+`,
+			want: []*types.Webhook{
+				{URL: "http://bot.example.com/clab", Timeout: 10 * time.Second, Retries: &three},
+				{
+					URL: "https://booking.example.com/hook", Secret: "s3cret",
+					Events:  []types.WebhookEventType{types.WebhookDeployFinished, types.WebhookDestroyFinished},
+					Timeout: 2 * time.Second, Retries: &zero,
+				},
+			},
+		},
+		"invalid-url": {
+			settings: `settings:
+  webhooks:
+    - url: bot.example.com/clab
+`,
+			wantErr: `webhook #1 has invalid url "bot.example.com/clab"`,
+		},
+		"unknown-event": {
+			settings: `settings:
+  webhooks:
+    - url: http://bot.example.com/clab
+      events: [lab-up]
+`,
+			wantErr: `unknown event "lab-up"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := newWebhooksTestLab(t, tt.settings)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if d := cmp.Diff(tt.want, c.Config.Settings.GetWebhooks()); d != "" {
+				t.Errorf("webhooks mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+// webhookRecorder is a webhook receiver recording the delivered events.
+type webhookRecorder struct {
+	m        sync.Mutex
+	events   []*WebhookEvent
+	attempts int
+	// statuses are the response statuses of the first requests.
+	statuses []int
+}
+
+func (r *webhookRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.attempts++
+
+	if len(r.statuses) > 0 {
+		status := r.statuses[0]
+		r.statuses = r.statuses[1:]
+
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+	}
+
+	body, _ := io.ReadAll(req.Body)
+
+	if sig := req.Header.Get(WebhookSignatureHeader); sig != "" && sig != WebhookSignature("s3cret", body) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	e := &WebhookEvent{}
+	if err := json.Unmarshal(body, e); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if req.Header.Get(WebhookEventHeader) != string(e.Event) || req.Header.Get(WebhookDeliveryHeader) != e.ID {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	r.events = append(r.events, e)
+}
+
+func (r *webhookRecorder) eventTypes() []types.WebhookEventType {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	var events []types.WebhookEventType
+	for _, e := range r.events {
+		events = append(events, e.Event)
+	}
+
+	return events
+}
+
+func TestNotifyWebhooks(t *testing.T) {
+	webhookRetryInterval = time.Millisecond
+	defer func() { webhookRetryInterval = time.Second }()
+
+	all := &webhookRecorder{statuses: []int{http.StatusServiceUnavailable, http.StatusOK}}
+	allSrv := httptest.NewServer(all)
+	defer allSrv.Close()
+
+	filtered := &webhookRecorder{}
+	filteredSrv := httptest.NewServer(filtered)
+	defer filteredSrv.Close()
+
+	rejecting := &webhookRecorder{statuses: []int{http.StatusNotFound}}
+	rejectingSrv := httptest.NewServer(rejecting)
+	defer rejectingSrv.Close()
+
+	c, err := newWebhooksTestLab(t, `settings:
+  webhooks:
+    - url: `+allSrv.URL+`
+      secret: s3cret
+    - url: `+filteredSrv.URL+`
+      events: [deploy-failed]
+    - url: `+rejectingSrv.URL+`
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.notifyWebhooks(types.WebhookDeployStarted, "", nil)
+	c.notifyWebhooks(types.WebhookNodeHealthy, "node1", nil)
+	c.notifyWebhooks(types.WebhookDeployFailed, "", errors.New("boom"))
+	c.webhooks.wait()
+
+	want := []types.WebhookEventType{types.WebhookDeployStarted, types.WebhookNodeHealthy, types.WebhookDeployFailed}
+	if d := cmp.Diff(want, all.eventTypes()); d != "" {
+		t.Errorf("delivered events mismatch (-want +got):\n%s", d)
+	}
+
+	// the first request failed with 503 and was retried
+	if all.attempts != 4 {
+		t.Errorf("expected 4 requests, got %d", all.attempts)
+	}
+
+	e := all.events[2]
+	if e.Lab != "webhooks" || e.Owner != "alice" || e.Error != "boom" ||
+		e.Topology != c.TopoPaths.TopologyFilenameAbsPath() {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	if all.events[1].Node != "node1" {
+		t.Errorf("expected node1 in the node event, got %q", all.events[1].Node)
+	}
+
+	if d := cmp.Diff([]types.WebhookEventType{types.WebhookDeployFailed}, filtered.eventTypes()); d != "" {
+		t.Errorf("filtered events mismatch (-want +got):\n%s", d)
+	}
+
+	// the client errors are not retried
+	if rejecting.attempts != 3 || len(rejecting.events) != 2 {
+		t.Errorf("expected 3 requests and 2 delivered events, got %d and %d",
+			rejecting.attempts, len(rejecting.events))
+	}
+}
+
+func TestWaitNodesHealth(t *testing.T) {
+	nodesHealthWaitTimeout = 10 * time.Millisecond
+	defer func() { nodesHealthWaitTimeout = time.Minute }()
+
+	rec := &webhookRecorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	c, err := newWebhooksTestLab(t, `settings:
+  webhooks:
+    - url: `+srv.URL+`
+      events: [node-unhealthy]
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockCtrl := gomock.NewController(t)
+	node := mocknodes.NewMockNode(mockCtrl)
+	node.EXPECT().Config().Return(&types.NodeConfig{
+		ShortName:   "node1",
+		Healthcheck: &types.HealthcheckConfig{Test: []string{"CMD", "true"}},
+	}).AnyTimes()
+	node.EXPECT().IsHealthy(gomock.Any()).Return(false, nil).AnyTimes()
+	c.Nodes["node1"] = node
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	watched := make(chan struct{})
+	go func() {
+		c.watchNodesHealth(ctx)
+		close(watched)
+	}()
+
+	// the node health check takes minutes to fail, the wait gives up on it way earlier
+	done := make(chan struct{})
+	go func() {
+		c.waitNodesHealth(watched, cancel)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the nodes health wait is not bounded")
+	}
+
+	c.webhooks.wait()
+
+	if d := cmp.Diff([]types.WebhookEventType{types.WebhookNodeUnhealthy}, rec.eventTypes()); d != "" {
+		t.Errorf("delivered events mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestHealthcheckFailTime(t *testing.T) {
+	tests := map[string]struct {
+		hc   *types.HealthcheckConfig
+		want time.Duration
+	}{
+		"docker-defaults": {
+			hc:   &types.HealthcheckConfig{Test: []string{"CMD", "true"}},
+			want: 3 * time.Minute,
+		},
+		"set": {
+			hc:   &types.HealthcheckConfig{Interval: 5, Timeout: 1, Retries: 2, StartPeriod: 10},
+			want: 22 * time.Second,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := healthcheckFailTime(tt.hc); got != tt.want {
+				t.Errorf("want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
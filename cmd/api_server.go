@@ -0,0 +1,78 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab/apiserver"
+)
+
+var (
+	apiAddress       string
+	apiTokensFile    string
+	apiTLSCert       string
+	apiTLSKey        string
+	apiTopologiesDir string
+)
+
+// apiServerCmd represents the api-server command.
+var apiServerCmd = &cobra.Command{
+	Use:   "api-server",
+	Short: "serve the lab lifecycle operations over HTTP REST API",
+	Long: `api-server exposes the deploy, destroy, inspect, exec, save and netem operations over HTTP/JSON.
+The users are authenticated with the tokens and operate only on the labs they own.
+reference: https://containerlab.dev/cmd/api-server/`,
+	PreRunE: sudoCheck,
+	RunE:    apiServerFn,
+}
+
+func init() {
+	rootCmd.AddCommand(apiServerCmd)
+	apiServerCmd.Flags().StringVarP(&apiAddress, "address", "a", "127.0.0.1:8080",
+		"address to listen on in the format of <ip>:<port>")
+	apiServerCmd.Flags().StringVarP(&apiTokensFile, "tokens-file", "", "",
+		"YAML file mapping the user names to their API tokens")
+	apiServerCmd.Flags().StringVarP(&apiTLSCert, "tls-cert", "", "",
+		"TLS certificate file, the API is served over HTTPS when set")
+	apiServerCmd.Flags().StringVarP(&apiTLSKey, "tls-key", "", "", "TLS key file")
+	apiServerCmd.Flags().StringVarP(&apiTopologiesDir, "topologies-dir", "",
+		apiserver.DefaultTopologiesDir,
+		"directory the topologies posted in the deploy requests are stored in")
+
+	apiServerCmd.MarkFlagRequired("tokens-file")
+	apiServerCmd.MarkFlagsRequiredTogether("tls-cert", "tls-key")
+}
+
+func apiServerFn(_ *cobra.Command, _ []string) error {
+	tokens, err := apiserver.LoadTokens(apiTokensFile)
+	if err != nil {
+		return err
+	}
+
+	if host, _, _ := net.SplitHostPort(apiAddress); apiTLSCert == "" && host != "localhost" &&
+		!net.ParseIP(host).IsLoopback() {
+		log.Warnf("API server listens on %s without TLS, the tokens are sent in clear text", apiAddress)
+	}
+
+	if err := apiserver.CheckTopologiesDir(apiTopologiesDir); err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	srv := apiserver.New(apiserver.NewClabBackend(rt, timeout, debug), tokens,
+		apiserver.WithTopologiesDir(apiTopologiesDir),
+	)
+
+	return srv.ListenAndServe(ctx, apiAddress, apiTLSCert, apiTLSKey)
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -47,6 +48,19 @@ var nodeFilter []string
 // skipLabDirFileACLs skips provisioning of extended File ACLs for the Lab directory.
 var skipLabDirFileACLs bool
 
+// instance id of the topology instance.
+var instance string
+
+// autoSubnet flag and the supernets the management subnets are picked from.
+var (
+	autoSubnet             bool
+	autoSubnetIPv4Supernet string
+	autoSubnetIPv6Supernet string
+)
+
+// hostKeyScanTimeout is the time to wait for the nodes to become healthy before their SSH host keys are scanned.
+var hostKeyScanTimeout time.Duration
+
 // deployCmd represents the deploy command.
 var deployCmd = &cobra.Command{
 	Use:          "deploy",
@@ -76,6 +90,16 @@ func init() {
 		"comma separated list of nodes to include")
 	deployCmd.Flags().BoolVarP(&skipLabDirFileACLs, "skip-labdir-acl", "", false,
 		"skip the lab directory extended ACLs provisioning")
+	deployCmd.Flags().StringVarP(&instance, "instance", "", "",
+		"deploy the lab as an instance with the given id, implies --auto-subnet")
+	deployCmd.Flags().BoolVarP(&autoSubnet, "auto-subnet", "", false,
+		"pick the management subnets that are not used by other container networks")
+	deployCmd.Flags().StringVarP(&autoSubnetIPv4Supernet, "auto-subnet-ipv4", "", clab.DefaultAutoSubnetIPv4Supernet,
+		"IPv4 supernet the management subnet is picked from")
+	deployCmd.Flags().StringVarP(&autoSubnetIPv6Supernet, "auto-subnet-ipv6", "", clab.DefaultAutoSubnetIPv6Supernet,
+		"IPv6 supernet the management subnet is picked from")
+	deployCmd.Flags().DurationVarP(&hostKeyScanTimeout, "host-key-scan-timeout", "", 30*time.Second,
+		"time to wait for the nodes to become healthy before their SSH host keys are pinned, 0 disables the pinning")
 }
 
 // deployFn function runs deploy sub command.
@@ -117,6 +141,12 @@ func deployFn(_ *cobra.Command, _ []string) error {
 	if v6 := mgmtIPv6Subnet.String(); v6 != "<nil>" {
 		opts = append(opts, clab.WithManagementIpv6Subnet(v6))
 	}
+	if instance != "" {
+		opts = append(opts, clab.WithInstance(instance))
+	}
+	if autoSubnet || instance != "" {
+		opts = append(opts, clab.WithAutoSubnet(autoSubnetIPv4Supernet, autoSubnetIPv6Supernet))
+	}
 
 	c, err := clab.NewContainerLab(opts...)
 	if err != nil {
@@ -135,7 +165,8 @@ func deployFn(_ *cobra.Command, _ []string) error {
 		SetReconfigure(reconfigure).
 		SetGraph(graph).
 		SetSkipPostDeploy(skipPostDeploy).
-		SetSkipLabDirFileACLs(skipLabDirFileACLs)
+		SetSkipLabDirFileACLs(skipLabDirFileACLs).
+		SetHostKeyScanTimeout(hostKeyScanTimeout)
 
 	containers, err := c.Deploy(ctx, deploymentOptions)
 	if err != nil {
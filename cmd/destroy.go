@@ -46,6 +46,13 @@ func init() {
 	destroyCmd.Flags().BoolVarP(&keepMgmtNet, "keep-mgmt-net", "", false, "do not remove the management network")
 	destroyCmd.Flags().StringSliceVarP(&nodeFilter, "node-filter", "", []string{},
 		"comma separated list of nodes to include")
+	destroyCmd.Flags().StringVarP(&instance, "instance", "", "", "id of the lab instance to destroy")
+}
+
+// labInstance identifies a lab by its topology file and instance id.
+type labInstance struct {
+	topo     string
+	instance string
 }
 
 func destroyFn(_ *cobra.Command, _ []string) error {
@@ -54,9 +61,9 @@ func destroyFn(_ *cobra.Command, _ []string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// topo will hold the reference to the topology file
+	// topo will hold the reference to the topology file and the instance id
 	// as the key and the respective lab directory as the referenced value
-	topos := map[string]string{}
+	topos := map[labInstance]string{}
 
 	switch {
 	case !all:
@@ -70,7 +77,7 @@ func destroyFn(_ *cobra.Command, _ []string) error {
 			return nil
 		}
 
-		topos[topo] = filepath.Dir(cnts[0].Labels[labels.NodeLabDir])
+		topos[labInstance{topo, instance}] = filepath.Dir(cnts[0].Labels[labels.NodeLabDir])
 
 	case all:
 		containers, err := listContainers(ctx, topo)
@@ -83,16 +90,17 @@ func destroyFn(_ *cobra.Command, _ []string) error {
 		}
 		// get unique topo files from all labs
 		for i := range containers {
-			topos[containers[i].Labels[labels.TopoFile]] =
-				filepath.Dir(containers[i].Labels[labels.NodeLabDir])
+			li := labInstance{containers[i].Labels[labels.TopoFile], containers[i].Labels[labels.Instance]}
+			topos[li] = filepath.Dir(containers[i].Labels[labels.NodeLabDir])
 		}
 	}
 
 	log.Debugf("We got the following topos struct for destroy: %+v", topos)
-	for topo, labdir := range topos {
+	for li, labdir := range topos {
 		opts := []clab.ClabOption{
 			clab.WithTimeout(timeout),
-			clab.WithTopoPath(topo, varsFile),
+			clab.WithTopoPath(li.topo, varsFile),
+			clab.WithInstance(li.instance),
 			clab.WithNodeFilter(nodeFilter),
 			clab.WithRuntime(rt,
 				&runtime.RuntimeConfig{
@@ -107,11 +115,18 @@ func destroyFn(_ *cobra.Command, _ []string) error {
 			clab.WithSkippedBindsPathsCheck(),
 		}
 
+		// the management network of an instance is looked up to avoid creating it
+		// with the topology subnets, which are used by the other instances
+		if li.instance != "" {
+			opts = append(opts, clab.WithAutoSubnet(clab.DefaultAutoSubnetIPv4Supernet,
+				clab.DefaultAutoSubnetIPv6Supernet))
+		}
+
 		if keepMgmtNet {
 			opts = append(opts, clab.WithKeepMgmtNet())
 		}
 
-		log.Debugf("going through extracted topos for destroy, got a topo file %v and generated opts list %+v", topo, opts)
+		log.Debugf("going through extracted topos for destroy, got a topo file %v and generated opts list %+v", li.topo, opts)
 		nc, err := clab.NewContainerLab(opts...)
 		if err != nil {
 			return err
@@ -200,7 +215,7 @@ func listContainers(ctx context.Context, topo string) ([]runtime.GenericContaine
 
 	// when topo file is provided, filter containers by lab name
 	if topo != "" {
-		opts = append(opts, clab.WithTopoPath(topo, varsFile))
+		opts = append(opts, clab.WithTopoPath(topo, varsFile), clab.WithInstance(instance))
 	}
 
 	c, err := clab.NewContainerLab(opts...)
@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/srl-labs/containerlab/clab"
@@ -18,9 +19,13 @@ import (
 )
 
 var (
-	labelsFilter []string
-	execFormat   string
-	execCommands []string
+	labelsFilter   []string
+	execFormat     string
+	execCommands   []string
+	execSpecFile   string
+	execCmdTimeout time.Duration
+	execRetries    int
+	execSaveOutput bool
 )
 
 // execCmd represents the exec command.
@@ -35,8 +40,12 @@ func execFn(_ *cobra.Command, _ []string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	if len(execCommands) == 0 {
-		return errors.New("provide command to execute")
+	if len(execCommands) == 0 && execSpecFile == "" {
+		return errors.New("provide command to execute or exec spec file")
+	}
+
+	if execCmdTimeout < 0 || execRetries < 0 {
+		return errors.New("cmd-timeout and retries can't be negative")
 	}
 
 	outputFormat, err := exec.ParseExecOutputFormat(execFormat)
@@ -44,6 +53,17 @@ func execFn(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
+	var spec *exec.Spec
+	if execSpecFile != "" {
+		spec, err = exec.LoadSpec(execSpecFile, exec.CmdOptions{
+			Timeout: execCmdTimeout,
+			Retries: &execRetries,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	opts := make([]clab.ClabOption, 0, 5)
 
 	// exec can work with or without a topology file
@@ -85,7 +105,14 @@ func execFn(_ *cobra.Command, _ []string) error {
 		filters = append(filters, types.FilterFromLabelStrings(labFilter)...)
 	}
 
-	resultCollection, err := c.Exec(ctx, execCommands, clab.NewExecOptions(filters))
+	execOptions := clab.NewExecOptions(filters).
+		SetSpec(spec).
+		SetCmdTimeout(execCmdTimeout).
+		SetRetries(execRetries).
+		SetMaxWorkers(maxWorkers).
+		SetSaveOutput(execSaveOutput)
+
+	resultCollection, err := c.Exec(ctx, execCommands, execOptions)
 	if err != nil {
 		return err
 	}
@@ -110,4 +137,14 @@ func init() {
 	execCmd.Flags().StringArrayVarP(&execCommands, "cmd", "", []string{}, "command to execute")
 	execCmd.Flags().StringSliceVarP(&labelsFilter, "label", "", []string{}, "labels to filter container subset")
 	execCmd.Flags().StringVarP(&execFormat, "format", "f", "plain", "output format. One of [json, plain]")
+	execCmd.Flags().StringVarP(&execSpecFile, "spec", "", "",
+		"path to the exec spec file mapping the nodes to the commands executed on them")
+	execCmd.Flags().DurationVarP(&execCmdTimeout, "cmd-timeout", "", 0,
+		"time a single command attempt is allowed to run for, 0 means no timeout")
+	execCmd.Flags().IntVarP(&execRetries, "retries", "", 0,
+		"number of times a failed command is retried with an exponential backoff")
+	execCmd.Flags().UintVarP(&maxWorkers, "max-workers", "", 0,
+		"limit the maximum number of nodes the commands are executed on concurrently, 0 means the number of CPUs")
+	execCmd.Flags().BoolVarP(&execSaveOutput, "save-output", "", false,
+		"save the command outputs to the per node files in the lab directory")
 }
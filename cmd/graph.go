@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"html/template"
 	"sort"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -24,12 +25,20 @@ var (
 	tmpl             string
 	offline          bool
 	dot              bool
+	svg              bool
+	png              bool
 	mermaid          bool
 	mermaidDirection string
 	drawio           bool
 	drawioVersion    string
 	drawioArgs       []string
+	d2               bool
+	plantuml         bool
+	cytoscape        bool
+	graphml          bool
 	staticDir        string
+	updateInterval   time.Duration
+	edit             bool
 )
 
 // graphCmd represents the graph command.
@@ -40,9 +49,14 @@ var graphCmd = &cobra.Command{
 	RunE:  graphFn,
 }
 
-func graphFn(_ *cobra.Command, _ []string) error {
+func graphFn(cmd *cobra.Command, _ []string) error {
 	var err error
 
+	// the editor is served on the loopback address unless the address is set explicitly
+	if edit && !cmd.Flags().Changed("srv") {
+		srv = clab.DefaultGraphEditorAddress
+	}
+
 	opts := []clab.ClabOption{
 		clab.WithTimeout(timeout),
 		clab.WithTopoPath(topo, varsFile),
@@ -70,6 +84,19 @@ func graphFn(_ *cobra.Command, _ []string) error {
 		return c.GenerateDotGraph()
 	}
 
+	if svg || png {
+		var formats []string
+		if svg {
+			formats = append(formats, clab.DiagramFormatSVG)
+		}
+
+		if png {
+			formats = append(formats, clab.DiagramFormatPNG)
+		}
+
+		return c.GenerateDiagrams(formats...)
+	}
+
 	if mermaid {
 		return c.GenerateMermaidGraph(mermaidDirection)
 	}
@@ -78,6 +105,22 @@ func graphFn(_ *cobra.Command, _ []string) error {
 		return c.GenerateDrawioDiagram(drawioVersion, drawioArgs)
 	}
 
+	if d2 {
+		return c.GenerateD2Graph()
+	}
+
+	if plantuml {
+		return c.GeneratePlantUMLGraph()
+	}
+
+	if cytoscape {
+		return c.GenerateCytoscapeGraph()
+	}
+
+	if graphml {
+		return c.GenerateGraphMLGraph()
+	}
+
 	gtopo := clab.GraphTopo{
 		Nodes: make([]types.ContainerDetails, 0, len(c.Nodes)),
 		Links: make([]clab.Link, 0, len(c.Links)),
@@ -135,9 +178,12 @@ func graphFn(_ *cobra.Command, _ []string) error {
 	topoD := clab.TopoData{
 		Name: c.Config.Name,
 		Data: template.JS(string(b)), // skipcq: GSC-G203
+		// the live status is only available for the deployed labs
+		Live: len(containers) > 0 && updateInterval > 0,
+		Edit: edit,
 	}
 
-	return c.ServeTopoGraph(tmpl, staticDir, srv, topoD)
+	return c.ServeTopoGraph(tmpl, staticDir, srv, topoD, updateInterval)
 }
 
 func init() {
@@ -146,7 +192,9 @@ func init() {
 		"HTTP server address serving the topology view")
 	graphCmd.Flags().BoolVarP(&offline, "offline", "o", false,
 		"use only information from topo file when building graph")
-	graphCmd.Flags().BoolVarP(&dot, "dot", "", false, "generate dot file")
+	graphCmd.Flags().BoolVarP(&dot, "dot", "", false, "generate dot file along with its svg and png renderings")
+	graphCmd.Flags().BoolVarP(&svg, "svg", "", false, "generate svg diagram file")
+	graphCmd.Flags().BoolVarP(&png, "png", "", false, "generate png diagram file")
 	graphCmd.Flags().BoolVarP(&mermaid, "mermaid", "", false, "print mermaid flowchart to stdout")
 	graphCmd.Flags().StringVarP(&mermaidDirection, "mermaid-direction", "", "TD", "specify direction of mermaid dirgram")
 	graphCmd.Flags().StringSliceVar(&drawioArgs, "drawio-args", []string{},
@@ -154,11 +202,21 @@ func init() {
 	graphCmd.Flags().BoolVarP(&drawio, "drawio", "", false, "generate drawio diagram file")
 	graphCmd.Flags().StringVarP(&drawioVersion, "drawio-version", "", "latest",
 		"version of the clab-io-draw container to use for generating drawio diagram file")
+	graphCmd.Flags().BoolVarP(&d2, "d2", "", false, "generate D2 diagram file")
+	graphCmd.Flags().BoolVarP(&plantuml, "plantuml", "", false, "generate PlantUML nwdiag diagram file")
+	graphCmd.Flags().BoolVarP(&cytoscape, "cytoscape", "", false, "generate Cytoscape.js JSON graph file")
+	graphCmd.Flags().BoolVarP(&graphml, "graphml", "", false, "generate GraphML graph file")
 	graphCmd.Flags().StringVarP(&tmpl, "template", "", "",
 		"Go html template used to generate the graph")
 	graphCmd.Flags().StringVarP(&staticDir, "static-dir", "", "",
 		"Serve static files from the specified directory")
+	graphCmd.Flags().DurationVarP(&updateInterval, "update-interval", "", 2*time.Second,
+		"interval of the live status updates of the topology view, 0 disables the updates")
 	graphCmd.Flags().StringSliceVarP(&nodeFilter, "node-filter", "", []string{},
 		"comma separated list of nodes to include")
-	graphCmd.MarkFlagsMutuallyExclusive("dot", "mermaid", "drawio")
+	graphCmd.Flags().BoolVarP(&edit, "edit", "", false,
+		"enable the topology editor saving the changes to the topology file, served on a loopback address")
+	graphCmd.MarkFlagsMutuallyExclusive("dot", "mermaid", "drawio", "d2", "plantuml", "cytoscape", "graphml", "svg")
+	graphCmd.MarkFlagsMutuallyExclusive("dot", "mermaid", "drawio", "d2", "plantuml", "cytoscape", "graphml", "png")
+	graphCmd.MarkFlagsMutuallyExclusive("edit", "node-filter")
 }
@@ -49,6 +49,7 @@ func init() {
 	inspectCmd.Flags().BoolVarP(&all, "all", "a", false, "show all deployed containerlab labs")
 	inspectCmd.Flags().BoolVarP(&wide, "wide", "w", false,
 		"also more details about a lab and its nodes")
+	inspectCmd.Flags().StringVarP(&instance, "instance", "", "", "id of the lab instance to inspect")
 }
 
 func inspectFn(_ *cobra.Command, _ []string) error {
@@ -75,6 +76,7 @@ func inspectFn(_ *cobra.Command, _ []string) error {
 	if topo != "" {
 		opts = append(opts,
 			clab.WithTopoPath(topo, varsFile),
+			clab.WithInstance(instance),
 			clab.WithNodeFilter(nodeFilter),
 		)
 	}
@@ -132,7 +134,7 @@ func inspectFn(_ *cobra.Command, _ []string) error {
 	return err
 }
 
-func toTableData(contDetails []types.ContainerDetails) []tableWriter.Row {
+func toTableData(contDetails []types.ContainerDetails, mgmtPorts bool) []tableWriter.Row {
 	tabData := make([]tableWriter.Row, 0, len(contDetails))
 	for i := range contDetails {
 		d := &contDetails[i]
@@ -157,6 +159,10 @@ func toTableData(contDetails []types.ContainerDetails) []tableWriter.Row {
 				ipWithoutPrefix(d.IPv4Address),
 				ipWithoutPrefix(d.IPv6Address)))
 
+		if mgmtPorts {
+			tabRow = append(tabRow, strings.ReplaceAll(d.MgmtPorts, ",", "\n"))
+		}
+
 		tabData = append(tabData, tabRow)
 	}
 	return tabData
@@ -224,6 +230,9 @@ func printContainerInspect(containers []runtime.GenericContainer, format string)
 			cdet.Owner = owner
 		}
 
+		cdet.MgmtPorts = cont.Labels[labels.MgmtPorts]
+		cdet.Ports = cont.Ports
+
 		contDetails = append(contDetails, *cdet)
 	}
 
@@ -246,7 +255,12 @@ func printContainerInspect(containers []runtime.GenericContainer, format string)
 		return nil
 
 	case "table":
-		tabData := toTableData(contDetails)
+		// the published management ports column is shown only when there are any
+		mgmtPorts := slices.ContainsFunc(contDetails, func(d types.ContainerDetails) bool {
+			return d.MgmtPorts != ""
+		})
+
+		tabData := toTableData(contDetails, mgmtPorts)
 		table := tableWriter.NewWriter()
 		table.SetOutputMirror(os.Stdout)
 		table.SetStyle(tableWriter.StyleRounded)
@@ -265,6 +279,10 @@ func printContainerInspect(containers []runtime.GenericContainer, format string)
 			"IPv4/6 Address",
 		}
 
+		if mgmtPorts {
+			header = append(header, "Mgmt Ports")
+		}
+
 		if wide {
 			header = slices.Insert(header, 1, "Owner")
 			table.SetColumnConfigs([]tableWriter.ColumnConfig{
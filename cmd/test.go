@@ -0,0 +1,146 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/clab/labtest"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+var (
+	testJUnitReport string
+	testJSONReport  string
+)
+
+// testCmd represents the test command.
+var testCmd = &cobra.Command{
+	Use:   "test [flags] TEST_FILE...",
+	Short: "run test suites against a deployed lab",
+	Long: `test runs the assertions defined in the YAML test files against the nodes of a deployed lab
+and reports the results in the JUnit XML and JSON formats.
+reference: https://containerlab.dev/cmd/test/`,
+	Args:    cobra.MinimumNArgs(1),
+	PreRunE: sudoCheck,
+	RunE:    testFn,
+}
+
+func init() {
+	rootCmd.AddCommand(testCmd)
+	testCmd.Flags().StringVarP(&testJUnitReport, "junit", "", "",
+		"write the JUnit XML report to the file, use - for stdout")
+	testCmd.Flags().StringVarP(&testJSONReport, "json", "", "",
+		"write the JSON report to the file, use - for stdout")
+}
+
+func testFn(_ *cobra.Command, args []string) error {
+	// the test files are loaded first to report their errors before the lab is inspected
+	suites := make([]*labtest.Suite, 0, len(args))
+
+	for _, f := range args {
+		s, err := labtest.LoadSuite(f)
+		if err != nil {
+			return err
+		}
+
+		suites = append(suites, s)
+	}
+
+	opts := []clab.ClabOption{
+		clab.WithTimeout(timeout),
+		clab.WithTopoPath(topo, varsFile),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+			},
+		),
+		clab.WithDebug(debug),
+	}
+
+	if name != "" {
+		opts = append(opts, clab.WithLabName(name))
+	}
+
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return err
+	}
+
+	if err := c.ResolveLinks(); err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	lab := labtest.NewClabLab(c)
+
+	results := make([]*labtest.SuiteResult, 0, len(suites))
+	tests, failures := 0, 0
+
+	for _, s := range suites {
+		r := labtest.Run(ctx, lab, s)
+
+		tests += r.Tests
+		failures += r.Failures
+
+		results = append(results, r)
+	}
+
+	// the details of the failed checks are printed unless a report is written to stdout
+	if testJUnitReport != "-" && testJSONReport != "-" {
+		if err := labtest.WriteFailures(os.Stdout, results); err != nil {
+			return err
+		}
+	}
+
+	if err := writeTestReport(testJUnitReport, results, labtest.WriteJUnit); err != nil {
+		return err
+	}
+
+	if err := writeTestReport(testJSONReport, results, labtest.WriteJSON); err != nil {
+		return err
+	}
+
+	if failures != 0 {
+		return fmt.Errorf("%d of %d tests failed", failures, tests)
+	}
+
+	return nil
+}
+
+// writeTestReport writes the report to the file, or to stdout when the file is -.
+func writeTestReport(file string, results []*labtest.SuiteResult,
+	write func(io.Writer, []*labtest.SuiteResult) error,
+) error {
+	switch file {
+	case "":
+		return nil
+	case "-":
+		return write(os.Stdout, results)
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	if err := write(f, results); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write the test report %s: %w", file, err)
+	}
+
+	return f.Close()
+}
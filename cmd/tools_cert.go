@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -31,6 +32,7 @@ var (
 	caCertPath       string
 	caKeyPath        string
 	keySize          int
+	keyAlgorithm     string
 )
 
 func init() {
@@ -48,6 +50,8 @@ func init() {
 	CACreateCmd.Flags().StringVarP(&path, "path", "p", "",
 		"path to write certificate and key to. Default is current working directory")
 	CACreateCmd.Flags().StringVarP(&caNamePrefix, "name", "n", "ca", "certificate/key filename prefix")
+	CACreateCmd.Flags().StringVarP(&keyAlgorithm, "key-algorithm", "", cert.DefaultKeyAlgorithm,
+		"private key algorithm, one of: "+strings.Join(cert.KeyAlgorithms, ", "))
 
 	signCertCmd.Flags().StringSliceVarP(&certHosts, "hosts", "", []string{},
 		"comma separate list of hosts of a certificate")
@@ -61,7 +65,9 @@ func init() {
 	signCertCmd.Flags().StringVarP(&path, "path", "p", "",
 		"path to write certificate and key to. Default is current working directory")
 	signCertCmd.Flags().StringVarP(&certNamePrefix, "name", "n", "cert", "certificate/key filename prefix")
-	signCertCmd.Flags().IntVarP(&keySize, "key-size", "", 2048, "private key size")
+	signCertCmd.Flags().IntVarP(&keySize, "key-size", "", cert.DefaultRSAKeySize, "private key size")
+	signCertCmd.Flags().StringVarP(&keyAlgorithm, "key-algorithm", "", "",
+		"private key algorithm, one of: "+strings.Join(cert.KeyAlgorithms, ", ")+". Defaults to the algorithm of the CA key")
 }
 
 var certCmd = &cobra.Command{
@@ -96,8 +102,12 @@ func createCA(_ *cobra.Command, _ []string) error {
 		}
 	}
 
-	log.Infof("Certificate attributes: CN=%s, C=%s, L=%s, O=%s, OU=%s, Validity period=%s",
-		commonName, country, locality, organization, organizationUnit, expiry)
+	if err := cert.ValidateKeyAlgorithm(keyAlgorithm); err != nil {
+		return err
+	}
+
+	log.Infof("Certificate attributes: CN=%s, C=%s, L=%s, O=%s, OU=%s, Validity period=%s, Key algorithm=%s",
+		commonName, country, locality, organization, organizationUnit, expiry, keyAlgorithm)
 
 	ca := cert.NewCA()
 
@@ -114,6 +124,7 @@ func createCA(_ *cobra.Command, _ []string) error {
 		OrganizationUnit: organizationUnit,
 		Expiry:           expDuration,
 		KeySize:          keySize,
+		KeyAlgorithm:     keyAlgorithm,
 	}
 
 	caCert, err := ca.GenerateCACert(csrInput)
@@ -146,6 +157,10 @@ func signCert(_ *cobra.Command, _ []string) error {
 		}
 	}
 
+	if err := cert.ValidateKeyAlgorithm(keyAlgorithm); err != nil {
+		return err
+	}
+
 	ca := cert.NewCA()
 
 	var caCert *cert.Certificate
@@ -183,6 +198,7 @@ func signCert(_ *cobra.Command, _ []string) error {
 			OrganizationUnit: organizationUnit,
 			Expiry:           expDuration,
 			KeySize:          keySize,
+			KeyAlgorithm:     keyAlgorithm,
 		})
 	if err != nil {
 		return err
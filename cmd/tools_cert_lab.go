@@ -0,0 +1,260 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	tableWriter "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/cert"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+var (
+	certNodes         []string
+	certListFormat    string
+	certInspectFormat string
+	certRenewBefore   time.Duration
+	certForce         bool
+	certRenewCA       bool
+)
+
+func init() {
+	certCmd.AddCommand(certListCmd)
+	certCmd.AddCommand(certInspectCmd)
+	certCmd.AddCommand(certRenewCmd)
+
+	certListCmd.Flags().StringVarP(&certListFormat, "format", "f", "table", "output format. One of [table, json]")
+
+	certInspectCmd.Flags().StringSliceVarP(&certNodes, "node", "", []string{},
+		"comma separated list of nodes to inspect the certificates of, the CA and all nodes by default")
+	certInspectCmd.Flags().StringVarP(&certInspectFormat, "format", "f", "text", "output format. One of [text, json]")
+
+	certRenewCmd.Flags().StringSliceVarP(&certNodes, "node", "", []string{},
+		"comma separated list of nodes to renew the certificates of, all nodes by default")
+	certRenewCmd.Flags().DurationVarP(&certRenewBefore, "renew-before", "", 7*24*time.Hour,
+		"renew the certificates expiring within this duration")
+	certRenewCmd.Flags().BoolVarP(&certForce, "force", "", false,
+		"renew the certificates regardless of their expiry and addresses")
+	certRenewCmd.Flags().BoolVarP(&certRenewCA, "ca", "", false,
+		"renew the lab CA certificate and re-issue the certificates of all nodes")
+}
+
+var certListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list the certificates of a lab",
+	Long: `list the CA and node certificates of a lab with their expiry and SANs
+reference: https://containerlab.dev/cmd/tools/cert/list/`,
+	RunE: certListFn,
+}
+
+var certInspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "show the details of the certificates of a lab",
+	RunE:  certInspectFn,
+}
+
+var certRenewCmd = &cobra.Command{
+	Use:   "renew",
+	Short: "re-issue the expiring certificates of a lab",
+	Long: `renew re-issues the node certificates that are missing, expire soon
+or do not list the current management addresses of the nodes, and the expiring lab CA.
+The renewed certificates are applied to the running nodes that support it
+reference: https://containerlab.dev/cmd/tools/cert/renew/`,
+	PreRunE: sudoCheck,
+	RunE:    certRenewFn,
+}
+
+// newCertLab returns the lab of the topology with the CA loaded from the lab directory.
+func newCertLab() (*clab.CLab, error) {
+	if topo == "" {
+		return nil, errors.New("provide the topology file of the lab with --topo flag")
+	}
+
+	opts := []clab.ClabOption{
+		clab.WithTimeout(timeout),
+		clab.WithTopoPath(topo, varsFile),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:   debug,
+				Timeout: timeout,
+			},
+		),
+		clab.WithDebug(debug),
+	}
+
+	if name != "" {
+		opts = append(opts, clab.WithLabName(name))
+	}
+
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, c.LoadCA()
+}
+
+func certListFn(_ *cobra.Command, _ []string) error {
+	c, err := newCertLab()
+	if err != nil {
+		return err
+	}
+
+	infos, err := c.CertificateInfos(nil)
+	if err != nil {
+		return err
+	}
+
+	switch certListFormat {
+	case "json":
+		return printCertJSON(infos)
+	case "table":
+		table := tableWriter.NewWriter()
+		table.SetOutputMirror(os.Stdout)
+		table.SetStyle(tableWriter.StyleRounded)
+		table.Style().Format.Header = text.FormatTitle
+		table.Style().Format.HeaderAlign = text.AlignCenter
+		table.Style().Options.SeparateRows = true
+		table.Style().Color = tableWriter.ColorOptions{
+			Header: text.Colors{text.Bold},
+		}
+
+		table.AppendHeader(tableWriter.Row{"Name", "Key Algorithm", "Expires", "Expires In", "SANs"})
+
+		for _, i := range infos {
+			table.AppendRow(tableWriter.Row{
+				i.Name,
+				i.KeyAlgorithm,
+				i.NotAfter.Local().Format(time.DateTime),
+				expiresIn(i.NotAfter),
+				strings.Join(slices.Concat(i.DNSNames, i.IPAddresses), "\n"),
+			})
+		}
+
+		table.Render()
+
+		return nil
+	}
+
+	return fmt.Errorf("unsupported output format %q", certListFormat)
+}
+
+func certInspectFn(_ *cobra.Command, _ []string) error {
+	c, err := newCertLab()
+	if err != nil {
+		return err
+	}
+
+	infos, err := c.CertificateInfos(certNodes)
+	if err != nil {
+		return err
+	}
+
+	switch certInspectFormat {
+	case "json":
+		return printCertJSON(infos)
+	case "text":
+		for idx, i := range infos {
+			if idx > 0 {
+				fmt.Println()
+			}
+
+			fmt.Printf("Name:          %s\n", i.Name)
+			fmt.Printf("Subject:       %s\n", i.Subject)
+			fmt.Printf("Issuer:        %s\n", i.Issuer)
+			fmt.Printf("Serial Number: %s\n", i.SerialNumber)
+			fmt.Printf("Key Algorithm: %s\n", i.KeyAlgorithm)
+			fmt.Printf("CA:            %t\n", i.IsCA)
+			fmt.Printf("Not Before:    %s\n", i.NotBefore.Local().Format(time.DateTime))
+			fmt.Printf("Not After:     %s (%s)\n", i.NotAfter.Local().Format(time.DateTime), expiresIn(i.NotAfter))
+
+			if len(i.DNSNames) != 0 {
+				fmt.Printf("DNS SANs:      %s\n", strings.Join(i.DNSNames, ", "))
+			}
+
+			if len(i.IPAddresses) != 0 {
+				fmt.Printf("IP SANs:       %s\n", strings.Join(i.IPAddresses, ", "))
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unsupported output format %q", certInspectFormat)
+}
+
+func certRenewFn(_ *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, err := newCertLab()
+	if err != nil {
+		return err
+	}
+
+	renewals, err := c.RenewCertificates(ctx, &clab.CertRenewOptions{
+		Nodes:       certNodes,
+		RenewBefore: certRenewBefore,
+		Force:       certForce,
+		RenewCA:     certRenewCA,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(renewals) == 0 {
+		log.Info("No certificates need to be renewed")
+		return nil
+	}
+
+	var reloaded int
+	for _, r := range renewals {
+		if r.Reloaded {
+			reloaded++
+		}
+	}
+
+	log.Infof("Renewed %d certificates, %d of them were reloaded by the running nodes", len(renewals), reloaded)
+
+	return nil
+}
+
+// printCertJSON prints the certificate summaries in JSON format.
+func printCertJSON(infos []*cert.Info) error {
+	b, err := json.MarshalIndent(infos, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(b))
+
+	return nil
+}
+
+// expiresIn returns the human readable time left until the expiry.
+func expiresIn(t time.Time) string {
+	d := time.Until(t)
+	if d <= 0 {
+		return "expired"
+	}
+
+	if d >= 48*time.Hour {
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+
+	return d.Truncate(time.Minute).String()
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/clab/dnsserver"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+var (
+	dnsAddresses []string
+	dnsDomain    string
+)
+
+func init() {
+	toolsCmd.AddCommand(dnsCmd)
+	dnsCmd.AddCommand(dnsServeCmd)
+
+	dnsServeCmd.Flags().StringSliceVarP(&dnsAddresses, "address", "a", []string{"127.0.0.1:53"},
+		"address to listen on in the format of <ip>:<port>, can be repeated")
+	dnsServeCmd.Flags().StringVarP(&dnsDomain, "domain", "", dnsserver.DefaultDomain,
+		"parent domain of the lab domains")
+}
+
+var dnsCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "DNS server operations",
+}
+
+var dnsServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "serve the lab node names over DNS",
+	Long: `serve answers A, AAAA and PTR queries for the <node>.<lab>.<domain> names
+of the running lab containers and forwards other queries to the host DNS servers.`,
+	RunE: dnsServeFn,
+}
+
+func dnsServeFn(_ *cobra.Command, _ []string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	opts := []clab.ClabOption{
+		clab.WithTimeout(timeout),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:   debug,
+				Timeout: timeout,
+			},
+		),
+		clab.WithDebug(debug),
+	}
+
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return err
+	}
+
+	upstreams, err := utils.ExtractDNSServersFromResolvConf(os.DirFS("/"),
+		[]string{"etc/resolv.conf", "run/systemd/resolve/resolv.conf"})
+	if err != nil {
+		return err
+	}
+
+	filter := []*types.GenericFilter{
+		{
+			FilterType: "label",
+			Field:      labels.Containerlab,
+			Operator:   "exists",
+		},
+	}
+
+	records := func(ctx context.Context) ([]*dnsserver.Record, error) {
+		containers, err := c.ListContainers(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		return dnsserver.RecordsFromContainers(containers, dnsDomain), nil
+	}
+
+	srv := dnsserver.New(records,
+		dnsserver.WithDomain(dnsDomain),
+		dnsserver.WithUpstreams(upstreams),
+	)
+
+	return srv.ListenAndServe(ctx, dnsAddresses)
+}
@@ -7,23 +7,15 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"math"
-	"net"
 	"os"
 	"strconv"
 	"time"
 
-	"github.com/containernetworking/plugins/pkg/ns"
-	gotc "github.com/florianl/go-tc"
 	tableWriter "github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
-	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/srl-labs/containerlab/clab"
-	"github.com/srl-labs/containerlab/internal/tc"
-	"github.com/srl-labs/containerlab/links"
 	"github.com/srl-labs/containerlab/runtime"
-	"github.com/vishvananda/netlink"
 )
 
 var (
@@ -81,23 +73,7 @@ var netemShowCmd = &cobra.Command{
 }
 
 func netemSetFn(_ *cobra.Command, _ []string) error {
-	// Get the runtime initializer.
-	_, rinit, err := clab.RuntimeInitializer(rt)
-	if err != nil {
-		return err
-	}
-
-	// init the runtime
-	rt := rinit()
-
-	// init runtime with timeout
-	err = rt.Init(
-		runtime.WithConfig(
-			&runtime.RuntimeConfig{
-				Timeout: timeout,
-			},
-		),
-	)
+	rt, err := netemRuntime()
 	if err != nil {
 		return err
 	}
@@ -105,67 +81,54 @@ func netemSetFn(_ *cobra.Command, _ []string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// retrieve the containers NSPath
-	nodeNsPath, err := rt.GetNSPath(ctx, netemNode)
+	imp, err := clab.SetImpairments(ctx, rt, netemNode, netemInterface, netemParams())
 	if err != nil {
 		return err
 	}
 
-	var nodeNs ns.NetNS
+	printImpairments([]*clab.NetemImpairment{imp})
 
-	if nodeNs, err = ns.GetNS(nodeNsPath); err != nil {
-		return err
-	}
+	return nil
+}
 
-	tcnl, err := tc.NewTC(int(nodeNs.Fd()))
+// netemRuntime returns the initialized container runtime.
+func netemRuntime() (runtime.ContainerRuntime, error) {
+	// Get the runtime initializer.
+	_, rinit, err := clab.RuntimeInitializer(rt)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	defer func() {
-		if err := tcnl.Close(); err != nil {
-			log.Errorf("could not close rtnetlink socket: %v\n", err)
-		}
-	}()
-
-	err = nodeNs.Do(func(_ ns.NetNS) error {
-		netemIfLink, err := netlink.LinkByName(links.SanitiseInterfaceName(netemInterface))
-		if err != nil {
-			return err
-		}
-
-		netemIfName := netemIfLink.Attrs().Name
-		link, err := net.InterfaceByName(netemIfName)
-		if err != nil {
-			return err
-		}
-
-		qdisc, err := tc.SetImpairments(tcnl, netemNode, link, netemDelay, netemJitter, netemLoss, netemRate, netemCorruption)
-		if err != nil {
-			return err
-		}
-
-		printImpairments([]gotc.Object{*qdisc})
+	// init the runtime
+	rt := rinit()
 
-		return nil
-	})
+	// init runtime with timeout
+	err = rt.Init(
+		runtime.WithConfig(
+			&runtime.RuntimeConfig{
+				Timeout: timeout,
+			},
+		),
+	)
 
-	return err
+	return rt, err
 }
 
-func validateInput(_ *cobra.Command, _ []string) error {
-	if netemLoss < 0 || netemLoss > 100 {
-		return fmt.Errorf("packet loss must be in the range between 0 and 100")
-	}
-
-	if netemJitter != 0 && netemDelay == 0 {
-		return fmt.Errorf("jitter cannot be set without setting delay")
+func netemParams() *clab.NetemParams {
+	return &clab.NetemParams{
+		Delay:      netemDelay,
+		Jitter:     netemJitter,
+		Loss:       netemLoss,
+		Rate:       netemRate,
+		Corruption: netemCorruption,
 	}
+}
 
-	return nil
+func validateInput(_ *cobra.Command, _ []string) error {
+	return netemParams().Validate()
 }
 
-func printImpairments(qdiscs []gotc.Object) {
+func printImpairments(imps []*clab.NetemImpairment) {
 	table := tableWriter.NewWriter()
 	table.SetOutputMirror(os.Stdout)
 	table.SetStyle(tableWriter.StyleRounded)
@@ -188,30 +151,23 @@ func printImpairments(qdiscs []gotc.Object) {
 
 	var rows []tableWriter.Row
 
-	for _, qdisc := range qdiscs {
-		rows = append(rows, qdiscToTableData(qdisc))
+	for _, imp := range imps {
+		rows = append(rows, impairmentToTableData(imp))
 	}
 
 	table.AppendRows(rows)
 	table.Render()
 }
 
-func qdiscToTableData(qdisc gotc.Object) tableWriter.Row {
-	link, err := netlink.LinkByIndex(int(qdisc.Ifindex))
-	if err != nil {
-		log.Errorf("could not get netlink interface by index: %v", err)
-	}
-
-	var delay, jitter, loss, rate, corruption string
-
-	ifDisplayName := link.Attrs().Name
-	if link.Attrs().Alias != "" {
-		ifDisplayName += fmt.Sprintf(" (%s)", link.Attrs().Alias)
+func impairmentToTableData(imp *clab.NetemImpairment) tableWriter.Row {
+	ifDisplayName := imp.Interface
+	if imp.Alias != "" {
+		ifDisplayName += fmt.Sprintf(" (%s)", imp.Alias)
 	}
 
 	// return N/A values when netem is not set
 	// which is the case when qdisc is not set for an interface
-	if qdisc.Netem == nil {
+	if !imp.Set {
 		return tableWriter.Row{
 			ifDisplayName,
 			"N/A", // delay
@@ -222,47 +178,18 @@ func qdiscToTableData(qdisc gotc.Object) tableWriter.Row {
 		}
 	}
 
-	if qdisc.Netem.Latency64 != nil {
-		delay = (time.Duration(*qdisc.Netem.Latency64) * time.Nanosecond).String()
-	}
-
-	if qdisc.Netem.Jitter64 != nil {
-		jitter = (time.Duration(*qdisc.Netem.Jitter64) * time.Nanosecond).String()
-	}
-
-	loss = strconv.FormatFloat(float64(qdisc.Netem.Qopt.Loss)/float64(math.MaxUint32)*100, 'f', 2, 64) + "%"
-	rate = strconv.Itoa(int(qdisc.Netem.Rate.Rate * 8 / 1000))
-	corruption = strconv.FormatFloat(float64(qdisc.Netem.Corrupt.Probability)/
-		float64(math.MaxUint32)*100, 'f', 2, 64) + "%"
-
 	return tableWriter.Row{
 		ifDisplayName,
-		delay,
-		jitter,
-		loss,
-		rate,
-		corruption,
+		imp.Delay,
+		imp.Jitter,
+		strconv.FormatFloat(imp.Loss, 'f', 2, 64) + "%",
+		strconv.Itoa(imp.Rate),
+		strconv.FormatFloat(imp.Corruption, 'f', 2, 64) + "%",
 	}
 }
 
 func netemShowFn(_ *cobra.Command, _ []string) error {
-	// Get the runtime initializer.
-	_, rinit, err := clab.RuntimeInitializer(rt)
-	if err != nil {
-		return err
-	}
-
-	// init the runtime
-	rt := rinit()
-
-	// init runtime with timeout
-	err = rt.Init(
-		runtime.WithConfig(
-			&runtime.RuntimeConfig{
-				Timeout: timeout,
-			},
-		),
-	)
+	rt, err := netemRuntime()
 	if err != nil {
 		return err
 	}
@@ -270,39 +197,12 @@ func netemShowFn(_ *cobra.Command, _ []string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// retrieve the containers NSPath
-	nodeNsPath, err := rt.GetNSPath(ctx, netemNode)
-	if err != nil {
-		return err
-	}
-
-	var nodeNs ns.NetNS
-
-	if nodeNs, err = ns.GetNS(nodeNsPath); err != nil {
-		return err
-	}
-
-	tcnl, err := tc.NewTC(int(nodeNs.Fd()))
+	imps, err := clab.Impairments(ctx, rt, netemNode)
 	if err != nil {
 		return err
 	}
 
-	defer func() {
-		if err := tcnl.Close(); err != nil {
-			log.Errorf("could not close rtnetlink socket: %v\n", err)
-		}
-	}()
+	printImpairments(imps)
 
-	err = nodeNs.Do(func(_ ns.NetNS) error {
-		qdiscs, err := tc.Impairments(tcnl)
-		if err != nil {
-			return err
-		}
-
-		printImpairments(qdiscs)
-
-		return nil
-	})
-
-	return err
+	return nil
 }
@@ -0,0 +1,210 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	tableWriter "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+var (
+	reachabilityAllPairs bool
+	reachabilityCount    int
+	reachabilityFormat   string
+)
+
+func init() {
+	toolsCmd.AddCommand(reachabilityCmd)
+
+	reachabilityCmd.Flags().BoolVarP(&reachabilityAllPairs, "all-pairs", "", false,
+		"ping the loopback address of every node from every other node instead of pinging across the links")
+	reachabilityCmd.Flags().IntVarP(&reachabilityCount, "count", "c", 3, "number of echo requests sent per check")
+	reachabilityCmd.Flags().StringVarP(&reachabilityFormat, "format", "f", "table",
+		"output format. One of [table, json]")
+}
+
+var reachabilityCmd = &cobra.Command{
+	Use:   "reachability",
+	Short: "check the reachability across the lab links or between the node loopbacks",
+	Long: `reachability pings across every lab link whose endpoints have known IP addresses,
+or between the loopback addresses of all the nodes in the all-pairs mode, and renders the pass/fail and latency matrix.
+reference: https://containerlab.dev/cmd/tools/reachability/`,
+	PreRunE: sudoCheck,
+	RunE:    reachabilityFn,
+}
+
+func reachabilityFn(_ *cobra.Command, _ []string) error {
+	if reachabilityFormat != "table" && reachabilityFormat != "json" {
+		return fmt.Errorf("output format %q is not supported, use one of [table, json]", reachabilityFormat)
+	}
+
+	if reachabilityCount <= 0 {
+		return errors.New("count should be positive")
+	}
+
+	opts := []clab.ClabOption{
+		clab.WithTimeout(timeout),
+		clab.WithTopoPath(topo, varsFile),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+			},
+		),
+		clab.WithDebug(debug),
+	}
+
+	if name != "" {
+		opts = append(opts, clab.WithLabName(name))
+	}
+
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return err
+	}
+
+	if err := c.ResolveLinks(); err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	var results []*clab.ReachabilityResult
+	if reachabilityAllPairs {
+		results = c.LoopbackReachability(ctx, reachabilityCount)
+	} else {
+		results = c.LinkReachability(ctx, reachabilityCount)
+	}
+
+	switch {
+	case reachabilityFormat == "json":
+		b, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(b))
+	case len(results) == 0:
+		fmt.Println("No reachability checks to run, no IP addresses are known")
+	case reachabilityAllPairs:
+		printReachabilityMatrix(results)
+	default:
+		printLinkReachability(results)
+	}
+
+	failed := 0
+	for _, r := range results {
+		if !r.Reachable {
+			failed++
+		}
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("%d of %d reachability checks failed", failed, len(results))
+	}
+
+	return nil
+}
+
+func newReachabilityTable() tableWriter.Writer {
+	table := tableWriter.NewWriter()
+	table.SetOutputMirror(os.Stdout)
+	table.SetStyle(tableWriter.StyleRounded)
+	table.Style().Format.Header = text.FormatTitle
+	table.Style().Format.HeaderAlign = text.AlignCenter
+	table.Style().Color = tableWriter.ColorOptions{
+		Header: text.Colors{text.Bold},
+	}
+
+	return table
+}
+
+// reachabilityCell returns the pass/fail mark and latency of the result.
+func reachabilityCell(r *clab.ReachabilityResult) string {
+	if !r.Reachable {
+		return text.FgRed.Sprint("✘")
+	}
+
+	return text.FgGreen.Sprint("✔") + fmt.Sprintf(" %.3f ms", r.Latency)
+}
+
+// printLinkReachability prints a row per link with the result of pinging across it.
+func printLinkReachability(results []*clab.ReachabilityResult) {
+	table := newReachabilityTable()
+
+	table.AppendHeader(tableWriter.Row{"Source", "Destination", "Address", "Result", "Error"})
+
+	for _, r := range results {
+		table.AppendRow(tableWriter.Row{
+			r.Source + ":" + r.SourceInterface,
+			r.Destination + ":" + r.DestinationInterface,
+			r.Address,
+			reachabilityCell(r),
+			r.Error,
+		})
+	}
+
+	table.Render()
+}
+
+// printReachabilityMatrix prints the matrix of the results with a row per source node
+// and a column per destination node.
+func printReachabilityMatrix(results []*clab.ReachabilityResult) {
+	var nodes []string
+
+	seen := map[string]bool{}
+	cells := map[string]*clab.ReachabilityResult{}
+
+	for _, r := range results {
+		for _, n := range []string{r.Source, r.Destination} {
+			if !seen[n] {
+				seen[n] = true
+				nodes = append(nodes, n)
+			}
+		}
+
+		cells[r.Source+"\x00"+r.Destination] = r
+	}
+
+	table := newReachabilityTable()
+
+	header := tableWriter.Row{"Source \\ Destination"}
+	for _, n := range nodes {
+		header = append(header, n)
+	}
+
+	table.AppendHeader(header)
+
+	for _, src := range nodes {
+		row := tableWriter.Row{src}
+
+		for _, dst := range nodes {
+			r, ok := cells[src+"\x00"+dst]
+			if !ok {
+				row = append(row, "-")
+				continue
+			}
+
+			row = append(row, reachabilityCell(r))
+		}
+
+		table.AppendRow(row)
+	}
+
+	table.Render()
+}
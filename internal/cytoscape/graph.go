@@ -0,0 +1,87 @@
+package cytoscape
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// A very minimalistic Cytoscape.js JSON generator
+// that covers the usecase of `containerlab graph`
+// command.
+
+// Graph is the graph in the Cytoscape.js JSON format
+// that can be loaded with cy.json() or imported to Cytoscape.
+type Graph struct {
+	Data     GraphData `json:"data"`
+	Elements Elements  `json:"elements"`
+}
+
+type GraphData struct {
+	Name string `json:"name"`
+}
+
+type Elements struct {
+	Nodes []Element `json:"nodes"`
+	Edges []Element `json:"edges"`
+}
+
+// Element is a node or an edge of the graph.
+type Element struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+func NewGraph(name string) *Graph {
+	return &Graph{
+		Data: GraphData{Name: name},
+		Elements: Elements{
+			Nodes: []Element{},
+			Edges: []Element{},
+		},
+	}
+}
+
+// AddNode adds the node, the empty attributes are omitted.
+func (g *Graph) AddNode(name, kind, image, group string) {
+	data := map[string]interface{}{
+		"id":    name,
+		"label": name,
+	}
+
+	for k, v := range map[string]string{"kind": kind, "image": image, "group": group} {
+		if v != "" {
+			data[k] = v
+		}
+	}
+
+	g.Elements.Nodes = append(g.Elements.Nodes, Element{Data: data})
+}
+
+// AddEdge adds the edge between the endpoints with the labels and variables of the link.
+func (g *Graph) AddEdge(source, sourceEndpoint, target, targetEndpoint string,
+	labels map[string]string, vars map[string]interface{},
+) {
+	data := map[string]interface{}{
+		"id":              source + ":" + sourceEndpoint + "--" + target + ":" + targetEndpoint,
+		"source":          source,
+		"source_endpoint": sourceEndpoint,
+		"target":          target,
+		"target_endpoint": targetEndpoint,
+	}
+
+	if len(labels) > 0 {
+		data["labels"] = labels
+	}
+
+	if len(vars) > 0 {
+		data["vars"] = vars
+	}
+
+	g.Elements.Edges = append(g.Elements.Edges, Element{Data: data})
+}
+
+func (g *Graph) Generate(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(g)
+}
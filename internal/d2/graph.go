@@ -0,0 +1,114 @@
+package d2
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// A very minimalistic D2 diagram generator
+// that covers the usecase of `containerlab graph`
+// command.
+
+type Diagram struct {
+	title string
+	nodes []Node
+	edges []Edge
+}
+
+type Node struct {
+	name  string
+	kind  string
+	group string
+}
+
+type Edge struct {
+	nodeA string
+	nodeB string
+	// labels of the edge ends
+	labelA string
+	labelB string
+	// label of the edge itself
+	label string
+}
+
+func NewDiagram() *Diagram {
+	return &Diagram{}
+}
+
+func (d *Diagram) SetTitle(title string) {
+	d.title = title
+}
+
+// AddNode adds the node labeled with its kind to the container of its group.
+func (d *Diagram) AddNode(name, kind, group string) {
+	d.nodes = append(d.nodes, Node{name: name, kind: kind, group: group})
+}
+
+// AddEdge adds the edge between the nodes with the labels of its ends and the edge label.
+func (d *Diagram) AddEdge(nodeA, labelA, nodeB, labelB, label string) {
+	d.edges = append(d.edges, Edge{nodeA: nodeA, nodeB: nodeB, labelA: labelA, labelB: labelB, label: label})
+}
+
+func (d *Diagram) Generate(w io.Writer) {
+	fmt.Fprintf(w, "title: %s {\n  shape: text\n  near: top-center\n  style.font-size: 24\n}\n\n", strconv.Quote(d.title))
+
+	// paths of the nodes including their group containers
+	paths := map[string]string{}
+	groups := map[string][]Node{}
+
+	for _, n := range d.nodes {
+		paths[n.name] = strconv.Quote(n.name)
+		if n.group != "" {
+			paths[n.name] = strconv.Quote(n.group) + "." + paths[n.name]
+		}
+
+		groups[n.group] = append(groups[n.group], n)
+	}
+
+	names := make([]string, 0, len(groups))
+	for g := range groups {
+		names = append(names, g)
+	}
+
+	sort.Strings(names)
+
+	for _, g := range names {
+		indent := ""
+		if g != "" {
+			fmt.Fprintf(w, "%s: {\n", strconv.Quote(g))
+			indent = "  "
+		}
+
+		for _, n := range groups[g] {
+			fmt.Fprintf(w, "%s%s: %s\n", indent, strconv.Quote(n.name), strconv.Quote(n.name+"\n"+n.kind))
+		}
+
+		if g != "" {
+			fmt.Fprintf(w, "}\n")
+		}
+	}
+
+	fmt.Fprintln(w)
+
+	for _, e := range d.edges {
+		a, b := paths[e.nodeA], paths[e.nodeB]
+		if a == "" {
+			a = strconv.Quote(e.nodeA)
+		}
+
+		if b == "" {
+			b = strconv.Quote(e.nodeB)
+		}
+
+		fmt.Fprintf(w, "%s -- %s", a, b)
+
+		if e.label != "" {
+			fmt.Fprintf(w, ": %s", strconv.Quote(e.label))
+		}
+
+		fmt.Fprintf(w, " {\n  source-arrowhead.label: %s\n  target-arrowhead.label: %s\n}\n",
+			strconv.Quote(e.labelA), strconv.Quote(e.labelB))
+	}
+}
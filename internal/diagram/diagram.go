@@ -0,0 +1,305 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package diagram lays out and renders the topology diagrams in SVG and PNG formats
+// without relying on the external tools like Graphviz.
+package diagram
+
+import (
+	"hash/fnv"
+	"image/color"
+	"math"
+	"unicode/utf8"
+)
+
+// Node is a node of the diagram.
+type Node struct {
+	Name  string
+	Group string
+	Kind  string
+	// X and Y are the coordinates of the node center, set by the layout unless the node is Fixed.
+	X, Y float64
+	// Fixed nodes keep their coordinates during the layout.
+	Fixed bool
+}
+
+// Edge is a link between the nodes of the diagram.
+// The labels are placed next to the ends of the edge.
+type Edge struct {
+	Source      string
+	Target      string
+	SourceLabel string
+	TargetLabel string
+}
+
+// Diagram is a topology diagram.
+type Diagram struct {
+	Title string
+	Nodes []*Node
+	Edges []*Edge
+}
+
+// New returns an empty diagram with the title.
+func New(title string) *Diagram {
+	return &Diagram{Title: title}
+}
+
+// AddNode adds the node to the diagram.
+func (d *Diagram) AddNode(n *Node) {
+	d.Nodes = append(d.Nodes, n)
+}
+
+// AddEdge adds the edge to the diagram.
+func (d *Diagram) AddEdge(e *Edge) {
+	d.Edges = append(d.Edges, e)
+}
+
+// node returns the node by its name.
+func (d *Diagram) node(name string) *Node {
+	for _, n := range d.Nodes {
+		if n.Name == name {
+			return n
+		}
+	}
+
+	return nil
+}
+
+const (
+	margin     = 40.0
+	nodeHeight = 36.0
+	nodeMinW   = 80.0
+	nodePadX   = 12.0
+	// titleHeight is the space above the diagram reserved for the title.
+	titleHeight = 40.0
+	// parallelEdgeGap is the distance between the parallel edges connecting the same nodes.
+	parallelEdgeGap = 10.0
+	// labelOffset is the distance of the edge labels from the node borders.
+	labelOffset = 4.0
+
+	// font scales in the diagram units, the glyphs are 5x7 units at scale 1
+	titleFontScale = 2.0
+	nodeFontScale  = 1.5
+	labelFontScale = 1.0
+)
+
+var (
+	colorBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	colorText       = color.RGBA{0x1f, 0x29, 0x37, 0xff}
+	colorEdge       = color.RGBA{0x6b, 0x72, 0x80, 0xff}
+	colorNodeBorder = color.RGBA{0x37, 0x41, 0x51, 0xff}
+	colorLabelBg    = color.RGBA{0xf3, 0xf4, 0xf6, 0xff}
+
+	// nodeColors is the palette the node groups are coloured with.
+	nodeColors = []color.RGBA{
+		{0xbf, 0xdb, 0xfe, 0xff},
+		{0xbb, 0xf7, 0xd0, 0xff},
+		{0xfe, 0xf0, 0x8a, 0xff},
+		{0xfe, 0xca, 0xca, 0xff},
+		{0xdd, 0xd6, 0xfe, 0xff},
+		{0xfe, 0xd7, 0xaa, 0xff},
+		{0xa5, 0xf3, 0xfc, 0xff},
+		{0xfb, 0xcf, 0xe8, 0xff},
+	}
+)
+
+// rect is a node box in the scene.
+type rect struct {
+	x, y, w, h float64
+	label      string
+	fill       color.RGBA
+}
+
+// segment is an edge line in the scene.
+type segment struct {
+	x1, y1, x2, y2 float64
+}
+
+// text is a text label in the scene centered at x, y.
+type text struct {
+	x, y  float64
+	value string
+	scale float64
+	// boxed labels are drawn on the background box
+	boxed bool
+}
+
+// scene is the laid out diagram in the diagram units, shared by the renderers.
+type scene struct {
+	width, height float64
+	rects         []rect
+	segments      []segment
+	texts         []text
+}
+
+// textWidth returns the width of the text rendered with the scale.
+func textWidth(s string, scale float64) float64 {
+	if s == "" {
+		return 0
+	}
+
+	return float64(utf8.RuneCountInString(s)*(glyphWidth+1)-1) * scale
+}
+
+// nodeWidth returns the width of the node box.
+func nodeWidth(n *Node) float64 {
+	return math.Max(nodeMinW, textWidth(n.Name, nodeFontScale)+2*nodePadX)
+}
+
+// groupColor returns the fill colour of the nodes of the group.
+func groupColor(group string) color.RGBA {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(group))
+
+	return nodeColors[h.Sum32()%uint32(len(nodeColors))]
+}
+
+// scene builds the scene of the laid out diagram.
+// The coordinates are shifted so that the diagram fits the canvas with the margins.
+func (d *Diagram) scene() *scene {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+
+	for _, n := range d.Nodes {
+		w := nodeWidth(n)
+		minX = math.Min(minX, n.X-w/2)
+		maxX = math.Max(maxX, n.X+w/2)
+		minY = math.Min(minY, n.Y-nodeHeight/2)
+		maxY = math.Max(maxY, n.Y+nodeHeight/2)
+	}
+
+	if len(d.Nodes) == 0 {
+		minX, minY, maxX, maxY = 0, 0, 0, 0
+	}
+
+	dx := margin - minX
+	dy := margin + titleHeight - minY
+
+	s := &scene{
+		width:  math.Max(maxX-minX+2*margin, textWidth(d.Title, titleFontScale)+2*margin),
+		height: maxY - minY + 2*margin + titleHeight,
+	}
+
+	s.texts = append(s.texts, text{
+		x: s.width / 2, y: margin, value: d.Title, scale: titleFontScale,
+	})
+
+	// the edges connecting the same nodes are drawn in parallel
+	pairs := map[[2]string]int{}
+	for _, e := range d.Edges {
+		pairs[edgePair(e)]++
+	}
+
+	seen := map[[2]string]int{}
+
+	var labels []text
+
+	for _, e := range d.Edges {
+		a, b := d.node(e.Source), d.node(e.Target)
+		if a == nil || b == nil {
+			continue
+		}
+
+		p := edgePair(e)
+		idx := seen[p]
+		offset := (float64(idx) - float64(pairs[p]-1)/2) * parallelEdgeGap
+		seen[p]++
+
+		// the labels of the parallel edges are staggered along the edges
+		stagger := float64(idx) * (labelHeight() + 2)
+
+		x1, y1, x2, y2 := a.X+dx, a.Y+dy, b.X+dx, b.Y+dy
+
+		length := math.Hypot(x2-x1, y2-y1)
+		if length == 0 {
+			continue
+		}
+
+		// the normal of the edge keeping the same direction for both orders of the pair
+		nx, ny := -(y2-y1)/length, (x2-x1)/length
+		if e.Source > e.Target {
+			nx, ny = -nx, -ny
+		}
+
+		x1, y1, x2, y2 = x1+nx*offset, y1+ny*offset, x2+nx*offset, y2+ny*offset
+
+		s.segments = append(s.segments, segment{x1, y1, x2, y2})
+
+		if e.SourceLabel != "" {
+			t := labelPosition(boxExit(nodeWidth(a), nodeHeight, x2-x1, y2-y1)+stagger,
+				boxExit(labelWidth(e.SourceLabel), labelHeight(), x2-x1, y2-y1), length)
+			labels = append(labels, text{
+				x: x1 + (x2-x1)*t, y: y1 + (y2-y1)*t,
+				value: e.SourceLabel, scale: labelFontScale, boxed: true,
+			})
+		}
+
+		if e.TargetLabel != "" {
+			t := labelPosition(boxExit(nodeWidth(b), nodeHeight, x1-x2, y1-y2)+stagger,
+				boxExit(labelWidth(e.TargetLabel), labelHeight(), x1-x2, y1-y2), length)
+			labels = append(labels, text{
+				x: x2 + (x1-x2)*t, y: y2 + (y1-y2)*t,
+				value: e.TargetLabel, scale: labelFontScale, boxed: true,
+			})
+		}
+	}
+
+	for _, n := range d.Nodes {
+		w := nodeWidth(n)
+		s.rects = append(s.rects, rect{
+			x: n.X + dx - w/2, y: n.Y + dy - nodeHeight/2,
+			w: w, h: nodeHeight,
+			label: n.Name,
+			fill:  groupColor(n.Group),
+		})
+	}
+
+	// the labels are drawn over the nodes and edges
+	s.texts = append(s.texts, labels...)
+
+	return s
+}
+
+// edgePair returns the ordered pair of the edge nodes.
+func edgePair(e *Edge) [2]string {
+	if e.Source < e.Target {
+		return [2]string{e.Source, e.Target}
+	}
+
+	return [2]string{e.Target, e.Source}
+}
+
+// boxExit returns the distance from the center of the box of size w x h
+// to its border in the direction dx, dy.
+func boxExit(w, h, dx, dy float64) float64 {
+	tx, ty := math.Inf(1), math.Inf(1)
+	if dx != 0 {
+		tx = w / 2 / math.Abs(dx)
+	}
+
+	if dy != 0 {
+		ty = h / 2 / math.Abs(dy)
+	}
+
+	return math.Min(tx, ty) * math.Hypot(dx, dy)
+}
+
+// labelPosition returns the fraction of the edge length the label is placed at, given the distance
+// from the node center to the node border and from the label center to the label border.
+func labelPosition(nodeExit, labelExit, length float64) float64 {
+	return math.Min((nodeExit+labelOffset+labelExit)/length, 0.45)
+}
+
+// labelPad is the padding of the edge labels background box.
+const labelPad = 3.0
+
+// labelWidth returns the width of the edge label background box.
+func labelWidth(s string) float64 {
+	return textWidth(s, labelFontScale) + 2*labelPad
+}
+
+// labelHeight returns the height of the edge label background box.
+func labelHeight() float64 {
+	return glyphHeight*labelFontScale + 2*labelPad
+}
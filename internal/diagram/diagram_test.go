@@ -0,0 +1,110 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package diagram
+
+import (
+	"bytes"
+	"image/png"
+	"math"
+	"strings"
+	"testing"
+)
+
+func testDiagram() *Diagram {
+	d := New("test")
+	d.AddNode(&Node{Name: "spine1", Group: "spine"})
+	d.AddNode(&Node{Name: "leaf1", Group: "leaf"})
+	d.AddNode(&Node{Name: "leaf2", Group: "leaf"})
+	d.AddNode(&Node{Name: "client1", Group: "server"})
+	d.AddEdge(&Edge{Source: "spine1", Target: "leaf1", SourceLabel: "e1-1", TargetLabel: "e1-49"})
+	d.AddEdge(&Edge{Source: "spine1", Target: "leaf2", SourceLabel: "e1-2", TargetLabel: "e1-49"})
+	d.AddEdge(&Edge{Source: "leaf1", Target: "client1", SourceLabel: "e1-1", TargetLabel: "eth1"})
+
+	return d
+}
+
+func TestLayeredLayout(t *testing.T) {
+	d := testDiagram()
+	d.Layout()
+
+	spine, leaf1, leaf2, client := d.node("spine1"), d.node("leaf1"), d.node("leaf2"), d.node("client1")
+
+	if !(spine.Y < leaf1.Y && leaf1.Y == leaf2.Y && leaf2.Y < client.Y) {
+		t.Errorf("nodes are not layered by their groups: spine %v, leafs %v %v, client %v",
+			spine.Y, leaf1.Y, leaf2.Y, client.Y)
+	}
+
+	if math.Abs(leaf2.X-leaf1.X) < nodeWidth(leaf1) {
+		t.Errorf("nodes of the same layer overlap: %v %v", leaf1.X, leaf2.X)
+	}
+}
+
+func TestForceDirectedLayout(t *testing.T) {
+	d := New("test")
+	for _, name := range []string{"a", "b", "c", "d"} {
+		d.AddNode(&Node{Name: name})
+	}
+
+	d.AddNode(&Node{Name: "fixed", X: 500, Y: 500, Fixed: true})
+	d.AddEdge(&Edge{Source: "a", Target: "b"})
+	d.AddEdge(&Edge{Source: "b", Target: "c"})
+	d.AddEdge(&Edge{Source: "c", Target: "d"})
+	d.AddEdge(&Edge{Source: "d", Target: "fixed"})
+
+	d.Layout()
+
+	if f := d.node("fixed"); f.X != 500 || f.Y != 500 {
+		t.Errorf("fixed node moved to %v,%v", f.X, f.Y)
+	}
+
+	for i, a := range d.Nodes {
+		for _, b := range d.Nodes[i+1:] {
+			if math.Hypot(a.X-b.X, a.Y-b.Y) < nodeHeight {
+				t.Errorf("nodes %s and %s overlap", a.Name, b.Name)
+			}
+		}
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	d := testDiagram()
+	d.Layout()
+
+	var b bytes.Buffer
+	if err := d.RenderSVG(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	svg := b.String()
+	for _, want := range []string{"<svg", ">spine1</text>", ">e1-49</text>", ">eth1</text>"} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("svg does not contain %q", want)
+		}
+	}
+
+	if got := strings.Count(svg, "<line "); got != 3 {
+		t.Errorf("expected 3 links, got %d", got)
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	d := testDiagram()
+	d.Layout()
+
+	var b bytes.Buffer
+	if err := d.RenderPNG(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := d.scene()
+	if img.Bounds().Dx() != int(math.Ceil(s.width*pngPixelRatio)) {
+		t.Errorf("unexpected image width %d", img.Bounds().Dx())
+	}
+}
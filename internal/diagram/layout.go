@@ -0,0 +1,271 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package diagram
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// layerGap is the vertical distance between the layers of the layered layout.
+	layerGap = 120.0
+	// nodeGap is the minimal horizontal distance between the node boxes of a layer.
+	nodeGap = 40.0
+	// forceDistance is the optimal distance between the nodes of the force-directed layout.
+	forceDistance = 160.0
+	forceIters    = 300
+	// minNodeDistance is the minimal distance between the node centers the force-directed layout is spread to.
+	minNodeDistance = 200.0
+)
+
+// groupOrder is the order of the well-known groups in the layered layout from the top layer down.
+// It matches the sort order of the HTML topology view.
+var groupOrder = []string{
+	"10", "9", "superspine", "8", "dc-gw", "7", "6", "spine", "5", "4",
+	"leaf", "border-leaf", "3", "server", "2", "1",
+}
+
+// Layout computes the coordinates of the nodes that are not fixed.
+// The nodes are arranged in layers by their groups when any of the nodes has a group set,
+// otherwise the force-directed layout is used.
+func (d *Diagram) Layout() {
+	for _, n := range d.Nodes {
+		if n.Group != "" {
+			d.layered()
+			return
+		}
+	}
+
+	d.forceDirected()
+}
+
+// layered places the nodes in horizontal layers by their groups.
+// The well-known groups are ordered as in groupOrder, followed by the other groups
+// in the alphabetical order and the nodes without a group at the bottom.
+// The nodes of a layer are ordered by the mean position of their neighbours in the layers above.
+func (d *Diagram) layered() {
+	layers := map[string][]*Node{}
+	for _, n := range d.Nodes {
+		if !n.Fixed {
+			layers[n.Group] = append(layers[n.Group], n)
+		}
+	}
+
+	groups := make([]string, 0, len(layers))
+	for g := range layers {
+		groups = append(groups, g)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groupRank(groups[i]) < groupRank(groups[j]) ||
+			groupRank(groups[i]) == groupRank(groups[j]) && groups[i] < groups[j]
+	})
+
+	neighbours := d.neighbours()
+	placed := map[string]bool{}
+
+	for _, n := range d.Nodes {
+		if n.Fixed {
+			placed[n.Name] = true
+		}
+	}
+
+	for li, g := range groups {
+		nodes := layers[g]
+
+		bary := map[string]float64{}
+		for _, n := range nodes {
+			bary[n.Name] = d.barycenter(n, neighbours, placed)
+		}
+
+		sort.SliceStable(nodes, func(i, j int) bool {
+			bi, bj := bary[nodes[i].Name], bary[nodes[j].Name]
+			if bi != bj {
+				return bi < bj
+			}
+			return nodes[i].Name < nodes[j].Name
+		})
+
+		width := -nodeGap
+		for _, n := range nodes {
+			width += nodeWidth(n) + nodeGap
+		}
+
+		x := -width / 2
+		for _, n := range nodes {
+			w := nodeWidth(n)
+			n.X = x + w/2
+			n.Y = float64(li) * layerGap
+			x += w + nodeGap
+		}
+
+		for _, n := range nodes {
+			placed[n.Name] = true
+		}
+	}
+}
+
+// groupRank returns the rank of the group in the layered layout.
+func groupRank(g string) int {
+	for i, o := range groupOrder {
+		if o == g {
+			return i
+		}
+	}
+
+	if g == "" {
+		return len(groupOrder) + 1
+	}
+
+	return len(groupOrder)
+}
+
+// neighbours returns the names of the neighbours of the nodes.
+func (d *Diagram) neighbours() map[string][]string {
+	nb := map[string][]string{}
+	for _, e := range d.Edges {
+		nb[e.Source] = append(nb[e.Source], e.Target)
+		nb[e.Target] = append(nb[e.Target], e.Source)
+	}
+
+	return nb
+}
+
+// barycenter returns the mean horizontal position of the placed neighbours of the node,
+// infinity if none of the neighbours is placed yet.
+func (d *Diagram) barycenter(n *Node, neighbours map[string][]string, placed map[string]bool) float64 {
+	var sum float64
+
+	var count int
+
+	for _, name := range neighbours[n.Name] {
+		if !placed[name] {
+			continue
+		}
+
+		if m := d.node(name); m != nil {
+			sum += m.X
+			count++
+		}
+	}
+
+	if count == 0 {
+		return math.Inf(1)
+	}
+
+	return sum / float64(count)
+}
+
+// forceDirected places the nodes using the Fruchterman-Reingold algorithm.
+// The nodes start on a circle in the alphabetical order, which makes the layout deterministic.
+func (d *Diagram) forceDirected() {
+	var free []*Node
+
+	for _, n := range d.Nodes {
+		if !n.Fixed {
+			free = append(free, n)
+		}
+	}
+
+	sort.Slice(free, func(i, j int) bool { return free[i].Name < free[j].Name })
+
+	radius := forceDistance * float64(len(free)) / (2 * math.Pi)
+	for i, n := range free {
+		angle := 2 * math.Pi * float64(i) / float64(len(free))
+		n.X = radius * math.Cos(angle)
+		n.Y = radius * math.Sin(angle)
+	}
+
+	temp := forceDistance
+	disp := make(map[*Node][2]float64, len(d.Nodes))
+
+	for iter := 0; iter < forceIters; iter++ {
+		for _, n := range d.Nodes {
+			disp[n] = [2]float64{}
+		}
+
+		// repulsion between all nodes
+		for i, a := range d.Nodes {
+			for _, b := range d.Nodes[i+1:] {
+				dx, dy := a.X-b.X, a.Y-b.Y
+
+				dist := math.Max(math.Hypot(dx, dy), 1)
+				f := forceDistance * forceDistance / dist
+
+				da, db := disp[a], disp[b]
+				da[0] += dx / dist * f
+				da[1] += dy / dist * f
+				db[0] -= dx / dist * f
+				db[1] -= dy / dist * f
+				disp[a], disp[b] = da, db
+			}
+		}
+
+		// attraction along the edges
+		for _, e := range d.Edges {
+			a, b := d.node(e.Source), d.node(e.Target)
+			if a == nil || b == nil || a == b {
+				continue
+			}
+
+			dx, dy := a.X-b.X, a.Y-b.Y
+
+			dist := math.Max(math.Hypot(dx, dy), 1)
+			f := dist * dist / forceDistance
+
+			da, db := disp[a], disp[b]
+			da[0] -= dx / dist * f
+			da[1] -= dy / dist * f
+			db[0] += dx / dist * f
+			db[1] += dy / dist * f
+			disp[a], disp[b] = da, db
+		}
+
+		for _, n := range free {
+			dx, dy := disp[n][0], disp[n][1]
+
+			l := math.Hypot(dx, dy)
+			if l == 0 {
+				continue
+			}
+
+			step := math.Min(l, temp)
+			n.X += dx / l * step
+			n.Y += dy / l * step
+		}
+
+		temp = math.Max(temp*0.98, 1)
+	}
+
+	spread(free)
+}
+
+// spread scales the positions of the nodes around their center
+// so that the closest nodes are at least minNodeDistance apart.
+func spread(nodes []*Node) {
+	closest := math.Inf(1)
+
+	var cx, cy float64
+
+	for i, a := range nodes {
+		cx += a.X / float64(len(nodes))
+		cy += a.Y / float64(len(nodes))
+
+		for _, b := range nodes[i+1:] {
+			closest = math.Min(closest, math.Hypot(a.X-b.X, a.Y-b.Y))
+		}
+	}
+
+	if closest >= minNodeDistance || closest == 0 || math.IsInf(closest, 1) {
+		return
+	}
+
+	f := minNodeDistance / closest
+	for _, n := range nodes {
+		n.X = cx + (n.X-cx)*f
+		n.Y = cy + (n.Y-cy)*f
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package diagram
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+)
+
+// pngPixelRatio is the number of the PNG pixels per the diagram unit.
+const pngPixelRatio = 2
+
+// RenderPNG writes the laid out diagram in PNG format to w.
+func (d *Diagram) RenderPNG(w io.Writer) error {
+	s := d.scene()
+
+	img := image.NewRGBA(image.Rect(0, 0,
+		int(math.Ceil(s.width*pngPixelRatio)), int(math.Ceil(s.height*pngPixelRatio))))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+
+	for _, l := range s.segments {
+		drawLine(img, l.x1*pngPixelRatio, l.y1*pngPixelRatio, l.x2*pngPixelRatio, l.y2*pngPixelRatio,
+			2*pngPixelRatio, colorEdge)
+	}
+
+	for _, r := range s.rects {
+		fillRect(img, r.x*pngPixelRatio, r.y*pngPixelRatio, r.w*pngPixelRatio, r.h*pngPixelRatio, colorNodeBorder)
+		fillRect(img, r.x*pngPixelRatio+2, r.y*pngPixelRatio+2, r.w*pngPixelRatio-4, r.h*pngPixelRatio-4, r.fill)
+		drawText(img, (r.x+r.w/2)*pngPixelRatio, (r.y+r.h/2)*pngPixelRatio, r.label, nodeFontScale*pngPixelRatio)
+	}
+
+	for _, t := range s.texts {
+		if t.boxed {
+			w, h := labelWidth(t.value), labelHeight()
+			fillRect(img, (t.x-w/2)*pngPixelRatio, (t.y-h/2)*pngPixelRatio, w*pngPixelRatio, h*pngPixelRatio,
+				colorLabelBg)
+		}
+
+		drawText(img, t.x*pngPixelRatio, t.y*pngPixelRatio, t.value, t.scale*pngPixelRatio)
+	}
+
+	return png.Encode(w, img)
+}
+
+// fillRect fills the rectangle with the colour.
+func fillRect(img *image.RGBA, x, y, w, h float64, c color.RGBA) {
+	r := image.Rect(int(math.Round(x)), int(math.Round(y)), int(math.Round(x+w)), int(math.Round(y+h)))
+	draw.Draw(img, r, &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+// drawLine draws the line of the width by stamping the squares along it.
+func drawLine(img *image.RGBA, x1, y1, x2, y2, width float64, c color.RGBA) {
+	steps := int(math.Max(math.Abs(x2-x1), math.Abs(y2-y1)))
+	for i := 0; i <= steps; i++ {
+		t := 0.0
+		if steps > 0 {
+			t = float64(i) / float64(steps)
+		}
+
+		x := x1 + (x2-x1)*t
+		y := y1 + (y2-y1)*t
+		fillRect(img, x-width/2, y-width/2, width, width, c)
+	}
+}
+
+// drawText draws the text centered at x, y with the bitmap font scaled to the integer pixel size.
+func drawText(img *image.RGBA, x, y float64, value string, scale float64) {
+	if value == "" {
+		return
+	}
+
+	px := math.Max(math.Round(scale), 1)
+
+	left := x - textWidth(value, px)/2
+	top := y - glyphHeight*px/2
+
+	i := 0
+	for _, r := range value {
+		g := glyph(r)
+		gx := left + float64(i*(glyphWidth+1))*px
+		i++
+
+		for col := 0; col < glyphWidth; col++ {
+			for row := 0; row < glyphHeight; row++ {
+				if g[col]>>row&1 == 1 {
+					fillRect(img, gx+float64(col)*px, top+float64(row)*px, px, px, colorText)
+				}
+			}
+		}
+	}
+}
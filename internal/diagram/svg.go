@@ -0,0 +1,70 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package diagram
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+)
+
+// svgCharWidth is the width of the monospace font characters relative to the font size.
+const svgCharWidth = 0.6
+
+// RenderSVG writes the laid out diagram in SVG format to w.
+func (d *Diagram) RenderSVG(w io.Writer) error {
+	s := d.scene()
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f">`+"\n",
+		s.width, s.height, s.width, s.height)
+	fmt.Fprintf(bw, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", svgColor(colorBackground))
+	fmt.Fprintf(bw, `<g font-family="monospace" fill="%s">`+"\n", svgColor(colorText))
+
+	for _, l := range s.segments {
+		fmt.Fprintf(bw, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="2"/>`+"\n",
+			l.x1, l.y1, l.x2, l.y2, svgColor(colorEdge))
+	}
+
+	for _, r := range s.rects {
+		fmt.Fprintf(bw, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="6" fill="%s" stroke="%s" stroke-width="1.5"/>`+"\n",
+			r.x, r.y, r.w, r.h, svgColor(r.fill), svgColor(colorNodeBorder))
+		svgText(bw, r.x+r.w/2, r.y+r.h/2, r.label, nodeFontScale)
+	}
+
+	for _, t := range s.texts {
+		if t.boxed {
+			w, h := labelWidth(t.value), labelHeight()
+			fmt.Fprintf(bw, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="2" fill="%s"/>`+"\n",
+				t.x-w/2, t.y-h/2, w, h, svgColor(colorLabelBg))
+		}
+
+		svgText(bw, t.x, t.y, t.value, t.scale)
+	}
+
+	fmt.Fprintln(bw, "</g>")
+	fmt.Fprintln(bw, "</svg>")
+
+	return bw.Flush()
+}
+
+// svgText writes the text centered at x, y with the font size matching the bitmap font scale.
+func svgText(w io.Writer, x, y float64, value string, scale float64) {
+	if value == "" {
+		return
+	}
+
+	size := float64(glyphWidth+1) * scale / svgCharWidth
+
+	fmt.Fprintf(w, `<text x="%.1f" y="%.1f" font-size="%.1f" text-anchor="middle" dominant-baseline="central">%s</text>`+"\n",
+		x, y, size, html.EscapeString(value))
+}
+
+// svgColor returns the colour in the hex notation.
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,130 @@
+package graphml
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// A very minimalistic GraphML generator
+// that covers the usecase of `containerlab graph`
+// command. The node and edge attributes are declared as GraphML keys
+// that tools like yEd and Gephi can map to the labels and styles.
+
+const namespace = "http://graphml.graphdrawing.org/xmlns"
+
+// the keys of the node and edge attributes
+var keys = []Key{
+	{ID: "label", For: "node", Name: "label", Type: "string"},
+	{ID: "kind", For: "node", Name: "kind", Type: "string"},
+	{ID: "image", For: "node", Name: "image", Type: "string"},
+	{ID: "group", For: "node", Name: "group", Type: "string"},
+	{ID: "source_endpoint", For: "edge", Name: "source_endpoint", Type: "string"},
+	{ID: "target_endpoint", For: "edge", Name: "target_endpoint", Type: "string"},
+	{ID: "labels", For: "edge", Name: "labels", Type: "string"},
+	{ID: "vars", For: "edge", Name: "vars", Type: "string"},
+}
+
+type GraphML struct {
+	XMLName xml.Name `xml:"graphml"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	Keys    []Key    `xml:"key"`
+	Graph   Graph    `xml:"graph"`
+}
+
+type Key struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type Graph struct {
+	ID          string `xml:"id,attr"`
+	EdgeDefault string `xml:"edgedefault,attr"`
+	Nodes       []Node `xml:"node"`
+	Edges       []Edge `xml:"edge"`
+}
+
+type Node struct {
+	ID   string `xml:"id,attr"`
+	Data []Data `xml:"data"`
+}
+
+type Edge struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+	Data   []Data `xml:"data"`
+}
+
+type Data struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func NewGraphML(name string) *GraphML {
+	return &GraphML{
+		XMLNS: namespace,
+		Keys:  keys,
+		Graph: Graph{ID: name, EdgeDefault: "undirected"},
+	}
+}
+
+// data returns the data elements of the non-empty values in the order of the keys.
+func data(values map[string]string) []Data {
+	var d []Data
+
+	for _, k := range keys {
+		if v := values[k.ID]; v != "" {
+			d = append(d, Data{Key: k.ID, Value: v})
+		}
+	}
+
+	return d
+}
+
+// AddNode adds the node with its attributes.
+func (g *GraphML) AddNode(name, kind, image, group string) {
+	g.Graph.Nodes = append(g.Graph.Nodes, Node{
+		ID: name,
+		Data: data(map[string]string{
+			"label": name,
+			"kind":  kind,
+			"image": image,
+			"group": group,
+		}),
+	})
+}
+
+// AddEdge adds the edge between the endpoints,
+// the labels and variables of the link are passed in their serialized form.
+func (g *GraphML) AddEdge(source, sourceEndpoint, target, targetEndpoint, labels, vars string) {
+	g.Graph.Edges = append(g.Graph.Edges, Edge{
+		ID:     source + ":" + sourceEndpoint + "--" + target + ":" + targetEndpoint,
+		Source: source,
+		Target: target,
+		Data: data(map[string]string{
+			"source_endpoint": sourceEndpoint,
+			"target_endpoint": targetEndpoint,
+			"labels":          labels,
+			"vars":            vars,
+		}),
+	})
+}
+
+func (g *GraphML) Generate(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(g); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
@@ -3,6 +3,7 @@ package mermaid
 import (
 	"fmt"
 	"io"
+	"sort"
 
 	"golang.org/x/exp/slices"
 )
@@ -14,17 +15,23 @@ import (
 type FlowChart struct {
 	title     string
 	direction string
-	edges     []Edge
+	// groups maps the group names to the names of their nodes
+	groups map[string][]string
+	edges  []Edge
 }
 
 type Edge struct {
 	nodeA string
 	nodeB string
+	// labels of the edge ends
+	labelA string
+	labelB string
 }
 
 func NewFlowChart() *FlowChart {
 	return &FlowChart{
-		edges: []Edge{},
+		groups: map[string][]string{},
+		edges:  []Edge{},
 	}
 }
 
@@ -41,8 +48,19 @@ func (fc *FlowChart) SetDirection(direction string) error {
 	return nil
 }
 
-func (fc *FlowChart) AddEdge(nodeA string, nodeB string) {
-	fc.edges = append(fc.edges, Edge{nodeA: nodeA, nodeB: nodeB})
+// AddNode adds the node to the subgraph of its group.
+// The nodes without a group don't need to be added explicitly.
+func (fc *FlowChart) AddNode(node, group string) {
+	if group == "" {
+		return
+	}
+
+	fc.groups[group] = append(fc.groups[group], node)
+}
+
+// AddEdge adds the edge between the nodes labeled with the labels of its ends.
+func (fc *FlowChart) AddEdge(nodeA, labelA, nodeB, labelB string) {
+	fc.edges = append(fc.edges, Edge{nodeA: nodeA, nodeB: nodeB, labelA: labelA, labelB: labelB})
 }
 
 func (fc *FlowChart) Generate(w io.Writer) {
@@ -50,7 +68,31 @@ func (fc *FlowChart) Generate(w io.Writer) {
 	fmt.Fprintf(w, "title: %s\n", fc.title)
 	fmt.Fprintf(w, "---\n")
 	fmt.Fprintf(w, "graph %s\n", fc.direction)
+
+	groups := make([]string, 0, len(fc.groups))
+	for g := range fc.groups {
+		groups = append(groups, g)
+	}
+
+	sort.Strings(groups)
+
+	// the subgraphs are identified by their index to not clash with the node names
+	for i, g := range groups {
+		fmt.Fprintf(w, "  subgraph group%d [\"%s\"]\n", i+1, g)
+
+		for _, node := range fc.groups[g] {
+			fmt.Fprintf(w, "    %s\n", node)
+		}
+
+		fmt.Fprintf(w, "  end\n")
+	}
+
 	for _, edge := range fc.edges {
-		fmt.Fprintf(w, "  %s---%s\n", edge.nodeA, edge.nodeB)
+		if edge.labelA == "" && edge.labelB == "" {
+			fmt.Fprintf(w, "  %s---%s\n", edge.nodeA, edge.nodeB)
+			continue
+		}
+
+		fmt.Fprintf(w, "  %s---|\"%s - %s\"|%s\n", edge.nodeA, edge.labelA, edge.labelB, edge.nodeB)
 	}
 }
@@ -0,0 +1,149 @@
+package plantuml
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// A very minimalistic PlantUML nwdiag generator
+// that covers the usecase of `containerlab graph`
+// command.
+// The point-to-point links are drawn as the networks connecting two nodes
+// with the interface names shown as the node addresses.
+
+type NwDiag struct {
+	title    string
+	nodes    []Node
+	networks []Network
+	// ids maps the node names to their nwdiag identifiers
+	ids map[string]string
+}
+
+type Node struct {
+	name  string
+	kind  string
+	group string
+}
+
+type Network struct {
+	members []Member
+	// label is shown as the address of the network
+	label string
+}
+
+type Member struct {
+	node    string
+	address string
+}
+
+var nonIDChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+func NewNwDiag() *NwDiag {
+	return &NwDiag{
+		ids: map[string]string{},
+	}
+}
+
+func (d *NwDiag) SetTitle(title string) {
+	d.title = title
+}
+
+// AddNode adds the node described with its name and kind to its group.
+func (d *NwDiag) AddNode(name, kind, group string) {
+	d.nodes = append(d.nodes, Node{name: name, kind: kind, group: group})
+	d.id(name)
+}
+
+// AddLink adds the network connecting the nodes, the interface names are shown as the node addresses.
+func (d *NwDiag) AddLink(nodeA, ifaceA, nodeB, ifaceB, label string) {
+	d.networks = append(d.networks, Network{
+		members: []Member{{node: nodeA, address: ifaceA}, {node: nodeB, address: ifaceB}},
+		label:   label,
+	})
+}
+
+// id returns the nwdiag identifier of the node name,
+// the characters not allowed in the identifiers are replaced and the clashes are resolved with a suffix.
+func (d *NwDiag) id(name string) string {
+	if id, ok := d.ids[name]; ok {
+		return id
+	}
+
+	base := nonIDChars.ReplaceAllString(name, "_")
+	id := base
+
+	for i := 2; d.idTaken(id); i++ {
+		id = fmt.Sprintf("%s_%d", base, i)
+	}
+
+	d.ids[name] = id
+
+	return id
+}
+
+func (d *NwDiag) idTaken(id string) bool {
+	for _, v := range d.ids {
+		if v == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+func (d *NwDiag) Generate(w io.Writer) {
+	fmt.Fprintf(w, "@startuml\n")
+	fmt.Fprintf(w, "title %s\n", d.title)
+	fmt.Fprintf(w, "nwdiag {\n")
+
+	groups := map[string][]string{}
+
+	for _, n := range d.nodes {
+		fmt.Fprintf(w, "  %s [description = %s];\n", d.id(n.name), quote(n.name+`\n`+n.kind))
+
+		if n.group != "" {
+			groups[n.group] = append(groups[n.group], n.name)
+		}
+	}
+
+	for i, nw := range d.networks {
+		fmt.Fprintf(w, "  network link%d {\n", i+1)
+
+		if nw.label != "" {
+			fmt.Fprintf(w, "    address = %s\n", quote(nw.label))
+		}
+
+		for _, m := range nw.members {
+			fmt.Fprintf(w, "    %s [address = %s];\n", d.id(m.node), quote(m.address))
+		}
+
+		fmt.Fprintf(w, "  }\n")
+	}
+
+	names := make([]string, 0, len(groups))
+	for g := range groups {
+		names = append(names, g)
+	}
+
+	sort.Strings(names)
+
+	for _, g := range names {
+		fmt.Fprintf(w, "  group {\n    description = %s;\n", quote(g))
+
+		for _, n := range groups[g] {
+			fmt.Fprintf(w, "    %s;\n", d.id(n))
+		}
+
+		fmt.Fprintf(w, "  }\n")
+	}
+
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "@enduml\n")
+}
@@ -11,4 +11,6 @@ const (
 	TopoFile      = "clab-topo-file"
 	NodeMgmtNetBr = "clab-mgmt-net-bridge"
 	Owner         = "clab-owner"
+	Instance      = "clab-instance"
+	MgmtPorts     = "clab-mgmt-ports"
 )
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/netip"
 
 	"github.com/containernetworking/plugins/pkg/ns"
 	log "github.com/sirupsen/logrus"
@@ -24,6 +25,10 @@ type Endpoint interface {
 	GetIfaceDisplayName() string
 	GetRandIfaceName() string
 	GetMac() net.HardwareAddr
+	// GetIPv4Addr and GetIPv6Addr return the addresses configured on the endpoint interface.
+	// The returned prefix is invalid (zero value) when no address is defined.
+	GetIPv4Addr() netip.Prefix
+	GetIPv6Addr() netip.Prefix
 	String() string
 	// GetLink retrieves the link that the endpoint is assigned to
 	GetLink() Link
@@ -53,8 +58,11 @@ type EndpointGeneric struct {
 	IfaceName  string
 	IfaceAlias string
 	// Link is the link this endpoint belongs to.
-	Link     Link
-	MAC      net.HardwareAddr
+	Link Link
+	MAC  net.HardwareAddr
+	// IPv4/IPv6 addresses configured on the interface
+	IPv4     netip.Prefix
+	IPv6     netip.Prefix
 	randName string
 }
 
@@ -101,6 +109,14 @@ func (e *EndpointGeneric) GetMac() net.HardwareAddr {
 	return e.MAC
 }
 
+func (e *EndpointGeneric) GetIPv4Addr() netip.Prefix {
+	return e.IPv4
+}
+
+func (e *EndpointGeneric) GetIPv6Addr() netip.Prefix {
+	return e.IPv6
+}
+
 func (e *EndpointGeneric) GetLink() Link {
 	return e.Link
 }
@@ -3,6 +3,8 @@ package links
 import (
 	"fmt"
 	"net"
+	"net/netip"
+	"strings"
 
 	"github.com/srl-labs/containerlab/utils"
 )
@@ -13,6 +15,10 @@ type EndpointRaw struct {
 	Node  string `yaml:"node"`
 	Iface string `yaml:"interface"`
 	MAC   string `yaml:"mac,omitempty"`
+	// IPv4 and IPv6 addresses in the prefix notation (e.g. 10.0.0.1/31)
+	// configured on the interface by containerlab.
+	IPv4 string `yaml:"ipv4,omitempty"`
+	IPv6 string `yaml:"ipv6,omitempty"`
 }
 
 // NewEndpointRaw creates a new EndpointRaw struct.
@@ -24,6 +30,77 @@ func NewEndpointRaw(node, nodeIf, Mac string) *EndpointRaw {
 	}
 }
 
+// newEndpointRawFromBrief creates a new EndpointRaw struct from the node name and the
+// interface definition used in the brief link notation.
+// The interface definition may carry comma separated addresses after the @ sign,
+// e.g. eth1@10.0.0.1/31 or eth1@10.0.0.1/31,2001:db8::1/127.
+func newEndpointRawFromBrief(node, ifaceDef string) (*EndpointRaw, error) {
+	iface, addrs, found := strings.Cut(ifaceDef, "@")
+
+	e := NewEndpointRaw(node, iface, "")
+	if !found {
+		return e, nil
+	}
+
+	for _, a := range strings.Split(addrs, ",") {
+		p, err := netip.ParsePrefix(strings.TrimSpace(a))
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q for endpoint %s:%s: %w", a, node, iface, err)
+		}
+
+		switch {
+		case p.Addr().Is4() && e.IPv4 == "":
+			e.IPv4 = p.String()
+		case p.Addr().Is6() && e.IPv6 == "":
+			e.IPv6 = p.String()
+		default:
+			return nil, fmt.Errorf("endpoint %s:%s has more than one address of the same family", node, iface)
+		}
+	}
+
+	return e, nil
+}
+
+// briefString returns the representation of the endpoint in the brief link notation.
+func (er *EndpointRaw) briefString() string {
+	s := fmt.Sprintf("%s:%s", er.Node, er.Iface)
+
+	var addrs []string
+	for _, a := range []string{er.IPv4, er.IPv6} {
+		if a != "" {
+			addrs = append(addrs, a)
+		}
+	}
+
+	if len(addrs) > 0 {
+		s += "@" + strings.Join(addrs, ",")
+	}
+
+	return s
+}
+
+// parseAddresses parses the IPv4/IPv6 addresses of the raw endpoint
+// and sets them on the generic endpoint.
+func (er *EndpointRaw) parseAddresses(e *EndpointGeneric) error {
+	if er.IPv4 != "" {
+		p, err := netip.ParsePrefix(er.IPv4)
+		if err != nil || !p.Addr().Is4() {
+			return fmt.Errorf("invalid ipv4 address %q for endpoint %s:%s", er.IPv4, er.Node, er.Iface)
+		}
+		e.IPv4 = p
+	}
+
+	if er.IPv6 != "" {
+		p, err := netip.ParsePrefix(er.IPv6)
+		if err != nil || !p.Addr().Is6() {
+			return fmt.Errorf("invalid ipv6 address %q for endpoint %s:%s", er.IPv6, er.Node, er.Iface)
+		}
+		e.IPv6 = p
+	}
+
+	return nil
+}
+
 // Resolve resolves the EndpointRaw into an Endpoint interface that is implemented
 // by a concrete endpoint struct such as EndpointBridge, EndpointHost, EndpointVeth.
 // The type of an endpoint is determined by the node it belongs to.
@@ -54,6 +131,11 @@ func (er *EndpointRaw) Resolve(params *ResolveParams, l Link) (Endpoint, error)
 		genericEndpoint.MAC = m
 	}
 
+	err = er.parseAddresses(genericEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
 	var e Endpoint
 
 	switch node.GetLinkEndpointType() {
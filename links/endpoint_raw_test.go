@@ -0,0 +1,63 @@
+package links
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_newEndpointRawFromBrief(t *testing.T) {
+	tests := map[string]struct {
+		node     string
+		ifaceDef string
+		want     *EndpointRaw
+		brief    string
+		wantErr  bool
+	}{
+		"no addresses": {
+			node:     "node1",
+			ifaceDef: "eth1",
+			want:     &EndpointRaw{Node: "node1", Iface: "eth1"},
+			brief:    "node1:eth1",
+		},
+		"ipv4 address": {
+			node:     "node1",
+			ifaceDef: "eth1@10.0.0.1/31",
+			want:     &EndpointRaw{Node: "node1", Iface: "eth1", IPv4: "10.0.0.1/31"},
+			brief:    "node1:eth1@10.0.0.1/31",
+		},
+		"ipv4 and ipv6 addresses": {
+			node:     "node1",
+			ifaceDef: "eth1@2001:db8::1/127,10.0.0.1/31",
+			want:     &EndpointRaw{Node: "node1", Iface: "eth1", IPv4: "10.0.0.1/31", IPv6: "2001:db8::1/127"},
+			brief:    "node1:eth1@10.0.0.1/31,2001:db8::1/127",
+		},
+		"two ipv4 addresses": {
+			node:     "node1",
+			ifaceDef: "eth1@10.0.0.1/31,10.0.0.3/31",
+			wantErr:  true,
+		},
+		"address without prefix length": {
+			node:     "node1",
+			ifaceDef: "eth1@10.0.0.1",
+			wantErr:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := newEndpointRawFromBrief(tt.node, tt.ifaceDef)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newEndpointRawFromBrief() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("newEndpointRawFromBrief() mismatch (-want +got):\n%s", d)
+			}
+
+			if got != nil && got.briefString() != tt.brief {
+				t.Errorf("briefString() = %s, want %s", got.briefString(), tt.brief)
+			}
+		})
+	}
+}
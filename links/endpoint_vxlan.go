@@ -41,3 +41,23 @@ func (e *EndpointVxlan) Deploy(ctx context.Context) error {
 func (e *EndpointVxlan) IsNodeless() bool {
 	return false
 }
+
+// GetRemote returns the address of the remote VTEP.
+func (e *EndpointVxlan) GetRemote() net.IP {
+	return e.remote
+}
+
+// GetVNI returns the VxLAN network identifier.
+func (e *EndpointVxlan) GetVNI() int {
+	return e.vni
+}
+
+// GetUDPPort returns the UDP port of the VxLAN tunnel.
+func (e *EndpointVxlan) GetUDPPort() int {
+	return e.udpPort
+}
+
+// GetParentIface returns the name of the host interface the VxLAN tunnel is sourced from.
+func (e *EndpointVxlan) GetParentIface() string {
+	return e.parentIface
+}
@@ -40,6 +40,16 @@ func (l *LinkCommonParams) GetMTU() int {
 	return l.MTU
 }
 
+// GetLabels returns the labels of the link.
+func (l *LinkCommonParams) GetLabels() map[string]string {
+	return l.Labels
+}
+
+// GetVars returns the variables of the link.
+func (l *LinkCommonParams) GetVars() map[string]interface{} {
+	return l.Vars
+}
+
 // LinkDefinition represents a link definition in the topology file.
 type LinkDefinition struct {
 	Type string  `yaml:"type,omitempty"`
@@ -332,6 +342,10 @@ type Link interface {
 	GetEndpoints() []Endpoint
 	// GetMTU returns the Link MTU.
 	GetMTU() int
+	// GetLabels returns the Link labels.
+	GetLabels() map[string]string
+	// GetVars returns the Link variables.
+	GetVars() map[string]interface{}
 }
 
 func extractHostNodeInterfaceData(lb *LinkBriefRaw, specialEPIndex int) (host, hostIf, node, nodeIf string, err error) {
@@ -22,7 +22,7 @@ func (r *LinkHostRaw) ToLinkBriefRaw() *LinkBriefRaw {
 		LinkCommonParams: r.LinkCommonParams,
 	}
 
-	lc.Endpoints[0] = fmt.Sprintf("%s:%s", r.Endpoint.Node, r.Endpoint.Iface)
+	lc.Endpoints[0] = r.Endpoint.briefString()
 	lc.Endpoints[1] = fmt.Sprintf("%s:%s", "host", r.HostInterface)
 
 	return lc
@@ -33,10 +33,15 @@ func hostLinkFromBrief(lb *LinkBriefRaw, specialEPIndex int) (*LinkHostRaw, erro
 	if err != nil {
 		return nil, err
 	}
+
+	ep, err := newEndpointRawFromBrief(node, nodeIf)
+	if err != nil {
+		return nil, err
+	}
 	link := &LinkHostRaw{
 		LinkCommonParams: lb.LinkCommonParams,
 		HostInterface:    hostIf,
-		Endpoint:         NewEndpointRaw(node, nodeIf, ""),
+		Endpoint:         ep,
 	}
 
 	// set default link mtu if MTU is unset
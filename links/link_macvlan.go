@@ -27,7 +27,7 @@ func (r *LinkMacVlanRaw) ToLinkBriefRaw() *LinkBriefRaw {
 		},
 	}
 
-	lc.Endpoints[0] = fmt.Sprintf("%s:%s", r.Endpoint.Node, r.Endpoint.Iface)
+	lc.Endpoints[0] = r.Endpoint.briefString()
 	lc.Endpoints[1] = fmt.Sprintf("%s:%s", "macvlan", r.HostInterface)
 
 	return lc
@@ -42,10 +42,15 @@ func macVlanLinkFromBrief(lb *LinkBriefRaw, specialEPIndex int) (*LinkMacVlanRaw
 	if err != nil {
 		return nil, err
 	}
+
+	ep, err := newEndpointRawFromBrief(node, nodeIf)
+	if err != nil {
+		return nil, err
+	}
 	link := &LinkMacVlanRaw{
 		LinkCommonParams: lb.LinkCommonParams,
 		HostInterface:    hostIf,
-		Endpoint:         NewEndpointRaw(node, nodeIf, ""),
+		Endpoint:         ep,
 	}
 
 	// set default link mtu if MTU is unset
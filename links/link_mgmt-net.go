@@ -26,7 +26,7 @@ func (r *LinkMgmtNetRaw) ToLinkBriefRaw() *LinkBriefRaw {
 		},
 	}
 
-	lc.Endpoints[0] = fmt.Sprintf("%s:%s", r.Endpoint.Node, r.Endpoint.Iface)
+	lc.Endpoints[0] = r.Endpoint.briefString()
 	lc.Endpoints[1] = fmt.Sprintf("%s:%s", "mgmt-net", r.HostInterface)
 
 	return lc
@@ -84,10 +84,15 @@ func mgmtNetLinkFromBrief(lb *LinkBriefRaw, specialEPIndex int) (*LinkMgmtNetRaw
 		return nil, err
 	}
 
+	ep, err := newEndpointRawFromBrief(node, nodeIf)
+	if err != nil {
+		return nil, err
+	}
+
 	link := &LinkMgmtNetRaw{
 		LinkCommonParams: lb.LinkCommonParams,
 		HostInterface:    hostIf,
-		Endpoint:         NewEndpointRaw(node, nodeIf, ""),
+		Endpoint:         ep,
 	}
 
 	// set default link mtu if MTU is unset
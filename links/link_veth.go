@@ -25,7 +25,7 @@ func (r *LinkVEthRaw) ToLinkBriefRaw() *LinkBriefRaw {
 	}
 
 	for _, e := range r.Endpoints {
-		lc.Endpoints = append(lc.Endpoints, fmt.Sprintf("%s:%s", e.Node, e.Iface))
+		lc.Endpoints = append(lc.Endpoints, e.briefString())
 	}
 	return lc
 }
@@ -75,12 +75,19 @@ func linkVEthRawFromLinkBriefRaw(lb *LinkBriefRaw) (*LinkVEthRaw, error) {
 		return nil, err
 	}
 
+	epA, err := newEndpointRawFromBrief(host, hostIf)
+	if err != nil {
+		return nil, err
+	}
+
+	epB, err := newEndpointRawFromBrief(node, nodeIf)
+	if err != nil {
+		return nil, err
+	}
+
 	link := &LinkVEthRaw{
 		LinkCommonParams: lb.LinkCommonParams,
-		Endpoints: []*EndpointRaw{
-			NewEndpointRaw(host, hostIf, ""),
-			NewEndpointRaw(node, nodeIf, ""),
-		},
+		Endpoints:        []*EndpointRaw{epA, epB},
 	}
 
 	// set default link mtu if MTU is unset
@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	ns "github.com/containernetworking/plugins/pkg/ns"
 	exec "github.com/srl-labs/containerlab/clab/exec"
 	runtime "github.com/srl-labs/containerlab/runtime"
 	gomock "go.uber.org/mock/gomock"
@@ -71,6 +72,20 @@ func (mr *MockNodeOverwritesMockRecorder) CheckInterfaceName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckInterfaceName", reflect.TypeOf((*MockNodeOverwrites)(nil).CheckInterfaceName))
 }
 
+// ExecFunction mocks base method.
+func (m *MockNodeOverwrites) ExecFunction(arg0 context.Context, arg1 func(ns.NetNS) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecFunction", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecFunction indicates an expected call of ExecFunction.
+func (mr *MockNodeOverwritesMockRecorder) ExecFunction(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecFunction", reflect.TypeOf((*MockNodeOverwrites)(nil).ExecFunction), arg0, arg1)
+}
+
 // GetContainerName mocks base method.
 func (m *MockNodeOverwrites) GetContainerName() string {
 	m.ctrl.T.Helper()
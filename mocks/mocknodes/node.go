@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 
 	ns "github.com/containernetworking/plugins/pkg/ns"
+	cert "github.com/srl-labs/containerlab/cert"
 	exec "github.com/srl-labs/containerlab/clab/exec"
 	links "github.com/srl-labs/containerlab/links"
 	nodes "github.com/srl-labs/containerlab/nodes"
@@ -406,6 +407,20 @@ func (mr *MockNodeMockRecorder) PreDeploy(ctx, params any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreDeploy", reflect.TypeOf((*MockNode)(nil).PreDeploy), ctx, params)
 }
 
+// ReloadCertificates mocks base method.
+func (m *MockNode) ReloadCertificates(ctx context.Context, certInfra *cert.Cert, topoName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReloadCertificates", ctx, certInfra, topoName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReloadCertificates indicates an expected call of ReloadCertificates.
+func (mr *MockNodeMockRecorder) ReloadCertificates(ctx, certInfra, topoName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReloadCertificates", reflect.TypeOf((*MockNode)(nil).ReloadCertificates), ctx, certInfra, topoName)
+}
+
 // RunExec mocks base method.
 func (m *MockNode) RunExec(ctx context.Context, execCmd *exec.ExecCmd) (*exec.ExecResult, error) {
 	m.ctrl.T.Helper()
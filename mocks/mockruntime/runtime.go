@@ -73,6 +73,20 @@ func (mr *MockContainerRuntimeMockRecorder) CreateContainer(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContainer", reflect.TypeOf((*MockContainerRuntime)(nil).CreateContainer), arg0, arg1)
 }
 
+// CreateExtraNets mocks base method.
+func (m *MockContainerRuntime) CreateExtraNets(arg0 context.Context, arg1 []*types.Network) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExtraNets", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateExtraNets indicates an expected call of CreateExtraNets.
+func (mr *MockContainerRuntimeMockRecorder) CreateExtraNets(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExtraNets", reflect.TypeOf((*MockContainerRuntime)(nil).CreateExtraNets), arg0, arg1)
+}
+
 // CreateNet mocks base method.
 func (m *MockContainerRuntime) CreateNet(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -101,6 +115,20 @@ func (mr *MockContainerRuntimeMockRecorder) DeleteContainer(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteContainer", reflect.TypeOf((*MockContainerRuntime)(nil).DeleteContainer), arg0, arg1)
 }
 
+// DeleteExtraNets mocks base method.
+func (m *MockContainerRuntime) DeleteExtraNets(arg0 context.Context, arg1 []*types.Network) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExtraNets", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExtraNets indicates an expected call of DeleteExtraNets.
+func (mr *MockContainerRuntimeMockRecorder) DeleteExtraNets(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExtraNets", reflect.TypeOf((*MockContainerRuntime)(nil).DeleteExtraNets), arg0, arg1)
+}
+
 // DeleteNet mocks base method.
 func (m *MockContainerRuntime) DeleteNet(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -250,6 +278,21 @@ func (mr *MockContainerRuntimeMockRecorder) ListContainers(arg0, arg1 any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContainers", reflect.TypeOf((*MockContainerRuntime)(nil).ListContainers), arg0, arg1)
 }
 
+// ListNets mocks base method.
+func (m *MockContainerRuntime) ListNets(arg0 context.Context) ([]*types.Network, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNets", arg0)
+	ret0, _ := ret[0].([]*types.Network)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNets indicates an expected call of ListNets.
+func (mr *MockContainerRuntimeMockRecorder) ListNets(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNets", reflect.TypeOf((*MockContainerRuntime)(nil).ListNets), arg0)
+}
+
 // Mgmt mocks base method.
 func (m *MockContainerRuntime) Mgmt() *types.MgmtNet {
 	m.ctrl.T.Helper()
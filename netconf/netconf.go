@@ -17,7 +17,8 @@ import (
 // SaveConfig saves the running config to the startup by means
 // of invoking a netconf rpc <copy-config> from running to startup datastore
 // this method is used on the network elements that can't perform configuration save via other means.
-func SaveConfig(addr, username, password, _ string) error {
+// When the sshKey path is set the private key is used for authentication in addition to the password.
+func SaveConfig(addr, username, password, sshKey, _ string) error {
 	opts := []util.Option{
 		options.WithAuthNoStrictKey(),
 		options.WithAuthUsername(username),
@@ -26,6 +27,10 @@ func SaveConfig(addr, username, password, _ string) error {
 		options.WithPort(830),
 	}
 
+	if sshKey != "" {
+		opts = append(opts, options.WithAuthPrivateKey(sshKey, ""))
+	}
+
 	d, err := netconf.NewDriver(
 		addr,
 		opts...,
@@ -25,6 +25,9 @@ var (
 
 	//go:embed c8000.cfg
 	cfgTemplate string
+
+	// ifNameRegexp matches the Hu0_0_0_X and FH0_0_0_X interface names.
+	ifNameRegexp = regexp.MustCompile(`^(Hu|FH)0_0_0_\d+$`)
 )
 
 const (
@@ -33,7 +36,9 @@ const (
 
 // Register registers the node in the NodeRegistry.
 func Register(r *nodes.NodeRegistry) {
-	nrea := nodes.NewNodeRegistryEntryAttributes(defaultCredentials, nil)
+	nrea := nodes.NewNodeRegistryEntryAttributes(defaultCredentials, nil).
+		WithMgmtPorts(map[string]int{nodes.MgmtServiceGNMI: 9339}).
+		WithInterfaceRegexp(ifNameRegexp)
 	r.Register(kindnames, func() nodes.Node {
 		return new(c8000)
 	}, nrea)
@@ -72,9 +77,11 @@ func (n *c8000) PreDeploy(ctx context.Context, params *nodes.PreDeployParams) er
 }
 
 func (n *c8000) SaveConfig(_ context.Context) error {
+	creds := n.Credentials(defaultCredentials)
 	err := netconf.SaveConfig(n.Cfg.LongName,
-		defaultCredentials.GetUsername(),
-		defaultCredentials.GetPassword(),
+		creds.GetUsername(),
+		creds.GetPassword(),
+		creds.GetSSHKey(),
 		scrapliPlatformName,
 	)
 	if err != nil {
@@ -117,10 +124,8 @@ func (n *c8000) create8000Files(_ context.Context) error {
 
 // CheckInterfaceName checks if a name of the interface referenced in the topology file correct.
 func (n *c8000) CheckInterfaceName() error {
-	ifRe := regexp.MustCompile(`^(Hu|FH)0_0_0_\d+$`)
-
 	for _, e := range n.Endpoints {
-		if !ifRe.MatchString(e.GetIfaceName()) {
+		if !ifNameRegexp.MatchString(e.GetIfaceName()) {
 			return fmt.Errorf("cisco 8000 interface name %q doesn't match the required pattern. Cisco 8000 interfaces should be named as Hu0_0_0_X (100G interfaces) or FH0_0_0_X (400G interfaces) where X is the interface number", e.GetIfaceName())
 		}
 	}
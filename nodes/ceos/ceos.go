@@ -50,12 +50,18 @@ var (
 	saveCmd = "Cli -p 15 -c wr"
 
 	defaultCredentials = nodes.NewCredentials("admin", "admin")
+
+	// ifNameRegexp allows eth and et interfaces
+	// https://regex101.com/r/umQW5Z/2
+	ifNameRegexp = regexp.MustCompile(`eth[1-9][\w.]*$|et[1-9][\w.]*$`)
 )
 
 // Register registers the node in the NodeRegistry.
 func Register(r *nodes.NodeRegistry) {
 	generateNodeAttributes := nodes.NewGenerateNodeAttributes(generateable, generateIfFormat)
-	nrea := nodes.NewNodeRegistryEntryAttributes(defaultCredentials, generateNodeAttributes)
+	nrea := nodes.NewNodeRegistryEntryAttributes(defaultCredentials, generateNodeAttributes).
+		WithMgmtPorts(map[string]int{nodes.MgmtServiceGNMI: 6030}).
+		WithInterfaceRegexp(ifNameRegexp)
 
 	r.Register(KindNames, func() nodes.Node {
 		return new(ceos)
@@ -291,11 +297,8 @@ func (n *ceos) ceosPostDeploy(_ context.Context) error {
 
 // CheckInterfaceName checks if a name of the interface referenced in the topology file correct.
 func (n *ceos) CheckInterfaceName() error {
-	// allow eth and et interfaces
-	// https://regex101.com/r/umQW5Z/2
-	ifRe := regexp.MustCompile(`eth[1-9][\w.]*$|et[1-9][\w.]*$`)
 	for _, e := range n.Endpoints {
-		if !ifRe.MatchString(e.GetIfaceName()) {
+		if !ifNameRegexp.MatchString(e.GetIfaceName()) {
 			return fmt.Errorf("arista cEOS node %q has an interface named %q which doesn't match the required pattern. Interfaces should be named as ethX or etX, where X consists of alpanumerical characters", n.Cfg.ShortName, e.GetIfaceName())
 		}
 	}
@@ -66,6 +66,9 @@ func (s *crpd) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		o(s)
 	}
 
+	// cRPD uses the linux kernel for forwarding
+	s.Cfg.KernelDataplane = true
+
 	// mount config and log dirs
 	s.Cfg.Binds = append(s.Cfg.Binds,
 		fmt.Sprint(filepath.Join(s.Cfg.LabDir, "config"), ":/config"),
@@ -18,6 +18,10 @@ var (
 	kindnames                 = []string{"cvx", "cumulus_cvx"}
 	defaultCvxKernelImageRef  = "docker.io/networkop/kernel:4.19"
 	defaultIgniteSandboxImage = "networkop/ignite:dev"
+
+	// ifNameRegexp allows swpX interface names
+	// https://regex101.com/r/SV0k1J/1
+	ifNameRegexp = regexp.MustCompile(`swp[\d\.]+$`)
 )
 
 var memoryReqs = map[string]string{
@@ -27,9 +31,12 @@ var memoryReqs = map[string]string{
 
 // Register registers the node in the NodeRegistry.
 func Register(r *nodes.NodeRegistry) {
+	nrea := nodes.NewNodeRegistryEntryAttributes(nil, nil).
+		WithInterfaceRegexp(ifNameRegexp)
+
 	r.Register(kindnames, func() nodes.Node {
 		return new(cvx)
-	}, nil)
+	}, nrea)
 	nodes.SetNonDefaultRuntimePerKind(kindnames, ignite.RuntimeName)
 }
 
@@ -117,11 +124,8 @@ func (c *cvx) GetImages(_ context.Context) map[string]string {
 
 // CheckInterfaceName checks if a name of the interface referenced in the topology file correct.
 func (c *cvx) CheckInterfaceName() error {
-	// allow swpX interface names
-	// https://regex101.com/r/SV0k1J/1
-	ifRe := regexp.MustCompile(`swp[\d\.]+$`)
 	for _, e := range c.Endpoints {
-		if !ifRe.MatchString(e.GetIfaceName()) {
+		if !ifNameRegexp.MatchString(e.GetIfaceName()) {
 			return fmt.Errorf("%q interface name %q doesn't match the required pattern. It should be named as swpX, where X is >=0", c.Cfg.ShortName, e.GetIfaceName())
 		}
 	}
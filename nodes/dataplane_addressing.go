@@ -0,0 +1,115 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// ConfigureDataplaneAddressing returns a function that is executed in the network namespace of a node
+// to assign the addresses defined for the node's endpoints and to install the node's static routes.
+func ConfigureDataplaneAddressing(cfg *types.NodeConfig, eps []links.Endpoint) func(ns.NetNS) error {
+	return func(_ ns.NetNS) error {
+		for _, ep := range eps {
+			for _, p := range []netip.Prefix{ep.GetIPv4Addr(), ep.GetIPv6Addr()} {
+				if !p.IsValid() {
+					continue
+				}
+
+				err := addIfaceAddress(ep.GetIfaceName(), p)
+				if err != nil {
+					return fmt.Errorf("node %s: %w", cfg.ShortName, err)
+				}
+			}
+		}
+
+		for _, r := range cfg.StaticRoutes {
+			err := addStaticRoute(r)
+			if err != nil {
+				return fmt.Errorf("node %s: %w", cfg.ShortName, err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// addIfaceAddress adds the address p to the interface ifName.
+func addIfaceAddress(ifName string, p netip.Prefix) error {
+	l, err := netlink.LinkByName(ifName)
+	if err != nil {
+		return fmt.Errorf("failed to lookup interface %q: %w", ifName, err)
+	}
+
+	addr, err := netlink.ParseAddr(p.String())
+	if err != nil {
+		return err
+	}
+
+	// for ipv6 addresses the duplicate address detection is skipped
+	// to have the address usable right away
+	if p.Addr().Is6() {
+		addr.Flags = addr.Flags | unix.IFA_F_NODAD
+	}
+
+	log.Debugf("Adding address %s to interface %s", p, ifName)
+
+	err = netlink.AddrReplace(l, addr)
+	if err != nil {
+		return fmt.Errorf("failed to add address %s to interface %q: %w", p, ifName, err)
+	}
+
+	return nil
+}
+
+// addStaticRoute installs the static route r.
+func addStaticRoute(r *types.StaticRoute) error {
+	_, dst, err := net.ParseCIDR(r.Prefix)
+	if err != nil {
+		return fmt.Errorf("invalid static route prefix %q: %w", r.Prefix, err)
+	}
+
+	route := &netlink.Route{
+		Dst: dst,
+	}
+
+	if r.NextHop != "" {
+		route.Gw = net.ParseIP(r.NextHop)
+		if route.Gw == nil {
+			return fmt.Errorf("invalid next-hop %q for static route %s", r.NextHop, r.Prefix)
+		}
+	}
+
+	if r.Interface != "" {
+		l, err := netlink.LinkByName(r.Interface)
+		if err != nil {
+			return fmt.Errorf("failed to lookup interface %q for static route %s: %w", r.Interface, r.Prefix, err)
+		}
+
+		route.LinkIndex = l.Attrs().Index
+	}
+
+	if route.Gw == nil && route.LinkIndex == 0 {
+		return fmt.Errorf("static route %s must have a next-hop or an interface defined", r.Prefix)
+	}
+
+	log.Debugf("Adding static route %s via %s dev %s", r.Prefix, r.NextHop, r.Interface)
+
+	err = netlink.RouteReplace(route)
+	if err != nil {
+		return fmt.Errorf("failed to add static route %s: %w", r.Prefix, err)
+	}
+
+	return nil
+}
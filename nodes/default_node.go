@@ -97,6 +97,12 @@ func (d *DefaultNode) SaveConfig(_ context.Context) error {
 	return nil
 }
 
+// ReloadCertificates is not supported by default, the renewed certificates
+// are used by the node when it is redeployed.
+func (d *DefaultNode) ReloadCertificates(_ context.Context, _ *cert.Cert, _ string) error {
+	return fmt.Errorf("%w for %q node kind", ErrCertReloadNotSupported, d.Cfg.Kind)
+}
+
 // CheckDeploymentConditions wraps individual functions that check if a node
 // satisfies deployment requirements.
 func (d *DefaultNode) CheckDeploymentConditions(ctx context.Context) error {
@@ -416,6 +422,7 @@ type NodeOverwrites interface {
 	GetContainerName() string
 	VerifyLicenseFileExists(context.Context) error
 	RunExec(context.Context, *exec.ExecCmd) (*exec.ExecResult, error)
+	ExecFunction(context.Context, func(ns.NetNS) error) error
 }
 
 // LoadStartupConfigFileVr templates a startup-config using the file specified for VM-based nodes in the topo
@@ -516,29 +523,8 @@ func (d *DefaultNode) LoadOrGenerateCertificate(certInfra *cert.Cert, topoName s
 	if err != nil {
 		log.Debugf("creating node certificate for %s", nodeConfig.ShortName)
 
-		hosts := []string{
-			nodeConfig.ShortName,
-			nodeConfig.LongName,
-			nodeConfig.ShortName + "." + topoName + ".io",
-		}
-		// add the SANs provided via config
-		hosts = append(hosts, nodeConfig.Certificate.SANs...)
-
-		// add mgmt IPs as SANs to CSR
-		for _, ip := range []string{d.Cfg.MgmtIPv4Address, d.Cfg.MgmtIPv6Address} {
-			if ip != "" {
-				hosts = append(hosts, ip)
-			}
-		}
+		certInput := NodeCSRInput(nodeConfig, topoName)
 
-		certInput := &cert.NodeCSRInput{
-			CommonName:   nodeConfig.ShortName + "." + topoName + ".io",
-			Hosts:        hosts,
-			Organization: "containerlab",
-			Country:      "US",
-			KeySize:      d.Cfg.Certificate.KeySize,
-			Expiry:       d.Cfg.Certificate.ValidityDuration,
-		}
 		// Generate the cert for the node
 		nodeCert, err = certInfra.GenerateAndSignNodeCert(certInput)
 		if err != nil {
@@ -555,6 +541,35 @@ func (d *DefaultNode) LoadOrGenerateCertificate(certInfra *cert.Cert, topoName s
 	return nodeCert, nil
 }
 
+// NodeCSRInput returns the CSR input of the node certificate.
+// The management addresses of the node are added to the certificate SANs.
+func NodeCSRInput(cfg *types.NodeConfig, topoName string) *cert.NodeCSRInput {
+	hosts := []string{
+		cfg.ShortName,
+		cfg.LongName,
+		cfg.ShortName + "." + topoName + ".io",
+	}
+	// add the SANs provided via config
+	hosts = append(hosts, cfg.Certificate.SANs...)
+
+	// add mgmt IPs as SANs to CSR
+	for _, ip := range []string{cfg.MgmtIPv4Address, cfg.MgmtIPv6Address} {
+		if ip != "" {
+			hosts = append(hosts, ip)
+		}
+	}
+
+	return &cert.NodeCSRInput{
+		CommonName:   cfg.ShortName + "." + topoName + ".io",
+		Hosts:        hosts,
+		Organization: "containerlab",
+		Country:      "US",
+		KeySize:      cfg.Certificate.KeySize,
+		KeyAlgorithm: cfg.Certificate.KeyAlgorithm,
+		Expiry:       cfg.Certificate.ValidityDuration,
+	}
+}
+
 func (d *DefaultNode) AddLinkToContainer(ctx context.Context, link netlink.Link, f func(ns.NetNS) error) error {
 	// retrieve nodes nspath
 	nsp, err := d.getNSPath(ctx)
@@ -640,6 +655,8 @@ func (d *DefaultNode) GetShortName() string {
 
 // DeployEndpoints deploys endpoints associated with the node.
 // The deployment of endpoints is done by deploying a link with the endpoint triggering it.
+// For nodes with the kernel dataplane the link addresses and static routes
+// are configured once all the endpoints of the node are deployed.
 func (d *DefaultNode) DeployEndpoints(ctx context.Context) error {
 	for _, ep := range d.Endpoints {
 		err := ep.Deploy(ctx)
@@ -648,7 +665,11 @@ func (d *DefaultNode) DeployEndpoints(ctx context.Context) error {
 		}
 	}
 
-	return nil
+	if !d.Cfg.KernelDataplane {
+		return nil
+	}
+
+	return d.OverwriteNode.ExecFunction(ctx, ConfigureDataplaneAddressing(d.Cfg, d.Endpoints))
 }
 
 func (d *DefaultNode) GetState() state.NodeState {
@@ -667,6 +688,12 @@ func (d *DefaultNode) GetSSHConfig() *types.SSHConfig {
 	return d.SSHConfig
 }
 
+// Credentials returns the credentials of the node.
+// The credentials set in the topology take precedence over the given default credentials of the kind.
+func (d *DefaultNode) Credentials(defaults *Credentials) *Credentials {
+	return defaults.Merge(d.Cfg.Credentials)
+}
+
 func (d *DefaultNode) GetContainerStatus(ctx context.Context) runtime.ContainerStatus {
 	return d.Runtime.GetContainerStatus(ctx, d.GetContainerName())
 }
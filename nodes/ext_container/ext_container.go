@@ -37,6 +37,7 @@ func (s *extcont) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 	// Since we would stop deployment on pre-existing containers.
 	s.Cfg.SkipUniquenessCheck = true
 	s.Cfg.LongName = s.Cfg.ShortName
+	s.Cfg.KernelDataplane = true
 	return nil
 }
 
@@ -36,7 +36,8 @@ const (
 // Register registers the node in the NodeRegistry.
 func Register(r *nodes.NodeRegistry) {
 	generateNodeAttributes := nodes.NewGenerateNodeAttributes(generateable, generateIfFormat)
-	nrea := nodes.NewNodeRegistryEntryAttributes(defaultCredentials, generateNodeAttributes)
+	nrea := nodes.NewNodeRegistryEntryAttributes(defaultCredentials, generateNodeAttributes).
+		WithInterfaceRegexp(nodes.InterfaceRegexpOf(nodes.VMInterfaceRegexp, InterfaceRegexp))
 
 	r.Register(kindnames, func() nodes.Node {
 		return new(fortigate)
@@ -49,7 +50,7 @@ type fortigate struct {
 
 func (n *fortigate) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 	// Init VRNode
-	n.VRNode = *nodes.NewVRNode(n)
+	n.VRNode = *nodes.NewVRNode(n, defaultCredentials, "")
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
 
@@ -57,10 +58,11 @@ func (n *fortigate) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error
 	for _, o := range opts {
 		o(n)
 	}
+	creds := n.Credentials(defaultCredentials)
 	// env vars are used to set launch.py arguments in vrnetlab container
 	defEnv := map[string]string{
-		"USERNAME":           defaultCredentials.GetUsername(),
-		"PASSWORD":           defaultCredentials.GetPassword(),
+		"USERNAME":           creds.GetUsername(),
+		"PASSWORD":           creds.GetPassword(),
 		"CONNECTION_MODE":    nodes.VrDefConnMode,
 		"VCPU":               "2",
 		"RAM":                "2048",
@@ -79,7 +81,7 @@ func (n *fortigate) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error
 	}
 
 	n.Cfg.Cmd = fmt.Sprintf("--username %s --password %s --hostname %s --connection-mode %s --trace",
-		defaultCredentials.GetUsername(), defaultCredentials.GetPassword(), n.Cfg.ShortName, n.Cfg.Env["CONNECTION_MODE"])
+		creds.GetUsername(), creds.GetPassword(), n.Cfg.ShortName, n.Cfg.Env["CONNECTION_MODE"])
 
 	return nil
 }
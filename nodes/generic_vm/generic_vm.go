@@ -49,11 +49,12 @@ func (n *genericVM) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error
 	for _, o := range opts {
 		o(n)
 	}
+	creds := n.Credentials(defaultCredentials)
 	// env vars are used to set launch.py arguments in vrnetlab container
 	defEnv := map[string]string{
 		"CONNECTION_MODE":    nodes.VrDefConnMode,
-		"USERNAME":           defaultCredentials.GetUsername(),
-		"PASSWORD":           defaultCredentials.GetPassword(),
+		"USERNAME":           creds.GetUsername(),
+		"PASSWORD":           creds.GetPassword(),
 		"DOCKER_NET_V4_ADDR": n.Mgmt.IPv4Subnet,
 		"DOCKER_NET_V6_ADDR": n.Mgmt.IPv6Subnet,
 	}
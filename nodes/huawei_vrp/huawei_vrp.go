@@ -47,7 +47,7 @@ type huawei_vrp struct {
 
 func (n *huawei_vrp) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 	// Init VRNode
-	n.VRNode = *nodes.NewVRNode(n)
+	n.VRNode = *nodes.NewVRNode(n, defaultCredentials, scrapliPlatformName)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
 
@@ -55,11 +55,12 @@ func (n *huawei_vrp) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error
 	for _, o := range opts {
 		o(n)
 	}
+	creds := n.Credentials(defaultCredentials)
 	// env vars are used to set launch.py arguments in vrnetlab container
 	defEnv := map[string]string{
 		"CONNECTION_MODE":    nodes.VrDefConnMode,
-		"USERNAME":           defaultCredentials.GetUsername(),
-		"PASSWORD":           defaultCredentials.GetPassword(),
+		"USERNAME":           creds.GetUsername(),
+		"PASSWORD":           creds.GetPassword(),
 		"DOCKER_NET_V4_ADDR": n.Mgmt.IPv4Subnet,
 		"DOCKER_NET_V6_ADDR": n.Mgmt.IPv6Subnet,
 		"VCPU":               "2",
@@ -87,9 +88,11 @@ func (s *huawei_vrp) PreDeploy(_ context.Context, params *nodes.PreDeployParams)
 }
 
 func (n *huawei_vrp) SaveConfig(_ context.Context) error {
+	creds := n.Credentials(defaultCredentials)
 	err := netconf.SaveConfig(n.Cfg.LongName,
-		defaultCredentials.GetUsername(),
-		defaultCredentials.GetPassword(),
+		creds.GetUsername(),
+		creds.GetPassword(),
+		creds.GetSSHKey(),
 		scrapliPlatformName,
 	)
 	if err != nil {
@@ -0,0 +1,54 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// WithInterfaceRegexp sets the pattern the names of the kind's interfaces
+// referenced in the topology file have to match.
+func (nrea *NodeRegistryEntryAttributes) WithInterfaceRegexp(re *regexp.Regexp) *NodeRegistryEntryAttributes {
+	nrea.interfaceRegexp = re
+	// the anchored pattern is compiled once, as the names are checked for every link endpoint
+	nrea.fullInterfaceRegexp = regexp.MustCompile(`^(?:` + re.String() + `)$`)
+	return nrea
+}
+
+// GetInterfaceRegexp returns the pattern of the kind's interface names,
+// nil if the kind accepts any interface name.
+func (nre *NodeRegistryEntry) GetInterfaceRegexp() *regexp.Regexp {
+	if nre == nil || nre.attributes == nil {
+		return nil
+	}
+
+	return nre.attributes.interfaceRegexp
+}
+
+// CheckInterfaceName checks if the interface name matches the kind's interface pattern as a whole.
+func (nre *NodeRegistryEntry) CheckInterfaceName(name string) error {
+	re := nre.GetInterfaceRegexp()
+	if re == nil {
+		return nil
+	}
+
+	if !nre.attributes.fullInterfaceRegexp.MatchString(name) {
+		return fmt.Errorf("interface name %q doesn't match the pattern %q", name, re.String())
+	}
+
+	return nil
+}
+
+// InterfaceRegexpOf returns the pattern matching the interface names that match any of the patterns.
+func InterfaceRegexpOf(res ...*regexp.Regexp) *regexp.Regexp {
+	alts := make([]string, 0, len(res))
+	for _, re := range res {
+		alts = append(alts, `(?:`+re.String()+`)`)
+	}
+
+	return regexp.MustCompile(strings.Join(alts, "|"))
+}
@@ -0,0 +1,37 @@
+package nodes
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestRegistryEntryCheckInterfaceName(t *testing.T) {
+	r := NewNodeRegistry()
+
+	aliasRe := regexp.MustCompile(`Gi0/(?P<port>\d+)`)
+
+	_ = r.Register([]string{"vm"}, nil, NewNodeRegistryEntryAttributes(nil, nil).
+		WithInterfaceRegexp(InterfaceRegexpOf(VMInterfaceRegexp, aliasRe)))
+	_ = r.Register([]string{"any"}, nil, nil)
+
+	tests := map[string]struct {
+		kind    string
+		name    string
+		wantErr bool
+	}{
+		"vm interface":           {kind: "vm", name: "eth1"},
+		"alias interface":        {kind: "vm", name: "Gi0/1"},
+		"partially matching":     {kind: "vm", name: "xeth1", wantErr: true},
+		"not matching":           {kind: "vm", name: "e1-1", wantErr: true},
+		"kind without a pattern": {kind: "any", name: "whatever"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := r.Kind(tc.kind).CheckInterfaceName(tc.name)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("got error %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -50,11 +50,12 @@ func (n *IPInfusionOcNOS) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption)
 	for _, o := range opts {
 		o(n)
 	}
+	creds := n.Credentials(defaultCredentials)
 	// env vars are used to set launch.py arguments in vrnetlab container
 	defEnv := map[string]string{
 		"CONNECTION_MODE":    nodes.VrDefConnMode,
-		"USERNAME":           defaultCredentials.GetUsername(),
-		"PASSWORD":           defaultCredentials.GetPassword(),
+		"USERNAME":           creds.GetUsername(),
+		"PASSWORD":           creds.GetPassword(),
 		"DOCKER_NET_V4_ADDR": n.Mgmt.IPv4Subnet,
 		"DOCKER_NET_V6_ADDR": n.Mgmt.IPv6Subnet,
 	}
@@ -76,9 +77,11 @@ func (s *IPInfusionOcNOS) PreDeploy(_ context.Context, params *nodes.PreDeployPa
 }
 
 func (n *IPInfusionOcNOS) SaveConfig(_ context.Context) error {
+	creds := n.Credentials(defaultCredentials)
 	err := netconf.SaveConfig(n.Cfg.LongName,
-		defaultCredentials.GetUsername(),
-		defaultCredentials.GetPassword(),
+		creds.GetUsername(),
+		creds.GetPassword(),
+		creds.GetSSHKey(),
 		scrapliPlatformName,
 	)
 	if err != nil {
@@ -0,0 +1,81 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package linux
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/cert"
+	"github.com/srl-labs/containerlab/clab/exec"
+)
+
+// caInstallScriptTmpl installs the CA certificate into the trust store of the container.
+// Debian/Alpine and RHEL-like trust store tools are used when available, otherwise
+// the certificate is appended to the system bundle unless it is there already.
+const caInstallScriptTmpl = `set -e
+CA='%[2]s'
+if command -v update-ca-certificates >/dev/null 2>&1; then
+  mkdir -p /usr/local/share/ca-certificates
+  printf '%%s\n' "$CA" > /usr/local/share/ca-certificates/%[1]s.crt
+  update-ca-certificates >/dev/null
+elif command -v update-ca-trust >/dev/null 2>&1; then
+  mkdir -p /etc/pki/ca-trust/source/anchors
+  printf '%%s\n' "$CA" > /etc/pki/ca-trust/source/anchors/%[1]s.crt
+  update-ca-trust extract
+else
+  mkdir -p /etc/ssl/certs
+  touch /etc/ssl/certs/ca-certificates.crt
+  if ! grep -qxF "$(printf '%%s\n' "$CA" | sed -n 2p)" /etc/ssl/certs/ca-certificates.crt; then
+    printf '%%s\n' "$CA" >> /etc/ssl/certs/ca-certificates.crt
+  fi
+fi`
+
+// installCA installs the lab CA certificate into the trust store of the container,
+// so that the clients running in the container trust the certificates of the lab nodes.
+func (n *linux) installCA(ctx context.Context) error {
+	caCert, err := n.cert.LoadCaCert()
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Installing the lab CA certificate into the trust store of node %q", n.Cfg.ShortName)
+
+	script := caInstallScript("clab-"+n.topologyName+"-ca", caCert.Cert)
+
+	res, err := n.RunExec(ctx, exec.NewExecCmdFromSlice([]string{"sh", "-c", script}))
+	if err != nil {
+		return err
+	}
+
+	if res.GetReturnCode() != 0 {
+		return fmt.Errorf("failed to install the lab CA certificate on node %q: %s",
+			n.Cfg.ShortName, res.GetStdErrString())
+	}
+
+	return nil
+}
+
+// ReloadCertificates installs the lab CA certificate from the certificate storage
+// into the trust store of the running container, replacing the previously installed one.
+// It is a noop for the nodes that do not install the lab CA.
+func (n *linux) ReloadCertificates(ctx context.Context, certInfra *cert.Cert, topoName string) error {
+	if c := n.Cfg.Certificate; c == nil || c.InstallCA == nil || !*c.InstallCA {
+		return nil
+	}
+
+	n.cert = certInfra
+	n.topologyName = topoName
+
+	return n.installCA(ctx)
+}
+
+// caInstallScript returns the shell script installing the PEM encoded CA certificate
+// into the trust store under the given name.
+func caInstallScript(name string, caPEM []byte) string {
+	return fmt.Sprintf(caInstallScriptTmpl, name, bytes.TrimSpace(caPEM))
+}
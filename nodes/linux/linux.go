@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/cert"
 	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/nodes/state"
 	"github.com/srl-labs/containerlab/runtime/ignite"
@@ -39,6 +40,9 @@ func Register(r *nodes.NodeRegistry) {
 type linux struct {
 	nodes.DefaultNode
 	vmChans *operations.VMChannels
+	// cert and topologyName are used to install the lab CA in the post-deploy stage
+	cert         *cert.Cert
+	topologyName string
 }
 
 func (n *linux) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
@@ -54,6 +58,10 @@ func (n *linux) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		n.Cfg.RestartPolicy = "always"
 	}
 
+	// linux nodes use the kernel dataplane, so the link addresses
+	// can be configured directly on the container interfaces.
+	n.Cfg.KernelDataplane = true
+
 	for _, o := range opts {
 		o(n)
 	}
@@ -67,6 +75,13 @@ func (n *linux) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 	return nil
 }
 
+func (n *linux) PreDeploy(ctx context.Context, params *nodes.PreDeployParams) error {
+	n.cert = params.Cert
+	n.topologyName = params.TopologyName
+
+	return n.DefaultNode.PreDeploy(ctx, params)
+}
+
 func (n *linux) Deploy(ctx context.Context, _ *nodes.DeployParams) error {
 	// Set the "CLAB_INTFS" variable to the number of interfaces
 	// Which is required by vrnetlab to determine if all configured interfaces are present
@@ -96,6 +111,12 @@ func (n *linux) PostDeploy(ctx context.Context, _ *nodes.PostDeployParams) error
 		log.Error(err)
 	}
 
+	if c := n.Cfg.Certificate; c != nil && c.InstallCA != nil && *c.InstallCA && n.cert != nil {
+		if err := n.installCA(ctx); err != nil {
+			log.Error(err)
+		}
+	}
+
 	// when ignite runtime is in use
 	if n.vmChans != nil {
 		return <-n.vmChans.SpawnFinished
@@ -0,0 +1,50 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+// Management services of the nodes that can be published on the host ports.
+const (
+	MgmtServiceSSH     = "ssh"
+	MgmtServiceGNMI    = "gnmi"
+	MgmtServiceNETCONF = "netconf"
+	MgmtServiceHTTPS   = "https"
+)
+
+// MgmtServices is the list of the management services in the order their host ports are allocated.
+var MgmtServices = []string{MgmtServiceSSH, MgmtServiceGNMI, MgmtServiceNETCONF, MgmtServiceHTTPS}
+
+// DefaultMgmtPorts maps the management services to the container ports they listen on,
+// unless the kind registers different ports.
+var DefaultMgmtPorts = map[string]int{
+	MgmtServiceSSH:     22,
+	MgmtServiceGNMI:    57400,
+	MgmtServiceNETCONF: 830,
+	MgmtServiceHTTPS:   443,
+}
+
+// WithMgmtPorts sets the container ports of the kind's management services
+// that differ from the DefaultMgmtPorts.
+func (nrea *NodeRegistryEntryAttributes) WithMgmtPorts(ports map[string]int) *NodeRegistryEntryAttributes {
+	nrea.mgmtPorts = ports
+	return nrea
+}
+
+// GetMgmtPorts returns the container ports of the kind's management services.
+func (nre *NodeRegistryEntry) GetMgmtPorts() map[string]int {
+	ports := make(map[string]int, len(DefaultMgmtPorts))
+	for s, p := range DefaultMgmtPorts {
+		ports[s] = p
+	}
+
+	if nre == nil || nre.attributes == nil {
+		return ports
+	}
+
+	for s, p := range nre.attributes.mgmtPorts {
+		ports[s] = p
+	}
+
+	return ports
+}
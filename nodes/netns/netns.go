@@ -0,0 +1,400 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package netns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/containernetworking/plugins/pkg/utils/sysctl"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	cExec "github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/labels"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/nodes/host"
+	"github.com/srl-labs/containerlab/nodes/state"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// KindName is the name of the netns kind.
+	KindName = "netns"
+
+	generateable     = true
+	generateIfFormat = "eth%d"
+
+	// name of the management interface created in the netns
+	// when static management addresses are provided.
+	mgmtIfName = "eth0"
+)
+
+var kindnames = []string{KindName}
+
+// Register registers the node in the NodeRegistry.
+func Register(r *nodes.NodeRegistry) {
+	generateNodeAttributes := nodes.NewGenerateNodeAttributes(generateable, generateIfFormat)
+	nrea := nodes.NewNodeRegistryEntryAttributes(nil, generateNodeAttributes)
+
+	r.Register(kindnames, func() nodes.Node {
+		return new(netnsNode)
+	}, nrea)
+}
+
+// netnsNode is a node backed by a plain named linux network namespace
+// without any container image. It is a lightweight alternative to the linux kind
+// when only the networking stack of a node is needed.
+type netnsNode struct {
+	nodes.DefaultNode
+}
+
+func (n *netnsNode) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
+	// Init DefaultNode
+	n.DefaultNode = *nodes.NewDefaultNode(n)
+
+	n.Cfg = cfg
+	for _, o := range opts {
+		o(n)
+	}
+
+	// netns nodes are not managed by the container runtime
+	// and therefore have no container to check for uniqueness
+	n.Cfg.SkipUniquenessCheck = true
+	n.Cfg.KernelDataplane = true
+
+	if n.Cfg.Sysctls == nil {
+		n.Cfg.Sysctls = map[string]string{}
+	}
+
+	// make ipv6 enabled on all netns node interfaces the same way it is done for linux nodes
+	if _, ok := n.Cfg.Sysctls["net.ipv6.conf.all.disable_ipv6"]; !ok {
+		n.Cfg.Sysctls["net.ipv6.conf.all.disable_ipv6"] = "0"
+	}
+
+	return nil
+}
+
+// nsPath returns the path to the named network namespace of the node.
+func (n *netnsNode) nsPath() string {
+	return utils.NamedNetnsPath(n.Cfg.LongName)
+}
+
+func (n *netnsNode) CheckDeploymentConditions(_ context.Context) error {
+	err := n.VerifyHostRequirements()
+	if err != nil {
+		return err
+	}
+
+	if utils.FileOrDirExists(n.nsPath()) {
+		return fmt.Errorf("network namespace %q already exists. Destroy the lab before deploying it again", n.Cfg.LongName)
+	}
+
+	return n.CheckInterfaceName()
+}
+
+// CheckInterfaceName allows any interface name for netns nodes, but checks
+// that eth0 is not used when management addresses are set, since eth0 is reserved for the management interface.
+func (n *netnsNode) CheckInterfaceName() error {
+	err := n.CheckInterfaceOverlap()
+	if err != nil {
+		return err
+	}
+
+	if !n.hasMgmtAddress() {
+		return nil
+	}
+
+	for _, e := range n.Endpoints {
+		if e.GetIfaceName() == mgmtIfName {
+			return fmt.Errorf("%s interface name is not allowed for %s node when mgmt-ipv4/mgmt-ipv6 is set", mgmtIfName, n.Cfg.ShortName)
+		}
+	}
+
+	return nil
+}
+
+func (n *netnsNode) Deploy(ctx context.Context, _ *nodes.DeployParams) error {
+	log.Debugf("Creating network namespace %q for node %q", n.Cfg.LongName, n.Cfg.ShortName)
+
+	err := utils.CreateNamedNetns(n.Cfg.LongName)
+	if err != nil {
+		return err
+	}
+
+	err = n.ExecFunction(ctx, n.applySysctls)
+	if err != nil {
+		return err
+	}
+
+	if n.hasMgmtAddress() {
+		err = n.deployMgmtInterface(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	n.SetState(state.Deployed)
+
+	return nil
+}
+
+// applySysctls sets the node's sysctls in the node's network namespace.
+func (n *netnsNode) applySysctls(_ ns.NetNS) error {
+	for k, v := range n.Cfg.Sysctls {
+		if _, err := sysctl.Sysctl(k, v); err != nil {
+			return fmt.Errorf("failed to set sysctl %s=%s for node %q: %w", k, v, n.Cfg.ShortName, err)
+		}
+	}
+
+	return nil
+}
+
+// hasMgmtAddress returns true if a static management address is set for the node.
+func (n *netnsNode) hasMgmtAddress() bool {
+	return n.Cfg.MgmtIPv4Address != "" || n.Cfg.MgmtIPv6Address != ""
+}
+
+// deployMgmtInterface creates a veth pair connecting the node's netns to the
+// management bridge and configures the static management addresses
+// along with the default routes via the management network gateways.
+func (n *netnsNode) deployMgmtInterface(ctx context.Context) error {
+	if n.Mgmt == nil || n.Mgmt.Bridge == "" {
+		return fmt.Errorf("management bridge is not known, unable to connect node %q to the management network", n.Cfg.ShortName)
+	}
+
+	br, err := utils.BridgeByName(n.Mgmt.Bridge)
+	if err != nil {
+		return err
+	}
+
+	// both ends get random names to avoid collisions in the root netns,
+	// the netns side is renamed to eth0 once moved to the node's netns.
+	veth := &netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{
+			Name:        "ns-" + uuid.New().String()[:8],
+			MasterIndex: br.Attrs().Index,
+			MTU:         br.Attrs().MTU,
+		},
+		PeerName: "ns-" + uuid.New().String()[:8],
+	}
+
+	err = netlink.LinkAdd(veth)
+	if err != nil {
+		return fmt.Errorf("failed to create management veth for node %q: %w", n.Cfg.ShortName, err)
+	}
+
+	if err := netlink.LinkSetUp(veth); err != nil {
+		return err
+	}
+
+	peer, err := netlink.LinkByName(veth.PeerName)
+	if err != nil {
+		return err
+	}
+
+	return n.AddLinkToContainer(ctx, peer, n.configureMgmtInterface(peer))
+}
+
+// configureMgmtInterface returns a function that is executed in the netns of the node
+// to rename the management interface, assign the addresses and install default routes.
+func (n *netnsNode) configureMgmtInterface(l netlink.Link) func(ns.NetNS) error {
+	return func(_ ns.NetNS) error {
+		err := netlink.LinkSetName(l, mgmtIfName)
+		if err != nil {
+			return err
+		}
+
+		addrs := []struct {
+			addr   string
+			subnet string
+			gw     string
+		}{
+			{n.Cfg.MgmtIPv4Address, n.Mgmt.IPv4Subnet, n.Mgmt.IPv4Gw},
+			{n.Cfg.MgmtIPv6Address, n.Mgmt.IPv6Subnet, n.Mgmt.IPv6Gw},
+		}
+
+		for _, a := range addrs {
+			if a.addr == "" {
+				continue
+			}
+
+			_, subnet, err := net.ParseCIDR(a.subnet)
+			if err != nil {
+				return fmt.Errorf("failed to parse management subnet %q: %w", a.subnet, err)
+			}
+
+			ones, _ := subnet.Mask.Size()
+
+			nlAddr, err := netlink.ParseAddr(fmt.Sprintf("%s/%d", a.addr, ones))
+			if err != nil {
+				return err
+			}
+
+			if err := netlink.AddrAdd(l, nlAddr); err != nil {
+				return fmt.Errorf("failed to add address %s to %s: %w", nlAddr, mgmtIfName, err)
+			}
+
+			if a.gw == "" {
+				continue
+			}
+
+			// the link must be up for the route to be installed
+			if err := netlink.LinkSetUp(l); err != nil {
+				return err
+			}
+
+			err = netlink.RouteAdd(&netlink.Route{
+				LinkIndex: l.Attrs().Index,
+				Gw:        net.ParseIP(a.gw),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to add default route via %s: %w", a.gw, err)
+			}
+		}
+
+		return netlink.LinkSetUp(l)
+	}
+}
+
+func (n *netnsNode) Delete(ctx context.Context) error {
+	for _, e := range n.Endpoints {
+		err := e.GetLink().Remove(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// deleting the netns removes all the interfaces in it,
+	// including the management veth pair.
+	return utils.DeleteNamedNetns(n.Cfg.LongName)
+}
+
+// DeleteNetnsSymlink is a noop for netns nodes, since the named netns is removed by Delete.
+func (*netnsNode) DeleteNetnsSymlink() error { return nil }
+
+func (*netnsNode) GetImages(_ context.Context) map[string]string { return map[string]string{} }
+func (*netnsNode) PullImage(_ context.Context) error             { return nil }
+
+// UpdateConfigWithRuntimeInfo is a noop for netns nodes, since the
+// management addresses are statically defined.
+func (*netnsNode) UpdateConfigWithRuntimeInfo(_ context.Context) error { return nil }
+
+// GetContainers returns a skeleton of a container that represents the netns node
+// to enable inspection and graphing of netns nodes.
+func (n *netnsNode) GetContainers(_ context.Context) ([]runtime.GenericContainer, error) {
+	if !utils.FileOrDirExists(n.nsPath()) {
+		return nil, fmt.Errorf("Node: %s. %w", n.Cfg.LongName, nodes.ErrContainersNotFound)
+	}
+
+	lbls := map[string]string{
+		labels.NodeKind: KindName,
+	}
+	for k, v := range n.Cfg.Labels {
+		lbls[k] = v
+	}
+
+	gc := runtime.GenericContainer{
+		Names:   []string{n.Cfg.LongName},
+		State:   "running",
+		ID:      "N/A",
+		ShortID: "N/A",
+		Image:   "N/A",
+		Labels:  lbls,
+		Status:  "running",
+		NetworkSettings: runtime.GenericMgmtIPs{
+			IPv4addr: n.Cfg.MgmtIPv4Address,
+			IPv6addr: n.Cfg.MgmtIPv6Address,
+		},
+	}
+
+	if n.Mgmt != nil {
+		gc.NetworkSettings.IPv4Gw = n.Mgmt.IPv4Gw
+		gc.NetworkSettings.IPv6Gw = n.Mgmt.IPv6Gw
+		gc.NetworkSettings.IPv4pLen = prefixLength(n.Mgmt.IPv4Subnet)
+		gc.NetworkSettings.IPv6pLen = prefixLength(n.Mgmt.IPv6Subnet)
+	}
+
+	return []runtime.GenericContainer{gc}, nil
+}
+
+// prefixLength returns the prefix length of a subnet provided in a CIDR notation.
+func prefixLength(s string) int {
+	_, subnet, err := net.ParseCIDR(s)
+	if err != nil {
+		return 0
+	}
+
+	ones, _ := subnet.Mask.Size()
+
+	return ones
+}
+
+// GetContainerStatus returns the running status when the netns of the node exists.
+func (n *netnsNode) GetContainerStatus(_ context.Context) runtime.ContainerStatus {
+	if utils.FileOrDirExists(n.nsPath()) {
+		return runtime.Running
+	}
+
+	return runtime.NotFound
+}
+
+// IsHealthy returns true when the netns of the node exists.
+func (n *netnsNode) IsHealthy(ctx context.Context) (bool, error) {
+	return n.GetContainerStatus(ctx) == runtime.Running, nil
+}
+
+// RunExec runs the command on the container host within the node's network namespace.
+func (n *netnsNode) RunExec(ctx context.Context, e *cExec.ExecCmd) (*cExec.ExecResult, error) {
+	cmd := append([]string{"ip", "netns", "exec", n.Cfg.LongName}, e.GetCmd()...)
+
+	res, err := host.RunExec(ctx, cExec.NewExecCmdFromSlice(cmd))
+	if err != nil {
+		log.Errorf("%s: failed to execute cmd: %q with error %v", n.Cfg.LongName, e.GetCmdString(), err)
+		return nil, err
+	}
+
+	// report the original command in the result
+	res.Cmd = e.GetCmd()
+
+	return res, nil
+}
+
+// AddLinkToContainer moves the link to the node's network namespace
+// and runs the function f within this namespace.
+func (n *netnsNode) AddLinkToContainer(_ context.Context, link netlink.Link, f func(ns.NetNS) error) error {
+	netns, err := ns.GetNS(n.nsPath())
+	if err != nil {
+		return err
+	}
+	defer netns.Close()
+
+	if err = netlink.LinkSetNsFd(link, int(netns.Fd())); err != nil {
+		return err
+	}
+
+	return netns.Do(f)
+}
+
+// ExecFunction executes the given function in the node's network namespace.
+func (n *netnsNode) ExecFunction(_ context.Context, f func(ns.NetNS) error) error {
+	netns, err := ns.GetNS(n.nsPath())
+	if err != nil {
+		return fmt.Errorf("failed to get network namespace of node %q: %w", n.Cfg.ShortName, err)
+	}
+	defer netns.Close()
+
+	return netns.Do(f)
+}
+
+// IsNetnsNode returns true if the node config belongs to a netns kind node.
+func IsNetnsNode(cfg *types.NodeConfig) bool {
+	return strings.EqualFold(cfg.Kind, KindName)
+}
@@ -38,6 +38,8 @@ var (
 	ErrCommandExecError = errors.New("command execution error")
 	// ErrContainersNotFound indicated that for a given node no containers where found in the runtime.
 	ErrContainersNotFound = errors.New("containers not found")
+	// ErrCertReloadNotSupported is returned when the node can not apply the renewed certificates at runtime.
+	ErrCertReloadNotSupported = errors.New("certificate reload is not supported")
 )
 
 // SetNonDefaultRuntimePerKind sets a non default runtime for kinds that requires that (see cvx).
@@ -93,6 +95,9 @@ type Node interface {
 	GenerateConfig(dst, templ string) error      // Generate the nodes configuration
 	// UpdateConfigWithRuntimeInfo updates node config with runtime info like IP addresses assigned by runtime
 	UpdateConfigWithRuntimeInfo(context.Context) error
+	// ReloadCertificates applies the node and CA certificates found in the certificate storage
+	// to the running node, e.g. after the certificates were renewed.
+	ReloadCertificates(ctx context.Context, certInfra *cert.Cert, topoName string) error
 	// RunExec execute a single command for a given node.
 	RunExec(ctx context.Context, execCmd *exec.ExecCmd) (*exec.ExecResult, error)
 	// Adds the given link to the Node (container). After adding the Link to the node,
@@ -2,8 +2,11 @@ package nodes
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
+
+	"github.com/srl-labs/containerlab/types"
 )
 
 type Initializer func() Node
@@ -81,8 +84,17 @@ type NodeRegistryEntry struct {
 	attributes    *NodeRegistryEntryAttributes
 }
 
+// GetKindNames returns the names the kind is registered with.
+func (nre *NodeRegistryEntry) GetKindNames() []string {
+	if nre == nil {
+		return nil
+	}
+
+	return nre.nodeKindNames
+}
+
 func (nre *NodeRegistryEntry) GetCredentials() *Credentials {
-	if nre.attributes == nil {
+	if nre == nil || nre.attributes == nil {
 		return nil
 	}
 
@@ -119,6 +131,13 @@ func newRegistryEntry(nodeKindNames []string, initFunction Initializer,
 type NodeRegistryEntryAttributes struct {
 	credentials        *Credentials
 	generateAttributes *GenerateNodeAttributes
+	// mgmtPorts maps the management services to the container ports
+	// when they differ from the DefaultMgmtPorts
+	mgmtPorts map[string]int
+	// interfaceRegexp is the pattern of the kind's interface names
+	interfaceRegexp *regexp.Regexp
+	// fullInterfaceRegexp is the interfaceRegexp anchored to match the whole interface name
+	fullInterfaceRegexp *regexp.Regexp
 }
 
 func NewNodeRegistryEntryAttributes(c *Credentials, ga *GenerateNodeAttributes) *NodeRegistryEntryAttributes {
@@ -170,6 +189,8 @@ func (ga *GenerateNodeAttributes) GetInterfaceFormat() string {
 type Credentials struct {
 	username string
 	password string
+	// sshKey is the path to the private key used for SSH authentication
+	sshKey string
 }
 
 // NewCredentials constructor for the Credentials struct.
@@ -196,6 +217,42 @@ func (c *Credentials) GetPassword() string {
 	return c.password
 }
 
+// GetSSHKey returns the path to the private key used for SSH authentication.
+func (c *Credentials) GetSSHKey() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.sshKey
+}
+
+// Merge returns new credentials with the attributes set in the credentials config
+// overriding the attributes of c. The receiver is not modified.
+func (c *Credentials) Merge(cc *types.CredentialsConfig) *Credentials {
+	res := &Credentials{}
+	if c != nil {
+		*res = *c
+	}
+
+	if cc == nil {
+		return res
+	}
+
+	if cc.Username != "" {
+		res.username = cc.Username
+	}
+
+	if cc.Password != "" {
+		res.password = cc.Password
+	}
+
+	if cc.SSHKey != "" {
+		res.sshKey = cc.SSHKey
+	}
+
+	return res
+}
+
 // Slice returns credentials as a slice.
 func (c *Credentials) Slice() []string {
 	if c == nil {
@@ -48,6 +48,8 @@ const (
 	// overlayCfgPath is a path to a file with additional config that clab adds on top of the default config.
 	// Partial config provided via startup-config parameter is an overlay config.
 	overlayCfgPath = "/tmp/clab-overlay-config"
+	// tlsCfgPath is a path to a file with the config replacing the certificate of the clab TLS profile.
+	tlsCfgPath = "/tmp/clab-tls-config"
 )
 
 var (
@@ -111,12 +113,16 @@ var (
 
 	InterfaceRegexp = regexp.MustCompile(`ethernet-(?P<linecard>\d+)/(?P<port>\d+)(?:/(?P<channel>\d+))?`)
 	InterfaceHelp   = "ethernet-L/P, ethernet-L/P/C or eL-P, eL-P-C (where L, P, C >= 1)"
+
+	// ifNameRegexp matches ethernetX-X-X, eX-X-X and mgmt0 interface names.
+	ifNameRegexp = regexp.MustCompile(`(:?e|ethernet)\d+-\d+(-\d+)?|mgmt0`)
 )
 
 // Register registers the node in the NodeRegistry.
 func Register(r *nodes.NodeRegistry) {
 	generateNodeAttributes := nodes.NewGenerateNodeAttributes(generateable, generateIfFormat)
-	nrea := nodes.NewNodeRegistryEntryAttributes(defaultCredentials, generateNodeAttributes)
+	nrea := nodes.NewNodeRegistryEntryAttributes(defaultCredentials, generateNodeAttributes).
+		WithInterfaceRegexp(nodes.InterfaceRegexpOf(ifNameRegexp, InterfaceRegexp))
 
 	r.Register(kindNames, func() nodes.Node {
 		return new(srl)
@@ -313,6 +319,61 @@ func (s *srl) SaveConfig(ctx context.Context) error {
 	return nil
 }
 
+// ReloadCertificates replaces the key, certificate and trust anchor of the clab TLS profile
+// with the node certificate and the lab CA certificate from the certificate storage
+// and saves the configuration, so that the node trusts the renewed lab CA.
+func (s *srl) ReloadCertificates(ctx context.Context, certInfra *cert.Cert, _ string) error {
+	certificate, err := certInfra.LoadNodeCert(s.Cfg.ShortName)
+	if err != nil {
+		return err
+	}
+
+	caCertificate, err := certInfra.LoadCaCert()
+	if err != nil {
+		return err
+	}
+
+	s.Config().TLSCert = string(certificate.Cert)
+	s.Config().TLSKey = string(certificate.Key)
+	s.Config().TLSAnchor = string(caCertificate.Cert)
+
+	cfg := tlsProfileConfig(s.Cfg.TLSKey, s.Cfg.TLSCert, s.Cfg.TLSAnchor)
+
+	cmd := exec.NewExecCmdFromSlice([]string{
+		"bash", "-c",
+		fmt.Sprintf("echo '%s' > %s", cfg, tlsCfgPath),
+	})
+	if _, err := s.RunExec(ctx, cmd); err != nil {
+		return err
+	}
+
+	cmd = exec.NewExecCmdFromSlice([]string{
+		"bash", "-c",
+		fmt.Sprintf("su -s /bin/bash admin -c '/opt/srlinux/bin/sr_cli -ed < %s'", tlsCfgPath),
+	})
+	execResult, err := s.RunExec(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	if len(execResult.GetStdErrString()) != 0 {
+		return fmt.Errorf("%w:%s", nodes.ErrCommandExecError, execResult.GetStdErrString())
+	}
+
+	log.Debugf("node %s. stdout: %s", s.Cfg.ShortName, execResult.GetStdOutString())
+
+	return nil
+}
+
+// tlsProfileConfig returns the sr_cli commands setting the key, certificate and trust anchor
+// of the clab TLS profile. The client authentication setting is left unchanged.
+func tlsProfileConfig(key, certificate, anchor string) string {
+	return fmt.Sprintf(`set / system tls server-profile clab-profile key "%s"
+set / system tls server-profile clab-profile certificate "%s"
+set / system tls server-profile clab-profile trust-anchor "%s"
+commit save`, key, certificate, anchor)
+}
+
 // Ready returns when the node boot sequence reached the stage when it is ready to accept config commands
 // returns an error if not ready by the expiry of the timer readyTimeout.
 func (s *srl) Ready(ctx context.Context) error {
@@ -816,8 +877,6 @@ func (s *srl) GetMappedInterfaceName(ifName string) (string, error) {
 
 // CheckInterfaceName checks if a name of the interface referenced in the topology file correct.
 func (s *srl) CheckInterfaceName() error {
-	// allow ethernetX-X-X, eX-X-X and mgmt0 interface names
-	ifRe := regexp.MustCompile(`(:?e|ethernet)\d+-\d+(-\d+)?|mgmt0`)
 	nm := strings.ToLower(s.Cfg.NetworkMode)
 
 	err := s.CheckInterfaceOverlap()
@@ -826,7 +885,7 @@ func (s *srl) CheckInterfaceName() error {
 	}
 
 	for _, e := range s.Endpoints {
-		if !ifRe.MatchString(e.GetIfaceName()) {
+		if !ifNameRegexp.MatchString(e.GetIfaceName()) {
 			return fmt.Errorf("nokia sr linux interface name %q doesn't match the required pattern: %s", e.GetIfaceName(), s.InterfaceHelp)
 		}
 
@@ -150,3 +150,21 @@ func TestSRLInterfaceParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestTLSProfileConfig(t *testing.T) {
+	cfg := tlsProfileConfig("KEY", "CERT", "CA")
+
+	for _, want := range []string{
+		`set / system tls server-profile clab-profile key "KEY"`,
+		`set / system tls server-profile clab-profile certificate "CERT"`,
+		`set / system tls server-profile clab-profile trust-anchor "CA"`,
+	} {
+		if !strings.Contains(cfg, want) {
+			t.Errorf("config %q does not contain %q", cfg, want)
+		}
+	}
+
+	if !strings.HasSuffix(cfg, "commit save") {
+		t.Errorf("config %q is not committed", cfg)
+	}
+}
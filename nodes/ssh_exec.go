@@ -0,0 +1,384 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scrapli/scrapligo/driver/options"
+	"github.com/scrapli/scrapligo/platform"
+	"github.com/scrapli/scrapligo/transport"
+	"github.com/scrapli/scrapligo/util"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	// sshExecConnTimeout is the time to wait for the SSH connection to the node.
+	sshExecConnTimeout = 15 * time.Second
+	// sshExecOpsTimeout is the time the CLI command is allowed to run for when the context has no deadline.
+	sshExecOpsTimeout = 10 * time.Minute
+)
+
+// RunExecSSH executes the command on the node over SSH using the given credentials.
+// When the scrapligo platform name is set the command is sent to the node CLI by the platform driver,
+// and the return code is 1 when the output contains one of the platform failure messages.
+// Otherwise the command runs in an SSH session and its exit status is the return code.
+func (d *DefaultNode) RunExecSSH(ctx context.Context, execCmd *exec.ExecCmd, creds *Credentials,
+	platformName string,
+) (*exec.ExecResult, error) {
+	host, err := d.sshExecHost(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	addr := net.JoinHostPort(host, strconv.Itoa(d.Cfg.ExecConfig.GetPort()))
+
+	log.Debugf("%s: executing %q over SSH at %s", d.Cfg.ShortName, execCmd.GetCmdString(), addr)
+
+	knownHosts, err := d.sshKnownHostsFile(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if platformName != "" {
+		return runExecScrapli(ctx, execCmd, host, creds, platformName, d.Cfg.ExecConfig.GetPort(), knownHosts)
+	}
+
+	return runExecSSHSession(ctx, execCmd, addr, creds, knownHosts)
+}
+
+// sshKnownHostsFile returns the lab known_hosts file the SSH host key of the node at addr is verified against.
+// The host keys are pinned in the file on deploy. When the file doesn't exist or the node host key is not pinned in it,
+// an error is returned, unless the exec config of the node accepts the insecure host keys,
+// in which case an empty string is returned and the host key is not verified, which is logged as a warning.
+func (d *DefaultNode) sshKnownHostsFile(addr string) (string, error) {
+	tp := &types.TopoPaths{}
+	file := ""
+
+	var reason error
+
+	switch {
+	case d.Cfg.LabDir == "" || tp.SetLabDir(filepath.Dir(d.Cfg.LabDir)) != nil ||
+		!utils.FileExists(tp.KnownHostsFileAbsPath()):
+		reason = errors.New("lab known_hosts file is not found")
+	default:
+		file = tp.KnownHostsFileAbsPath()
+
+		pinned, err := knownHostsPinned(file, addr)
+
+		switch {
+		case err != nil:
+			reason = fmt.Errorf("failed to read the known_hosts file %s: %w", file, err)
+		case !pinned:
+			reason = fmt.Errorf("SSH host key of %s is not pinned in %s", addr, file)
+		}
+	}
+
+	if reason == nil {
+		return file, nil
+	}
+
+	if !d.Cfg.ExecConfig.GetInsecureHostKey() {
+		return "", fmt.Errorf("%s: %w, redeploy the lab to pin the host key "+
+			"or set insecure-host-key in the exec-config to skip the host key verification", d.Cfg.ShortName, reason)
+	}
+
+	log.Warnf("%s: %v, skipping SSH host key verification of %s", d.Cfg.ShortName, reason, addr)
+
+	return "", nil
+}
+
+// knownHostsPinned returns true when the known_hosts file has a host key of the host at addr.
+func knownHostsPinned(file, addr string) (bool, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return false, err
+	}
+
+	want := knownhosts.Normalize(addr)
+
+	for len(b) > 0 {
+		_, hosts, _, _, rest, err := ssh.ParseKnownHosts(b)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return false, err
+		}
+
+		for _, h := range hosts {
+			if knownhosts.Normalize(h) == want {
+				return true, nil
+			}
+		}
+
+		b = rest
+	}
+
+	return false, nil
+}
+
+// sshHostKeyCallback returns the callback verifying the host keys against the known_hosts file,
+// or accepting any host key when the file is not set.
+func sshHostKeyCallback(knownHosts string) (ssh.HostKeyCallback, error) {
+	if knownHosts == "" {
+		// the insecure host key is accepted, which is logged by sshKnownHostsFile
+		return ssh.InsecureIgnoreHostKey(), nil // skipcq: GSC-G106
+	}
+
+	cb, err := knownhosts.New(knownHosts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the known hosts: %w", err)
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		if err := cb(hostname, remote, key); err != nil {
+			return fmt.Errorf("host key of %s does not match the key pinned in %s, "+
+				"redeploy the lab if the node was recreated: %w", hostname, knownHosts, err)
+		}
+
+		return nil
+	}, nil
+}
+
+// sshExecHost returns the management address of the node, the IPv4 address is preferred.
+// The addresses assigned by the runtime are looked up when they are not set in the node config.
+func (d *DefaultNode) sshExecHost(ctx context.Context) (string, error) {
+	for _, a := range []string{d.Cfg.MgmtIPv4Address, d.Cfg.MgmtIPv6Address} {
+		if a != "" {
+			return a, nil
+		}
+	}
+
+	cnts, err := d.GetContainers(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if len(cnts) == 0 {
+		return "", fmt.Errorf("%s: container not found", d.Cfg.ShortName)
+	}
+
+	for _, a := range []string{cnts[0].NetworkSettings.IPv4addr, cnts[0].NetworkSettings.IPv6addr} {
+		if a != "" {
+			return a, nil
+		}
+	}
+
+	return "", fmt.Errorf("%s: no management address to connect to over SSH", d.Cfg.ShortName)
+}
+
+// runExecScrapli sends the command to the node CLI with the scrapligo platform driver.
+// The host key is verified against the known_hosts file when it is set.
+func runExecScrapli(ctx context.Context, execCmd *exec.ExecCmd, host string, creds *Credentials,
+	platformName string, port int, knownHosts string,
+) (*exec.ExecResult, error) {
+	opsTimeout := sshExecOpsTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		opsTimeout = time.Until(deadline)
+	}
+
+	opts := []util.Option{
+		options.WithAuthUsername(creds.GetUsername()),
+		options.WithAuthPassword(creds.GetPassword()),
+		options.WithTransportType(transport.StandardTransport),
+		options.WithPort(port),
+		options.WithTimeoutSocket(sshExecConnTimeout),
+		options.WithTimeoutOps(opsTimeout),
+	}
+
+	if creds.GetSSHKey() != "" {
+		opts = append(opts, options.WithAuthPrivateKey(creds.GetSSHKey(), ""))
+	}
+
+	if knownHosts != "" {
+		opts = append(opts, options.WithSSHKnownHostsFile(knownHosts))
+	} else {
+		opts = append(opts, options.WithAuthNoStrictKey())
+	}
+
+	p, err := platform.NewPlatform(platformName, host, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to create platform %q: %w", host, platformName, err)
+	}
+
+	drv, err := p.GetNetworkDriver()
+	if err != nil {
+		return nil, fmt.Errorf("%s: could not create the driver: %w", host, err)
+	}
+
+	var res *exec.ExecResult
+
+	err = runCancelable(ctx, func() error {
+		if err := drv.Open(); err != nil {
+			return err
+		}
+
+		rsp, err := drv.SendCommand(execCmd.GetCmdString())
+		if err != nil {
+			return err
+		}
+
+		res = exec.NewExecResult(execCmd)
+		res.SetStdOut([]byte(rsp.Result))
+
+		if rsp.Failed != nil {
+			res.SetReturnCode(1)
+			res.SetStdErr([]byte(rsp.Failed.Error()))
+		}
+
+		return nil
+	}, func() { _ = drv.Close() })
+	if err != nil {
+		return nil, err
+	}
+
+	_ = drv.Close()
+
+	return res, nil
+}
+
+// runExecSSHSession runs the command in an SSH session.
+// The arguments of the command are quoted for the remote shell.
+// The host key is verified against the known_hosts file when it is set.
+func runExecSSHSession(ctx context.Context, execCmd *exec.ExecCmd, addr string, creds *Credentials,
+	knownHosts string,
+) (*exec.ExecResult, error) {
+	hostKeyCallback, err := sshHostKeyCallback(knownHosts)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &ssh.ClientConfig{
+		User: creds.GetUsername(),
+		Auth: []ssh.AuthMethod{
+			ssh.Password(creds.GetPassword()),
+			ssh.KeyboardInteractive(func(_, _ string, questions []string, _ []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i := range answers {
+					answers[i] = creds.GetPassword()
+				}
+
+				return answers, nil
+			}),
+		},
+		HostKeyCallback: hostKeyCallback,
+	}
+
+	if creds.GetSSHKey() != "" {
+		key, err := os.ReadFile(creds.GetSSHKey())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the ssh private key: %w", err)
+		}
+
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the ssh private key %s: %w", creds.GetSSHKey(), err)
+		}
+
+		cfg.Auth = append([]ssh.AuthMethod{ssh.PublicKeys(signer)}, cfg.Auth...)
+	}
+
+	conn, err := (&net.Dialer{Timeout: sshExecConnTimeout}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	// closing the connection closes the client as well
+	defer conn.Close()
+
+	var res *exec.ExecResult
+
+	err = runCancelable(ctx, func() error {
+		c, chans, reqs, err := ssh.NewClientConn(conn, addr, cfg)
+		if err != nil {
+			return err
+		}
+
+		session, err := ssh.NewClient(c, chans, reqs).NewSession()
+		if err != nil {
+			return err
+		}
+		defer session.Close()
+
+		var stdout, stderr bytes.Buffer
+		session.Stdout = &stdout
+		session.Stderr = &stderr
+
+		res = exec.NewExecResult(execCmd)
+
+		err = session.Run(shellJoin(execCmd.GetCmd()))
+
+		var exitErr *ssh.ExitError
+		switch {
+		case errors.As(err, &exitErr):
+			res.SetReturnCode(exitErr.ExitStatus())
+		case err != nil:
+			return err
+		}
+
+		res.SetStdOut(stdout.Bytes())
+		res.SetStdErr(stderr.Bytes())
+
+		return nil
+	}, func() { _ = conn.Close() })
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// runCancelable runs f and calls abort when the context is done before f returns
+// to make it return early, the context error is returned then.
+func runCancelable(ctx context.Context, f func() error, abort func()) error {
+	done := make(chan error, 1)
+
+	go func() {
+		done <- f()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		abort()
+		<-done
+
+		return ctx.Err()
+	}
+}
+
+// shellJoin joins the command arguments into a command line for a POSIX shell,
+// quoting the arguments that contain characters special to the shell.
+func shellJoin(args []string) string {
+	quoted := make([]string, 0, len(args))
+
+	for _, a := range args {
+		if a != "" && !strings.ContainsAny(a, " \t\n\"'\\$`|&;<>()*?[]#~!{}") {
+			quoted = append(quoted, a)
+			continue
+		}
+
+		quoted = append(quoted, "'"+strings.ReplaceAll(a, "'", `'"'"'`)+"'")
+	}
+
+	return strings.Join(quoted, " ")
+}
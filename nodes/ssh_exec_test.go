@@ -0,0 +1,281 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/srl-labs/containerlab/clab/exec"
+	"github.com/srl-labs/containerlab/types"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// startTestSSHServer starts an SSH server accepting the admin/secret credentials
+// and returns its address and host key. The exec requests print the command to stdout and "warning" to stderr,
+// the commands containing "fail" exit with status 3 and the "sleep" command never returns.
+func startTestSSHServer(t *testing.T) (string, ssh.PublicKey) {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if c.User() == "admin" && string(pass) == "secret" {
+				return nil, nil
+			}
+
+			return nil, errors.New("access denied")
+		},
+	}
+	cfg.AddHostKey(signer)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go serveTestSSHConn(conn, cfg)
+		}
+	}()
+
+	return l.Addr().String(), signer.PublicKey()
+}
+
+func serveTestSSHConn(conn net.Conn, cfg *ssh.ServerConfig) {
+	defer conn.Close()
+
+	_, chans, reqs, err := ssh.NewServerConn(conn, cfg)
+	if err != nil {
+		return
+	}
+
+	go ssh.DiscardRequests(reqs)
+
+	for nc := range chans {
+		ch, reqs, err := nc.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer ch.Close()
+
+			for req := range reqs {
+				if req.Type != "exec" {
+					_ = req.Reply(false, nil)
+					continue
+				}
+
+				var payload struct{ Command string }
+				if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+					_ = req.Reply(false, nil)
+					return
+				}
+
+				_ = req.Reply(true, nil)
+
+				if payload.Command == "sleep" {
+					time.Sleep(time.Minute)
+					return
+				}
+
+				_, _ = ch.Write([]byte(payload.Command))
+				_, _ = ch.Stderr().Write([]byte("warning"))
+
+				status := struct{ Status uint32 }{}
+				if strings.Contains(payload.Command, "fail") {
+					status.Status = 3
+				}
+
+				_, _ = ch.SendRequest("exit-status", false, ssh.Marshal(status))
+
+				return
+			}
+		}()
+	}
+}
+
+func TestRunExecSSHSession(t *testing.T) {
+	addr, _ := startTestSSHServer(t)
+	creds := NewCredentials("admin", "secret")
+
+	tests := map[string]struct {
+		cmd        []string
+		wantStdout string
+		wantRC     int
+	}{
+		"quoted-args": {
+			cmd:        []string{"sh", "-c", "echo 'a' $HOME"},
+			wantStdout: `sh -c 'echo '"'"'a'"'"' $HOME'`,
+		},
+		"exit-status": {
+			cmd:        []string{"fail"},
+			wantStdout: "fail",
+			wantRC:     3,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := runExecSSHSession(context.Background(), exec.NewExecCmdFromSlice(tt.cmd), addr, creds, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(res.Stdout) != tt.wantStdout || res.Stderr != "warning" || res.ReturnCode != tt.wantRC {
+				t.Errorf("unexpected result %+v", res)
+			}
+		})
+	}
+
+	t.Run("wrong-password", func(t *testing.T) {
+		_, err := runExecSSHSession(context.Background(), exec.NewExecCmdFromSlice([]string{"ls"}), addr,
+			NewCredentials("admin", "wrong"), "")
+		if err == nil || !strings.Contains(err.Error(), "unable to authenticate") {
+			t.Errorf("expected authentication error, got %v", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+
+		_, err := runExecSSHSession(ctx, exec.NewExecCmdFromSlice([]string{"sleep"}), addr, creds, "")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded error, got %v", err)
+		}
+
+		if time.Since(start) > 5*time.Second {
+			t.Errorf("the command wasn't canceled in time")
+		}
+	})
+}
+
+func TestSSHKnownHosts(t *testing.T) {
+	addr, hostKey := startTestSSHServer(t)
+	creds := NewCredentials("admin", "secret")
+
+	labDir := t.TempDir()
+	d := &DefaultNode{Cfg: &types.NodeConfig{ShortName: "node1", LabDir: filepath.Join(labDir, "node1")}}
+
+	if _, err := d.sshKnownHostsFile(addr); err == nil || !strings.Contains(err.Error(), "known_hosts file is not found") {
+		t.Errorf("expected the missing known_hosts file error, got %v", err)
+	}
+
+	insecure := &DefaultNode{Cfg: &types.NodeConfig{
+		ShortName:  "node1",
+		LabDir:     d.Cfg.LabDir,
+		ExecConfig: &types.ExecConfig{InsecureHostKey: true},
+	}}
+
+	if f, err := insecure.sshKnownHostsFile(addr); f != "" || err != nil {
+		t.Errorf("expected no known_hosts file with the insecure host keys accepted, got %q, %v", f, err)
+	}
+
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherSigner, err := ssh.NewSignerFromKey(otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		key      ssh.PublicKey
+		addr     string
+		pinned   bool
+		insecure bool
+		wantErr  string
+	}{
+		"pinned":            {key: hostKey, addr: addr, pinned: true},
+		"mismatch":          {key: otherSigner.PublicKey(), addr: addr, pinned: true, wantErr: "does not match the key pinned"},
+		"unpinned":          {key: hostKey, addr: "127.0.0.2:22", wantErr: "is not pinned"},
+		"unpinned insecure": {key: hostKey, addr: "127.0.0.2:22", insecure: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			file := filepath.Join(labDir, "known_hosts")
+			if err := os.WriteFile(file, []byte("# node1\n"+knownhosts.Line([]string{tt.addr}, tt.key)+"\n"), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			n := d
+			if tt.insecure {
+				n = insecure
+			}
+
+			knownHosts, err := n.sshKnownHostsFile(addr)
+			if err != nil {
+				if tt.wantErr == "" || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if (knownHosts != "") != tt.pinned {
+				t.Fatalf("expected pinned %v, got known_hosts file %q", tt.pinned, knownHosts)
+			}
+
+			_, err = runExecSSHSession(context.Background(), exec.NewExecCmdFromSlice([]string{"ls"}), addr, creds,
+				knownHosts)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestShellJoin(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"plain":  {args: []string{"ip", "-br", "a"}, want: "ip -br a"},
+		"spaces": {args: []string{"sh", "-c", "show version"}, want: "sh -c 'show version'"},
+		"quote":  {args: []string{"echo", "it's"}, want: `echo 'it'"'"'s'`},
+		"empty":  {args: []string{"echo", ""}, want: "echo ''"},
+		"pipe":   {args: []string{"a|b"}, want: "'a|b'"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := shellJoin(tt.args); got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
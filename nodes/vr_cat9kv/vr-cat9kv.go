@@ -39,7 +39,8 @@ const (
 // Register registers the node in the NodeRegistry.
 func Register(r *nodes.NodeRegistry) {
 	generateNodeAttributes := nodes.NewGenerateNodeAttributes(generateable, generateIfFormat)
-	nrea := nodes.NewNodeRegistryEntryAttributes(defaultCredentials, generateNodeAttributes)
+	nrea := nodes.NewNodeRegistryEntryAttributes(defaultCredentials, generateNodeAttributes).
+		WithInterfaceRegexp(nodes.InterfaceRegexpOf(nodes.VMInterfaceRegexp, InterfaceRegexp))
 
 	r.Register(kindNames, func() nodes.Node {
 		return new(vrCat9kv)
@@ -52,7 +53,7 @@ type vrCat9kv struct {
 
 func (n *vrCat9kv) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 	// Init VRNode
-	n.VRNode = *nodes.NewVRNode(n)
+	n.VRNode = *nodes.NewVRNode(n, defaultCredentials, scrapliPlatformName)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
 
@@ -60,11 +61,12 @@ func (n *vrCat9kv) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 	for _, o := range opts {
 		o(n)
 	}
+	creds := n.Credentials(defaultCredentials)
 	// env vars are used to set launch.py arguments in vrnetlab container
 	defEnv := map[string]string{
 		"CONNECTION_MODE":    nodes.VrDefConnMode,
-		"USERNAME":           defaultCredentials.GetUsername(),
-		"PASSWORD":           defaultCredentials.GetPassword(),
+		"USERNAME":           creds.GetUsername(),
+		"PASSWORD":           creds.GetPassword(),
 		"DOCKER_NET_V4_ADDR": n.Mgmt.IPv4Subnet,
 		"DOCKER_NET_V6_ADDR": n.Mgmt.IPv6Subnet,
 		"VCPU":               "4",
@@ -101,9 +103,11 @@ func (s *vrCat9kv) PreDeploy(_ context.Context, params *nodes.PreDeployParams) e
 }
 
 func (n *vrCat9kv) SaveConfig(_ context.Context) error {
+	creds := n.Credentials(defaultCredentials)
 	err := netconf.SaveConfig(n.Cfg.LongName,
-		defaultCredentials.GetUsername(),
-		defaultCredentials.GetPassword(),
+		creds.GetUsername(),
+		creds.GetPassword(),
+		creds.GetSSHKey(),
 		scrapliPlatformName,
 	)
 	if err != nil {
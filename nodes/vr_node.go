@@ -1,21 +1,34 @@
 package nodes
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/clab/exec"
 	"github.com/srl-labs/containerlab/links"
+	"github.com/srl-labs/containerlab/types"
 )
 
 var VMInterfaceRegexp = regexp.MustCompile(`eth[1-9][0-9]*$`) // skipcq: GO-C4007
 
 type VRNode struct {
 	DefaultNode
+	// defaultCredentials are the credentials of the kind used to execute the commands over SSH.
+	defaultCredentials *Credentials
+	// scrapliPlatform is the scrapligo platform of the kind driving the CLI of the VM over SSH,
+	// empty if the commands are executed in a plain SSH session.
+	scrapliPlatform string
 }
 
-func NewVRNode(n NodeOverwrites) *VRNode {
-	vr := &VRNode{}
+// NewVRNode creates a VRNode with the default credentials and the scrapligo platform of the kind,
+// which are used when the node commands are executed over SSH.
+func NewVRNode(n NodeOverwrites, defaultCredentials *Credentials, scrapliPlatform string) *VRNode {
+	vr := &VRNode{
+		defaultCredentials: defaultCredentials,
+		scrapliPlatform:    scrapliPlatform,
+	}
 
 	vr.DefaultNode = *NewDefaultNode(n)
 
@@ -63,3 +76,25 @@ func (vr *VRNode) CheckInterfaceName() error {
 
 	return nil
 }
+
+// RunExec executes the command in the vrnetlab container,
+// or in the VM over SSH when the ssh exec transport is configured for the node.
+func (vr *VRNode) RunExec(ctx context.Context, execCmd *exec.ExecCmd) (*exec.ExecResult, error) {
+	if vr.Cfg.ExecConfig.GetTransport() != types.ExecTransportSSH {
+		return vr.DefaultNode.RunExec(ctx, execCmd)
+	}
+
+	platformName := vr.scrapliPlatform
+	if vr.Cfg.ExecConfig.Platform != "" {
+		platformName = vr.Cfg.ExecConfig.Platform
+	}
+
+	res, err := vr.RunExecSSH(ctx, execCmd, vr.Credentials(vr.defaultCredentials), platformName)
+	if err != nil {
+		log.Errorf("%s: failed to execute cmd: %q over SSH with error %v",
+			vr.Cfg.ShortName, execCmd.GetCmdString(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
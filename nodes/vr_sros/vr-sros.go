@@ -60,7 +60,8 @@ type SROSTemplateData struct {
 // Register registers the node in the NodeRegistry.
 func Register(r *nodes.NodeRegistry) {
 	generateNodeAttributes := nodes.NewGenerateNodeAttributes(generateable, generateIfFormat)
-	nrea := nodes.NewNodeRegistryEntryAttributes(defaultCredentials, generateNodeAttributes)
+	nrea := nodes.NewNodeRegistryEntryAttributes(defaultCredentials, generateNodeAttributes).
+		WithInterfaceRegexp(nodes.InterfaceRegexpOf(nodes.VMInterfaceRegexp, InterfaceRegexp))
 	r.Register(kindNames, func() nodes.Node {
 		return new(vrSROS)
 	}, nrea)
@@ -74,7 +75,7 @@ type vrSROS struct {
 
 func (s *vrSROS) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 	// Init DefaultNode
-	s.VRNode = *nodes.NewVRNode(s)
+	s.VRNode = *nodes.NewVRNode(s, defaultCredentials, scrapliPlatformName)
 	// set virtualization requirement
 	s.HostRequirements.VirtRequired = true
 	s.LicensePolicy = types.LicensePolicyWarn
@@ -170,10 +171,11 @@ func (s *vrSROS) PostDeploy(ctx context.Context, _ *nodes.PostDeployParams) erro
 		}
 	}
 
+	creds := s.Credentials(defaultCredentials)
 	// apply the aggregated config snippets
 	if b.Len() > 0 {
 		err := s.applyPartialConfig(ctx, s.Cfg.MgmtIPv4Address, scrapliPlatformName,
-			defaultCredentials.GetUsername(), defaultCredentials.GetPassword(),
+			creds.GetUsername(), creds.GetPassword(),
 			b,
 		)
 		if err != nil {
@@ -185,9 +187,11 @@ func (s *vrSROS) PostDeploy(ctx context.Context, _ *nodes.PostDeployParams) erro
 }
 
 func (s *vrSROS) SaveConfig(_ context.Context) error {
+	creds := s.Credentials(defaultCredentials)
 	err := netconf.SaveConfig(s.Cfg.LongName,
-		defaultCredentials.GetUsername(),
-		defaultCredentials.GetPassword(),
+		creds.GetUsername(),
+		creds.GetPassword(),
+		creds.GetSSHKey(),
 		scrapliPlatformName,
 	)
 	if err != nil {
@@ -240,7 +244,8 @@ func nodeConfigExists(labDir string) bool {
 func (s *vrSROS) isHealthy(ctx context.Context) bool {
 	ex := exec.NewExecCmdFromSlice([]string{"grep", "0 running", "/health"})
 
-	res, err := s.RunExec(ctx, ex)
+	// the health file is in the vrnetlab container
+	res, err := s.DefaultNode.RunExec(ctx, ex)
 	if err != nil {
 		return false
 	}
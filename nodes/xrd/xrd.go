@@ -41,6 +41,9 @@ var (
 
 	//go:embed xrd.cfg
 	cfgTemplate string
+
+	// ifNameRegexp matches the Gi0-0-0-X interface names.
+	ifNameRegexp = regexp.MustCompile(`^Gi0-0-0-\d+$`)
 )
 
 const (
@@ -53,7 +56,9 @@ const (
 // Register registers the node in the NodeRegistry.
 func Register(r *nodes.NodeRegistry) {
 	generateNodeAttributes := nodes.NewGenerateNodeAttributes(generateable, generateIfFormat)
-	nrea := nodes.NewNodeRegistryEntryAttributes(defaultCredentials, generateNodeAttributes)
+	nrea := nodes.NewNodeRegistryEntryAttributes(defaultCredentials, generateNodeAttributes).
+		WithMgmtPorts(map[string]int{nodes.MgmtServiceGNMI: 9339}).
+		WithInterfaceRegexp(ifNameRegexp)
 
 	r.Register(kindNames, func() nodes.Node {
 		return new(xrd)
@@ -120,9 +125,11 @@ func (n *xrd) PostDeploy(_ context.Context, _ *nodes.PostDeployParams) error {
 }
 
 func (n *xrd) SaveConfig(_ context.Context) error {
+	creds := n.Credentials(defaultCredentials)
 	err := netconf.SaveConfig(n.Cfg.LongName,
-		defaultCredentials.GetUsername(),
-		defaultCredentials.GetPassword(),
+		creds.GetUsername(),
+		creds.GetPassword(),
+		creds.GetSSHKey(),
 		scrapliPlatformName,
 	)
 	if err != nil {
@@ -191,9 +198,8 @@ func (n *xrd) genInterfacesEnv() {
 
 // CheckInterfaceName checks if a name of the interface referenced in the topology file correct.
 func (n *xrd) CheckInterfaceName() error {
-	ifRe := regexp.MustCompile(`^Gi0-0-0-\d+$`)
 	for _, e := range n.Endpoints {
-		if !ifRe.MatchString(e.GetIfaceName()) {
+		if !ifNameRegexp.MatchString(e.GetIfaceName()) {
 			return fmt.Errorf("cisco XRd interface name %q doesn't match the required pattern. XRd interfaces should be named as Gi0-0-0-X where X is the interface number", e.GetIfaceName())
 		}
 	}
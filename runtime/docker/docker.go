@@ -187,7 +187,7 @@ func (d *DockerRuntime) CreateNet(ctx context.Context) (err error) {
 
 	log.Debugf("Docker network %q, bridge name %q", d.mgmt.Network, bridgeName)
 
-	return d.postCreateNetActions()
+	return d.postCreateNetActions(nctx)
 }
 
 // skipcq: GO-R1005
@@ -324,7 +324,7 @@ func getMgmtBridgeIPs(bridgeName string, netResource networkapi.Inspect) (string
 }
 
 // postCreateNetActions performs additional actions after the network has been created.
-func (d *DockerRuntime) postCreateNetActions() (err error) {
+func (d *DockerRuntime) postCreateNetActions(ctx context.Context) (err error) {
 	log.Debug("Disable RPF check on the docker host")
 	err = setSysctl("net/ipv4/conf/all/rp_filter", 0)
 	if err != nil {
@@ -353,6 +353,12 @@ func (d *DockerRuntime) postCreateNetActions() (err error) {
 		log.Warnf("errors during iptables rules install: %v", err)
 	}
 
+	// isolation rules are installed after the forwarding rule to take precedence over it.
+	// unlike the forwarding rule, failing to install them is an error
+	if err := d.installIsolationRules(ctx); err != nil {
+		return fmt.Errorf("failed to install management network isolation rules: %w", err)
+	}
+
 	return nil
 }
 
@@ -390,6 +396,11 @@ func (d *DockerRuntime) DeleteNet(ctx context.Context) (err error) {
 		log.Warnf("errors during iptables rules removal: %v", err)
 	}
 
+	err = d.deleteIsolationRules()
+	if err != nil {
+		log.Warnf("errors during isolation rules removal: %v", err)
+	}
+
 	return nil
 }
 
@@ -530,6 +541,15 @@ func (d *DockerRuntime) CreateContainer(ctx context.Context, node *types.NodeCon
 	if err != nil {
 		return "", err
 	}
+
+	// extra networks can only be used alongside the management network
+	if containerHostConfig.NetworkMode == container.NetworkMode(d.mgmt.Network) {
+		err = d.connectExtraNets(nctx, cont.ID, node)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	return cont.ID, nil
 }
 
@@ -627,6 +647,9 @@ func (d *DockerRuntime) postStartActions(ctx context.Context, cID string, node *
 	if err != nil {
 		return err
 	}
+	if err = d.renameExtraNetIfaces(ctx, cID, nspath, node); err != nil {
+		return fmt.Errorf("failed to name the extra network interfaces of %q: %w", node.ShortName, err)
+	}
 	err = utils.LinkContainerNS(nspath, node.LongName)
 	return err
 }
@@ -1,8 +1,18 @@
 package docker
 
 import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/filters"
+	networkapi "github.com/docker/docker/api/types/network"
+
 	log "github.com/sirupsen/logrus"
 	"github.com/srl-labs/containerlab/runtime/docker/firewall"
+	"github.com/srl-labs/containerlab/runtime/docker/firewall/definitions"
 )
 
 // deleteFwdRule deletes `allow` rule installed with installFwdRule when the bridge interface doesn't exist anymore.
@@ -40,3 +50,81 @@ func (d *DockerRuntime) installFwdRule() (err error) {
 
 	return f.InstallForwardingRules()
 }
+
+// installIsolationRules installs the rules isolating the management bridge
+// from the other container runtime bridges and restricting its egress traffic
+// when the isolation policy is enabled.
+func (d *DockerRuntime) installIsolationRules(ctx context.Context) error {
+	if !d.mgmt.IsolationEnabled() {
+		return nil
+	}
+
+	if d.mgmt.Bridge == "" || d.mgmt.Bridge == "docker0" {
+		return fmt.Errorf("management network isolation requires a dedicated bridge, got %q", d.mgmt.Bridge)
+	}
+
+	egressAllow := make([]netip.Prefix, 0, len(d.mgmt.Isolation.EgressAllow))
+	for _, s := range d.mgmt.Isolation.EgressAllow {
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return fmt.Errorf("invalid egress-allow prefix %q, an IPv4 or IPv6 prefix is expected", s)
+		}
+
+		egressAllow = append(egressAllow, p.Masked())
+	}
+
+	peers, err := d.bridgePeers(ctx)
+	if err != nil {
+		return err
+	}
+
+	f, err := firewall.NewFirewallClient(d.mgmt.Bridge)
+	if err != nil {
+		return err
+	}
+	log.Debugf("using %s as the firewall interface", f.Name())
+
+	return f.InstallIsolationRules(peers, egressAllow)
+}
+
+// bridgePeers returns the names of the docker bridges the management bridge is isolated from,
+// which don't have the runtime bridge prefix, such as docker0 and the user named management bridges.
+// The bridges with the runtime bridge prefix are matched by the prefix.
+func (d *DockerRuntime) bridgePeers(ctx context.Context) ([]string, error) {
+	nets, err := d.Client.NetworkList(ctx, networkapi.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("driver", "bridge")),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the docker bridge networks: %w", err)
+	}
+
+	peers := make([]string, 0, len(nets))
+
+	for _, n := range nets {
+		name := n.Options["com.docker.network.bridge.name"]
+		if name == "" || name == d.mgmt.Bridge || strings.HasPrefix(name, definitions.RuntimeBridgePrefix) {
+			continue
+		}
+
+		peers = append(peers, name)
+	}
+
+	sort.Strings(peers)
+
+	return peers, nil
+}
+
+// deleteIsolationRules deletes the rules installed with installIsolationRules
+// when the bridge interface doesn't exist anymore.
+func (d *DockerRuntime) deleteIsolationRules() error {
+	if !d.mgmt.IsolationEnabled() || d.mgmt.Bridge == "" {
+		return nil
+	}
+
+	f, err := firewall.NewFirewallClient(d.mgmt.Bridge)
+	if err != nil {
+		return err
+	}
+
+	return f.DeleteIsolationRules()
+}
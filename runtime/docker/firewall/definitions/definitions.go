@@ -1,6 +1,9 @@
 package definitions
 
-import "errors"
+import (
+	"errors"
+	"net/netip"
+)
 
 var ErrNotAvailable = errors.New("not available")
 
@@ -8,14 +11,99 @@ const (
 	DockerFWUserChain = "DOCKER-USER"
 	DockerFWTable     = "filter"
 
+	// DockerFWIsolationChain is the chain of the isolation rules of all management bridges,
+	// jumped to by the first rule of the DOCKER-USER chain.
+	DockerFWIsolationChain = "CLAB-ISOLATION"
+
 	IPTablesRuleComment = "set by containerlab"
 
 	IPTablesCommentMaxSize = 256
+
+	// IsolationJumpComment is the comment of the DOCKER-USER rule jumping to the isolation chain.
+	IsolationJumpComment = IPTablesRuleComment + " isolation"
+
+	// IsolationRuleCommentPrefix prefixes the comments of the isolation rules,
+	// the comment is completed with the name of the isolated management bridge.
+	IsolationRuleCommentPrefix = IsolationJumpComment + " "
+
+	// RuntimeBridgePrefix is the prefix of the bridges created by the container runtime.
+	RuntimeBridgePrefix = "br-"
 )
 
+// IsolationRuleComment returns the comment the isolation rules of the bridge are tagged with.
+func IsolationRuleComment(bridgeName string) string {
+	return IsolationRuleCommentPrefix + bridgeName
+}
+
 // ClabFirewall is the interface that all firewall clients must implement.
 type ClabFirewall interface {
 	DeleteForwardingRules() error
 	InstallForwardingRules() error
+	// DeleteIsolationRules deletes the isolation rules of the bridge.
+	DeleteIsolationRules() error
+	// InstallIsolationRules installs the IPv4 and IPv6 rules blocking the traffic between the bridge
+	// and the runtime bridges and the peer bridges. If egressAllow is not empty,
+	// the traffic leaving the bridge is only allowed towards the listed prefixes.
+	// The rules are installed in the isolation chain, which is jumped to first from the DOCKER-USER chain,
+	// so they take precedence over the forwarding rules of all bridges.
+	InstallIsolationRules(peers []string, egressAllow []netip.Prefix) error
 	Name() string
 }
+
+// IsolationRule is a rule of the management bridge isolation policy.
+type IsolationRule struct {
+	// InIface and OutIface match the input and output interface names,
+	// the names ending with + match all interfaces with the given prefix.
+	InIface  string
+	OutIface string
+	// Established matches the packets of the established and related connections.
+	Established bool
+	// Dst matches the destination prefix.
+	Dst netip.Prefix
+	// Return returns the matched packets to the DOCKER-USER chain, otherwise they are dropped.
+	Return bool
+}
+
+// BridgeIsolationRules returns the rules dropping the traffic between the bridge
+// and the runtime bridges and the peer bridges, in the order they are evaluated.
+// The peers are the bridges not named with the runtime bridge prefix, such as the user named management bridges.
+// The bridge rules of all management bridges must be evaluated before their egress rules,
+// since the egress rules return the allowed traffic without checking the other bridges.
+func BridgeIsolationRules(bridgeName string, peers []string) []IsolationRule {
+	// the bridge itself has the runtime bridge prefix unless it is named by the user
+	rules := []IsolationRule{{InIface: bridgeName, OutIface: bridgeName, Return: true}}
+
+	for _, p := range append([]string{RuntimeBridgePrefix + "+"}, peers...) {
+		if p == bridgeName || p == "" {
+			continue
+		}
+
+		rules = append(rules,
+			IsolationRule{InIface: bridgeName, OutIface: p},
+			IsolationRule{InIface: p, OutIface: bridgeName},
+		)
+	}
+
+	return rules
+}
+
+// EgressRules returns the IPv4 or IPv6 rules restricting the traffic leaving the bridge
+// in the order they are evaluated. The traffic is dropped unless it is destined to the allowed prefixes
+// of the address family or belongs to the connections established from the outside.
+func EgressRules(bridgeName string, egressAllow []netip.Prefix, ipv6 bool) []IsolationRule {
+	if len(egressAllow) == 0 {
+		return nil
+	}
+
+	rules := []IsolationRule{{InIface: bridgeName, Established: true, Return: true}}
+
+	for _, p := range egressAllow {
+		if p.Addr().Is6() != ipv6 {
+			continue
+		}
+
+		rules = append(rules, IsolationRule{InIface: bridgeName, Dst: p, Return: true})
+	}
+
+	return append(rules, IsolationRule{InIface: bridgeName})
+}
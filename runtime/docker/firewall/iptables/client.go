@@ -2,8 +2,11 @@ package iptables
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"net/netip"
 	"os/exec"
+	"slices"
 	"strings"
 
 	"github.com/google/shlex"
@@ -14,11 +17,28 @@ import (
 
 const (
 	iptCheckCmd = "-vL DOCKER-USER"
+	iptListCmd  = "-S DOCKER-USER"
 	iptAllowCmd = "-I DOCKER-USER -o %s -j ACCEPT -m comment --comment \"" + definitions.IPTablesRuleComment + "\""
 	iptDelCmd   = "-D DOCKER-USER -o %s -j ACCEPT -m comment --comment \"" + definitions.IPTablesRuleComment + "\""
 	ipTables    = "ip_tables"
+
+	iptablesBin  = "iptables"
+	ip6tablesBin = "ip6tables"
 )
 
+// run runs the iptables binary with the args and returns its standard output,
+// the standard error is added to the returned error.
+var run = func(bin string, args ...string) ([]byte, error) {
+	out, err := exec.Command(bin, args...).Output()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		err = fmt.Errorf("%w: %s", err, bytes.TrimSpace(exitErr.Stderr))
+	}
+
+	return out, err
+}
+
 // IpTablesClient is a client for iptables.
 type IpTablesClient struct {
 	bridgeName string
@@ -49,28 +69,32 @@ func (*IpTablesClient) Name() string {
 
 // InstallForwardingRules installs the forwarding rules.
 func (c *IpTablesClient) InstallForwardingRules() error {
-	// first check if a rule already exists to not create duplicates
-	res, err := exec.Command("iptables", strings.Split(iptCheckCmd, " ")...).Output()
-	if bytes.Contains(res, []byte(c.bridgeName)) {
-		log.Debugf("found iptables forwarding rule targeting the bridge %q. Skipping creation of the forwarding rule.", c.bridgeName)
+	if err := checkUserChain(); err != nil {
 		return err
 	}
-	if err != nil {
-		// non nil error typically means that DOCKER-USER chain doesn't exist
-		// this happens with old docker installations (centos7 hello) from default repos
-		return fmt.Errorf("missing DOCKER-USER iptables chain. See http://containerlab.dev/manual/network/#external-access")
-	}
 
 	cmd, err := shlex.Split(fmt.Sprintf(iptAllowCmd, c.bridgeName))
 	if err != nil {
 		return err
 	}
 
+	// first check if a rule already exists to not create duplicates.
+	// the check is done for the exact rule, since the isolation rules reference the bridge too
+	checkCmd := append([]string{"-C"}, cmd[1:]...)
+	if _, err := run(iptablesBin, checkCmd...); err == nil {
+		log.Debugf("found iptables forwarding rule targeting the bridge %q. Skipping creation of the forwarding rule.", c.bridgeName)
+		return nil
+	}
+
 	log.Debugf("Installing iptables rules for bridge %q", c.bridgeName)
 
-	stdOutErr, err := exec.Command("iptables", cmd...).CombinedOutput()
-	if err != nil {
-		log.Warnf("Iptables install stdout/stderr result is: %s", stdOutErr)
+	// the rule is inserted below the jump to the isolation chain for the isolation rules to take precedence over it
+	if res, err := run(iptablesBin, "-S", definitions.DockerFWUserChain); err == nil &&
+		firstRuleHasComment(res, definitions.IsolationJumpComment) {
+		cmd = append([]string{cmd[0], cmd[1], "2"}, cmd[2:]...)
+	}
+
+	if _, err := run(iptablesBin, cmd...); err != nil {
 		return fmt.Errorf("unable to install iptables rule using '%s' command: %w", cmd, err)
 	}
 
@@ -79,15 +103,18 @@ func (c *IpTablesClient) InstallForwardingRules() error {
 
 // DeleteForwardingRules deletes the forwarding rules.
 func (c *IpTablesClient) DeleteForwardingRules() error {
-	// first check if a rule exists before trying to delete it
-	res, err := exec.Command("iptables", strings.Split(iptCheckCmd, " ")...).Output()
+	if err := checkUserChain(); err != nil {
+		return err
+	}
+
+	cmd, err := shlex.Split(fmt.Sprintf(iptDelCmd, c.bridgeName))
 	if err != nil {
-		// non nil error typically means that DOCKER-USER chain doesn't exist
-		// this happens with old docker installations (centos7 hello) from default repos
-		return fmt.Errorf("missing DOCKER-USER iptables chain. See http://containerlab.dev/manual/network/#external-access")
+		return err
 	}
 
-	if !bytes.Contains(res, []byte(c.bridgeName)) {
+	// first check if a rule exists before trying to delete it
+	checkCmd := append([]string{"-C"}, cmd[1:]...)
+	if _, err := run(iptablesBin, checkCmd...); err != nil {
 		log.Debug("external access iptables rule doesn't exist. Skipping deletion")
 		return nil
 	}
@@ -101,19 +128,286 @@ func (c *IpTablesClient) DeleteForwardingRules() error {
 		return nil
 	}
 
-	cmd, err := shlex.Split(fmt.Sprintf(iptDelCmd, c.bridgeName))
+	log.Debugf("removing clab iptables rules for bridge %q", c.bridgeName)
+	log.Debugf("trying to delete the forwarding rule with cmd: iptables %s", cmd)
+
+	if _, err := run(iptablesBin, cmd...); err != nil {
+		return fmt.Errorf("unable to delete iptables rules: %w", err)
+	}
+
+	return nil
+}
+
+// InstallIsolationRules installs the isolation rules of the bridge replacing the existing ones,
+// so that the changes of the peers and the allowed egress prefixes are applied on redeploy.
+// The bridge rules are inserted at the top of the isolation chain and the egress rules are appended to it,
+// so the bridge rules of all bridges are evaluated first, and the jump to the isolation chain
+// is moved to the top of the DOCKER-USER chain above the forwarding rules of the bridges deployed since.
+// The IPv4 rules are installed with iptables and the IPv6 rules with ip6tables.
+func (c *IpTablesClient) InstallIsolationRules(peers []string, egressAllow []netip.Prefix) error {
+	if err := checkUserChain(); err != nil {
+		return err
+	}
+
+	for _, bin := range []string{iptablesBin, ip6tablesBin} {
+		if bin == ip6tablesBin && !userChainExists(bin) {
+			log.Warnf("missing DOCKER-USER ip6tables chain, the IPv6 traffic of the bridge %q is not isolated", c.bridgeName)
+			continue
+		}
+
+		if _, err := run(bin, "-S", definitions.DockerFWIsolationChain); err != nil {
+			if _, err := run(bin, "-N", definitions.DockerFWIsolationChain); err != nil {
+				return fmt.Errorf("unable to create %s chain %s: %w", bin, definitions.DockerFWIsolationChain, err)
+			}
+		}
+
+		if err := c.deleteIsolationRules(bin); err != nil {
+			return err
+		}
+
+		log.Debugf("Installing %s isolation rules for bridge %q", bin, c.bridgeName)
+
+		comment := definitions.IsolationRuleComment(c.bridgeName)
+
+		var cmds [][]string
+
+		// bridge rules are inserted at the top of the chain, hence the reverse order
+		bridgeRules := definitions.BridgeIsolationRules(c.bridgeName, peers)
+		for i := len(bridgeRules) - 1; i >= 0; i-- {
+			cmds = append(cmds, isolationRuleArgs("-I", bridgeRules[i], comment))
+		}
+
+		for _, r := range definitions.EgressRules(c.bridgeName, egressAllow, bin == ip6tablesBin) {
+			cmds = append(cmds, isolationRuleArgs("-A", r, comment))
+		}
+
+		for _, cmd := range cmds {
+			if _, err := run(bin, cmd...); err != nil {
+				return fmt.Errorf("unable to install %s rule using '%s' command: %w", bin, cmd, err)
+			}
+		}
+
+		if err := installIsolationJump(bin); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteIsolationRules deletes the isolation rules of the bridge when the bridge doesn't exist anymore,
+// the isolation chain is deleted with the rules of the last bridge.
+func (c *IpTablesClient) DeleteIsolationRules() error {
+	if err := checkUserChain(); err != nil {
+		return err
+	}
+
+	// same as the forwarding rule, the isolation rules are kept while the bridge exists
+	if _, err := utils.BridgeByName(c.bridgeName); err == nil {
+		log.Debugf("bridge %s is still in use, not removing the isolation rules", c.bridgeName)
+		return nil
+	}
+
+	for _, bin := range []string{iptablesBin, ip6tablesBin} {
+		if bin == ip6tablesBin && !userChainExists(bin) {
+			continue
+		}
+
+		if _, err := run(bin, "-S", definitions.DockerFWIsolationChain); err != nil {
+			log.Debugf("%s chain %s doesn't exist. Skipping deletion", bin, definitions.DockerFWIsolationChain)
+			continue
+		}
+
+		if err := c.deleteIsolationRules(bin); err != nil {
+			return err
+		}
+
+		if err := deleteIsolationChain(bin); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteIsolationRules deletes the rules tagged with the isolation comment of the bridge
+// from the isolation chain and the DOCKER-USER chain using the given iptables binary.
+func (c *IpTablesClient) deleteIsolationRules(bin string) error {
+	for _, chain := range []string{definitions.DockerFWIsolationChain, definitions.DockerFWUserChain} {
+		if err := deleteRulesWithComment(bin, chain, definitions.IsolationRuleComment(c.bridgeName)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// installIsolationJump makes the jump to the isolation chain the first rule of the DOCKER-USER chain.
+func installIsolationJump(bin string) error {
+	res, err := run(bin, "-S", definitions.DockerFWUserChain)
+	if err != nil {
+		return fmt.Errorf("unable to list %s rules: %w", bin, err)
+	}
+
+	jumps, err := ruleDeleteArgs(res, definitions.IsolationJumpComment)
 	if err != nil {
 		return err
 	}
 
-	log.Debugf("removing clab iptables rules for bridge %q", c.bridgeName)
-	log.Debugf("trying to delete the forwarding rule with cmd: iptables %s", cmd)
+	if len(jumps) == 1 && firstRuleHasComment(res, definitions.IsolationJumpComment) {
+		return nil
+	}
+
+	for _, cmd := range jumps {
+		if _, err := run(bin, cmd...); err != nil {
+			return fmt.Errorf("unable to delete %s rule using '%s' command: %w", bin, cmd, err)
+		}
+	}
+
+	cmd := []string{
+		"-I", definitions.DockerFWUserChain, "-m", "comment", "--comment", definitions.IsolationJumpComment,
+		"-j", definitions.DockerFWIsolationChain,
+	}
+	if _, err := run(bin, cmd...); err != nil {
+		return fmt.Errorf("unable to install %s rule using '%s' command: %w", bin, cmd, err)
+	}
 
-	stdOutErr, err := exec.Command("iptables", cmd...).CombinedOutput()
+	return nil
+}
+
+// deleteIsolationChain deletes the isolation chain and the jump to it when the chain has no rules left.
+func deleteIsolationChain(bin string) error {
+	res, err := run(bin, "-S", definitions.DockerFWIsolationChain)
 	if err != nil {
-		log.Warnf("Iptables delete stdout/stderr result is: %s", stdOutErr)
-		return fmt.Errorf("unable to delete iptables rules: %w", err)
+		return fmt.Errorf("unable to list %s rules: %w", bin, err)
+	}
+
+	if bytes.Contains(res, []byte("-A "+definitions.DockerFWIsolationChain+" ")) {
+		return nil
+	}
+
+	log.Debugf("removing %s chain %s", bin, definitions.DockerFWIsolationChain)
+
+	if err := deleteRulesWithComment(bin, definitions.DockerFWUserChain, definitions.IsolationJumpComment); err != nil {
+		return err
+	}
+
+	if _, err := run(bin, "-X", definitions.DockerFWIsolationChain); err != nil {
+		return fmt.Errorf("unable to delete %s chain %s: %w", bin, definitions.DockerFWIsolationChain, err)
+	}
+
+	return nil
+}
+
+// deleteRulesWithComment deletes the rules tagged with the comment from the chain using the given iptables binary.
+func deleteRulesWithComment(bin, chain, comment string) error {
+	res, err := run(bin, "-S", chain)
+	if err != nil {
+		return fmt.Errorf("unable to list %s rules: %w", bin, err)
+	}
+
+	cmds, err := ruleDeleteArgs(res, comment)
+	if err != nil {
+		return err
+	}
+
+	for _, cmd := range cmds {
+		log.Debugf("trying to delete the isolation rule with cmd: %s %s", bin, cmd)
+
+		if _, err := run(bin, cmd...); err != nil {
+			return fmt.Errorf("unable to delete %s rules: %w", bin, err)
+		}
 	}
 
 	return nil
 }
+
+// firstRuleHasComment returns true if the first rule listed with `iptables -S` is tagged with the comment.
+func firstRuleHasComment(listing []byte, comment string) bool {
+	for _, line := range bytes.Split(listing, []byte("\n")) {
+		args, err := shlex.Split(string(line))
+		if err != nil || len(args) < 2 || args[0] != "-A" {
+			continue
+		}
+
+		idx := slices.Index(args, "--comment")
+
+		return idx >= 0 && idx+1 < len(args) && args[idx+1] == comment
+	}
+
+	return false
+}
+
+// userChainExists returns true if the DOCKER-USER chain exists in the tables of the iptables binary.
+func userChainExists(bin string) bool {
+	_, err := run(bin, strings.Split(iptCheckCmd, " ")...)
+	return err == nil
+}
+
+// checkUserChain returns an error if the DOCKER-USER chain doesn't exist.
+func checkUserChain() error {
+	if !userChainExists(iptablesBin) {
+		// the DOCKER-USER chain is typically missing
+		// with old docker installations (centos7 hello) from default repos
+		return fmt.Errorf("missing DOCKER-USER iptables chain. See http://containerlab.dev/manual/network/#external-access")
+	}
+
+	return nil
+}
+
+// isolationRuleArgs returns the iptables arguments inserting (-I) or appending (-A) the isolation rule
+// to the isolation chain.
+func isolationRuleArgs(op string, r definitions.IsolationRule, comment string) []string {
+	args := []string{op, definitions.DockerFWIsolationChain}
+
+	if r.InIface != "" {
+		args = append(args, "-i", r.InIface)
+	}
+
+	if r.OutIface != "" {
+		args = append(args, "-o", r.OutIface)
+	}
+
+	if r.Dst.IsValid() {
+		args = append(args, "-d", r.Dst.String())
+	}
+
+	if r.Established {
+		args = append(args, "-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED")
+	}
+
+	args = append(args, "-m", "comment", "--comment", comment)
+
+	if r.Return {
+		return append(args, "-j", "RETURN")
+	}
+
+	return append(args, "-j", "DROP")
+}
+
+// ruleDeleteArgs returns the iptables arguments deleting the rules with the given comment
+// from the rules listed with `iptables -S`.
+func ruleDeleteArgs(listing []byte, comment string) ([][]string, error) {
+	var res [][]string
+
+	for _, line := range bytes.Split(listing, []byte("\n")) {
+		args, err := shlex.Split(string(line))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(args) < 2 || args[0] != "-A" {
+			continue
+		}
+
+		idx := slices.Index(args, "--comment")
+		if idx < 0 || idx+1 >= len(args) || args[idx+1] != comment {
+			continue
+		}
+
+		args[0] = "-D"
+		res = append(res, args)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,361 @@
+package iptables
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/runtime/docker/firewall/definitions"
+)
+
+func TestIsolationRuleArgs(t *testing.T) {
+	comment := definitions.IsolationRuleComment("br-1")
+
+	egressAllow := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("2001:db8::/32")}
+
+	tests := map[string]struct {
+		bridge      string
+		peers       []string
+		egressAllow []netip.Prefix
+		ipv6        bool
+		want        []string
+	}{
+		"inter-lab isolation": {
+			bridge: "br-1",
+			want: []string{
+				"-i br-1 -o br-1 -j RETURN",
+				"-i br-1 -o br-+ -j DROP",
+				"-i br-+ -o br-1 -j DROP",
+			},
+		},
+		"user named bridges": {
+			bridge: "foo",
+			peers:  []string{"docker0", "foo", "bar"},
+			want: []string{
+				"-i foo -o foo -j RETURN",
+				"-i foo -o br-+ -j DROP",
+				"-i br-+ -o foo -j DROP",
+				"-i foo -o docker0 -j DROP",
+				"-i docker0 -o foo -j DROP",
+				"-i foo -o bar -j DROP",
+				"-i bar -o foo -j DROP",
+			},
+		},
+		"egress restriction": {
+			bridge:      "br-1",
+			egressAllow: egressAllow,
+			want: []string{
+				"-i br-1 -o br-1 -j RETURN",
+				"-i br-1 -o br-+ -j DROP",
+				"-i br-+ -o br-1 -j DROP",
+				"-i br-1 -m conntrack --ctstate RELATED,ESTABLISHED -j RETURN",
+				"-i br-1 -d 10.0.0.0/8 -j RETURN",
+				"-i br-1 -j DROP",
+			},
+		},
+		"ipv6 egress restriction": {
+			bridge:      "br-1",
+			egressAllow: egressAllow,
+			ipv6:        true,
+			want: []string{
+				"-i br-1 -o br-1 -j RETURN",
+				"-i br-1 -o br-+ -j DROP",
+				"-i br-+ -o br-1 -j DROP",
+				"-i br-1 -m conntrack --ctstate RELATED,ESTABLISHED -j RETURN",
+				"-i br-1 -d 2001:db8::/32 -j RETURN",
+				"-i br-1 -j DROP",
+			},
+		},
+		"ipv6 egress restriction without ipv6 prefixes": {
+			bridge:      "br-1",
+			egressAllow: egressAllow[:1],
+			ipv6:        true,
+			want: []string{
+				"-i br-1 -o br-1 -j RETURN",
+				"-i br-1 -o br-+ -j DROP",
+				"-i br-+ -o br-1 -j DROP",
+				"-i br-1 -m conntrack --ctstate RELATED,ESTABLISHED -j RETURN",
+				"-i br-1 -j DROP",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rules := append(definitions.BridgeIsolationRules(tt.bridge, tt.peers),
+				definitions.EgressRules(tt.bridge, tt.egressAllow, tt.ipv6)...)
+
+			var got []string
+			for _, r := range rules {
+				args := strings.Join(isolationRuleArgs("-A", r, comment), " ")
+				// strip the chain and the comment to keep the expectations short
+				args = strings.TrimPrefix(args, "-A CLAB-ISOLATION ")
+				args = strings.Replace(args, " -m comment --comment "+comment, "", 1)
+
+				got = append(got, args)
+			}
+
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Fatalf("rules mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+// fakeIPTables keeps the rules of the filter table chains
+// and evaluates the forwarded packets against them.
+type fakeIPTables struct {
+	chains map[string][][]string
+	order  []string
+}
+
+func newFakeIPTables() *fakeIPTables {
+	return &fakeIPTables{
+		chains: map[string][][]string{definitions.DockerFWUserChain: {{"-j", "RETURN"}}},
+		order:  []string{definitions.DockerFWUserChain},
+	}
+}
+
+// run implements the iptables commands used by the client, the ip6tables chains don't exist.
+func (f *fakeIPTables) run(bin string, args ...string) ([]byte, error) {
+	if bin != iptablesBin {
+		return nil, errors.New("no chain")
+	}
+
+	op, chain := args[0], args[1]
+	rules, exists := f.chains[chain]
+
+	if op != "-N" && !exists {
+		return nil, fmt.Errorf("no chain %s", chain)
+	}
+
+	switch op {
+	case "-vL":
+	case "-N":
+		f.chains[chain] = nil
+		f.order = append(f.order, chain)
+	case "-X":
+		if len(rules) != 0 {
+			return nil, fmt.Errorf("chain %s is not empty", chain)
+		}
+
+		delete(f.chains, chain)
+	case "-S":
+		var b strings.Builder
+
+		fmt.Fprintf(&b, "-N %s\n", chain)
+
+		for _, r := range rules {
+			fmt.Fprintf(&b, "-A %s", chain)
+
+			for _, a := range r {
+				if strings.Contains(a, " ") {
+					a = `"` + a + `"`
+				}
+
+				fmt.Fprintf(&b, " %s", a)
+			}
+
+			b.WriteString("\n")
+		}
+
+		return []byte(b.String()), nil
+	case "-A":
+		f.chains[chain] = append(rules, args[2:])
+	case "-I":
+		pos, spec := 0, args[2:]
+		if n, err := strconv.Atoi(spec[0]); err == nil {
+			pos, spec = n-1, spec[1:]
+		}
+
+		f.chains[chain] = slices.Insert(rules, pos, spec)
+	case "-C", "-D":
+		i := slices.IndexFunc(rules, func(r []string) bool { return slices.Equal(r, args[2:]) })
+		if i < 0 {
+			return nil, errors.New("no such rule")
+		}
+
+		if op == "-D" {
+			f.chains[chain] = slices.Delete(rules, i, i+1)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported command %v", args)
+	}
+
+	return nil, nil
+}
+
+// verdict returns the verdict of the DOCKER-USER chain for the packet forwarded between the interfaces.
+func (f *fakeIPTables) verdict(in, out string, dst netip.Addr) string {
+	v := f.eval(definitions.DockerFWUserChain, in, out, dst)
+	if v == "RETURN" {
+		// the packet continues to the docker chains
+		return "ACCEPT"
+	}
+
+	return v
+}
+
+func (f *fakeIPTables) eval(chain, in, out string, dst netip.Addr) string {
+	ifMatch := func(pattern, name string) bool {
+		if prefix, ok := strings.CutSuffix(pattern, "+"); ok {
+			return strings.HasPrefix(name, prefix)
+		}
+
+		return pattern == name
+	}
+
+	for _, r := range f.chains[chain] {
+		match, target := true, ""
+
+		for i := 0; i < len(r); i++ {
+			switch r[i] {
+			case "-i":
+				i++
+				match = match && ifMatch(r[i], in)
+			case "-o":
+				i++
+				match = match && ifMatch(r[i], out)
+			case "-d":
+				i++
+				match = match && netip.MustParsePrefix(r[i]).Contains(dst)
+			case "--ctstate":
+				// the packets are new connections
+				i++
+				match = false
+			case "-j":
+				i++
+				target = r[i]
+			}
+		}
+
+		if !match {
+			continue
+		}
+
+		switch target {
+		case "ACCEPT", "DROP", "RETURN":
+			return target
+		default:
+			if v := f.eval(target, in, out, dst); v != "RETURN" {
+				return v
+			}
+		}
+	}
+
+	return "RETURN"
+}
+
+func TestIsolationRulesOrdering(t *testing.T) {
+	f := newFakeIPTables()
+
+	orig := run
+	run = f.run
+	t.Cleanup(func() { run = orig })
+
+	install := func(bridge string, peers []string, egressAllow ...netip.Prefix) {
+		t.Helper()
+
+		c := &IpTablesClient{bridgeName: bridge}
+		if err := c.InstallForwardingRules(); err != nil {
+			t.Fatal(err)
+		}
+
+		if peers == nil && egressAllow == nil {
+			return
+		}
+
+		if err := c.InstallIsolationRules(peers, egressAllow); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// lab A is isolated, lab B with the user named bridge restricts its egress traffic
+	// and is deployed after lab A, lab C with the user named bridge is deployed after lab B,
+	// and lab D is not isolated and deployed last
+	install("br-aaa", []string{})
+	install("foo", []string{}, netip.MustParsePrefix("10.0.0.0/8"))
+	install("bar", []string{"foo"})
+	install("br-ddd", nil)
+
+	dst := netip.MustParseAddr("10.0.1.5")
+
+	tests := []struct {
+		in, out string
+		want    string
+	}{
+		{"br-aaa", "br-aaa", "ACCEPT"},
+		{"br-aaa", "eth0", "ACCEPT"},
+		{"eth0", "br-aaa", "ACCEPT"},
+		{"br-aaa", "foo", "DROP"},
+		{"foo", "br-aaa", "DROP"},
+		{"foo", "bar", "DROP"},
+		{"bar", "foo", "DROP"},
+		{"foo", "foo", "ACCEPT"},
+		{"foo", "eth0", "ACCEPT"},
+		{"bar", "br-aaa", "DROP"},
+		{"br-ddd", "br-aaa", "DROP"},
+		{"br-aaa", "br-ddd", "DROP"},
+		{"br-ddd", "foo", "DROP"},
+		{"eth0", "br-ddd", "ACCEPT"},
+	}
+
+	for _, tt := range tests {
+		if got := f.verdict(tt.in, tt.out, dst); got != tt.want {
+			t.Errorf("%s -> %s: got %s, want %s", tt.in, tt.out, got, tt.want)
+		}
+	}
+
+	if got := f.verdict("foo", "eth0", netip.MustParseAddr("192.0.2.1")); got != "DROP" {
+		t.Errorf("foo egress to the disallowed prefix: got %s, want DROP", got)
+	}
+
+	first := f.chains[definitions.DockerFWUserChain][0]
+	if !slices.Contains(first, definitions.DockerFWIsolationChain) {
+		t.Errorf("the first DOCKER-USER rule %v doesn't jump to the isolation chain", first)
+	}
+
+	// redeploying lab A keeps a single jump at the top
+	install("br-aaa", []string{"foo", "bar"})
+
+	jumps := 0
+	for _, r := range f.chains[definitions.DockerFWUserChain] {
+		if slices.Contains(r, definitions.DockerFWIsolationChain) {
+			jumps++
+		}
+	}
+
+	if jumps != 1 {
+		t.Errorf("got %d jumps to the isolation chain, want 1", jumps)
+	}
+}
+
+func TestRuleDeleteArgs(t *testing.T) {
+	listing := `-N DOCKER-USER
+-A DOCKER-USER -i br-1 -o br-+ -m comment --comment "set by containerlab isolation br-1" -j DROP
+-A DOCKER-USER -i br-12 -o br-+ -m comment --comment "set by containerlab isolation br-12" -j DROP
+-A DOCKER-USER -o br-1 -m comment --comment "set by containerlab" -j ACCEPT
+-A DOCKER-USER -j RETURN
+`
+
+	got, err := ruleDeleteArgs([]byte(listing), definitions.IsolationRuleComment("br-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{
+			"-D", "DOCKER-USER", "-i", "br-1", "-o", "br-+", "-m", "comment",
+			"--comment", "set by containerlab isolation br-1", "-j", "DROP",
+		},
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("delete args mismatch (-want +got):\n%s", d)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"net/netip"
 
 	"github.com/google/nftables"
 	"github.com/google/nftables/expr"
@@ -129,14 +130,244 @@ func (c *NftablesClient) InstallForwardingRules() error {
 	if err != nil {
 		return err
 	}
-	// mark and note for installation
-	c.insertRule(rule.rule)
+	// the rule is added below the jump to the isolation chain for the isolation rules to take precedence over it
+	if len(rules) != 0 && len(getRulesWithComment(definitions.IsolationJumpComment, rules[:1])) == 1 {
+		rule.rule.Position = rules[0].Handle
+		c.nftConn.AddRule(rule.rule)
+	} else {
+		// mark and note for installation
+		c.insertRule(rule.rule)
+	}
 	// flush changes out to nftables
 	c.flush()
 
 	return nil
 }
 
+// InstallIsolationRules installs the isolation rules of the bridge replacing the existing ones,
+// so that the changes of the peers and the allowed egress prefixes are applied on redeploy.
+// The bridge rules are inserted at the top of the isolation chain and the egress rules are appended to it,
+// so the bridge rules of all bridges are evaluated first, and the jump to the isolation chain
+// is moved to the top of the DOCKER-USER chain above the forwarding rules of the bridges deployed since.
+// The rules are installed in the IPv4 and IPv6 tables.
+func (c *NftablesClient) InstallIsolationRules(peers []string, egressAllow []netip.Prefix) error {
+	defer c.close()
+
+	comment := definitions.IsolationRuleComment(c.bridgeName)
+
+	for _, family := range []nftables.TableFamily{nftables.TableFamilyIPv4, nftables.TableFamilyIPv6} {
+		userChain, err := c.getChain(definitions.DockerFWUserChain, definitions.DockerFWTable, family)
+		if err != nil {
+			if family == nftables.TableFamilyIPv6 {
+				log.Warnf("missing IPv6 DOCKER-USER chain, the IPv6 traffic of the bridge %q is not isolated", c.bridgeName)
+				continue
+			}
+
+			return fmt.Errorf("%w. See http://containerlab.dev/manual/network/#external-access", err)
+		}
+
+		userRules, err := c.nftConn.GetRules(userChain.Table, userChain)
+		if err != nil {
+			return err
+		}
+
+		isoChain, err := c.getChain(definitions.DockerFWIsolationChain, definitions.DockerFWTable, family)
+		if err != nil {
+			isoChain = c.nftConn.AddChain(&nftables.Chain{
+				Name:  definitions.DockerFWIsolationChain,
+				Table: userChain.Table,
+			})
+		} else {
+			isoRules, err := c.nftConn.GetRules(isoChain.Table, isoChain)
+			if err != nil {
+				return err
+			}
+
+			for _, r := range getRulesWithComment(comment, isoRules) {
+				c.deleteRule(r)
+			}
+		}
+
+		for _, r := range getRulesWithComment(comment, userRules) {
+			c.deleteRule(r)
+		}
+
+		log.Debugf("Installing nftables isolation rules for bridge %q and family %v", c.bridgeName, family)
+
+		// bridge rules are inserted at the top of the chain, hence the reverse order
+		bridgeRules := definitions.BridgeIsolationRules(c.bridgeName, peers)
+		for i := len(bridgeRules) - 1; i >= 0; i-- {
+			rule, err := newIsolationRule(isoChain, bridgeRules[i], comment)
+			if err != nil {
+				return err
+			}
+
+			c.insertRule(rule.rule)
+		}
+
+		for _, r := range definitions.EgressRules(c.bridgeName, egressAllow, family == nftables.TableFamilyIPv6) {
+			rule, err := newIsolationRule(isoChain, r, comment)
+			if err != nil {
+				return err
+			}
+
+			c.nftConn.AddRule(rule.rule)
+		}
+
+		if err := c.installIsolationJump(userChain, userRules); err != nil {
+			return err
+		}
+	}
+
+	return c.flush()
+}
+
+// installIsolationJump makes the jump to the isolation chain the first rule of the DOCKER-USER chain.
+func (c *NftablesClient) installIsolationJump(userChain *nftables.Chain, userRules []*nftables.Rule) error {
+	jumps := getRulesWithComment(definitions.IsolationJumpComment, userRules)
+	if len(jumps) == 1 && userRules[0] == jumps[0] {
+		return nil
+	}
+
+	for _, r := range jumps {
+		c.deleteRule(r)
+	}
+
+	rule := &clabNftablesRule{rule: &nftables.Rule{Table: userChain.Table, Chain: userChain}}
+
+	if err := rule.AddComment(definitions.IsolationJumpComment); err != nil {
+		return err
+	}
+
+	if err := rule.AddCounter(); err != nil {
+		return err
+	}
+
+	rule.AddVerdictJump(definitions.DockerFWIsolationChain)
+	c.insertRule(rule.rule)
+
+	return nil
+}
+
+// DeleteIsolationRules deletes the isolation rules of the bridge when the bridge doesn't exist anymore,
+// the isolation chain is deleted with the rules of the last bridge.
+func (c *NftablesClient) DeleteIsolationRules() error {
+	defer c.close()
+
+	comment := definitions.IsolationRuleComment(c.bridgeName)
+
+	// same as the forwarding rule, the isolation rules are kept while the bridge exists
+	if _, err := utils.BridgeByName(c.bridgeName); err == nil {
+		log.Debugf("bridge %s is still in use, not removing the isolation rules", c.bridgeName)
+		return nil
+	}
+
+	for _, family := range []nftables.TableFamily{nftables.TableFamilyIPv4, nftables.TableFamilyIPv6} {
+		userChain, err := c.getChain(definitions.DockerFWUserChain, definitions.DockerFWTable, family)
+		if err != nil {
+			if family == nftables.TableFamilyIPv6 {
+				continue
+			}
+
+			return fmt.Errorf("%w. See http://containerlab.dev/manual/network/#external-access", err)
+		}
+
+		userRules, err := c.nftConn.GetRules(userChain.Table, userChain)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range getRulesWithComment(comment, userRules) {
+			c.deleteRule(r)
+		}
+
+		isoChain, err := c.getChain(definitions.DockerFWIsolationChain, definitions.DockerFWTable, family)
+		if err != nil {
+			log.Debug("isolation rules don't exist. Skipping deletion")
+			continue
+		}
+
+		isoRules, err := c.nftConn.GetRules(isoChain.Table, isoChain)
+		if err != nil {
+			return err
+		}
+
+		bridgeRules := getRulesWithComment(comment, isoRules)
+
+		log.Debugf("removing clab isolation rules for bridge %q and family %v", c.bridgeName, family)
+		for _, r := range bridgeRules {
+			c.deleteRule(r)
+		}
+
+		if len(bridgeRules) < len(isoRules) {
+			continue
+		}
+
+		log.Debugf("removing nftables chain %s of family %v", definitions.DockerFWIsolationChain, family)
+		for _, r := range getRulesWithComment(definitions.IsolationJumpComment, userRules) {
+			c.deleteRule(r)
+		}
+
+		c.nftConn.DelChain(isoChain)
+	}
+
+	return c.flush()
+}
+
+// newIsolationRule creates the nftables rule of the chain from the isolation rule definition.
+func newIsolationRule(chain *nftables.Chain, r definitions.IsolationRule, comment string) (*clabNftablesRule, error) {
+	rule := &clabNftablesRule{rule: &nftables.Rule{Table: chain.Table, Chain: chain}}
+
+	if r.InIface != "" {
+		rule.AddInputInterfaceFilter(r.InIface)
+	}
+
+	if r.OutIface != "" {
+		rule.AddOutputInterfaceFilter(r.OutIface)
+	}
+
+	if r.Dst.IsValid() {
+		if err := rule.AddDestinationFilter(r.Dst); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.Established {
+		rule.AddEstablishedFilter()
+	}
+
+	if err := rule.AddComment(comment); err != nil {
+		return nil, err
+	}
+
+	if err := rule.AddCounter(); err != nil {
+		return nil, err
+	}
+
+	if r.Return {
+		rule.AddVerdictReturn()
+		return rule, nil
+	}
+
+	return rule, rule.AddVerdictDrop()
+}
+
+// getChain returns the chain with the name in the table of the family.
+func (nftC *NftablesClient) getChain(name, tableName string, family nftables.TableFamily) (*nftables.Chain, error) {
+	chains, err := nftC.getChains(name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range chains {
+		if c.Table.Name == tableName && c.Table.Family == family {
+			return c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no match for chain %q, table %q with family %q found", name, tableName, family)
+}
+
 func (nftC *NftablesClient) getChains(name string) ([]*nftables.Chain, error) {
 	var result []*nftables.Chain
 
@@ -242,13 +473,10 @@ func (*NftablesClient) getRulesForMgmtBr(brName string, rules []*nftables.Rule)
 			// Match is a match expression
 			// in the case of the rule we are looking for, it should contain
 			// a comment extension with the comment set by containerlab
+			// the comment of the isolation rules starts with the same text, hence the exact match
 			case *expr.Match:
-				if v.Name == "comment" {
-					if val, ok := v.Info.(*xt.Unknown); ok {
-						if bytes.HasPrefix(*val, []byte(definitions.IPTablesRuleComment)) {
-							commentFound = true
-						}
-					}
+				if c, ok := matchComment(v); ok && c == definitions.IPTablesRuleComment {
+					commentFound = true
 				}
 			default:
 				continue
@@ -262,3 +490,36 @@ func (*NftablesClient) getRulesForMgmtBr(brName string, rules []*nftables.Rule)
 
 	return result
 }
+
+// getRulesWithComment returns all rules that have the provided comment.
+func getRulesWithComment(comment string, rules []*nftables.Rule) []*nftables.Rule {
+	var result []*nftables.Rule
+
+	for _, r := range rules {
+		for _, e := range r.Exprs {
+			if m, ok := e.(*expr.Match); ok {
+				if c, ok := matchComment(m); ok && c == comment {
+					result = append(result, r)
+					break
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// matchComment returns the comment of the comment match extension.
+func matchComment(m *expr.Match) (string, bool) {
+	if m.Name != "comment" {
+		return "", false
+	}
+
+	val, ok := m.Info.(*xt.Unknown)
+	if !ok {
+		return "", false
+	}
+
+	// the comment is zero-padded to the max comment size
+	return string(bytes.TrimRight(*val, "\x00")), true
+}
@@ -2,8 +2,12 @@ package nftables
 
 import (
 	"fmt"
+	"net"
+	"net/netip"
+	"strings"
 
 	"github.com/google/nftables"
+	"github.com/google/nftables/binaryutil"
 	"github.com/google/nftables/expr"
 	"github.com/google/nftables/xt"
 	"github.com/srl-labs/containerlab/runtime/docker/firewall/definitions"
@@ -14,21 +18,95 @@ type clabNftablesRule struct {
 }
 
 func (cnr *clabNftablesRule) AddOutputInterfaceFilter(oif string) {
+	cnr.addInterfaceFilter(expr.MetaKeyOIFNAME, oif)
+}
+
+// AddInputInterfaceFilter adds the input interface match to the rule.
+func (cnr *clabNftablesRule) AddInputInterfaceFilter(iif string) {
+	cnr.addInterfaceFilter(expr.MetaKeyIIFNAME, iif)
+}
+
+// addInterfaceFilter adds the interface name match to the rule.
+// The name ending with + matches all interfaces with the given prefix.
+func (cnr *clabNftablesRule) addInterfaceFilter(key expr.MetaKey, name string) {
 	// define the metadata to evaluate
-	metaOifName := &expr.Meta{
-		Key:            expr.MetaKeyOIFNAME,
+	metaIfName := &expr.Meta{
+		Key:            key,
 		SourceRegister: false,
 		Register:       1,
 	}
+
+	// exact match compares the zero-terminated name,
+	// prefix match compares only the prefix bytes
+	data := []byte(name + "\x00")
+	if prefix, ok := strings.CutSuffix(name, "+"); ok {
+		data = []byte(prefix)
+	}
+
 	// define the comparison
 	comp := &expr.Cmp{
 		Op:       expr.CmpOpEq,
 		Register: 1,
-		Data:     []byte(oif + "\x00"),
+		Data:     data,
 	}
 
 	// add expr to rule
-	cnr.rule.Exprs = append(cnr.rule.Exprs, metaOifName, comp)
+	cnr.rule.Exprs = append(cnr.rule.Exprs, metaIfName, comp)
+}
+
+// AddDestinationFilter adds the IPv4 or IPv6 destination prefix match to the rule.
+func (cnr *clabNftablesRule) AddDestinationFilter(p netip.Prefix) error {
+	// the destination address offset and length in the IPv4 and IPv6 headers
+	offset, addrLen := uint32(16), 4
+	if p.Addr().Is6() {
+		offset, addrLen = 24, 16
+	}
+
+	// load the destination address from the IP header
+	payload := &expr.Payload{
+		DestRegister: 1,
+		Base:         expr.PayloadBaseNetworkHeader,
+		Offset:       offset,
+		Len:          uint32(addrLen),
+	}
+	// mask it with the prefix length
+	mask := &expr.Bitwise{
+		SourceRegister: 1,
+		DestRegister:   1,
+		Len:            uint32(addrLen),
+		Mask:           net.CIDRMask(p.Bits(), addrLen*8),
+		Xor:            make([]byte, addrLen),
+	}
+	comp := &expr.Cmp{
+		Op:       expr.CmpOpEq,
+		Register: 1,
+		Data:     p.Masked().Addr().AsSlice(),
+	}
+
+	cnr.rule.Exprs = append(cnr.rule.Exprs, payload, mask, comp)
+	return nil
+}
+
+// AddEstablishedFilter adds the match of the established and related connections to the rule.
+func (cnr *clabNftablesRule) AddEstablishedFilter() {
+	ct := &expr.Ct{
+		Register: 1,
+		Key:      expr.CtKeySTATE,
+	}
+	state := &expr.Bitwise{
+		SourceRegister: 1,
+		DestRegister:   1,
+		Len:            4,
+		Mask:           binaryutil.NativeEndian.PutUint32(expr.CtStateBitESTABLISHED | expr.CtStateBitRELATED),
+		Xor:            binaryutil.NativeEndian.PutUint32(0),
+	}
+	comp := &expr.Cmp{
+		Op:       expr.CmpOpNeq,
+		Register: 1,
+		Data:     binaryutil.NativeEndian.PutUint32(0),
+	}
+
+	cnr.rule.Exprs = append(cnr.rule.Exprs, ct, state, comp)
 }
 
 func (cnr *clabNftablesRule) AddCounter() error {
@@ -53,6 +131,16 @@ func (cnr *clabNftablesRule) AddVerdictDrop() error {
 	return nil
 }
 
+// AddVerdictReturn makes the rule return to the calling chain.
+func (cnr *clabNftablesRule) AddVerdictReturn() {
+	cnr.rule.Exprs = append(cnr.rule.Exprs, &expr.Verdict{Kind: expr.VerdictReturn})
+}
+
+// AddVerdictJump makes the rule jump to the chain.
+func (cnr *clabNftablesRule) AddVerdictJump(chain string) {
+	cnr.rule.Exprs = append(cnr.rule.Exprs, &expr.Verdict{Kind: expr.VerdictJump, Chain: chain})
+}
+
 // AddComment adds a comment to the rule.
 func (cnr *clabNftablesRule) AddComment(comment string) error {
 	// convert comment to byte
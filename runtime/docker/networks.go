@@ -0,0 +1,203 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+
+	networkapi "github.com/docker/docker/api/types/network"
+	dockerC "github.com/docker/docker/client"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// endpointIfnameOpt is the endpoint driver option setting the name of the container interface.
+const endpointIfnameOpt = "com.docker.network.endpoint.ifname"
+
+// CreateExtraNets creates the extra docker networks, existing networks are reused.
+func (d *DockerRuntime) CreateExtraNets(ctx context.Context, nets []*types.Network) error {
+	nctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	for _, n := range nets {
+		_, err := d.Client.NetworkInspect(nctx, n.Network, networkapi.InspectOptions{})
+		switch {
+		case err == nil:
+			log.Debugf("network %q was found. Reusing it...", n.Network)
+			continue
+		case !dockerC.IsErrNotFound(err):
+			return err
+		}
+
+		log.Infof("Creating docker network: Name=%q, IPv4Subnet=%q, IPv6Subnet=%q, MTU=%d",
+			n.Network, n.IPv4Subnet, n.IPv6Subnet, n.MTU)
+
+		var ipamConfig []networkapi.IPAMConfig
+		if n.IPv4Subnet != "" {
+			ipamConfig = append(ipamConfig, networkapi.IPAMConfig{Subnet: n.IPv4Subnet})
+		}
+		if n.IPv6Subnet != "" {
+			ipamConfig = append(ipamConfig, networkapi.IPAMConfig{Subnet: n.IPv6Subnet})
+		}
+
+		netwOpts := map[string]string{}
+		if n.MTU != 0 {
+			netwOpts["com.docker.network.driver.mtu"] = strconv.Itoa(n.MTU)
+		}
+		if n.Bridge != "" {
+			netwOpts["com.docker.network.bridge.name"] = n.Bridge
+		}
+
+		_, err = d.Client.NetworkCreate(nctx, n.Network, networkapi.CreateOptions{
+			Driver:     "bridge",
+			EnableIPv6: utils.Pointer(n.IPv6Subnet != ""),
+			IPAM: &networkapi.IPAM{
+				Driver: "default",
+				Config: ipamConfig,
+			},
+			Labels: map[string]string{
+				"containerlab": "",
+			},
+			Options: netwOpts,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteExtraNets deletes the extra docker networks that have no containers attached.
+func (d *DockerRuntime) DeleteExtraNets(ctx context.Context, nets []*types.Network) error {
+	if d.config.KeepMgmtNet {
+		return nil
+	}
+
+	nctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	for _, n := range nets {
+		nres, err := d.Client.NetworkInspect(nctx, n.Network, networkapi.InspectOptions{})
+		if err != nil {
+			if dockerC.IsErrNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		if len(nres.Containers) > 0 {
+			log.Debugf("network %q has %d active endpoints, deletion skipped", n.Network, len(nres.Containers))
+			continue
+		}
+
+		err = d.Client.NetworkRemove(nctx, n.Network)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListNets lists the existing docker networks.
+func (d *DockerRuntime) ListNets(ctx context.Context) ([]*types.Network, error) {
+	nctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	nres, err := d.Client.NetworkList(nctx, networkapi.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	nets := make([]*types.Network, 0, len(nres))
+	for _, nr := range nres {
+		n := &types.Network{
+			Network: nr.Name,
+			Bridge:  nr.Options["com.docker.network.bridge.name"],
+		}
+
+		for _, c := range nr.IPAM.Config {
+			p, err := netip.ParsePrefix(c.Subnet)
+			if err != nil {
+				continue
+			}
+
+			switch {
+			case p.Addr().Is4() && n.IPv4Subnet == "":
+				n.IPv4Subnet = c.Subnet
+			case p.Addr().Is6() && n.IPv6Subnet == "":
+				n.IPv6Subnet = c.Subnet
+			}
+		}
+
+		nets = append(nets, n)
+	}
+
+	return nets, nil
+}
+
+// connectExtraNets connects a created container to the extra networks of the node.
+// The interface name is requested from the daemon, which is only honored by docker 28 and newer,
+// the interfaces are therefore renamed after the container starts by renameExtraNetIfaces.
+func (d *DockerRuntime) connectExtraNets(ctx context.Context, cID string, node *types.NodeConfig) error {
+	for _, n := range node.Networks {
+		log.Debugf("Connecting container %q to network %q", node.ShortName, n.Network)
+
+		err := d.Client.NetworkConnect(ctx, n.Network, cID, &networkapi.EndpointSettings{
+			IPAMConfig: &networkapi.EndpointIPAMConfig{
+				IPv4Address: n.IPv4Address,
+				IPv6Address: n.IPv6Address,
+			},
+			Aliases:    node.Aliases,
+			DriverOpts: map[string]string{endpointIfnameOpt: n.Interface},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renameExtraNetIfaces makes sure that the container interfaces attached to the extra networks
+// have the names set in the network attachments of the node.
+// The interfaces are matched to the networks by the MAC address of the network endpoint.
+func (d *DockerRuntime) renameExtraNetIfaces(ctx context.Context, cID, nspath string, node *types.NodeConfig) error {
+	if len(node.Networks) == 0 {
+		return nil
+	}
+
+	nctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	cJSON, err := d.Client.ContainerInspect(nctx, cID)
+	if err != nil {
+		return err
+	}
+
+	names := map[string]string{}
+
+	for _, n := range node.Networks {
+		ep, ok := cJSON.NetworkSettings.Networks[n.Network]
+		if !ok || ep.MacAddress == "" {
+			return fmt.Errorf("container %q is not attached to the network %q", node.ShortName, n.Network)
+		}
+
+		mac, err := net.ParseMAC(ep.MacAddress)
+		if err != nil {
+			return err
+		}
+
+		names[mac.String()] = n.Interface
+	}
+
+	return utils.RenameLinksByMAC(nspath, names)
+}
@@ -139,6 +139,18 @@ func (c *IgniteRuntime) DeleteNet(ctx context.Context) error {
 	return c.ctrRuntime.DeleteNet(ctx)
 }
 
+func (c *IgniteRuntime) CreateExtraNets(ctx context.Context, nets []*types.Network) error {
+	return c.ctrRuntime.CreateExtraNets(ctx, nets)
+}
+
+func (c *IgniteRuntime) DeleteExtraNets(ctx context.Context, nets []*types.Network) error {
+	return c.ctrRuntime.DeleteExtraNets(ctx, nets)
+}
+
+func (c *IgniteRuntime) ListNets(ctx context.Context) ([]*types.Network, error) {
+	return c.ctrRuntime.ListNets(ctx)
+}
+
 // PullImage pulls the provided image name if it does not exist.
 // Ignite does ignore the pullPolicy though.
 func (*IgniteRuntime) PullImage(_ context.Context, imageName string, _ types.PullPolicyValue) error {
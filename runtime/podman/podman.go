@@ -138,6 +138,99 @@ func (r *PodmanRuntime) DeleteNet(ctx context.Context) error {
 	return nil
 }
 
+// CreateExtraNets creates the extra podman networks, existing networks are reused.
+func (r *PodmanRuntime) CreateExtraNets(ctx context.Context, nets []*types.Network) error {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range nets {
+		b, err := network.Exists(ctx, n.Network, &network.ExistsOptions{})
+		if err != nil {
+			return err
+		}
+		if b {
+			log.Debugf("network %q was found. Reusing it...", n.Network)
+			continue
+		}
+		netopts, err := extraNetOpts(n)
+		if err != nil {
+			return err
+		}
+		log.Debugf("Trying to create network with params: %+v", netopts)
+		_, err = network.Create(ctx, &netopts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteExtraNets deletes the extra podman networks that have no containers attached.
+func (r *PodmanRuntime) DeleteExtraNets(ctx context.Context, nets []*types.Network) error {
+	if r.config.KeepMgmtNet {
+		return nil
+	}
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range nets {
+		b, err := network.Exists(ctx, n.Network, &network.ExistsOptions{})
+		if err != nil {
+			return err
+		}
+		if !b {
+			continue
+		}
+		listOpts := new(containers.ListOptions).WithAll(true).
+			WithFilters(map[string][]string{"network": {n.Network}})
+		cList, err := containers.List(ctx, listOpts)
+		if err != nil {
+			return err
+		}
+		if len(cList) > 0 {
+			log.Debugf("network %q has %d attached containers, deletion skipped", n.Network, len(cList))
+			continue
+		}
+		log.Debugf("trying to delete network %v", n.Network)
+		_, err = network.Remove(ctx, n.Network, &network.RemoveOptions{})
+		if err != nil {
+			return fmt.Errorf("error while trying to remove network %q: %w", n.Network, err)
+		}
+	}
+	return nil
+}
+
+// ListNets lists the existing podman networks.
+func (r *PodmanRuntime) ListNets(ctx context.Context) ([]*types.Network, error) {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nets, err := network.List(ctx, &network.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*types.Network, 0, len(nets))
+	for _, n := range nets {
+		pn := &types.Network{Network: n.Name, Bridge: n.NetworkInterface}
+		for _, s := range n.Subnets {
+			switch {
+			case s.Subnet.IP.To4() != nil && pn.IPv4Subnet == "":
+				pn.IPv4Subnet = s.Subnet.String()
+			case s.Subnet.IP.To4() == nil && pn.IPv6Subnet == "":
+				pn.IPv6Subnet = s.Subnet.String()
+			}
+		}
+		res = append(res, pn)
+	}
+
+	return res, nil
+}
+
 func (r *PodmanRuntime) PullImage(ctx context.Context, image string, pullPolicy types.PullPolicyValue) error {
 	ctx, err := r.connect(ctx)
 	if err != nil {
@@ -218,6 +218,19 @@ func (r *PodmanRuntime) createContainerSpec(ctx context.Context, cfg *types.Node
 			StaticMAC:     hwAddr,
 			InterfaceName: "",
 		}}
+		for _, n := range cfg.Networks {
+			var ips []net.IP
+			for _, a := range []string{n.IPv4Address, n.IPv6Address} {
+				if ip := net.ParseIP(a); ip != nil {
+					ips = append(ips, ip)
+				}
+			}
+			nets[n.Network] = netTypes.PerNetworkOptions{
+				StaticIPs:     ips,
+				Aliases:       cfg.Aliases,
+				InterfaceName: n.Interface,
+			}
+		}
 		portmap, err := r.convertPortMap(ctx, cfg.PortBindings)
 		if err != nil {
 			return sg, err
@@ -517,3 +530,39 @@ func (r *PodmanRuntime) postStartActions(ctx context.Context, cID string, cfg *t
 	err = r.disableTXOffload(ctx)
 	return err
 }
+
+// extraNetOpts compiles the podman network definition for an extra network.
+func extraNetOpts(n *types.Network) (netTypes.Network, error) {
+	var (
+		err     error
+		ipv6    bool
+		subnets = make([]netTypes.Subnet, 0)
+		options = map[string]string{}
+	)
+	for _, s := range []string{n.IPv4Subnet, n.IPv6Subnet} {
+		if s == "" {
+			continue
+		}
+		subnet := netTypes.Subnet{}
+		subnet.Subnet, err = netTypes.ParseCIDR(s)
+		if err != nil {
+			return netTypes.Network{}, err
+		}
+		if subnet.Subnet.IP.To4() == nil {
+			ipv6 = true
+		}
+		subnets = append(subnets, subnet)
+	}
+	if n.MTU != 0 {
+		options["mtu"] = strconv.Itoa(n.MTU)
+	}
+	return netTypes.Network{
+		Driver:           "bridge",
+		Labels:           map[string]string{"containerlab": ""},
+		Subnets:          subnets,
+		IPv6Enabled:      ipv6,
+		Options:          options,
+		Name:             n.Network,
+		NetworkInterface: n.Bridge,
+	}, nil
+}
@@ -30,6 +30,12 @@ type ContainerRuntime interface {
 	CreateNet(context.Context) error
 	// Delete container (bridge) network
 	DeleteNet(context.Context) error
+	// Create extra container networks nodes can be attached to in addition to the management network
+	CreateExtraNets(context.Context, []*types.Network) error
+	// Delete extra container networks
+	DeleteExtraNets(context.Context, []*types.Network) error
+	// List existing container networks with their subnets
+	ListNets(context.Context) ([]*types.Network, error)
 	// Pull container image if not present
 	PullImage(context.Context, string, types.PullPolicyValue) error
 	// CreateContainer creates a container, but does not start it
@@ -0,0 +1,106 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// HookEvent is the lab lifecycle event the hooks run at.
+type HookEvent string
+
+const (
+	HookPreDeploy   HookEvent = "pre-deploy"
+	HookPostDeploy  HookEvent = "post-deploy"
+	HookPreDestroy  HookEvent = "pre-destroy"
+	HookPostDestroy HookEvent = "post-destroy"
+)
+
+// HookFailurePolicy defines what happens when a hook fails.
+type HookFailurePolicy string
+
+const (
+	// HookFailurePolicyFail makes the failed hook fail the lab operation.
+	HookFailurePolicyFail HookFailurePolicy = "fail"
+	// HookFailurePolicyIgnore logs the hook failure and lets the lab operation continue.
+	HookFailurePolicyIgnore HookFailurePolicy = "ignore"
+)
+
+// DefaultHookTimeout is the time a hook is allowed to run for when its timeout is not set.
+const DefaultHookTimeout = time.Minute
+
+// Hooks are the host commands run at the lab lifecycle events.
+type Hooks struct {
+	PreDeploy   []*Hook `yaml:"pre-deploy,omitempty" json:"pre-deploy,omitempty"`
+	PostDeploy  []*Hook `yaml:"post-deploy,omitempty" json:"post-deploy,omitempty"`
+	PreDestroy  []*Hook `yaml:"pre-destroy,omitempty" json:"pre-destroy,omitempty"`
+	PostDestroy []*Hook `yaml:"post-destroy,omitempty" json:"post-destroy,omitempty"`
+}
+
+// Hook is a host command or script run at a lab lifecycle event.
+type Hook struct {
+	// Command is run with the shell on the container host.
+	Command string `yaml:"command" json:"command"`
+	// Timeout is the time the command is allowed to run for.
+	Timeout time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// OnFailure is the policy applied when the command fails or times out.
+	OnFailure HookFailurePolicy `yaml:"on-failure,omitempty" json:"on-failure,omitempty"`
+}
+
+// IsEmpty returns true when there are no hooks for any of the events.
+func (h *Hooks) IsEmpty() bool {
+	return h == nil || len(h.PreDeploy)+len(h.PostDeploy)+len(h.PreDestroy)+len(h.PostDestroy) == 0
+}
+
+// Get returns the hooks of the event.
+func (h *Hooks) Get(event HookEvent) []*Hook {
+	if h == nil {
+		return nil
+	}
+
+	switch event {
+	case HookPreDeploy:
+		return h.PreDeploy
+	case HookPostDeploy:
+		return h.PostDeploy
+	case HookPreDestroy:
+		return h.PreDestroy
+	case HookPostDestroy:
+		return h.PostDestroy
+	}
+
+	return nil
+}
+
+// InitDefaults validates the hooks and sets their default timeout and failure policy.
+func (h *Hooks) InitDefaults() error {
+	for _, event := range []HookEvent{HookPreDeploy, HookPostDeploy, HookPreDestroy, HookPostDestroy} {
+		for i, hook := range h.Get(event) {
+			if hook == nil || hook.Command == "" {
+				return fmt.Errorf("%s hook #%d has no command", event, i+1)
+			}
+
+			if hook.Timeout < 0 {
+				return fmt.Errorf("%s hook %q has a negative timeout", event, hook.Command)
+			}
+
+			if hook.Timeout == 0 {
+				hook.Timeout = DefaultHookTimeout
+			}
+
+			switch hook.OnFailure {
+			case "":
+				hook.OnFailure = HookFailurePolicyFail
+			case HookFailurePolicyFail, HookFailurePolicyIgnore:
+			default:
+				return fmt.Errorf("%s hook %q has invalid on-failure policy %q, should be one of %q",
+					event, hook.Command, hook.OnFailure, []HookFailurePolicy{HookFailurePolicyFail, HookFailurePolicyIgnore})
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+// IPAMConfig is the topology-level IP address management configuration.
+// When set, containerlab deterministically allocates the addresses
+// for point-to-point links and node loopbacks from the configured pools.
+type IPAMConfig struct {
+	// P2P is the pool the point-to-point link subnets (/31 and /127) are allocated from.
+	P2P *IPAMPool `yaml:"p2p,omitempty" json:"p2p,omitempty"`
+	// Loopback is the pool the node loopback addresses (/32 and /128) are allocated from.
+	Loopback *IPAMPool `yaml:"loopback,omitempty" json:"loopback,omitempty"`
+}
+
+// IPAMPool holds the per address family subnets of an IPAM pool.
+// A family is allocated only when its subnet is set.
+type IPAMPool struct {
+	IPv4Subnet string `yaml:"ipv4-subnet,omitempty" json:"ipv4-subnet,omitempty"`
+	IPv6Subnet string `yaml:"ipv6-subnet,omitempty" json:"ipv6-subnet,omitempty"`
+}
+
+// IPAMAllocations holds the addresses allocated by containerlab IPAM.
+type IPAMAllocations struct {
+	// Loopbacks is a map of node names to the loopback addresses of the node.
+	Loopbacks map[string]*IPAMAddresses `json:"loopbacks,omitempty"`
+	// Links is the list of the addresses allocated for the point-to-point links
+	// in the order the links are defined in the topology.
+	Links []*IPAMLink `json:"links,omitempty"`
+}
+
+// IPAMAddresses is a pair of IPv4 and IPv6 addresses in the prefix notation.
+type IPAMAddresses struct {
+	IPv4 string `json:"ipv4,omitempty"`
+	IPv6 string `json:"ipv6,omitempty"`
+}
+
+// IPAMLink holds the addresses allocated for both ends of a point-to-point link.
+type IPAMLink struct {
+	A *IPAMEndpoint `json:"a"`
+	Z *IPAMEndpoint `json:"z"`
+}
+
+// IPAMEndpoint holds the addresses allocated for a link endpoint.
+type IPAMEndpoint struct {
+	Node      string `json:"node"`
+	Interface string `json:"interface"`
+	IPAMAddresses
+}
@@ -73,10 +73,18 @@ type NodeDefinition struct {
 	DNS *DNSConfig `yaml:"dns,omitempty"`
 	// Certificate configuration
 	Certificate *CertificateConfig `yaml:"certificate,omitempty"`
+	// Credentials used to access the node
+	Credentials *CredentialsConfig `yaml:"credentials,omitempty"`
+	// Exec configuration of the transport the node commands are executed over
+	ExecConfig *ExecConfig `yaml:"exec-config,omitempty"`
 	// Healthcheck configuration
 	HealthCheck *HealthcheckConfig `yaml:"healthcheck,omitempty"`
 	// Network aliases
 	Aliases []string `yaml:"aliases,omitempty"`
+	// Static routes configured in the node's network namespace
+	StaticRoutes []*StaticRoute `yaml:"static-routes,omitempty"`
+	// Extra runtime networks the node is attached to
+	Networks []*NetworkAttachment `yaml:"networks,omitempty"`
 }
 
 // Interface compliance.
@@ -383,6 +391,20 @@ func (n *NodeDefinition) GetCertificateConfig() *CertificateConfig {
 	return n.Certificate
 }
 
+func (n *NodeDefinition) GetCredentialsConfig() *CredentialsConfig {
+	if n == nil {
+		return nil
+	}
+	return n.Credentials
+}
+
+func (n *NodeDefinition) GetExecConfig() *ExecConfig {
+	if n == nil {
+		return nil
+	}
+	return n.ExecConfig
+}
+
 func (n *NodeDefinition) GetHealthcheckConfig() *HealthcheckConfig {
 	if n == nil {
 		return nil
@@ -397,6 +419,20 @@ func (n *NodeDefinition) GetAliases() []string {
 	return n.Aliases
 }
 
+func (n *NodeDefinition) GetStaticRoutes() []*StaticRoute {
+	if n == nil {
+		return nil
+	}
+	return n.StaticRoutes
+}
+
+func (n *NodeDefinition) GetNetworks() []*NetworkAttachment {
+	if n == nil {
+		return nil
+	}
+	return n.Networks
+}
+
 // ImportEnvs imports all environment variales defined in the shell
 // if __IMPORT_ENVS is set to true.
 func (n *NodeDefinition) ImportEnvs() {
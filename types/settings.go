@@ -5,6 +5,17 @@ import "time"
 // Settings is the structure for global containerlab settings.
 type Settings struct {
 	CertificateAuthority *CertificateAuthority `yaml:"certificate-authority"`
+	// Webhooks are the HTTP endpoints notified of the lab lifecycle events.
+	Webhooks []*Webhook `yaml:"webhooks"`
+}
+
+// GetWebhooks returns the webhooks of the settings.
+func (s *Settings) GetWebhooks() []*Webhook {
+	if s == nil {
+		return nil
+	}
+
+	return s.Webhooks
 }
 
 // CertificateAuthority is the structure for global containerlab certificate authority settings.
@@ -16,6 +27,9 @@ type CertificateAuthority struct {
 	// KeySize is the size of the CA private key in bits
 	// when containerlab is in charge of the CA generation.
 	KeySize int `yaml:"key-size"`
+	// KeyAlgorithm is the algorithm of the CA private key, one of rsa, ecdsa-p256, ecdsa-p384 and ed25519.
+	// The node keys use the same algorithm unless it is set for the node.
+	KeyAlgorithm string `yaml:"key-algorithm"`
 	// ValidityDuration is the duration of the CA certificate validity
 	// when containerlab is in charge of the CA generation.
 	ValidityDuration time.Duration `yaml:"validity-duration"`
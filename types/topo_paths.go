@@ -12,8 +12,11 @@ import (
 
 const (
 	ansibleInventoryFileName  = "ansible-inventory.yml"
+	nornirInventoryDir        = "nornir-inventory"
+	netboxImportDir           = "netbox-import"
 	topologyExportDatFileName = "topology-data.json"
 	authzKeysFileName         = "authorized_keys"
+	knownHostsFileName        = "known_hosts"
 	tlsDir                    = ".tls"
 	caDir                     = "ca"
 	graph                     = "graph"
@@ -113,6 +116,11 @@ func (t *TopoPaths) SetExternalCaFiles(certFile, keyFile string) error {
 	return nil
 }
 
+// HasExternalCA returns true if the externally generated CA files are used.
+func (t *TopoPaths) HasExternalCA() bool {
+	return t.externalCACertFile != ""
+}
+
 // SSHConfigPath returns the topology dependent ssh config file name.
 func (t *TopoPaths) SSHConfigPath() string {
 	return fmt.Sprintf(sshConfigFilePathTmpl, t.topoName)
@@ -133,6 +141,11 @@ func (t *TopoPaths) AuthorizedKeysFilename() string {
 	return path.Join(t.labDir, authzKeysFileName)
 }
 
+// KnownHostsFileAbsPath returns the path of the known_hosts file with the pinned SSH host keys of the lab nodes.
+func (t *TopoPaths) KnownHostsFileAbsPath() string {
+	return path.Join(t.labDir, knownHostsFileName)
+}
+
 // GraphDir returns the directory that takes the graphs.
 func (t *TopoPaths) GraphDir() string {
 	return path.Join(t.labDir, graph)
@@ -163,6 +176,16 @@ func (t *TopoPaths) AnsibleInventoryFileAbsPath() string {
 	return path.Join(t.labDir, ansibleInventoryFileName)
 }
 
+// NornirInventoryDir returns the absolute path to the directory with the Nornir SimpleInventory files.
+func (t *TopoPaths) NornirInventoryDir() string {
+	return path.Join(t.labDir, nornirInventoryDir)
+}
+
+// NetBoxImportDir returns the absolute path to the directory with the NetBox import files.
+func (t *TopoPaths) NetBoxImportDir() string {
+	return path.Join(t.labDir, netboxImportDir)
+}
+
 // TopologyFilenameAbsPath returns the absolute path to the topology file.
 func (t *TopoPaths) TopologyFilenameAbsPath() string {
 	return t.topoFile
@@ -204,6 +227,12 @@ func (t *TopoPaths) TopologyFileIsSet() bool {
 	return t.topoFile != ""
 }
 
+// TopologyEditBakFileAbsPath returns the name of the file the topology is backed up to
+// before it is overwritten by the topology editor.
+func (t *TopoPaths) TopologyEditBakFileAbsPath() string {
+	return path.Join(t.TopologyFileDir(), backupFilePrefix+t.TopologyFilenameBase()+".edit"+backupFileSuffix)
+}
+
 // TopologyBakFileAbsPath returns the backup topology file name.
 func (t *TopoPaths) TopologyBakFileAbsPath() string {
 	return path.Join(t.TopologyFileDir(), backupFilePrefix+t.TopologyFilenameBase()+backupFileSuffix)
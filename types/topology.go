@@ -14,6 +14,9 @@ type Topology struct {
 	Kinds    map[string]*NodeDefinition `yaml:"kinds,omitempty"`
 	Nodes    map[string]*NodeDefinition `yaml:"nodes,omitempty"`
 	Links    []*links.LinkDefinition    `yaml:"links,omitempty"`
+	IPAM     *IPAMConfig                `yaml:"ipam,omitempty"`
+	// Networks are the extra runtime networks nodes can be attached to.
+	Networks map[string]*Network `yaml:"networks,omitempty"`
 }
 
 func NewTopology() *Topology {
@@ -564,6 +567,32 @@ func (t *Topology) GetCertificateConfig(name string) *CertificateConfig {
 	return cc
 }
 
+// GetCredentialsConfig returns the credentials configuration for the given node.
+// It merges the default, kind and node credentials with the node-level attributes taking precedence.
+func (t *Topology) GetCredentialsConfig(name string) *CredentialsConfig {
+	cc := &CredentialsConfig{}
+
+	cc.Merge(
+		t.GetDefaults().GetCredentialsConfig()).Merge(
+		t.GetKind(t.GetNodeKind(name)).GetCredentialsConfig()).Merge(
+		t.Nodes[name].GetCredentialsConfig())
+
+	return cc
+}
+
+// GetExecConfig returns the exec configuration for the given node.
+// It merges the default, kind and node exec configuration with the node-level attributes taking precedence.
+func (t *Topology) GetExecConfig(name string) *ExecConfig {
+	ec := &ExecConfig{}
+
+	ec.Merge(
+		t.GetDefaults().GetExecConfig()).Merge(
+		t.GetKind(t.GetNodeKind(name)).GetExecConfig()).Merge(
+		t.Nodes[name].GetExecConfig())
+
+	return ec
+}
+
 func (t *Topology) GetHealthCheckConfig(name string) *HealthcheckConfig {
 	if ndef, ok := t.Nodes[name]; ok {
 		nodeHealthcheckConf := ndef.GetHealthcheckConfig()
@@ -588,3 +617,33 @@ func (t *Topology) GetNodeAliases(name string) []string {
 	}
 	return nil
 }
+
+// GetNodeStaticRoutes returns the static routes defined for the given node
+// with the node-level definition taking precedence over the kind and defaults.
+func (t *Topology) GetNodeStaticRoutes(name string) []*StaticRoute {
+	if ndef, ok := t.Nodes[name]; ok {
+		if v := ndef.GetStaticRoutes(); len(v) > 0 {
+			return v
+		}
+		if v := t.GetKind(t.GetNodeKind(name)).GetStaticRoutes(); len(v) > 0 {
+			return v
+		}
+		return t.GetDefaults().GetStaticRoutes()
+	}
+	return nil
+}
+
+// GetNodeNetworks returns the extra networks the given node is attached to
+// with the node-level definition taking precedence over the kind and defaults.
+func (t *Topology) GetNodeNetworks(name string) []*NetworkAttachment {
+	if ndef, ok := t.Nodes[name]; ok {
+		if v := ndef.GetNetworks(); len(v) > 0 {
+			return v
+		}
+		if v := t.GetKind(t.GetNodeKind(name)).GetNetworks(); len(v) > 0 {
+			return v
+		}
+		return t.GetDefaults().GetNetworks()
+	}
+	return nil
+}
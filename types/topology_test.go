@@ -570,3 +570,41 @@ func TestGetNodeCertificateConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestGetExecConfig(t *testing.T) {
+	topo := &Topology{
+		Defaults: &NodeDefinition{
+			ExecConfig: &ExecConfig{Port: 2222},
+		},
+		Kinds: map[string]*NodeDefinition{
+			"nokia_sros": {ExecConfig: &ExecConfig{Transport: ExecTransportSSH, Platform: "nokia_sros_classic"}},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"sros1":   {Kind: "nokia_sros"},
+			"sros2":   {Kind: "nokia_sros", ExecConfig: &ExecConfig{Transport: ExecTransportContainer, Port: 22}},
+			"client1": {Kind: "linux"},
+		},
+	}
+
+	want := map[string]*ExecConfig{
+		"sros1":   {Transport: ExecTransportSSH, Platform: "nokia_sros_classic", Port: 2222},
+		"sros2":   {Transport: ExecTransportContainer, Platform: "nokia_sros_classic", Port: 22},
+		"client1": {Port: 2222},
+	}
+
+	for name, w := range want {
+		if d := cmp.Diff(w, topo.GetExecConfig(name)); d != "" {
+			t.Errorf("node %s exec config mismatch (-want +got):\n%s", name, d)
+		}
+	}
+
+	if got := topo.GetExecConfig("client1").GetTransport(); got != ExecTransportContainer {
+		t.Errorf("expected the default transport %q, got %q", ExecTransportContainer, got)
+	}
+
+	for _, ec := range []*ExecConfig{{Transport: "telnet"}, {Port: 70000}} {
+		if err := ec.Validate(); err == nil {
+			t.Errorf("expected validation error for %+v", ec)
+		}
+	}
+}
@@ -55,6 +55,63 @@ type MgmtNet struct {
 	IPv6Range      string `yaml:"ipv6-range,omitempty" json:"ipv6-range,omitempty"`
 	MTU            int    `yaml:"mtu,omitempty" json:"mtu,omitempty"`
 	ExternalAccess *bool  `yaml:"external-access,omitempty" json:"external-access,omitempty"`
+	// DNSServer enables the containerlab DNS server for the lab node names.
+	// The server listens on the management network gateway addresses.
+	DNSServer bool `yaml:"dns-server,omitempty" json:"dns-server,omitempty"`
+	// PortPublishing enables the publishing of the nodes management services on the host ports.
+	PortPublishing *PortPublishing `yaml:"port-publishing,omitempty" json:"port-publishing,omitempty"`
+	// Isolation is the firewall policy isolating the management network from the other labs.
+	Isolation *MgmtIsolation `yaml:"isolation,omitempty" json:"isolation,omitempty"`
+}
+
+// MgmtIsolation is the firewall policy of the management network.
+// The policy is applied to the management bridge, therefore all labs sharing
+// the management network are isolated together.
+type MgmtIsolation struct {
+	// Enabled blocks the traffic between the management bridge and the other container runtime bridges.
+	Enabled bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	// EgressAllow restricts the traffic leaving the management network to the listed IPv4 and IPv6 prefixes.
+	EgressAllow []string `yaml:"egress-allow,omitempty" json:"egress-allow,omitempty"`
+}
+
+// IsolationEnabled returns true if the isolation policy is enabled for the management network.
+func (m *MgmtNet) IsolationEnabled() bool {
+	return m.Isolation != nil && m.Isolation.Enabled
+}
+
+// PortPublishing configures the publishing of the nodes management services,
+// such as SSH and gNMI, on the host ports allocated from the port range.
+type PortPublishing struct {
+	// PortRange is the range of the host ports, e.g. 50000-50999.
+	PortRange string `yaml:"port-range,omitempty" json:"port-range,omitempty"`
+	// HostIP is the host address the ports are published on, all host addresses by default.
+	HostIP string `yaml:"host-ip,omitempty" json:"host-ip,omitempty"`
+	// Services is the list of the published services, all management services by default.
+	Services []string `yaml:"services,omitempty" json:"services,omitempty"`
+}
+
+// Network is a named container runtime network that nodes can be attached to
+// in addition to the management network, e.g. an out-of-band or an internet-facing network.
+type Network struct {
+	// container runtime network name, defaults to the name of the network in the topology
+	Network    string `yaml:"network,omitempty" json:"network,omitempty"`
+	Bridge     string `yaml:"bridge,omitempty" json:"bridge,omitempty"`
+	IPv4Subnet string `yaml:"ipv4-subnet,omitempty" json:"ipv4-subnet,omitempty"`
+	IPv6Subnet string `yaml:"ipv6-subnet,omitempty" json:"ipv6-subnet,omitempty"`
+	MTU        int    `yaml:"mtu,omitempty" json:"mtu,omitempty"`
+}
+
+// NetworkAttachment attaches a node to one of the networks defined in the topology.
+type NetworkAttachment struct {
+	// Name of the network in the topology networks section.
+	Name string `yaml:"name" json:"name"`
+	// Network is the container runtime network name, populated from the network definition.
+	Network     string `yaml:"-" json:"network,omitempty"`
+	IPv4Address string `yaml:"ipv4-address,omitempty" json:"ipv4-address,omitempty"`
+	IPv6Address string `yaml:"ipv6-address,omitempty" json:"ipv6-address,omitempty"`
+	// Interface is the name of the container interface attached to the network.
+	// Defaults to eth1, eth2, etc. following the order of the node network attachments.
+	Interface string `yaml:"interface,omitempty" json:"interface,omitempty"`
 }
 
 // Interface compliance.
@@ -141,6 +198,8 @@ type NodeConfig struct {
 	ResultingPortBindings []*GenericPortBinding `json:"port-bindings,omitempty"`
 	// PortSet define the ports that should be exposed on a container
 	PortSet nat.PortSet `json:"portset,omitempty"`
+	// MgmtPorts maps the management services published on the host to the host ports
+	MgmtPorts map[string]int `json:"mgmt-ports,omitempty"`
 	// NetworkMode defines container networking mode.
 	// If set to `host` the host networking will be used for this node, else bridged network
 	NetworkMode string `json:"networkmode,omitempty"`
@@ -161,10 +220,18 @@ type NodeConfig struct {
 	TLSAnchor            string `json:"tls-anchor,omitempty"`
 	// TLS Certificate configuration
 	Certificate *CertificateConfig
+	// Credentials set in the topology to access the node, not marshalled to keep the secrets out of the exports
+	Credentials *CredentialsConfig `json:"-"`
+	// ExecConfig is the configuration of the transport the node commands are executed over
+	ExecConfig *ExecConfig `json:"exec-config,omitempty"`
 	// Healthcheck configuration parameters
 	Healthcheck *HealthcheckConfig
 	// Network aliases
 	Aliases []string `json:"aliases,omitempty"`
+	// Static routes configured in the node's network namespace
+	StaticRoutes []*StaticRoute `json:"static-routes,omitempty"`
+	// Networks is a list of the runtime networks the node is attached to in addition to the management network.
+	Networks []*NetworkAttachment `json:"networks,omitempty"`
 	// NSPath      string `json:"nspath,omitempty"` // network namespace path for this node
 	// list of ports to publish with mysocketctl
 	Publish []string `json:"publish,omitempty"`
@@ -195,6 +262,10 @@ type NodeConfig struct {
 	// Introduced to prevent the check from running with ext-containers, since
 	// they should be present by definition.
 	SkipUniquenessCheck bool
+	// KernelDataplane flag indicates that the dataplane of the node is the kernel networking stack.
+	// For such nodes containerlab configures the link addresses and static routes
+	// in the node's network namespace.
+	KernelDataplane bool
 }
 
 type GenericFilter struct {
@@ -286,6 +357,8 @@ type ContainerDetails struct {
 	IPv6Address string                `json:"ipv6_address,omitempty"`
 	Ports       []*GenericPortBinding `json:"ports,omitempty"`
 	Owner       string                `json:"owner,omitempty"`
+	// MgmtPorts lists the management services published on the host ports, e.g. ssh=50000,gnmi=50001
+	MgmtPorts string `json:"mgmt_ports,omitempty"`
 }
 
 // GenericPortBinding represents a port binding.
@@ -321,16 +394,30 @@ type DNSConfig struct {
 	Search []string `yaml:"search,omitempty"`
 }
 
+// StaticRoute represents a static route configured in the node's network namespace.
+type StaticRoute struct {
+	// Prefix is the destination prefix of the route, e.g. 10.0.0.0/8 or ::/0
+	Prefix string `yaml:"prefix" json:"prefix"`
+	// NextHop is the gateway address of the route
+	NextHop string `yaml:"next-hop,omitempty" json:"next-hop,omitempty"`
+	// Interface is the name of the outgoing interface of the route
+	Interface string `yaml:"interface,omitempty" json:"interface,omitempty"`
+}
+
 // CertificateConfig represents TLS parameters set for a node.
 type CertificateConfig struct {
 	// default false value indicates that the node does not use TLS
 	Issue *bool `yaml:"issue,omitempty"`
 	// KeySize is the size of the key in bits
 	KeySize int `yaml:"key-size,omitempty"`
+	// KeyAlgorithm is the algorithm of the key, one of rsa, ecdsa-p256, ecdsa-p384 and ed25519
+	KeyAlgorithm string `yaml:"key-algorithm,omitempty"`
 	// ValidityDuration is the duration of the certificate validity
 	ValidityDuration time.Duration `yaml:"validity-duration"`
 	// list of subject Alternative Names (SAN) to be added to the node's certificate
 	SANs []string `yaml:"sans,omitempty"`
+	// InstallCA installs the lab CA certificate into the trust store of the linux nodes
+	InstallCA *bool `yaml:"install-ca,omitempty"`
 }
 
 // Merge merges the given CertificateConfig into the current one.
@@ -351,13 +438,142 @@ func (c *CertificateConfig) Merge(x *CertificateConfig) *CertificateConfig {
 		c.KeySize = x.KeySize
 	}
 
+	if x.KeyAlgorithm != "" {
+		c.KeyAlgorithm = x.KeyAlgorithm
+	}
+
 	if len(x.SANs) > 0 {
 		c.SANs = x.SANs
 	}
 
+	if x.InstallCA != nil {
+		c.InstallCA = x.InstallCA
+	}
+
+	return c
+}
+
+// CredentialsConfig represents the credentials used to access a node.
+type CredentialsConfig struct {
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	// SSHKey is the path to the private key used to access the node over SSH
+	SSHKey string `yaml:"ssh-key,omitempty"`
+	// EnvFile is the path to the env file with USERNAME, PASSWORD and SSH_KEY variables
+	// used for the credentials that are not set explicitly
+	EnvFile string `yaml:"env-file,omitempty"`
+}
+
+// Merge merges the given CredentialsConfig into the current one.
+func (c *CredentialsConfig) Merge(x *CredentialsConfig) *CredentialsConfig {
+	if x == nil {
+		return c
+	}
+
+	if x.Username != "" {
+		c.Username = x.Username
+	}
+
+	if x.Password != "" {
+		c.Password = x.Password
+	}
+
+	if x.SSHKey != "" {
+		c.SSHKey = x.SSHKey
+	}
+
+	if x.EnvFile != "" {
+		c.EnvFile = x.EnvFile
+	}
+
 	return c
 }
 
+const (
+	// ExecTransportContainer is the transport executing the node commands in the node container.
+	ExecTransportContainer = "container"
+	// ExecTransportSSH is the transport executing the node commands over SSH.
+	ExecTransportSSH = "ssh"
+)
+
+// ExecConfig represents the configuration of the transport the node commands are executed over.
+type ExecConfig struct {
+	// Transport is one of container and ssh, the commands are executed in the node container by default
+	Transport string `yaml:"transport,omitempty" json:"transport,omitempty"`
+	// Platform is the scrapligo platform driving the node CLI over SSH, the kind default is used when unset.
+	// The commands are executed in a plain SSH session when neither is set
+	Platform string `yaml:"platform,omitempty" json:"platform,omitempty"`
+	// Port is the SSH port of the node, 22 by default
+	Port int `yaml:"port,omitempty" json:"port,omitempty"`
+	// InsecureHostKey allows connecting to the node whose SSH host key is not pinned
+	// in the lab known_hosts file without verifying the host key
+	InsecureHostKey bool `yaml:"insecure-host-key,omitempty" json:"insecure-host-key,omitempty"`
+}
+
+// Merge merges the given ExecConfig into the current one.
+func (e *ExecConfig) Merge(x *ExecConfig) *ExecConfig {
+	if x == nil {
+		return e
+	}
+
+	if x.Transport != "" {
+		e.Transport = x.Transport
+	}
+
+	if x.Platform != "" {
+		e.Platform = x.Platform
+	}
+
+	if x.Port != 0 {
+		e.Port = x.Port
+	}
+
+	if x.InsecureHostKey {
+		e.InsecureHostKey = true
+	}
+
+	return e
+}
+
+// GetTransport returns the exec transport, container by default.
+func (e *ExecConfig) GetTransport() string {
+	if e == nil || e.Transport == "" {
+		return ExecTransportContainer
+	}
+
+	return e.Transport
+}
+
+// GetPort returns the SSH port, 22 by default.
+func (e *ExecConfig) GetPort() int {
+	if e == nil || e.Port == 0 {
+		return 22
+	}
+
+	return e.Port
+}
+
+// GetInsecureHostKey returns true when the unpinned SSH host keys are accepted without verification.
+func (e *ExecConfig) GetInsecureHostKey() bool {
+	return e != nil && e.InsecureHostKey
+}
+
+// Validate checks the exec transport and SSH port.
+func (e *ExecConfig) Validate() error {
+	switch e.GetTransport() {
+	case ExecTransportContainer, ExecTransportSSH:
+	default:
+		return fmt.Errorf("unsupported exec transport %q, use one of [%s, %s]",
+			e.Transport, ExecTransportContainer, ExecTransportSSH)
+	}
+
+	if p := e.GetPort(); p < 1 || p > 65535 {
+		return fmt.Errorf("invalid exec ssh port %d", p)
+	}
+
+	return nil
+}
+
 // PullPolicyValue represents Image pull policy values.
 type PullPolicyValue string
 
@@ -0,0 +1,99 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// WebhookEventType is the type of the lab lifecycle event the webhooks are notified of.
+type WebhookEventType string
+
+const (
+	WebhookDeployStarted   WebhookEventType = "deploy-started"
+	WebhookDeployFinished  WebhookEventType = "deploy-finished"
+	WebhookDeployFailed    WebhookEventType = "deploy-failed"
+	WebhookDestroyStarted  WebhookEventType = "destroy-started"
+	WebhookDestroyFinished WebhookEventType = "destroy-finished"
+	WebhookDestroyFailed   WebhookEventType = "destroy-failed"
+	WebhookNodeHealthy     WebhookEventType = "node-healthy"
+	WebhookNodeUnhealthy   WebhookEventType = "node-unhealthy"
+)
+
+// WebhookEventTypes is the list of the supported webhook event types.
+var WebhookEventTypes = []WebhookEventType{
+	WebhookDeployStarted, WebhookDeployFinished, WebhookDeployFailed,
+	WebhookDestroyStarted, WebhookDestroyFinished, WebhookDestroyFailed,
+	WebhookNodeHealthy, WebhookNodeUnhealthy,
+}
+
+const (
+	// DefaultWebhookTimeout is the timeout of a webhook request when it is not set.
+	DefaultWebhookTimeout = 10 * time.Second
+	// DefaultWebhookRetries is the number of times a failed webhook request is retried when it is not set.
+	DefaultWebhookRetries = 3
+)
+
+// Webhook is an HTTP endpoint the lab lifecycle events are posted to.
+type Webhook struct {
+	// URL is the http(s) URL the events are posted to.
+	URL string `yaml:"url"`
+	// Secret is the key the events are signed with using HMAC-SHA256.
+	Secret string `yaml:"secret,omitempty"`
+	// Events are the event types the webhook is notified of, all events when empty.
+	Events []WebhookEventType `yaml:"events,omitempty"`
+	// Timeout is the timeout of a single request.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// Retries is the number of times a failed request is retried with an exponential backoff.
+	Retries *int `yaml:"retries,omitempty"`
+}
+
+// Subscribed returns true if the webhook is notified of the event type.
+func (w *Webhook) Subscribed(e WebhookEventType) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, e)
+}
+
+// InitWebhooks validates the webhooks and sets their default timeout and retries.
+func (s *Settings) InitWebhooks() error {
+	for i, w := range s.GetWebhooks() {
+		if w == nil {
+			return fmt.Errorf("webhook #%d is empty", i+1)
+		}
+
+		u, err := url.Parse(w.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhook #%d has invalid url %q, should be an http(s) URL", i+1, w.URL)
+		}
+
+		for _, e := range w.Events {
+			if !slices.Contains(WebhookEventTypes, e) {
+				return fmt.Errorf("webhook %q has unknown event %q, should be one of %q",
+					u.Redacted(), e, WebhookEventTypes)
+			}
+		}
+
+		if w.Timeout < 0 {
+			return fmt.Errorf("webhook %q has a negative timeout", u.Redacted())
+		}
+
+		if w.Timeout == 0 {
+			w.Timeout = DefaultWebhookTimeout
+		}
+
+		if w.Retries == nil {
+			r := DefaultWebhookRetries
+			w.Retries = &r
+		}
+
+		if *w.Retries < 0 {
+			return fmt.Errorf("webhook %q has a negative number of retries", u.Redacted())
+		}
+	}
+
+	return nil
+}
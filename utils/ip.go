@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net"
+	"net/netip"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
@@ -45,3 +46,29 @@ func CIDRToDDN(length int) string {
 	mask := net.CIDRMask(length, 32)
 	return fmt.Sprintf("%d.%d.%d.%d", mask[0], mask[1], mask[2], mask[3])
 }
+
+// IPFarEnd calculates the far end IP of a point-to-point subnet (first free IP in the subnet),
+// the returned prefix is invalid if there is no far end IP.
+func IPFarEnd(in netip.Prefix) netip.Prefix {
+	if in.Addr().Is4() && in.Bits() == 32 {
+		return netip.Prefix{}
+	}
+
+	n := in.Addr().Next()
+
+	if in.Addr().Is4() && in.Bits() <= 30 {
+		if !in.Contains(n) || !in.Contains(in.Addr().Prev()) {
+			return netip.Prefix{}
+		}
+		if !in.Contains(n.Next()) {
+			n = in.Addr().Prev()
+		}
+	}
+	if !in.Contains(n) {
+		n = in.Addr().Prev()
+	}
+	if !in.Contains(n) {
+		return netip.Prefix{}
+	}
+	return netip.PrefixFrom(n, in.Bits())
+}
@@ -9,13 +9,21 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 
+	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/jsimonetti/rtnetlink/rtnl"
 	log "github.com/sirupsen/logrus"
 	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
 )
 
+// NamedNetnsDir is the directory where named network namespaces
+// are bind mounted, same as used by iproute2.
+const NamedNetnsDir = "/run/netns"
+
 // BridgeByName returns a *netlink.Bridge referenced by its name.
 func BridgeByName(name string) (*netlink.Bridge, error) {
 	l, err := netlink.LinkByName(name)
@@ -44,6 +52,122 @@ func LinkContainerNS(nspath, containerName string) error {
 	return nil
 }
 
+// NamedNetnsPath returns the path to the named network namespace.
+func NamedNetnsPath(name string) string {
+	return filepath.Join(NamedNetnsDir, name)
+}
+
+// CreateNamedNetns creates a named network namespace (the equivalent of `ip netns add <name>`)
+// and brings up its loopback interface.
+// The namespace is created in a dedicated OS thread so that the calling goroutine
+// stays in its original network namespace.
+func CreateNamedNetns(name string) error {
+	errCh := make(chan error, 1)
+
+	go func() {
+		// the thread is not unlocked on purpose in case of a failure
+		// to restore the original netns, the runtime will then terminate the thread.
+		runtime.LockOSThread()
+
+		origNs, err := netns.Get()
+		if err != nil {
+			errCh <- err
+			return
+		}
+		defer origNs.Close()
+
+		newNs, err := netns.NewNamed(name)
+		if err != nil {
+			errCh <- fmt.Errorf("failed to create network namespace %q: %w", name, err)
+			return
+		}
+		defer newNs.Close()
+
+		// NewNamed switches the thread to the new namespace,
+		// this is a good moment to bring up the loopback interface.
+		lo, err := netlink.LinkByName("lo")
+		if err == nil {
+			err = netlink.LinkSetUp(lo)
+		}
+
+		if restoreErr := netns.Set(origNs); restoreErr != nil {
+			errCh <- fmt.Errorf("failed to restore original network namespace: %w", restoreErr)
+			return
+		}
+
+		runtime.UnlockOSThread()
+
+		errCh <- err
+	}()
+
+	return <-errCh
+}
+
+// DeleteNamedNetns deletes a named network namespace created with CreateNamedNetns.
+// A missing namespace is not treated as an error.
+func DeleteNamedNetns(name string) error {
+	if !FileOrDirExists(NamedNetnsPath(name)) {
+		return nil
+	}
+
+	log.Debugf("Deleting named network namespace %q", name)
+	return netns.DeleteNamed(name)
+}
+
+// RenameLinksByMAC renames the links in the network namespace at nspath
+// whose hardware address is a key of the names map to the name it maps to.
+// The links are renamed via temporary names, so that the names can be swapped between the links.
+func RenameLinksByMAC(nspath string, names map[string]string) error {
+	netNs, err := ns.GetNS(nspath)
+	if err != nil {
+		return err
+	}
+	defer netNs.Close()
+
+	return netNs.Do(func(_ ns.NetNS) error {
+		ls, err := netlink.LinkList()
+		if err != nil {
+			return err
+		}
+
+		var renamed []netlink.Link
+
+		for _, l := range ls {
+			name, ok := names[l.Attrs().HardwareAddr.String()]
+			if !ok || name == l.Attrs().Name {
+				continue
+			}
+
+			if err := netlink.LinkSetDown(l); err != nil {
+				return err
+			}
+
+			tmp := fmt.Sprintf("clabtmp%d", l.Attrs().Index)
+			if err := netlink.LinkSetName(l, tmp); err != nil {
+				return fmt.Errorf("failed to rename link %q: %w", l.Attrs().Name, err)
+			}
+
+			renamed = append(renamed, l)
+		}
+
+		for _, l := range renamed {
+			name := names[l.Attrs().HardwareAddr.String()]
+
+			log.Debugf("Renaming link with MAC %s to %q", l.Attrs().HardwareAddr, name)
+
+			if err := netlink.LinkSetName(l, name); err != nil {
+				return fmt.Errorf("failed to rename link to %q: %w", name, err)
+			}
+
+			if err := netlink.LinkSetUp(l); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
 // GenMac generates a random MAC address for a given OUI.
 func GenMac(oui string) (net.HardwareAddr, error) {
 	buf := make([]byte, 3)